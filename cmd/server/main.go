@@ -1,53 +1,333 @@
 package main
 
 import (
-	"log"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+	_ "time/tzdata"
 
 	"payflow-api/internal/config"
+	"payflow-api/internal/entity"
+	"payflow-api/internal/gateway"
 	"payflow-api/internal/handler"
+	"payflow-api/internal/metrics"
 	"payflow-api/internal/repository"
 	"payflow-api/internal/usecase"
+	"payflow-api/internal/worker"
 	"payflow-api/pkg/database"
+	"payflow-api/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 )
 
 func main() {
 	// Carregar configurações
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("Erro ao carregar configurações: %v", err)
+		fatal("erro ao carregar configurações", err)
 	}
 
+	slog.SetDefault(logger.New(cfg.Log.Level, os.Stdout))
+
 	// Conectar ao banco de dados
 	db, err := database.NewPostgresConnection(cfg)
 	if err != nil {
-		log.Fatalf("Erro ao conectar com banco de dados: %v", err)
+		fatal("erro ao conectar com banco de dados", err)
 	}
 	defer db.Close()
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	appMetrics := metrics.New()
+	appMetrics.RegisterDBStats(db.DB, cfg.Database.DBName)
+
+	// Serviços externos
+	requestTimeout := time.Duration(cfg.External.RequestTimeout) * time.Second
+	authorizer := gateway.NewInstrumentedAuthorizer(gateway.NewHTTPAuthorizer(cfg.External.AuthorizerURL, requestTimeout), appMetrics)
+	notifier := gateway.NewInstrumentedNotifier(gateway.NewHTTPNotifier(cfg.External.NotificationURL, requestTimeout), appMetrics)
+	webhookTimeout := time.Duration(cfg.Webhook.TimeoutSec) * time.Second
+	webhookSender := gateway.NewHTTPWebhookSender(webhookTimeout, cfg.Webhook.AllowLocalhost)
+	eventPublisher, err := newEventPublisher(cfg.Publisher)
+	if err != nil {
+		fatal("erro ao configurar publicação de eventos", err)
+	}
+
 	// Inicializar camadas
 	userRepo := repository.NewUserPostgresRepository(db)
-	userUseCase := usecase.NewUserUseCase(userRepo)
+	transactionRepo := repository.NewTransactionPostgresRepository(db)
+	holdRepo := repository.NewBalanceHoldPostgresRepository(db)
+	limitRepo := repository.NewLimitPostgresRepository(db)
+	pricingRepo := repository.NewPricingPostgresRepository(db)
+	recurringRepo := repository.NewRecurringPostgresRepository(db)
+	paymentRequestRepo := repository.NewPaymentRequestPostgresRepository(db)
+	pixKeyRepo := repository.NewPixKeyPostgresRepository(db)
+	batchRepo := repository.NewTransferBatchPostgresRepository(db)
+	splitRepo := repository.NewSplitPaymentPostgresRepository(db)
+	disputeRepo := repository.NewDisputePostgresRepository(db)
+	riskRepo := repository.NewRiskPostgresRepository(db)
+	dataExportRepo := repository.NewDataExportPostgresRepository(db)
+	auditRepo := repository.NewAuditPostgresRepository(db)
+	statementRepo := repository.NewStatementPostgresRepository(db)
+	receiptRepo := repository.NewReceiptPostgresRepository(db)
+	webhookRepo := repository.NewWebhookPostgresRepository(db)
+	outboxRepo := repository.NewOutboxPostgresRepository(db)
+	userStreamRepo := repository.NewUserStreamPostgresRepository(db)
+
+	limitsLocation, err := time.LoadLocation(cfg.Limits.Timezone)
+	if err != nil {
+		fatal("fuso horário inválido para limites", err)
+	}
+
+	auditUseCase := usecase.NewAuditUseCase(auditRepo)
+	eventBus := usecase.NewEventBus(db, outboxRepo, usecase.EventPolicy{
+		Retry: entity.OutboxRetryPolicy{
+			BaseDelay: time.Duration(cfg.Events.RetryBaseSec) * time.Second,
+			MaxDelay:  time.Duration(cfg.Events.RetryMaxSec) * time.Second,
+		},
+		Grace: time.Duration(cfg.Events.GraceSec) * time.Second,
+		Lease: time.Duration(cfg.Events.LeaseSec) * time.Second,
+	})
+	eventBus.Subscribe("transfer-notification", usecase.EventAsync,
+		usecase.NewTransferNotificationHandler(transactionRepo, userRepo, notifier), entity.EventTransferCompleted)
+
+	// As transferências são contadas no commit da mudança de status, não pelos assinantes do barramento
+	transferEvents := usecase.NewTransferMetricsRecorder(db, eventBus, appMetrics)
+	appMetrics.RegisterOutboxBacklog(func(ctx context.Context) (map[string]int, error) {
+		backlog, err := outboxRepo.CountBacklog(ctx)
+		if err != nil {
+			return nil, err
+		}
+		queues := map[string]int{"dispatch": backlog.Undispatched}
+		// Sem broker configurado nada é publicado e a fila só cresceria
+		if eventPublisher != nil {
+			queues["publish"] = backlog.Unpublished
+		}
+		return queues, nil
+	})
+
+	userStreamHub := usecase.NewUserStreamHub()
+	userStreamUseCase := usecase.NewUserStreamUseCase(db, userStreamRepo, userRepo, userStreamHub, usecase.UserStreamPolicy{
+		Retention:             time.Duration(cfg.Stream.RetentionMinutes) * time.Minute,
+		MaxConnectionsPerUser: cfg.Stream.MaxConnectionsPerUser,
+	})
+	eventBus.Subscribe("user-stream", usecase.EventAsync, userStreamUseCase.HandleEvent,
+		entity.EventBalanceChanged, entity.EventTransferAuthorized, entity.EventTransferCompleted,
+		entity.EventTransferFailed, entity.EventTransferReversed)
+	webhookUseCase := usecase.NewWebhookUseCase(db, webhookRepo, userRepo, webhookSender, usecase.WebhookPolicy{
+		Retry: entity.WebhookRetryPolicy{
+			MaxAttempts: cfg.Webhook.MaxAttempts,
+			BaseDelay:   time.Duration(cfg.Webhook.RetryBaseSec) * time.Second,
+			MaxDelay:    time.Duration(cfg.Webhook.RetryMaxSec) * time.Second,
+		},
+		Lease: webhookTimeout + time.Minute,
+		URLs:  entity.WebhookURLPolicy{AllowLocalhost: cfg.Webhook.AllowLocalhost},
+	}, auditUseCase)
+	userUseCase := usecase.NewUserUseCase(db, userRepo, pixKeyRepo, entity.RetentionPolicy{
+		Period: time.Duration(cfg.Privacy.RetentionDays) * 24 * time.Hour,
+	}, eventBus, auditUseCase)
+	limitUseCase := usecase.NewLimitUseCase(db, limitRepo, userRepo, entity.LimitPolicy{
+		Location:       limitsLocation,
+		NightStartHour: cfg.Limits.NightStartHour,
+		NightEndHour:   cfg.Limits.NightEndHour,
+	}, auditUseCase)
+	pricingUseCase := usecase.NewPricingUseCase(db, pricingRepo, userRepo, auditUseCase)
+
+	riskPolicy := entity.RiskPolicy{
+		VelocityMaxPerMinute: cfg.Risk.VelocityMaxPerMinute,
+		VelocityAction:       entity.RiskDecision(cfg.Risk.VelocityAction),
+		NewPayeeThreshold:    decimal.NewFromInt(int64(cfg.Risk.NewPayeeThreshold)),
+		NewPayeeAction:       entity.RiskDecision(cfg.Risk.NewPayeeAction),
+		AnomalyMultiplier:    decimal.NewFromInt(int64(cfg.Risk.AnomalyMultiplier)),
+		AnomalyMinHistory:    cfg.Risk.AnomalyMinHistory,
+		AnomalyAction:        entity.RiskDecision(cfg.Risk.AnomalyAction),
+		NewAccountAge:        time.Duration(cfg.Risk.NewAccountDays) * 24 * time.Hour,
+		NewAccountThreshold:  decimal.NewFromInt(int64(cfg.Risk.NewAccountThreshold)),
+		NewAccountAction:     entity.RiskDecision(cfg.Risk.NewAccountAction),
+		ReviewTTL:            time.Duration(cfg.Risk.ReviewTTLHours) * time.Hour,
+	}
+	if err := riskPolicy.Validate(); err != nil {
+		fatal("configuração de risco inválida", err)
+	}
+	riskEngine := usecase.NewRiskEngine(riskRepo, riskPolicy)
+
+	transactionUseCase := usecase.NewTransactionUseCase(
+		db,
+		userRepo,
+		transactionRepo,
+		holdRepo,
+		pixKeyRepo,
+		limitUseCase,
+		pricingUseCase,
+		riskEngine,
+		authorizer,
+		time.Duration(cfg.Transfer.HoldTTLMinutes)*time.Minute,
+		transferEvents,
+		webhookUseCase,
+		auditUseCase,
+	)
+
+	batchUseCase := usecase.NewTransferBatchUseCase(db, batchRepo, userRepo, pixKeyRepo, transactionUseCase, cfg.Transfer.BatchMaxItems, time.Duration(cfg.Transfer.BatchLeaseSec)*time.Second, auditUseCase)
+	splitPaymentUseCase := usecase.NewSplitPaymentUseCase(db, splitRepo, userRepo, transactionRepo, pixKeyRepo, pricingRepo, limitUseCase, transactionUseCase, transferEvents, webhookUseCase, auditUseCase)
+	riskReviewUseCase := usecase.NewRiskReviewUseCase(db, riskRepo, transactionRepo, transactionUseCase, auditUseCase)
+	disputeUseCase := usecase.NewDisputeUseCase(db, disputeRepo, transactionRepo, splitRepo, transactionUseCase, entity.DisputePolicy{
+		Window:         time.Duration(cfg.Dispute.WindowDays) * 24 * time.Hour,
+		ResponseTime:   time.Duration(cfg.Dispute.ResponseDays) * 24 * time.Hour,
+		ResolutionTime: time.Duration(cfg.Dispute.ResolutionDays) * 24 * time.Hour,
+	}, webhookUseCase, auditUseCase)
+	pixKeyUseCase := usecase.NewPixKeyUseCase(db, pixKeyRepo, userRepo, auditUseCase)
+	recurringUseCase := usecase.NewRecurringUseCase(db, recurringRepo, userRepo, transactionRepo, auditUseCase)
+	paymentRequestUseCase := usecase.NewPaymentRequestUseCase(db, paymentRequestRepo, userRepo, transactionRepo, pixKeyRepo, transactionUseCase, entity.BRCodeSettings{
+		LocationURL:  cfg.Pix.LocationURL,
+		MerchantCity: cfg.Pix.MerchantCity,
+	}, auditUseCase)
+	eventBus.Subscribe("payment-request-settlement", usecase.EventSync, paymentRequestUseCase.HandleTransferCompleted, entity.EventTransferCompleted)
+
+	exportSigningKey := []byte(cfg.Export.SigningKey)
+	if len(exportSigningKey) == 0 {
+		// Sem chave configurada os links de download deixam de valer a cada reinício
+		slog.Warn("DATA_EXPORT_SIGNING_KEY não configurada; usando chave aleatória")
+		exportSigningKey = make([]byte, 32)
+		if _, err := rand.Read(exportSigningKey); err != nil {
+			fatal("erro ao gerar chave de assinatura", err)
+		}
+	}
+	dataExportUseCase := usecase.NewDataExportUseCase(db, dataExportRepo, userRepo, pixKeyRepo, transactionRepo, disputeRepo,
+		entity.NewDataExportSigner(exportSigningKey),
+		usecase.DataExportPolicy{
+			LinkTTL: time.Duration(cfg.Export.LinkTTLHours) * time.Hour,
+			BaseURL: cfg.Export.BaseURL,
+			Lease:   time.Duration(cfg.Export.LeaseSec) * time.Second,
+		},
+		auditUseCase,
+	)
+
+	statementUseCase := usecase.NewStatementUseCase(statementRepo, userRepo)
+
+	var receiptSigningKey ed25519.PrivateKey
+	if cfg.Receipt.SigningKey != "" {
+		receiptSigningKey, err = entity.ParseReceiptSigningKey(cfg.Receipt.SigningKey)
+		if err != nil {
+			fatal("erro na chave de assinatura de comprovantes", err)
+		}
+	} else if cfg.Server.Env == "development" {
+		// Sem chave configurada os comprovantes já emitidos deixam de conferir a cada reinício
+		slog.Warn("RECEIPT_SIGNING_KEY não configurada; usando chave aleatória")
+		if _, receiptSigningKey, err = ed25519.GenerateKey(rand.Reader); err != nil {
+			fatal("erro ao gerar chave de assinatura de comprovantes", err)
+		}
+	} else {
+		fatal("erro na chave de assinatura de comprovantes", errors.New("RECEIPT_SIGNING_KEY é obrigatória fora de development"))
+	}
+	receiptPreviousKeys, err := entity.ParseReceiptPublicKeys(cfg.Receipt.PreviousKeys)
+	if err != nil {
+		fatal("erro nas chaves anteriores de comprovantes", err)
+	}
+	receiptSigner := entity.NewReceiptSigner(receiptSigningKey, receiptPreviousKeys...)
+	receiptUseCase := usecase.NewReceiptUseCase(db, receiptRepo, transactionRepo, userRepo, receiptSigner, auditUseCase)
+
+	authTokenKey := []byte(cfg.Auth.TokenSecret)
+	if len(authTokenKey) == 0 {
+		if cfg.Server.Env != "development" {
+			fatal("erro na chave dos tokens de acesso", errors.New("AUTH_TOKEN_SECRET é obrigatória fora de development"))
+		}
+		// Sem chave configurada os tokens emitidos deixam de valer a cada reinício
+		slog.Warn("AUTH_TOKEN_SECRET não configurada; usando chave aleatória")
+		authTokenKey = make([]byte, 32)
+		if _, err := rand.Read(authTokenKey); err != nil {
+			fatal("erro ao gerar chave dos tokens de acesso", err)
+		}
+	}
+	authTokens := entity.NewAuthTokenSigner(authTokenKey, time.Duration(cfg.Auth.TokenTTLMin)*time.Minute)
+	authUseCase := usecase.NewAuthUseCase(userRepo, authTokens)
+
+	adminKeys, err := entity.ParseAdminKeys(cfg.Admin.APIKeys)
+	if err != nil {
+		fatal("erro nas chaves administrativas", err)
+	}
+	if adminKeys.Empty() {
+		slog.Warn("ADMIN_API_KEYS não configurada; rotas administrativas bloqueadas")
+	}
+
+	authHandler := handler.NewAuthHandler(authUseCase)
 	userHandler := handler.NewUserHandler(userUseCase)
+	transactionHandler := handler.NewTransactionHandler(transactionUseCase)
+	limitHandler := handler.NewLimitHandler(limitUseCase)
+	pricingHandler := handler.NewPricingHandler(pricingUseCase)
+	recurringHandler := handler.NewRecurringHandler(recurringUseCase)
+	pixKeyHandler := handler.NewPixKeyHandler(pixKeyUseCase)
+	paymentRequestHandler := handler.NewPaymentRequestHandler(paymentRequestUseCase)
+	batchHandler := handler.NewTransferBatchHandler(batchUseCase)
+	splitPaymentHandler := handler.NewSplitPaymentHandler(splitPaymentUseCase)
+	disputeHandler := handler.NewDisputeHandler(disputeUseCase)
+	riskReviewHandler := handler.NewRiskReviewHandler(riskReviewUseCase)
+	dataExportHandler := handler.NewDataExportHandler(dataExportUseCase)
+	auditHandler := handler.NewAuditHandler(auditUseCase)
+	statementHandler := handler.NewStatementHandler(statementUseCase)
+	receiptHandler := handler.NewReceiptHandler(receiptUseCase)
+	webhookHandler := handler.NewWebhookHandler(webhookUseCase)
+	userStreamHandler := handler.NewUserStreamHandler(userStreamUseCase, time.Duration(cfg.Stream.HeartbeatSec)*time.Second)
+
+	// Avisos de eventos novos do stream gravados por qualquer instância
+	go func() {
+		if err := db.Listen(ctx, repository.UserStreamChannel, userStreamHub.HandleNotification); err != nil {
+			slog.Error("erro na escuta do stream de eventos", "error", err)
+		}
+	}()
+
+	// Workers em segundo plano
+	go worker.RunEvery(ctx, "scheduled-transfers", time.Duration(cfg.Transfer.SchedulerIntervalSec)*time.Second, worker.ScheduledTransferJob(transactionUseCase))
+	go worker.RunEvery(ctx, "hold-expiry", time.Duration(cfg.Transfer.HoldSweepIntervalSec)*time.Second, worker.HoldExpiryJob(transactionUseCase))
+	go worker.RunEvery(ctx, "recurring-payments", time.Duration(cfg.Transfer.RecurringIntervalSec)*time.Second, worker.RecurringPaymentJob(recurringUseCase))
+	go worker.RunEvery(ctx, "payment-request-expiry", time.Duration(cfg.Transfer.PaymentRequestSweepIntervalSec)*time.Second, worker.PaymentRequestExpiryJob(paymentRequestUseCase))
+	go worker.RunEvery(ctx, "transfer-batches", time.Duration(cfg.Transfer.BatchIntervalSec)*time.Second, worker.TransferBatchJob(batchUseCase))
+	go worker.RunEvery(ctx, "dispute-sla", time.Duration(cfg.Dispute.SweepIntervalSec)*time.Second, worker.DisputeSLAJob(disputeUseCase))
+	go worker.RunEvery(ctx, "risk-review-expiry", time.Duration(cfg.Risk.ReviewSweepIntervalSec)*time.Second, worker.RiskReviewExpiryJob(riskReviewUseCase))
+	go worker.RunEvery(ctx, "user-anonymization", time.Duration(cfg.Privacy.AnonymizationSweepIntervalSec)*time.Second, worker.UserAnonymizationJob(userUseCase))
+	go worker.RunEvery(ctx, "data-exports", time.Duration(cfg.Export.IntervalSec)*time.Second, worker.DataExportJob(dataExportUseCase))
+	go worker.RunEvery(ctx, "webhook-deliveries", time.Duration(cfg.Webhook.IntervalSec)*time.Second, worker.WebhookDeliveryJob(webhookUseCase))
+	go worker.RunEvery(ctx, "user-stream-prune", time.Duration(cfg.Stream.PruneIntervalSec)*time.Second, worker.UserStreamPruneJob(userStreamUseCase))
+	go worker.RunEvery(ctx, "outbox-relay", time.Duration(cfg.Events.IntervalSec)*time.Second, worker.OutboxRelayJob(eventBus))
+	if eventPublisher != nil {
+		defer eventPublisher.Close()
+		eventPublishRelay := usecase.NewEventPublishRelay(db, outboxRepo, eventPublisher)
+		go worker.RunEvery(ctx, "event-publish", time.Duration(cfg.Publisher.IntervalSec)*time.Second, worker.EventPublishJob(eventPublishRelay))
+	}
 
 	// Configurar Gin
 	if cfg.Server.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	router := gin.Default()
+	// Mensagens de depuração do Gin no mesmo formato dos demais logs
+	gin.DebugPrintFunc = func(format string, values ...interface{}) {
+		slog.Debug(strings.TrimSpace(fmt.Sprintf(format, values...)), "component", "gin")
+	}
+
+	router := gin.New()
 
-	// Middleware básico
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	// Middleware básico: o ID da requisição vem primeiro para estar em todos os logs dela
+	router.Use(handler.RequestID())
+	router.Use(handler.RequestLogger())
+	router.Use(handler.RequestMetrics(appMetrics))
+	router.Use(handler.Recovery())
+	router.Use(handler.AuditContext())
 
 	// CORS simples para desenvolvimento
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, "+handler.AuthorizationHeader+", "+handler.AdminKeyHeader+", "+handler.RequestIDHeader+", "+handler.LastEventIDHeader)
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -57,6 +337,9 @@ func main() {
 		c.Next()
 	})
 
+	// Coleta do Prometheus, fora do prefixo da API
+	router.GET("/metrics", gin.WrapH(appMetrics.Handler()))
+
 	// Rotas básicas para teste
 	v1 := router.Group("/api/v1")
 	{
@@ -81,6 +364,9 @@ func main() {
 			})
 		})
 
+		// Emissão do token de acesso usado no cabeçalho Authorization das rotas dos usuários
+		v1.POST("/auth/token", authHandler.CreateToken)
+
 		// Rotas de usuários
 		users := v1.Group("/users")
 		{
@@ -88,32 +374,189 @@ func main() {
 			users.GET("/", userHandler.ListUsers)
 			users.GET("/:id", userHandler.GetUser)
 			users.PUT("/:id", userHandler.UpdateUser)
-			users.DELETE("/:id", userHandler.DeleteUser)
+			users.DELETE("/:id", handler.RequireUser(authTokens), userHandler.DeleteUser)
 			users.GET("/:id/balance", userHandler.GetBalance)
+			users.GET("/:id/statement", handler.RequireUser(authTokens), statementHandler.GetStatement)
+			users.GET("/:id/events", handler.RequireStreamUser(authTokens), userStreamHandler.Stream)
 		}
 
-		// Rotas de transações (placeholder)
+		// Rotas de transações
 		transactions := v1.Group("/transactions")
 		{
-			transactions.GET("/", func(c *gin.Context) {
-				c.JSON(http.StatusOK, gin.H{
-					"message": "Endpoint de transações - Em desenvolvimento",
-					"routes": []string{
-						"GET /api/v1/transactions - Listar transações",
-						"POST /api/v1/transactions - Criar transação",
-						"GET /api/v1/transactions/:id - Buscar transação",
-					},
-				})
-			})
+			transactions.POST("/", handler.RequireUser(authTokens), transactionHandler.CreateTransaction)
+			transactions.GET("/", handler.RequireUser(authTokens), transactionHandler.ListTransactions)
+			transactions.GET("/fee-quote", pricingHandler.QuoteFee)
+			transactions.POST("/batch", handler.RequireUser(authTokens), batchHandler.CreateBatch)
+			transactions.GET("/batch/:id", handler.RequireUser(authTokens), batchHandler.GetBatch)
+			transactions.GET("/:id", handler.RequireUser(authTokens), transactionHandler.GetTransaction)
+			transactions.GET("/:id/receipt", handler.RequireUser(authTokens), receiptHandler.GetReceipt)
+			transactions.POST("/:id/cancel", handler.RequireUser(authTokens), transactionHandler.CancelTransaction)
+		}
+
+		// Conferência pública de comprovantes
+		receipts := v1.Group("/receipts")
+		{
+			receipts.GET("/public-key", receiptHandler.GetPublicKey)
+			receipts.POST("/verify", receiptHandler.Verify)
+			receipts.GET("/:code", receiptHandler.VerifyByCode)
+		}
+
+		// Rotas de pagamentos divididos
+		splitPayments := v1.Group("/split-payments", handler.RequireUser(authTokens))
+		{
+			splitPayments.POST("/", splitPaymentHandler.CreateSplitPayment)
+			splitPayments.GET("/:id", splitPaymentHandler.GetSplitPayment)
+		}
+
+		// Rotas de disputas
+		disputes := v1.Group("/disputes", handler.RequireUser(authTokens))
+		{
+			disputes.POST("/", disputeHandler.OpenDispute)
+			disputes.GET("/", disputeHandler.ListDisputes)
+			disputes.GET("/:id", disputeHandler.GetDispute)
+			disputes.POST("/:id/respond", disputeHandler.RespondDispute)
+		}
+
+		// Rotas de exportação de dados (LGPD); o download usa o link assinado no lugar do token de acesso
+		dataExports := v1.Group("/data-exports")
+		{
+			dataExports.POST("/", handler.RequireUser(authTokens), dataExportHandler.RequestExport)
+			dataExports.GET("/:id", handler.RequireUser(authTokens), dataExportHandler.GetExport)
+			dataExports.GET("/:id/download", dataExportHandler.Download)
+		}
+
+		// Rotas de webhooks dos lojistas
+		webhooks := v1.Group("/webhooks", handler.RequireUser(authTokens))
+		{
+			webhooks.POST("/endpoints", webhookHandler.CreateEndpoint)
+			webhooks.GET("/endpoints", webhookHandler.ListEndpoints)
+			webhooks.GET("/endpoints/:id", webhookHandler.GetEndpoint)
+			webhooks.PUT("/endpoints/:id", webhookHandler.UpdateEndpoint)
+			webhooks.DELETE("/endpoints/:id", webhookHandler.DeleteEndpoint)
+			webhooks.GET("/deliveries", webhookHandler.ListDeliveries)
+			webhooks.GET("/deliveries/:id", webhookHandler.GetDelivery)
+			webhooks.POST("/deliveries/:id/redeliver", webhookHandler.Redeliver)
+		}
+
+		// Rotas de chaves Pix
+		pixKeys := v1.Group("/pix-keys", handler.RequireUser(authTokens))
+		{
+			pixKeys.POST("/", pixKeyHandler.RegisterKey)
+			pixKeys.GET("/", pixKeyHandler.ListKeys)
+			pixKeys.GET("/lookup", pixKeyHandler.LookupKey)
+			pixKeys.DELETE("/:id", pixKeyHandler.DeleteKey)
+		}
+
+		// Rotas de cobranças
+		paymentRequests := v1.Group("/payment-requests", handler.RequireUser(authTokens))
+		{
+			paymentRequests.POST("/", paymentRequestHandler.CreatePaymentRequest)
+			paymentRequests.GET("/", paymentRequestHandler.ListPaymentRequests)
+			paymentRequests.GET("/:id", paymentRequestHandler.GetPaymentRequest)
+			paymentRequests.POST("/:id/pay", paymentRequestHandler.PayPaymentRequest)
+			paymentRequests.POST("/:id/cancel", paymentRequestHandler.CancelPaymentRequest)
+			paymentRequests.GET("/:id/brcode", paymentRequestHandler.GetBRCode)
+		}
+
+		// Leitura de QR Codes BR Code
+		brCodes := v1.Group("/brcode", handler.RequireUser(authTokens))
+		{
+			brCodes.POST("/parse", paymentRequestHandler.ParseBRCode)
+		}
+
+		// Rotas de pagamentos recorrentes
+		recurring := v1.Group("/recurring-payments", handler.RequireUser(authTokens))
+		{
+			recurring.POST("/", recurringHandler.CreateRecurringPayment)
+			recurring.GET("/", recurringHandler.ListRecurringPayments)
+			recurring.GET("/:id", recurringHandler.GetRecurringPayment)
+			recurring.POST("/:id/pause", recurringHandler.PauseRecurringPayment)
+			recurring.POST("/:id/resume", recurringHandler.ResumeRecurringPayment)
+			recurring.POST("/:id/cancel", recurringHandler.CancelRecurringPayment)
+			recurring.GET("/:id/runs", recurringHandler.ListRuns)
+		}
+
+		// Rotas administrativas
+		admin := v1.Group("/admin", handler.RequireAdmin(adminKeys))
+		{
+			admin.GET("/limits/defaults/:user_type", limitHandler.GetDefaultLimits)
+			admin.PUT("/limits/defaults/:user_type", limitHandler.UpdateDefaultLimits)
+			admin.PUT("/users/:id/status", userHandler.ChangeAccountStatus)
+			admin.GET("/users/:id/status-history", userHandler.GetAccountStatusHistory)
+			admin.GET("/users/:id/limits", limitHandler.GetUserLimits)
+			admin.PUT("/users/:id/limits", limitHandler.UpdateUserLimits)
+			admin.DELETE("/users/:id/limits", limitHandler.ResetUserLimits)
+			admin.GET("/pricing-plans", pricingHandler.ListPlans)
+			admin.POST("/pricing-plans", pricingHandler.CreatePlan)
+			admin.PUT("/users/:id/pricing-plan", pricingHandler.AssignPlan)
+			admin.GET("/platform/revenue", pricingHandler.GetRevenueAccount)
+			admin.POST("/split-payments/:id/refund", splitPaymentHandler.RefundSplitPayment)
+			admin.GET("/disputes", disputeHandler.ListAllDisputes)
+			admin.POST("/disputes/:id/resolve", disputeHandler.ResolveDispute)
+			admin.GET("/risk-reviews", riskReviewHandler.ListReviews)
+			admin.GET("/risk-reviews/:id", riskReviewHandler.GetReview)
+			admin.POST("/risk-reviews/:id/claim", riskReviewHandler.ClaimReview)
+			admin.POST("/risk-reviews/:id/approve", riskReviewHandler.ApproveReview)
+			admin.POST("/risk-reviews/:id/reject", riskReviewHandler.RejectReview)
+			admin.GET("/audit-logs", auditHandler.ListEntries)
+			admin.GET("/audit-logs/verify", auditHandler.VerifyChain)
 		}
 	}
 
 	// Iniciar servidor
-	log.Printf("🚀 Servidor iniciando na porta %s", cfg.Server.Port)
-	log.Printf("📚 Documentação disponível em: http://localhost:%s/api/v1/info", cfg.Server.Port)
-	log.Printf("💚 Health check em: http://localhost:%s/api/v1/health", cfg.Server.Port)
+	slog.Info("servidor iniciando",
+		"port", cfg.Server.Port,
+		"docs", "http://localhost:"+cfg.Server.Port+"/api/v1/info",
+		"health", "http://localhost:"+cfg.Server.Port+"/api/v1/health",
+	)
+
+	server := &http.Server{
+		Addr:    ":" + cfg.Server.Port,
+		Handler: router,
+	}
+	// Streams SSE não terminam sozinhos; sem isso o Shutdown esperaria o timeout
+	server.RegisterOnShutdown(userStreamHub.Close)
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatal("erro ao iniciar servidor", err)
+		}
+	}()
+
+	// Aguardar sinal de encerramento e finalizar requisições em andamento
+	<-ctx.Done()
+	slog.Info("encerrando servidor")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("erro ao encerrar servidor", "error", err)
+	}
+
+	// Assinantes que não terminarem a tempo ficam pendentes no outbox para o relay
+	if err := eventBus.Wait(shutdownCtx); err != nil {
+		slog.Warn("eventos de domínio ainda em despacho no encerramento", "error", err)
+	}
+}
+
+// fatal registra o erro que impede a API de subir e encerra o processo
+func fatal(message string, err error) {
+	slog.Error(message, "error", err)
+	os.Exit(1)
+}
 
-	if err := router.Run(":" + cfg.Server.Port); err != nil {
-		log.Fatalf("Erro ao iniciar servidor: %v", err)
+// newEventPublisher cria o publicador do broker configurado; sem broker os eventos não são publicados
+func newEventPublisher(cfg config.PublisherConfig) (gateway.EventPublisher, error) {
+	timeout := time.Duration(cfg.TimeoutSec) * time.Second
+	switch cfg.Driver {
+	case "", "none":
+		return nil, nil
+	case "nats":
+		return gateway.NewNATSEventPublisher(cfg.NATSURL, cfg.NATSSubject, cfg.NATSPartitions, timeout)
+	case "kafka":
+		return gateway.NewKafkaEventPublisher(strings.Split(cfg.KafkaBrokers, ","), cfg.KafkaTopic, timeout), nil
+	default:
+		return nil, fmt.Errorf("EVENTS_PUBLISHER inválido: %s (use none, nats ou kafka)", cfg.Driver)
 	}
 }
@@ -0,0 +1,101 @@
+// Receptor de webhooks para testes locais: confere a assinatura de cada entrega, descarta eventos
+// repetidos pelo ID e imprime o corpo. Com -fail-rate, recusa parte das entregas para exercitar
+// as novas tentativas do PayFlow.
+//
+//	go run ./cmd/webhook-receiver -secret whsec_... -addr :9000
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"payflow-api/internal/entity"
+)
+
+// maxBodySize limita o corpo aceito, como um endpoint de produção deveria fazer
+const maxBodySize = 1 << 20
+
+func main() {
+	addr := flag.String("addr", ":9000", "endereço em que o receptor escuta")
+	secret := flag.String("secret", os.Getenv("WEBHOOK_SECRET"), "segredo do endpoint (padrão: $WEBHOOK_SECRET)")
+	tolerance := flag.Duration("tolerance", 5*time.Minute, "diferença máxima entre o timestamp da entrega e o relógio local")
+	failRate := flag.Float64("fail-rate", 0, "fração das entregas respondidas com erro, de 0 a 1")
+	failStatus := flag.Int("fail-status", http.StatusServiceUnavailable, "status usado nas entregas recusadas de propósito")
+	flag.Parse()
+
+	if *secret == "" {
+		log.Fatalf("informe o segredo do endpoint com -secret ou WEBHOOK_SECRET")
+	}
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		deliveryID := r.Header.Get(entity.WebhookDeliveryHeader)
+		err = entity.VerifyWebhookSignature(*secret, r.Header.Get(entity.WebhookTimestampHeader),
+			r.Header.Get(entity.WebhookSignatureHeader), body, *tolerance, time.Now())
+		if err != nil {
+			log.Printf("entrega %s recusada: %v", deliveryID, err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if *failRate > 0 && rand.Float64() < *failRate {
+			log.Printf("entrega %s recusada de propósito com status %d", deliveryID, *failStatus)
+			w.WriteHeader(*failStatus)
+			return
+		}
+
+		var event struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			log.Printf("entrega %s com corpo inválido: %v", deliveryID, err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// Novas tentativas e reenvios manuais trazem o mesmo ID de evento
+		mu.Lock()
+		duplicate := seen[event.ID]
+		seen[event.ID] = true
+		mu.Unlock()
+		if duplicate {
+			log.Printf("evento %s (%s) já recebido; entrega %s ignorada", event.ID, event.Type, deliveryID)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, body, "", "  "); err != nil {
+			pretty.Write(body)
+		}
+		log.Printf("evento %s (%s) recebido na entrega %s:\n%s", event.ID, event.Type, deliveryID, pretty.String())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	log.Printf("receptor de webhooks escutando em %s", *addr)
+	if err := http.ListenAndServe(*addr, nil); err != nil {
+		log.Fatalf("erro no receptor de webhooks: %v", err)
+	}
+}
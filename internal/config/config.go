@@ -8,9 +8,29 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	External ExternalConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	External  ExternalConfig
+	Transfer  TransferConfig
+	Limits    LimitsConfig
+	Pix       PixConfig
+	Admin     AdminConfig
+	Auth      AuthConfig
+	Dispute   DisputeConfig
+	Risk      RiskConfig
+	Privacy   PrivacyConfig
+	Export    DataExportConfig
+	Receipt   ReceiptConfig
+	Webhook   WebhookConfig
+	Events    EventsConfig
+	Stream    StreamConfig
+	Publisher PublisherConfig
+	Log       LogConfig
+}
+
+// LogConfig define o nível mínimo dos logs: debug, info, warn ou error
+type LogConfig struct {
+	Level string
 }
 
 type ServerConfig struct {
@@ -33,6 +53,133 @@ type ExternalConfig struct {
 	RequestTimeout  int
 }
 
+// TransferConfig define reservas, workers e lotes de transferências. BatchLeaseSec é o prazo, por
+// transferência, da instância que processa um lote; vencido, outra instância retoma o lote.
+type TransferConfig struct {
+	HoldTTLMinutes                 int
+	HoldSweepIntervalSec           int
+	SchedulerIntervalSec           int
+	RecurringIntervalSec           int
+	PaymentRequestSweepIntervalSec int
+	BatchMaxItems                  int
+	BatchIntervalSec               int
+	BatchLeaseSec                  int
+}
+
+type LimitsConfig struct {
+	Timezone       string
+	NightStartHour int
+	NightEndHour   int
+}
+
+type PixConfig struct {
+	LocationURL  string
+	MerchantCity string
+}
+
+// AuthConfig define a chave HMAC que assina os tokens de acesso dos usuários e por quanto tempo
+// cada token vale
+type AuthConfig struct {
+	TokenSecret string
+	TokenTTLMin int
+}
+
+// AdminConfig lista as chaves pessoais dos administradores, no formato <admin_id>:<chave>
+// separado por vírgula; o admin_id identifica o autor das ações administrativas
+type AdminConfig struct {
+	APIKeys string
+}
+
+type RiskConfig struct {
+	VelocityMaxPerMinute   int
+	VelocityAction         string
+	NewPayeeThreshold      int
+	NewPayeeAction         string
+	AnomalyMultiplier      int
+	AnomalyMinHistory      int
+	AnomalyAction          string
+	NewAccountDays         int
+	NewAccountThreshold    int
+	NewAccountAction       string
+	ReviewTTLHours         int
+	ReviewSweepIntervalSec int
+}
+
+type DisputeConfig struct {
+	WindowDays       int
+	ResponseDays     int
+	ResolutionDays   int
+	SweepIntervalSec int
+}
+
+// PrivacyConfig define a retenção dos dados pessoais de contas excluídas (LGPD)
+type PrivacyConfig struct {
+	RetentionDays                 int
+	AnonymizationSweepIntervalSec int
+}
+
+// DataExportConfig define a assinatura e a validade dos links de download das exportações de dados
+// e o prazo da instância que gera cada arquivo
+type DataExportConfig struct {
+	SigningKey   string
+	BaseURL      string
+	LinkTTLHours int
+	IntervalSec  int
+	LeaseSec     int
+}
+
+// ReceiptConfig define a chave Ed25519 que assina os comprovantes (base64 da semente de 32 bytes) e
+// as chaves públicas das chaves anteriores, em base64 separadas por vírgula, que continuam conferindo
+// os comprovantes emitidos antes da troca
+type ReceiptConfig struct {
+	SigningKey   string
+	PreviousKeys string
+}
+
+// WebhookConfig define o envio dos webhooks dos lojistas: timeout, novas tentativas com espera
+// exponencial (base dobrando até o teto) e o intervalo do worker
+// AllowLocalhost aceita endpoints http://localhost para o receptor de testes local; só vale em development.
+type WebhookConfig struct {
+	TimeoutSec     int
+	MaxAttempts    int
+	RetryBaseSec   int
+	RetryMaxSec    int
+	IntervalSec    int
+	AllowLocalhost bool
+}
+
+// EventsConfig define o despacho dos eventos de domínio: a espera antes de o relay assumir um
+// evento recém-gravado, as novas tentativas com espera exponencial e o intervalo do relay
+type EventsConfig struct {
+	GraceSec     int
+	LeaseSec     int
+	RetryBaseSec int
+	RetryMaxSec  int
+	IntervalSec  int
+}
+
+// StreamConfig define o stream SSE dos usuários: retenção dos eventos para o replay, intervalo do
+// heartbeat, conexões simultâneas por usuário em cada instância e intervalo da limpeza
+type StreamConfig struct {
+	RetentionMinutes      int
+	HeartbeatSec          int
+	MaxConnectionsPerUser int
+	PruneIntervalSec      int
+}
+
+// PublisherConfig define a publicação dos eventos de domínio para outros sistemas: o broker (none,
+// nats ou kafka), o destino em cada um, o timeout de confirmação e o intervalo do relay
+type PublisherConfig struct {
+	Driver         string
+	NATSURL        string
+	NATSSubject    string
+	NATSPartitions int
+	KafkaBrokers   string
+	KafkaTopic     string
+	TimeoutSec     int
+	IntervalSec    int
+}
+
 func Load() (*Config, error) {
 	// Carrega variáveis de ambiente do arquivo .env se existir
 	godotenv.Load()
@@ -55,6 +202,103 @@ func Load() (*Config, error) {
 			NotificationURL: getEnv("NOTIFICATION_URL", "https://util.devi.tools/api/v1/notify"),
 			RequestTimeout:  getEnvAsInt("REQUEST_TIMEOUT", 10),
 		},
+		Transfer: TransferConfig{
+			HoldTTLMinutes:                 getEnvAsInt("HOLD_TTL_MINUTES", 15),
+			HoldSweepIntervalSec:           getEnvAsInt("HOLD_SWEEP_INTERVAL_SECONDS", 60),
+			SchedulerIntervalSec:           getEnvAsInt("SCHEDULER_INTERVAL_SECONDS", 30),
+			RecurringIntervalSec:           getEnvAsInt("RECURRING_INTERVAL_SECONDS", 60),
+			PaymentRequestSweepIntervalSec: getEnvAsInt("PAYMENT_REQUEST_SWEEP_INTERVAL_SECONDS", 60),
+			BatchMaxItems:                  getEnvAsInt("BATCH_MAX_ITEMS", 500),
+			BatchIntervalSec:               getEnvAsInt("BATCH_INTERVAL_SECONDS", 5),
+			BatchLeaseSec:                  getEnvAsInt("BATCH_LEASE_SECONDS", 120),
+		},
+		Limits: LimitsConfig{
+			Timezone:       getEnv("LIMITS_TIMEZONE", "America/Sao_Paulo"),
+			NightStartHour: getEnvAsInt("LIMITS_NIGHT_START_HOUR", 20),
+			NightEndHour:   getEnvAsInt("LIMITS_NIGHT_END_HOUR", 6),
+		},
+		Pix: PixConfig{
+			LocationURL:  getEnv("PIX_LOCATION_URL", "pix.payflow.local/v2/cobv"),
+			MerchantCity: getEnv("PIX_MERCHANT_CITY", "SAO PAULO"),
+		},
+		Admin: AdminConfig{
+			APIKeys: getEnv("ADMIN_API_KEYS", ""),
+		},
+		Auth: AuthConfig{
+			TokenSecret: getEnv("AUTH_TOKEN_SECRET", ""),
+			TokenTTLMin: getEnvAsInt("AUTH_TOKEN_TTL_MINUTES", 60),
+		},
+		Risk: RiskConfig{
+			VelocityMaxPerMinute:   getEnvAsInt("RISK_VELOCITY_MAX_PER_MINUTE", 5),
+			VelocityAction:         getEnv("RISK_VELOCITY_ACTION", "deny"),
+			NewPayeeThreshold:      getEnvAsInt("RISK_NEW_PAYEE_THRESHOLD", 1000),
+			NewPayeeAction:         getEnv("RISK_NEW_PAYEE_ACTION", "review"),
+			AnomalyMultiplier:      getEnvAsInt("RISK_ANOMALY_MULTIPLIER", 5),
+			AnomalyMinHistory:      getEnvAsInt("RISK_ANOMALY_MIN_HISTORY", 5),
+			AnomalyAction:          getEnv("RISK_ANOMALY_ACTION", "review"),
+			NewAccountDays:         getEnvAsInt("RISK_NEW_ACCOUNT_DAYS", 7),
+			NewAccountThreshold:    getEnvAsInt("RISK_NEW_ACCOUNT_THRESHOLD", 500),
+			NewAccountAction:       getEnv("RISK_NEW_ACCOUNT_ACTION", "review"),
+			ReviewTTLHours:         getEnvAsInt("RISK_REVIEW_TTL_HOURS", 24),
+			ReviewSweepIntervalSec: getEnvAsInt("RISK_REVIEW_SWEEP_INTERVAL_SECONDS", 60),
+		},
+		Dispute: DisputeConfig{
+			WindowDays:       getEnvAsInt("DISPUTE_WINDOW_DAYS", 90),
+			ResponseDays:     getEnvAsInt("DISPUTE_RESPONSE_DAYS", 7),
+			ResolutionDays:   getEnvAsInt("DISPUTE_RESOLUTION_DAYS", 30),
+			SweepIntervalSec: getEnvAsInt("DISPUTE_SWEEP_INTERVAL_SECONDS", 300),
+		},
+		Privacy: PrivacyConfig{
+			RetentionDays:                 getEnvAsInt("USER_DATA_RETENTION_DAYS", 1825),
+			AnonymizationSweepIntervalSec: getEnvAsInt("ANONYMIZATION_SWEEP_INTERVAL_SECONDS", 3600),
+		},
+		Export: DataExportConfig{
+			SigningKey:   getEnv("DATA_EXPORT_SIGNING_KEY", ""),
+			BaseURL:      getEnv("PUBLIC_BASE_URL", "http://localhost:8080"),
+			LinkTTLHours: getEnvAsInt("DATA_EXPORT_LINK_TTL_HOURS", 24),
+			IntervalSec:  getEnvAsInt("DATA_EXPORT_INTERVAL_SECONDS", 10),
+			LeaseSec:     getEnvAsInt("DATA_EXPORT_LEASE_SECONDS", 300),
+		},
+		Receipt: ReceiptConfig{
+			SigningKey:   getEnv("RECEIPT_SIGNING_KEY", ""),
+			PreviousKeys: getEnv("RECEIPT_PREVIOUS_PUBLIC_KEYS", ""),
+		},
+		Webhook: WebhookConfig{
+			TimeoutSec:   getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10),
+			MaxAttempts:  getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			RetryBaseSec: getEnvAsInt("WEBHOOK_RETRY_BASE_SECONDS", 30),
+			RetryMaxSec:  getEnvAsInt("WEBHOOK_RETRY_MAX_SECONDS", 21600),
+			IntervalSec:  getEnvAsInt("WEBHOOK_INTERVAL_SECONDS", 5),
+			// O receptor local só é aceito em desenvolvimento, qualquer que seja a variável
+			AllowLocalhost: getEnv("WEBHOOK_ALLOW_LOCALHOST", "false") == "true" &&
+				getEnv("ENVIRONMENT", "development") == "development",
+		},
+		Events: EventsConfig{
+			GraceSec:     getEnvAsInt("EVENTS_RELAY_GRACE_SECONDS", 60),
+			LeaseSec:     getEnvAsInt("EVENTS_RELAY_LEASE_SECONDS", 120),
+			RetryBaseSec: getEnvAsInt("EVENTS_RETRY_BASE_SECONDS", 10),
+			RetryMaxSec:  getEnvAsInt("EVENTS_RETRY_MAX_SECONDS", 3600),
+			IntervalSec:  getEnvAsInt("EVENTS_RELAY_INTERVAL_SECONDS", 5),
+		},
+		Stream: StreamConfig{
+			RetentionMinutes:      getEnvAsInt("STREAM_RETENTION_MINUTES", 1440),
+			HeartbeatSec:          getEnvAsInt("STREAM_HEARTBEAT_SECONDS", 15),
+			MaxConnectionsPerUser: getEnvAsInt("STREAM_MAX_CONNECTIONS_PER_USER", 5),
+			PruneIntervalSec:      getEnvAsInt("STREAM_PRUNE_INTERVAL_SECONDS", 300),
+		},
+		Log: LogConfig{
+			Level: getEnv("LOG_LEVEL", "info"),
+		},
+		Publisher: PublisherConfig{
+			Driver:         getEnv("EVENTS_PUBLISHER", "none"),
+			NATSURL:        getEnv("NATS_URL", "nats://localhost:4222"),
+			NATSSubject:    getEnv("NATS_SUBJECT", "payflow.events"),
+			NATSPartitions: getEnvAsInt("NATS_PARTITIONS", 16),
+			KafkaBrokers:   getEnv("KAFKA_BROKERS", "localhost:9092"),
+			KafkaTopic:     getEnv("KAFKA_TOPIC", "payflow.events"),
+			TimeoutSec:     getEnvAsInt("EVENTS_PUBLISH_TIMEOUT_SECONDS", 10),
+			IntervalSec:    getEnvAsInt("EVENTS_PUBLISH_INTERVAL_SECONDS", 2),
+		},
 	}, nil
 }
 
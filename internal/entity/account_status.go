@@ -0,0 +1,120 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountStatus string
+
+const (
+	// AccountStatusActive movimenta normalmente
+	AccountStatusActive AccountStatus = "active"
+	// AccountStatusBlocked não pode enviar dinheiro, mas continua recebendo
+	AccountStatusBlocked AccountStatus = "blocked"
+	// AccountStatusFrozen não pode enviar nem receber dinheiro
+	AccountStatusFrozen AccountStatus = "frozen"
+	// AccountStatusClosed foi encerrada com saldo zerado e não pode ser reaberta
+	AccountStatusClosed AccountStatus = "closed"
+)
+
+// AccountStatusSystemActor identifica as mudanças de status feitas pela própria plataforma
+const AccountStatusSystemActor = "system"
+
+// AccountStatusChange registra uma mudança de status da conta para auditoria
+type AccountStatusChange struct {
+	ID         string        `json:"id" db:"id"`
+	UserID     string        `json:"user_id" db:"user_id"`
+	FromStatus AccountStatus `json:"from_status" db:"from_status"`
+	ToStatus   AccountStatus `json:"to_status" db:"to_status"`
+	Reason     string        `json:"reason" db:"reason"`
+	ChangedBy  string        `json:"changed_by" db:"changed_by"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+}
+
+func (s AccountStatus) IsValid() bool {
+	switch s {
+	case AccountStatusActive, AccountStatusBlocked, AccountStatusFrozen, AccountStatusClosed:
+		return true
+	}
+	return false
+}
+
+// CanSend informa se o status permite enviar dinheiro
+func (s AccountStatus) CanSend() bool {
+	return s == AccountStatusActive
+}
+
+// CanReceive informa se o status permite receber dinheiro
+func (s AccountStatus) CanReceive() bool {
+	return s == AccountStatusActive || s == AccountStatusBlocked
+}
+
+func (s AccountStatus) Description() string {
+	switch s {
+	case AccountStatusActive:
+		return "Ativa"
+	case AccountStatusBlocked:
+		return "Bloqueada para envios"
+	case AccountStatusFrozen:
+		return "Congelada"
+	case AccountStatusClosed:
+		return "Encerrada"
+	default:
+		return "Status desconhecido"
+	}
+}
+
+// ChangeStatus altera o status da conta e retorna o registro de auditoria da mudança.
+// Contas encerradas não mudam mais de status e só podem ser encerradas com saldo zerado.
+func (u *User) ChangeStatus(to AccountStatus, reason, changedBy string, now time.Time) (*AccountStatusChange, error) {
+	if !to.IsValid() {
+		return nil, ErrInvalidAccountStatus
+	}
+	if u.IsClosed() {
+		return nil, ErrAccountClosed
+	}
+	if u.Status == to {
+		return nil, ErrAccountStatusUnchanged
+	}
+
+	reason = strings.TrimSpace(reason)
+	if len(reason) < 3 {
+		return nil, ErrAccountStatusReasonRequired
+	}
+
+	changedBy = strings.TrimSpace(changedBy)
+	if changedBy == "" {
+		changedBy = AccountStatusSystemActor
+	}
+
+	if to == AccountStatusClosed && (!u.Balance.IsZero() || !u.HeldBalance.IsZero()) {
+		return nil, ErrAccountHasBalance
+	}
+
+	change := &AccountStatusChange{
+		ID:         uuid.New().String(),
+		UserID:     u.ID,
+		FromStatus: u.Status,
+		ToStatus:   to,
+		Reason:     reason,
+		ChangedBy:  changedBy,
+		CreatedAt:  now,
+	}
+
+	u.Status = to
+	u.StatusReason = &reason
+	u.UpdatedAt = now
+
+	return change, nil
+}
+
+func (u *User) IsActive() bool {
+	return u.Status == AccountStatusActive
+}
+
+func (u *User) IsClosed() bool {
+	return u.Status == AccountStatusClosed
+}
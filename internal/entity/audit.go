@@ -0,0 +1,294 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditActorType string
+
+const (
+	AuditActorUser   AuditActorType = "user"
+	AuditActorAdmin  AuditActorType = "admin"
+	AuditActorSystem AuditActorType = "system"
+	// AuditActorAnonymous é quem chama rotas públicas sem se identificar, como o cadastro
+	AuditActorAnonymous AuditActorType = "anonymous"
+)
+
+// AuditRedacted substitui os valores sensíveis nos registros de auditoria
+const AuditRedacted = "[REDACTED]"
+
+// auditRedactedFields são os campos cujo valor nunca é gravado na trilha
+var auditRedactedFields = map[string]bool{
+	"password":  true,
+	"document":  true,
+	"secret":    true,
+	"token":     true,
+	"signature": true,
+	"api_key":   true,
+}
+
+// auditPersonalFields são os dados pessoais apagados na anonimização. Ficam no snapshot para que a
+// comparação detecte a mudança, mas são ocultados no registro: a trilha é imutável e não pode
+// guardar o que a exclusão da conta precisa apagar.
+var auditPersonalFields = map[string]bool{
+	"full_name": true,
+	"email":     true,
+	"key":       true,
+	"key_value": true,
+}
+
+// documentPattern reconhece CPF e CNPJ, com ou sem pontuação, em qualquer campo de texto
+var documentPattern = regexp.MustCompile(`^(\d{11}|\d{14}|\d{3}\.\d{3}\.\d{3}-\d{2}|\d{2}\.\d{3}\.\d{3}/\d{4}-\d{2})$`)
+
+// AuditMetadata identifica quem fez a requisição que originou a ação
+type AuditMetadata struct {
+	ActorType AuditActorType
+	ActorID   string
+	RequestID string
+	IP        string
+}
+
+// AuditChange é o valor de um campo antes e depois da ação
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEntry é um registro imutável da trilha de auditoria. Cada registro guarda o hash do
+// anterior, formando uma cadeia em que qualquer alteração ou remoção quebra os hashes seguintes.
+type AuditEntry struct {
+	ID           string                 `json:"id" db:"id"`
+	Sequence     int64                  `json:"sequence" db:"sequence"`
+	ActorType    AuditActorType         `json:"actor_type" db:"actor_type"`
+	ActorID      *string                `json:"actor_id,omitempty" db:"actor_id"`
+	Action       string                 `json:"action" db:"action"`
+	ResourceType string                 `json:"resource_type" db:"resource_type"`
+	ResourceID   string                 `json:"resource_id" db:"resource_id"`
+	Changes      map[string]AuditChange `json:"changes" db:"changes"`
+	RequestID    *string                `json:"request_id,omitempty" db:"request_id"`
+	IP           *string                `json:"ip,omitempty" db:"ip"`
+	CreatedAt    time.Time              `json:"created_at" db:"created_at"`
+	PrevHash     string                 `json:"prev_hash" db:"prev_hash"`
+	Hash         string                 `json:"hash" db:"hash"`
+}
+
+// AuditSnapshot converte o estado de um recurso em um mapa com os campos sensíveis já ocultados.
+// Deve ser chamado antes de alterar o recurso para capturar o estado anterior.
+func AuditSnapshot(resource interface{}) map[string]interface{} {
+	if resource == nil || (reflect.ValueOf(resource).Kind() == reflect.Ptr && reflect.ValueOf(resource).IsNil()) {
+		return nil
+	}
+	content, err := json.Marshal(resource)
+	if err != nil {
+		return map[string]interface{}{"error": "estado não serializável"}
+	}
+
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return map[string]interface{}{"value": AuditRedactValue("", string(content))}
+	}
+
+	for key, value := range snapshot {
+		snapshot[key] = AuditRedactValue(key, value)
+	}
+	return snapshot
+}
+
+// AuditRedactValue oculta segredos pelo nome do campo e números de documento pelo formato do valor
+func AuditRedactValue(field string, value interface{}) interface{} {
+	if auditRedactedFields[strings.ToLower(field)] && value != nil {
+		return AuditRedacted
+	}
+
+	switch v := value.(type) {
+	case string:
+		if documentPattern.MatchString(v) {
+			return AuditRedacted
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = AuditRedactValue(key, item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = AuditRedactValue("", item)
+		}
+	}
+	return value
+}
+
+// redactPersonalData oculta os dados pessoais das mudanças já calculadas
+func redactPersonalData(changes map[string]AuditChange) map[string]AuditChange {
+	for field, change := range changes {
+		changes[field] = AuditChange{
+			Before: redactPersonalValue(field, change.Before),
+			After:  redactPersonalValue(field, change.After),
+		}
+	}
+	return changes
+}
+
+func redactPersonalValue(field string, value interface{}) interface{} {
+	if auditPersonalFields[strings.ToLower(field)] && value != nil {
+		return AuditRedacted
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = redactPersonalValue(key, item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactPersonalValue("", item)
+		}
+	}
+	return value
+}
+
+// AuditDiff retorna apenas os campos que mudaram entre os dois estados; updated_at é ignorado
+func AuditDiff(before, after map[string]interface{}) map[string]AuditChange {
+	changes := map[string]AuditChange{}
+
+	for key, value := range after {
+		if key == "updated_at" {
+			continue
+		}
+		if previous, ok := before[key]; !ok || !reflect.DeepEqual(previous, value) {
+			changes[key] = AuditChange{Before: before[key], After: value}
+		}
+	}
+	for key, value := range before {
+		if _, ok := after[key]; !ok && key != "updated_at" {
+			changes[key] = AuditChange{Before: value, After: nil}
+		}
+	}
+
+	return changes
+}
+
+// NewAuditEntry registra a ação com a diferença entre os estados; before nulo indica criação
+func NewAuditEntry(meta AuditMetadata, action, resourceType, resourceID string, before, after interface{}, now time.Time) *AuditEntry {
+	if meta.ActorType == "" {
+		meta.ActorType = AuditActorSystem
+	}
+
+	entry := &AuditEntry{
+		ID:           uuid.New().String(),
+		ActorType:    meta.ActorType,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Changes:      redactPersonalData(AuditDiff(AuditSnapshot(before), AuditSnapshot(after))),
+		CreatedAt:    now.UTC().Truncate(time.Microsecond),
+	}
+	if meta.ActorID != "" {
+		entry.ActorID = &meta.ActorID
+	}
+	if meta.RequestID != "" {
+		entry.RequestID = &meta.RequestID
+	}
+	if meta.IP != "" {
+		entry.IP = &meta.IP
+	}
+
+	return entry
+}
+
+// Seal encadeia o registro ao anterior, definindo sequência e hash
+func (e *AuditEntry) Seal(sequence int64, prevHash string) {
+	e.Sequence = sequence
+	e.PrevHash = prevHash
+	e.Hash = e.ComputeHash()
+}
+
+// ComputeHash calcula o SHA-256 do hash anterior com o conteúdo canônico do registro
+func (e *AuditEntry) ComputeHash() string {
+	fields := []string{
+		e.PrevHash,
+		strconv.FormatInt(e.Sequence, 10),
+		e.ID,
+		string(e.ActorType),
+		stringValue(e.ActorID),
+		e.Action,
+		e.ResourceType,
+		e.ResourceID,
+		canonicalChanges(e.Changes),
+		stringValue(e.RequestID),
+		stringValue(e.IP),
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalChanges serializa as mudanças com as chaves ordenadas
+func canonicalChanges(changes map[string]AuditChange) string {
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		// encoding/json ordena as chaves dos mapas, então o resultado é determinístico
+		content, _ := json.Marshal(changes[key])
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.Write(content)
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
+// AuditChainVerification é o resultado da conferência da cadeia de hashes
+type AuditChainVerification struct {
+	Valid        bool   `json:"valid"`
+	Checked      int64  `json:"checked"`
+	LastSequence int64  `json:"last_sequence"`
+	LastHash     string `json:"last_hash"`
+	// BrokenAt é a sequência do primeiro registro adulterado, removido ou fora de ordem
+	BrokenAt *int64 `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Verify confere um trecho da cadeia a partir do estado já verificado; para no primeiro erro
+func (v *AuditChainVerification) Verify(entries []*AuditEntry) bool {
+	for _, entry := range entries {
+		reason := ""
+		switch {
+		case entry.Sequence != v.LastSequence+1:
+			reason = "sequência fora de ordem ou registro removido"
+		case entry.PrevHash != v.LastHash:
+			reason = "hash anterior não confere"
+		case entry.ComputeHash() != entry.Hash:
+			reason = "conteúdo do registro alterado"
+		}
+
+		if reason != "" {
+			sequence := entry.Sequence
+			v.Valid = false
+			v.BrokenAt = &sequence
+			v.Reason = reason
+			return false
+		}
+
+		v.Checked++
+		v.LastSequence = entry.Sequence
+		v.LastHash = entry.Hash
+	}
+
+	return true
+}
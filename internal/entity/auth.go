@@ -0,0 +1,143 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthTokenType é o esquema do cabeçalho Authorization que carrega o token de acesso
+const AuthTokenType = "Bearer"
+
+// AuthTokenSigner emite e confere os tokens de acesso dos usuários. O token é
+// <user_id>.<vencimento unix>.<HMAC-SHA256 dos dois>, então o ID do usuário não pode ser trocado
+// sem invalidar a assinatura.
+type AuthTokenSigner struct {
+	key []byte
+	ttl time.Duration
+}
+
+func NewAuthTokenSigner(key []byte, ttl time.Duration) *AuthTokenSigner {
+	return &AuthTokenSigner{key: key, ttl: ttl}
+}
+
+// Issue emite o token do usuário válido por ttl a partir de now
+func (s *AuthTokenSigner) Issue(userID string, now time.Time) *AuthTokenResponse {
+	expiresAt := now.Add(s.ttl).Truncate(time.Second)
+	content := userID + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+
+	return &AuthTokenResponse{
+		AccessToken: content + "." + s.sign(content),
+		TokenType:   AuthTokenType,
+		ExpiresAt:   expiresAt,
+	}
+}
+
+// Verify confere a assinatura em tempo constante e o vencimento e retorna o ID do usuário
+func (s *AuthTokenSigner) Verify(token string, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidAuthToken
+	}
+
+	content := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(s.sign(content)), []byte(parts[2])) {
+		return "", ErrInvalidAuthToken
+	}
+	if _, err := uuid.Parse(parts[0]); err != nil {
+		return "", ErrInvalidAuthToken
+	}
+
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", ErrInvalidAuthToken
+	}
+	if now.Unix() > expires {
+		return "", ErrAuthTokenExpired
+	}
+
+	return parts[0], nil
+}
+
+func (s *AuthTokenSigner) sign(content string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(content))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateAuthTokenRequest troca e-mail e senha por um token de acesso
+type CreateAuthTokenRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// AuthTokenResponse é o token de acesso enviado em Authorization: Bearer <access_token>
+type AuthTokenResponse struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// adminKey é a chave de acesso de um administrador identificado
+type adminKey struct {
+	adminID string
+	key     []byte
+}
+
+// AdminKeyring guarda uma chave por administrador, para que cada ação administrativa seja
+// atribuída a quem a fez e não a uma chave compartilhada
+type AdminKeyring struct {
+	keys []adminKey
+}
+
+// ParseAdminKeys lê as chaves no formato <admin_id>:<chave>, separadas por vírgula
+func ParseAdminKeys(encoded string) (*AdminKeyring, error) {
+	keyring := &AdminKeyring{}
+	seenIDs, seenKeys := map[string]bool{}, map[string]bool{}
+
+	for _, item := range strings.Split(encoded, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		adminID, key, ok := strings.Cut(item, ":")
+		adminID = strings.TrimSpace(adminID)
+		if !ok || adminID == "" || key == "" {
+			return nil, fmt.Errorf("chave administrativa deve estar no formato <admin_id>:<chave>")
+		}
+		if seenIDs[adminID] {
+			return nil, fmt.Errorf("administrador %s tem mais de uma chave", adminID)
+		}
+		if seenKeys[key] {
+			return nil, fmt.Errorf("chave administrativa repetida em %s", adminID)
+		}
+		seenIDs[adminID], seenKeys[key] = true, true
+		keyring.keys = append(keyring.keys, adminKey{adminID: adminID, key: []byte(key)})
+	}
+
+	return keyring, nil
+}
+
+// Identify retorna o administrador dono da chave. Todas as chaves são comparadas em tempo
+// constante, para que o tempo de resposta não revele qual delas chegou mais perto.
+func (k *AdminKeyring) Identify(key string) (string, bool) {
+	adminID := ""
+	for _, candidate := range k.keys {
+		if subtle.ConstantTimeCompare([]byte(key), candidate.key) == 1 {
+			adminID = candidate.adminID
+		}
+	}
+	return adminID, adminID != ""
+}
+
+// Empty indica que nenhum administrador foi configurado
+func (k *AdminKeyring) Empty() bool {
+	return len(k.keys) == 0
+}
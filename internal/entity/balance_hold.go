@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusReleased HoldStatus = "released"
+	HoldStatusExpired  HoldStatus = "expired"
+)
+
+// BalanceHold representa um valor reservado no saldo do pagador entre a autorização e a conclusão da transferência
+type BalanceHold struct {
+	ID            string          `json:"id" db:"id"`
+	UserID        string          `json:"user_id" db:"user_id"`
+	TransactionID string          `json:"transaction_id" db:"transaction_id"`
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	Status        HoldStatus      `json:"status" db:"status"`
+	ExpiresAt     time.Time       `json:"expires_at" db:"expires_at"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+	SettledAt     *time.Time      `json:"settled_at,omitempty" db:"settled_at"`
+}
+
+func NewBalanceHold(userID, transactionID string, amount decimal.Decimal, ttl time.Duration) (*BalanceHold, error) {
+	now := time.Now()
+	hold := &BalanceHold{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		TransactionID: transactionID,
+		Amount:        amount,
+		Status:        HoldStatusActive,
+		ExpiresAt:     now.Add(ttl),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := hold.Validate(); err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+func (h *BalanceHold) Validate() error {
+	if h.UserID == "" {
+		return errors.New("usuário da reserva é obrigatório")
+	}
+
+	if h.TransactionID == "" {
+		return errors.New("transação da reserva é obrigatória")
+	}
+
+	if h.Amount.LessThanOrEqual(decimal.Zero) {
+		return errors.New("valor da reserva deve ser maior que zero")
+	}
+
+	if !h.ExpiresAt.After(h.CreatedAt) {
+		return errors.New("validade da reserva deve ser posterior à criação")
+	}
+
+	return nil
+}
+
+// Capture marca a reserva como efetivada na conclusão da transferência
+func (h *BalanceHold) Capture() error {
+	return h.settle(HoldStatusCaptured)
+}
+
+// Release libera a reserva quando a transferência falha
+func (h *BalanceHold) Release() error {
+	return h.settle(HoldStatusReleased)
+}
+
+// Expire libera a reserva que ultrapassou o prazo de validade
+func (h *BalanceHold) Expire() error {
+	return h.settle(HoldStatusExpired)
+}
+
+// ExtendUntil prorroga a validade da reserva, por exemplo enquanto a transferência aguarda análise
+func (h *BalanceHold) ExtendUntil(expiresAt time.Time) error {
+	if !h.IsActive() {
+		return ErrHoldNotActive
+	}
+	if expiresAt.After(h.ExpiresAt) {
+		h.ExpiresAt = expiresAt
+		h.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (h *BalanceHold) settle(status HoldStatus) error {
+	if !h.IsActive() {
+		return ErrHoldNotActive
+	}
+	now := time.Now()
+	h.Status = status
+	h.SettledAt = &now
+	h.UpdatedAt = now
+	return nil
+}
+
+func (h *BalanceHold) IsActive() bool {
+	return h.Status == HoldStatusActive
+}
+
+func (h *BalanceHold) IsExpired(now time.Time) bool {
+	return h.IsActive() && !now.Before(h.ExpiresAt)
+}
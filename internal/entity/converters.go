@@ -1,5 +1,10 @@
 package entity
 
+import (
+	"strings"
+	"time"
+)
+
 func (u *User) ToCreateUserResponse() *CreateUserResponse {
 	return &CreateUserResponse{
 		ID:       u.ID,
@@ -13,17 +18,39 @@ func (u *User) ToCreateUserResponse() *CreateUserResponse {
 
 func (u *User) ToGetUserResponse() *GetUserResponse {
 	return &GetUserResponse{
-		ID:        u.ID,
-		FullName:  u.FullName,
-		Document:  u.maskDocument(),
-		Email:     u.Email,
-		UserType:  u.UserType,
-		Balance:   u.Balance.StringFixed(2),
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:                u.ID,
+		FullName:          u.FullName,
+		Document:          u.maskDocument(),
+		Email:             u.Email,
+		UserType:          u.UserType,
+		Balance:           u.Balance.StringFixed(2),
+		Status:            u.Status,
+		StatusDescription: u.Status.Description(),
+		StatusReason:      u.StatusReason,
+		CreatedAt:         u.CreatedAt,
+		UpdatedAt:         u.UpdatedAt,
 	}
 }
 
+// ToDataExportResponse converte a exportação; downloadURL vazio omite o link
+func (e *DataExport) ToDataExportResponse(downloadURL string) *DataExportResponse {
+	response := &DataExportResponse{
+		ID:                e.ID,
+		Status:            e.Status,
+		StatusDescription: e.GetStatusDescription(),
+		SizeBytes:         e.SizeBytes,
+		FailureReason:     e.FailureReason,
+		CreatedAt:         e.CreatedAt,
+		ReadyAt:           e.ReadyAt,
+		ExpiresAt:         e.ExpiresAt,
+		DownloadedAt:      e.DownloadedAt,
+	}
+	if downloadURL != "" {
+		response.DownloadURL = &downloadURL
+	}
+	return response
+}
+
 func (u *User) ToUserSummary() *UserSummary {
 	return &UserSummary{
 		ID:       u.ID,
@@ -37,10 +64,16 @@ func (u *User) ToBalanceResponse() *BalanceResponse {
 	return &BalanceResponse{
 		UserID:    u.ID,
 		Balance:   u.Balance.StringFixed(2),
+		Available: u.AvailableBalance().StringFixed(2),
+		Held:      u.HeldBalance.StringFixed(2),
 		UpdatedAt: u.UpdatedAt,
 	}
 }
 
+// maskDocument e maskName são as duas máscaras de dados do titular exibidos a terceiros. O documento
+// tem tamanho fixo e mantém dígitos em posições fixas; o nome tem tamanho variável, então mantém o
+// primeiro nome e as iniciais dos demais (Maria S***), como a consulta de chaves do Pix, para que o
+// pagador reconheça o recebedor sem ver o nome completo.
 func (u *User) maskDocument() string {
 	if len(u.Document) == 11 {
 		return u.Document[:3] + ".***.***-" + u.Document[9:]
@@ -51,15 +84,34 @@ func (u *User) maskDocument() string {
 	return u.Document
 }
 
+func (u *User) maskName() string {
+	parts := strings.Fields(u.FullName)
+	if len(parts) == 0 {
+		return ""
+	}
+
+	masked := []string{parts[0]}
+	for _, part := range parts[1:] {
+		// Inicial pela runa, não pelo byte, para não cortar letras acentuadas ao meio
+		masked = append(masked, string([]rune(part)[0])+"***")
+	}
+
+	return strings.Join(masked, " ")
+}
+
 func (t *Transaction) ToCreateTransactionResponse() *CreateTransactionResponse {
 	return &CreateTransactionResponse{
-		ID:         t.ID,
-		PayerID:    t.PayerID,
-		PayeeID:    t.PayeeID,
-		Amount:     t.Amount.StringFixed(2),
-		Status:     t.Status,
-		StatusDesc: t.GetStatusDescription(),
-		CreatedAt:  t.CreatedAt,
+		ID:           t.ID,
+		PayerID:      t.PayerID,
+		PayeeID:      t.PayeeID,
+		Amount:       t.Amount.StringFixed(2),
+		GrossAmount:  t.Amount.StringFixed(2),
+		FeeAmount:    t.FeeAmount.StringFixed(2),
+		NetAmount:    t.NetAmount().StringFixed(2),
+		Status:       t.Status,
+		StatusDesc:   t.GetStatusDescription(),
+		ScheduledFor: t.ScheduledFor,
+		CreatedAt:    t.CreatedAt,
 	}
 }
 
@@ -69,11 +121,14 @@ func (t *Transaction) ToGetTransactionResponse() *GetTransactionResponse {
 		PayerID:          t.PayerID,
 		PayeeID:          t.PayeeID,
 		Amount:           t.Amount.StringFixed(2),
+		FeeAmount:        t.FeeAmount.StringFixed(2),
+		NetAmount:        t.NetAmount().StringFixed(2),
 		Status:           t.Status,
 		StatusDesc:       t.GetStatusDescription(),
 		AuthorizationID:  t.AuthorizationID,
 		NotificationSent: t.NotificationSent,
 		FailureReason:    t.FailureReason,
+		ScheduledFor:     t.ScheduledFor,
 		CreatedAt:        t.CreatedAt,
 		UpdatedAt:        t.UpdatedAt,
 		CompletedAt:      t.CompletedAt,
@@ -89,6 +144,333 @@ func (t *Transaction) ToGetTransactionResponse() *GetTransactionResponse {
 	return response
 }
 
+func (l TransactionLimits) ToLimitValuesResponse() LimitValuesResponse {
+	return LimitValuesResponse{
+		PerTransaction: l.PerTransaction.StringFixed(2),
+		Daily:          l.Daily.StringFixed(2),
+		Monthly:        l.Monthly.StringFixed(2),
+		Nightly:        l.Nightly.StringFixed(2),
+	}
+}
+
+func (o *UserLimitOverride) ToLimitValuesResponse() *LimitValuesResponse {
+	if o == nil {
+		return nil
+	}
+
+	response := &LimitValuesResponse{}
+	if o.PerTransaction != nil {
+		response.PerTransaction = o.PerTransaction.StringFixed(2)
+	}
+	if o.Daily != nil {
+		response.Daily = o.Daily.StringFixed(2)
+	}
+	if o.Monthly != nil {
+		response.Monthly = o.Monthly.StringFixed(2)
+	}
+	if o.Nightly != nil {
+		response.Nightly = o.Nightly.StringFixed(2)
+	}
+	return response
+}
+
+func (u LimitUsage) ToLimitValuesResponse() LimitValuesResponse {
+	return LimitValuesResponse{
+		Daily:   u.Daily.StringFixed(2),
+		Monthly: u.Monthly.StringFixed(2),
+		Nightly: u.Nightly.StringFixed(2),
+	}
+}
+
+func (l *TransactionLimits) ApplyUpdateLimitsRequest(req *UpdateLimitsRequest) error {
+	if req.PerTransaction != nil {
+		l.PerTransaction = *req.PerTransaction
+	}
+	if req.Daily != nil {
+		l.Daily = *req.Daily
+	}
+	if req.Monthly != nil {
+		l.Monthly = *req.Monthly
+	}
+	if req.Nightly != nil {
+		l.Nightly = *req.Nightly
+	}
+
+	return l.Validate()
+}
+
+func FromUpdateLimitsRequest(userID string, req *UpdateLimitsRequest) *UserLimitOverride {
+	return &UserLimitOverride{
+		UserID:         userID,
+		PerTransaction: req.PerTransaction,
+		Daily:          req.Daily,
+		Monthly:        req.Monthly,
+		Nightly:        req.Nightly,
+		UpdatedAt:      time.Now(),
+	}
+}
+
+func (a *PlatformAccount) ToPlatformAccountResponse() *PlatformAccountResponse {
+	return &PlatformAccountResponse{
+		Code:      a.Code,
+		Balance:   a.Balance.StringFixed(2),
+		UpdatedAt: a.UpdatedAt,
+	}
+}
+
+func FromCreatePricingPlanRequest(req *CreatePricingPlanRequest) (*PricingPlan, error) {
+	return NewPricingPlan(
+		req.Name,
+		req.FeeType,
+		req.FixedAmount,
+		req.Percentage,
+		req.Tiers,
+		req.MinFee,
+		req.MaxFee,
+	)
+}
+
+func (m *RecurringMandate) ToRecurringPaymentResponse() *RecurringPaymentResponse {
+	return &RecurringPaymentResponse{
+		ID:             m.ID,
+		PayerID:        m.PayerID,
+		PayeeID:        m.PayeeID,
+		Amount:         m.Amount.StringFixed(2),
+		Description:    m.Description,
+		Frequency:      m.Frequency,
+		CronExpression: m.CronExpression,
+		StartDate:      m.StartDate,
+		EndDate:        m.EndDate,
+		MaxOccurrences: m.MaxOccurrences,
+		Occurrences:    m.Occurrences,
+		NextRunAt:      m.NextRunAt,
+		LastRunAt:      m.LastRunAt,
+		Status:         m.Status,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+func FromCreateRecurringPaymentRequest(req *CreateRecurringPaymentRequest, payerID string) (*RecurringMandate, error) {
+	return NewRecurringMandate(
+		payerID,
+		req.PayeeID,
+		req.Amount,
+		req.Description,
+		req.Frequency,
+		req.CronExpression,
+		req.StartDate,
+		req.EndDate,
+		req.MaxOccurrences,
+	)
+}
+
+func (b *TransferBatch) ToTransferBatchResponse() *TransferBatchResponse {
+	response := &TransferBatchResponse{
+		ID:             b.ID,
+		PayerID:        b.PayerID,
+		Mode:           b.Mode,
+		Status:         b.Status,
+		TotalItems:     b.TotalItems,
+		TotalAmount:    b.TotalAmount.StringFixed(2),
+		SucceededCount: b.SucceededCount,
+		FailedCount:    b.FailedCount,
+		CreatedAt:      b.CreatedAt,
+		UpdatedAt:      b.UpdatedAt,
+		CompletedAt:    b.CompletedAt,
+	}
+
+	for _, item := range b.Items {
+		response.Items = append(response.Items, BatchItemResponse{
+			Position:      item.Position,
+			PayeeID:       item.PayeeID,
+			PayeeKey:      item.PayeeKey,
+			Amount:        item.Amount.StringFixed(2),
+			Status:        item.Status,
+			TransactionID: item.TransactionID,
+			FailureReason: item.FailureReason,
+		})
+	}
+
+	return response
+}
+
+func (s *SplitPayment) ToSplitPaymentResponse() *SplitPaymentResponse {
+	response := &SplitPaymentResponse{
+		ID:                s.ID,
+		PayerID:           s.PayerID,
+		Amount:            s.Amount.StringFixed(2),
+		Description:       s.Description,
+		Status:            s.Status,
+		StatusDescription: s.GetStatusDescription(),
+		RefundedAmount:    s.RefundedAmount.StringFixed(2),
+		FailureReason:     s.FailureReason,
+		CreatedAt:         s.CreatedAt,
+		UpdatedAt:         s.UpdatedAt,
+		CompletedAt:       s.CompletedAt,
+	}
+
+	for _, leg := range s.Legs {
+		response.Legs = append(response.Legs, SplitLegResponse{
+			ID:             leg.ID,
+			Position:       leg.Position,
+			PayeeID:        leg.PayeeID,
+			PayeeKey:       leg.PayeeKey,
+			Platform:       leg.IsPlatform(),
+			SplitType:      leg.SplitType,
+			Value:          leg.Value.String(),
+			Amount:         leg.Amount.StringFixed(2),
+			RefundedAmount: leg.RefundedAmount.StringFixed(2),
+			TransactionID:  leg.TransactionID,
+		})
+	}
+
+	for _, refund := range s.Refunds {
+		refundResponse := SplitRefundResponse{
+			ID:        refund.ID,
+			Amount:    refund.Amount.StringFixed(2),
+			Reason:    refund.Reason,
+			CreatedAt: refund.CreatedAt,
+		}
+		for _, leg := range refund.Legs {
+			refundResponse.Legs = append(refundResponse.Legs, SplitRefundLegResponse{
+				LegID:    leg.LegID,
+				Position: leg.Position,
+				Amount:   leg.Amount.StringFixed(2),
+				Fee:      leg.Fee.StringFixed(2),
+			})
+		}
+		response.Refunds = append(response.Refunds, refundResponse)
+	}
+
+	return response
+}
+
+func (d *Dispute) ToDisputeResponse(now time.Time) *DisputeResponse {
+	return &DisputeResponse{
+		ID:                d.ID,
+		TransactionID:     d.TransactionID,
+		PayerID:           d.PayerID,
+		MerchantID:        d.MerchantID,
+		Reason:            d.Reason,
+		Status:            d.Status,
+		StatusDescription: d.GetStatusDescription(),
+		ResponseDueAt:     d.ResponseDueAt,
+		ResolutionDueAt:   d.ResolutionDueAt,
+		Overdue:           d.IsOverdue(now),
+		ResolutionNote:    d.ResolutionNote,
+		Evidence:          d.Evidence,
+		History:           d.History,
+		CreatedAt:         d.CreatedAt,
+		UpdatedAt:         d.UpdatedAt,
+		ResolvedAt:        d.ResolvedAt,
+		EscalatedAt:       d.EscalatedAt,
+	}
+}
+
+func (e *WebhookEndpoint) ToWebhookEndpointResponse() *WebhookEndpointResponse {
+	return &WebhookEndpointResponse{
+		ID:          e.ID,
+		URL:         e.URL,
+		Description: e.Description,
+		EventTypes:  e.EventTypes,
+		Active:      e.Active,
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+	}
+}
+
+func (d *WebhookDelivery) ToWebhookDeliveryResponse() *WebhookDeliveryResponse {
+	return &WebhookDeliveryResponse{
+		ID:                 d.ID,
+		EndpointID:         d.EndpointID,
+		EventID:            d.EventID,
+		EventType:          d.EventType,
+		Status:             d.Status,
+		StatusDescription:  d.GetStatusDescription(),
+		Attempts:           d.Attempts,
+		RedeliveryOf:       d.RedeliveryOf,
+		NextAttemptAt:      d.NextAttemptAt,
+		LastResponseStatus: d.LastResponseStatus,
+		LastError:          d.LastError,
+		AttemptLog:         d.AttemptLog,
+		CreatedAt:          d.CreatedAt,
+		DeliveredAt:        d.DeliveredAt,
+	}
+}
+
+func (r *RiskReview) ToRiskReviewResponse() *RiskReviewResponse {
+	return &RiskReviewResponse{
+		ID:                r.ID,
+		TransactionID:     r.TransactionID,
+		PayerID:           r.PayerID,
+		PayeeID:           r.PayeeID,
+		Amount:            r.Amount,
+		Decision:          r.Decision,
+		Reasons:           r.Reasons,
+		Status:            r.Status,
+		StatusDescription: r.GetStatusDescription(),
+		DueAt:             r.DueAt,
+		ClaimedBy:         r.ClaimedBy,
+		ClaimedAt:         r.ClaimedAt,
+		DecidedBy:         r.DecidedBy,
+		DecidedAt:         r.DecidedAt,
+		DecisionNote:      r.DecisionNote,
+		Events:            r.Events,
+		CreatedAt:         r.CreatedAt,
+		UpdatedAt:         r.UpdatedAt,
+	}
+}
+
+func (k *PixKey) ToPixKeyResponse() *PixKeyResponse {
+	return &PixKeyResponse{
+		ID:        k.ID,
+		KeyType:   k.KeyType,
+		Key:       k.KeyValue,
+		CreatedAt: k.CreatedAt,
+	}
+}
+
+// ToPixKeyLookupResponse expõe apenas dados mascarados do titular da chave
+func (k *PixKey) ToPixKeyLookupResponse(owner *User) *PixKeyLookupResponse {
+	return &PixKeyLookupResponse{
+		KeyType:    k.KeyType,
+		Key:        k.KeyValue,
+		HolderName: owner.maskName(),
+		Document:   owner.maskDocument(),
+		UserType:   owner.UserType,
+	}
+}
+
+func (p *PaymentRequest) ToPaymentRequestResponse() *PaymentRequestResponse {
+	return &PaymentRequestResponse{
+		ID:                p.ID,
+		TxID:              p.TxID,
+		MerchantID:        p.MerchantID,
+		PayerID:           p.PayerID,
+		Amount:            p.Amount.StringFixed(2),
+		Description:       p.Description,
+		Status:            p.Status,
+		StatusDescription: p.GetStatusDescription(),
+		ExpiresAt:         p.ExpiresAt,
+		TransactionID:     p.TransactionID,
+		PaidBy:            p.PaidBy,
+		PaidAt:            p.PaidAt,
+		CreatedAt:         p.CreatedAt,
+		UpdatedAt:         p.UpdatedAt,
+	}
+}
+
+func FromCreatePaymentRequestRequest(req *CreatePaymentRequestRequest, merchantID string) (*PaymentRequest, error) {
+	return NewPaymentRequest(
+		merchantID,
+		req.PayerID,
+		req.Amount,
+		req.Description,
+		req.ExpiresAt,
+	)
+}
+
 func FromCreateUserRequest(req *CreateUserRequest) (*User, error) {
 	return NewUser(
 		req.FullName,
@@ -100,11 +482,22 @@ func FromCreateUserRequest(req *CreateUserRequest) (*User, error) {
 }
 
 func FromCreateTransactionRequest(req *CreateTransactionRequest, payerID string) (*Transaction, error) {
-	return NewTransaction(
+	transaction, err := NewTransaction(
 		payerID,
 		req.PayeeID,
 		req.Amount,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ScheduledFor != nil {
+		if err := transaction.Schedule(*req.ScheduledFor); err != nil {
+			return nil, err
+		}
+	}
+
+	return transaction, nil
 }
 
 func (u *User) ApplyUpdateUserRequest(req *UpdateUserRequest) error {
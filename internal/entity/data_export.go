@@ -0,0 +1,159 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DataExportStatus string
+
+const (
+	// DataExportStatusPending aguarda o worker
+	DataExportStatusPending DataExportStatus = "pending"
+	// DataExportStatusProcessing está sendo gerada
+	DataExportStatusProcessing DataExportStatus = "processing"
+	// DataExportStatusReady pode ser baixada uma vez pelo link assinado
+	DataExportStatusReady DataExportStatus = "ready"
+	// DataExportStatusDownloaded já foi baixada e o arquivo foi descartado
+	DataExportStatusDownloaded DataExportStatus = "downloaded"
+	// DataExportStatusExpired não foi baixada a tempo e o arquivo foi descartado
+	DataExportStatusExpired DataExportStatus = "expired"
+	DataExportStatusFailed  DataExportStatus = "failed"
+)
+
+// DataExport é o pedido do titular por uma cópia de todos os seus dados (LGPD, art. 18, II e V)
+type DataExport struct {
+	ID            string           `json:"id" db:"id"`
+	UserID        string           `json:"user_id" db:"user_id"`
+	Status        DataExportStatus `json:"status" db:"status"`
+	Archive       []byte           `json:"-" db:"archive"`
+	SizeBytes     int              `json:"size_bytes" db:"size_bytes"`
+	FailureReason *string          `json:"failure_reason,omitempty" db:"failure_reason"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at" db:"updated_at"`
+	ReadyAt       *time.Time       `json:"ready_at,omitempty" db:"ready_at"`
+	ExpiresAt     *time.Time       `json:"expires_at,omitempty" db:"expires_at"`
+	DownloadedAt  *time.Time       `json:"downloaded_at,omitempty" db:"downloaded_at"`
+}
+
+func NewDataExport(userID string, now time.Time) *DataExport {
+	return &DataExport{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Status:    DataExportStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// MarkReady guarda o arquivo gerado, disponível para download até now+ttl
+func (e *DataExport) MarkReady(archive []byte, ttl time.Duration, now time.Time) error {
+	if e.Status != DataExportStatusProcessing {
+		return ErrDataExportNotProcessing
+	}
+
+	expiresAt := now.Add(ttl)
+	e.Archive = archive
+	e.SizeBytes = len(archive)
+	e.Status = DataExportStatusReady
+	e.ReadyAt = &now
+	e.ExpiresAt = &expiresAt
+	e.UpdatedAt = now
+	return nil
+}
+
+func (e *DataExport) Fail(reason string, now time.Time) {
+	e.Status = DataExportStatusFailed
+	e.FailureReason = &reason
+	e.Archive = nil
+	e.UpdatedAt = now
+}
+
+// Download entrega o arquivo uma única vez e o descarta em seguida
+func (e *DataExport) Download(now time.Time) ([]byte, error) {
+	switch e.Status {
+	case DataExportStatusReady:
+	case DataExportStatusDownloaded:
+		return nil, ErrDataExportAlreadyDownloaded
+	case DataExportStatusExpired:
+		return nil, ErrDataExportExpired
+	default:
+		return nil, ErrDataExportNotReady
+	}
+
+	if now.After(*e.ExpiresAt) {
+		e.Status = DataExportStatusExpired
+		e.Archive = nil
+		e.UpdatedAt = now
+		return nil, ErrDataExportExpired
+	}
+
+	archive := e.Archive
+	e.Archive = nil
+	e.Status = DataExportStatusDownloaded
+	e.DownloadedAt = &now
+	e.UpdatedAt = now
+	return archive, nil
+}
+
+// IsInProgress informa se o pedido ainda está na fila ou sendo gerado
+func (e *DataExport) IsInProgress() bool {
+	return e.Status == DataExportStatusPending || e.Status == DataExportStatusProcessing
+}
+
+// FileName é o nome sugerido para o arquivo baixado
+func (e *DataExport) FileName() string {
+	return "payflow-dados-" + e.UserID + ".zip"
+}
+
+func (e *DataExport) GetStatusDescription() string {
+	switch e.Status {
+	case DataExportStatusPending:
+		return "Na fila"
+	case DataExportStatusProcessing:
+		return "Gerando arquivo"
+	case DataExportStatusReady:
+		return "Pronta para download"
+	case DataExportStatusDownloaded:
+		return "Baixada"
+	case DataExportStatusExpired:
+		return "Link expirado"
+	case DataExportStatusFailed:
+		return "Falhou"
+	default:
+		return "Status desconhecido"
+	}
+}
+
+// DataExportSigner assina os links de download com HMAC-SHA256 sobre o ID da exportação e o vencimento
+type DataExportSigner struct {
+	key []byte
+}
+
+func NewDataExportSigner(key []byte) *DataExportSigner {
+	return &DataExportSigner{key: key}
+}
+
+// Sign retorna a assinatura hexadecimal do link que vale até expiresAt
+func (s *DataExportSigner) Sign(exportID string, expiresAt time.Time) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(exportID + "." + strconv.FormatInt(expiresAt.Unix(), 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify confere a assinatura em tempo constante e se o link ainda não venceu
+func (s *DataExportSigner) Verify(exportID string, expires int64, signature string, now time.Time) error {
+	expected := s.Sign(exportID, time.Unix(expires, 0))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidDownloadSignature
+	}
+	if now.Unix() > expires {
+		return ErrDataExportExpired
+	}
+	return nil
+}
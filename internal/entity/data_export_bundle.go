@@ -0,0 +1,213 @@
+package entity
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"time"
+)
+
+// DataExportFormatVersion identifica o layout do arquivo exportado
+const DataExportFormatVersion = "1"
+
+const (
+	DataExportDirectionSent     = "sent"
+	DataExportDirectionReceived = "received"
+)
+
+// DataExportBundle reúne todos os dados mantidos sobre o titular
+type DataExportBundle struct {
+	FormatVersion        string                   `json:"format_version"`
+	GeneratedAt          time.Time                `json:"generated_at"`
+	Profile              *User                    `json:"profile"`
+	PixKeys              []*PixKey                `json:"pix_keys"`
+	Sessions             []DataExportSession      `json:"sessions"`
+	Transactions         []*DataExportTransaction `json:"transactions"`
+	Disputes             []*Dispute               `json:"disputes"`
+	Consents             []DataExportConsent      `json:"consents"`
+	AccountStatusHistory []*AccountStatusChange   `json:"account_status_history"`
+	Notes                []string                 `json:"notes"`
+}
+
+// DataExportSession e DataExportConsent mantêm as seções no formato mesmo sem registros:
+// a API identifica o usuário por tokens de acesso sem estado e não coleta consentimentos
+type DataExportSession struct{}
+type DataExportConsent struct{}
+
+// DataExportTransaction é uma transação do titular sem os dados cadastrais da contraparte
+type DataExportTransaction struct {
+	ID            string            `json:"id"`
+	Direction     string            `json:"direction"`
+	PayerID       string            `json:"payer_id"`
+	PayeeID       string            `json:"payee_id"`
+	Amount        string            `json:"amount"`
+	FeeAmount     string            `json:"fee_amount"`
+	Status        TransactionStatus `json:"status"`
+	FailureReason *string           `json:"failure_reason,omitempty"`
+	ScheduledFor  *time.Time        `json:"scheduled_for,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	CompletedAt   *time.Time        `json:"completed_at,omitempty"`
+}
+
+// NewDataExportBundle monta o conteúdo da exportação a partir dos registros do titular
+func NewDataExportBundle(user *User, pixKeys []*PixKey, transactions []*Transaction, disputes []*Dispute, history []*AccountStatusChange, now time.Time) *DataExportBundle {
+	bundle := &DataExportBundle{
+		FormatVersion:        DataExportFormatVersion,
+		GeneratedAt:          now,
+		Profile:              user,
+		PixKeys:              pixKeys,
+		Sessions:             []DataExportSession{},
+		Transactions:         make([]*DataExportTransaction, 0, len(transactions)),
+		Disputes:             disputes,
+		Consents:             []DataExportConsent{},
+		AccountStatusHistory: history,
+		Notes: []string{
+			"sessions: a API não mantém sessões; cada requisição identifica o usuário por um token de acesso assinado, que não é guardado",
+			"consents: nenhum consentimento é coletado; os dados são tratados para execução do contrato e cumprimento de obrigação legal",
+		},
+	}
+
+	if bundle.PixKeys == nil {
+		bundle.PixKeys = []*PixKey{}
+	}
+	if bundle.Disputes == nil {
+		bundle.Disputes = []*Dispute{}
+	}
+	if bundle.AccountStatusHistory == nil {
+		bundle.AccountStatusHistory = []*AccountStatusChange{}
+	}
+
+	for _, transaction := range transactions {
+		direction := DataExportDirectionReceived
+		if transaction.PayerID == user.ID {
+			direction = DataExportDirectionSent
+		}
+
+		bundle.Transactions = append(bundle.Transactions, &DataExportTransaction{
+			ID:            transaction.ID,
+			Direction:     direction,
+			PayerID:       transaction.PayerID,
+			PayeeID:       transaction.PayeeID,
+			Amount:        transaction.Amount.StringFixed(2),
+			FeeAmount:     transaction.FeeAmount.StringFixed(2),
+			Status:        transaction.Status,
+			FailureReason: transaction.FailureReason,
+			ScheduledFor:  transaction.ScheduledFor,
+			CreatedAt:     transaction.CreatedAt,
+			CompletedAt:   transaction.CompletedAt,
+		})
+	}
+
+	return bundle
+}
+
+// Archive gera o zip com export.json e um CSV por seção
+func (b *DataExportBundle) Archive() ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	file, err := archive.Create("export.json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(content); err != nil {
+		return nil, err
+	}
+
+	for _, table := range b.csvTables() {
+		if err := writeCSV(archive, table.name, table.rows); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type csvTable struct {
+	name string
+	rows [][]string
+}
+
+func (b *DataExportBundle) csvTables() []csvTable {
+	user := b.Profile
+	profile := [][]string{
+		{"id", "full_name", "document", "email", "user_type", "balance", "held_balance", "status", "created_at", "updated_at"},
+		{user.ID, user.FullName, user.Document, user.Email, string(user.UserType), user.Balance.StringFixed(2), user.HeldBalance.StringFixed(2), string(user.Status), formatCSVTime(&user.CreatedAt), formatCSVTime(&user.UpdatedAt)},
+	}
+
+	pixKeys := [][]string{{"id", "key_type", "key", "created_at"}}
+	for _, key := range b.PixKeys {
+		pixKeys = append(pixKeys, []string{key.ID, string(key.KeyType), key.KeyValue, formatCSVTime(&key.CreatedAt)})
+	}
+
+	transactions := [][]string{{"id", "direction", "payer_id", "payee_id", "amount", "fee_amount", "status", "failure_reason", "scheduled_for", "created_at", "completed_at"}}
+	for _, t := range b.Transactions {
+		transactions = append(transactions, []string{
+			t.ID, t.Direction, t.PayerID, t.PayeeID, t.Amount, t.FeeAmount, string(t.Status),
+			stringValue(t.FailureReason), formatCSVTime(t.ScheduledFor), formatCSVTime(&t.CreatedAt), formatCSVTime(t.CompletedAt),
+		})
+	}
+
+	disputes := [][]string{{"id", "transaction_id", "role", "reason", "status", "resolution_note", "created_at", "resolved_at"}}
+	for _, d := range b.Disputes {
+		role := string(DisputePartyPayer)
+		if d.MerchantID == user.ID {
+			role = string(DisputePartyMerchant)
+		}
+		disputes = append(disputes, []string{
+			d.ID, d.TransactionID, role, string(d.Reason), string(d.Status),
+			stringValue(d.ResolutionNote), formatCSVTime(&d.CreatedAt), formatCSVTime(d.ResolvedAt),
+		})
+	}
+
+	history := [][]string{{"id", "from_status", "to_status", "reason", "changed_by", "created_at"}}
+	for _, change := range b.AccountStatusHistory {
+		history = append(history, []string{change.ID, string(change.FromStatus), string(change.ToStatus), change.Reason, change.ChangedBy, formatCSVTime(&change.CreatedAt)})
+	}
+
+	return []csvTable{
+		{"profile.csv", profile},
+		{"pix_keys.csv", pixKeys},
+		{"sessions.csv", [][]string{{"id", "created_at", "expires_at"}}},
+		{"transactions.csv", transactions},
+		{"disputes.csv", disputes},
+		{"consents.csv", [][]string{{"id", "purpose", "granted_at", "revoked_at"}}},
+		{"account_status_history.csv", history},
+	}
+}
+
+func writeCSV(archive *zip.Writer, name string, rows [][]string) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
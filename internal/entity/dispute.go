@@ -0,0 +1,315 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DisputeStatus string
+
+const (
+	// DisputeStatusOpen aguarda a resposta do lojista
+	DisputeStatusOpen DisputeStatus = "open"
+	// DisputeStatusUnderReview aguarda a decisão de um administrador
+	DisputeStatusUnderReview DisputeStatus = "under_review"
+	// DisputeStatusWonByPayer foi decidida a favor do pagador e a transação revertida
+	DisputeStatusWonByPayer DisputeStatus = "won_by_payer"
+	// DisputeStatusWonByMerchant foi decidida a favor do lojista
+	DisputeStatusWonByMerchant DisputeStatus = "won_by_merchant"
+)
+
+type DisputeReason string
+
+const (
+	DisputeReasonUnauthorized    DisputeReason = "unauthorized"
+	DisputeReasonNotReceived     DisputeReason = "not_received"
+	DisputeReasonDuplicate       DisputeReason = "duplicate"
+	DisputeReasonIncorrectAmount DisputeReason = "incorrect_amount"
+	DisputeReasonOther           DisputeReason = "other"
+)
+
+type DisputeParty string
+
+const (
+	DisputePartyPayer    DisputeParty = "payer"
+	DisputePartyMerchant DisputeParty = "merchant"
+	DisputePartyAdmin    DisputeParty = "admin"
+	DisputePartySystem   DisputeParty = "system"
+)
+
+// MaxDisputeAttachments é a quantidade máxima de anexos por evidência
+const MaxDisputeAttachments = 10
+
+// DisputePolicy define a janela para abrir disputas e os prazos (SLA) de resposta e decisão
+type DisputePolicy struct {
+	Window         time.Duration
+	ResponseTime   time.Duration
+	ResolutionTime time.Duration
+}
+
+// Dispute é a contestação de uma transação concluída pelo pagador
+type Dispute struct {
+	ID              string        `json:"id" db:"id"`
+	TransactionID   string        `json:"transaction_id" db:"transaction_id"`
+	PayerID         string        `json:"payer_id" db:"payer_id"`
+	MerchantID      string        `json:"merchant_id" db:"merchant_id"`
+	Reason          DisputeReason `json:"reason" db:"reason"`
+	Status          DisputeStatus `json:"status" db:"status"`
+	ResponseDueAt   time.Time     `json:"response_due_at" db:"response_due_at"`
+	ResolutionDueAt time.Time     `json:"resolution_due_at" db:"resolution_due_at"`
+	ResolutionNote  *string       `json:"resolution_note,omitempty" db:"resolution_note"`
+	CreatedAt       time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at" db:"updated_at"`
+	ResolvedAt      *time.Time    `json:"resolved_at,omitempty" db:"resolved_at"`
+	EscalatedAt     *time.Time    `json:"escalated_at,omitempty" db:"escalated_at"`
+
+	Evidence []*DisputeEvidence `json:"evidence,omitempty" db:"-"`
+	History  []*DisputeEvent    `json:"history,omitempty" db:"-"`
+}
+
+// DisputeEvidence é a descrição e os anexos enviados por uma das partes
+type DisputeEvidence struct {
+	ID          string       `json:"id" db:"id"`
+	DisputeID   string       `json:"dispute_id" db:"dispute_id"`
+	Party       DisputeParty `json:"party" db:"party"`
+	SubmittedBy string       `json:"submitted_by" db:"submitted_by"`
+	Description string       `json:"description" db:"description"`
+	Attachments []string     `json:"attachments" db:"attachments"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+}
+
+// DisputeEvent registra uma mudança de status da disputa
+type DisputeEvent struct {
+	ID         string         `json:"id" db:"id"`
+	DisputeID  string         `json:"dispute_id" db:"dispute_id"`
+	FromStatus *DisputeStatus `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   DisputeStatus  `json:"to_status" db:"to_status"`
+	Actor      DisputeParty   `json:"actor" db:"actor"`
+	ActorID    *string        `json:"actor_id,omitempty" db:"actor_id"`
+	Note       string         `json:"note" db:"note"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+}
+
+// NewDispute abre a disputa de uma transação concluída dentro da janela permitida
+func NewDispute(transaction *Transaction, payerID string, reason DisputeReason, description string, attachments []string, policy DisputePolicy, now time.Time) (*Dispute, error) {
+	// Não revelar transações de outros usuários
+	if transaction.PayerID != payerID {
+		return nil, ErrTransactionNotFound
+	}
+	if !transaction.IsCompleted() || transaction.CompletedAt == nil {
+		return nil, ErrTransactionNotCompleted
+	}
+	if now.After(transaction.CompletedAt.Add(policy.Window)) {
+		return nil, ErrDisputeWindowClosed
+	}
+	if !reason.IsValid() {
+		return nil, ErrInvalidDisputeReason
+	}
+
+	dispute := &Dispute{
+		ID:              uuid.New().String(),
+		TransactionID:   transaction.ID,
+		PayerID:         transaction.PayerID,
+		MerchantID:      transaction.PayeeID,
+		Reason:          reason,
+		Status:          DisputeStatusOpen,
+		ResponseDueAt:   now.Add(policy.ResponseTime),
+		ResolutionDueAt: now.Add(policy.ResolutionTime),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if _, err := dispute.addEvidence(DisputePartyPayer, payerID, description, attachments, now); err != nil {
+		return nil, err
+	}
+	dispute.record(nil, DisputeStatusOpen, DisputePartyPayer, &payerID, "disputa aberta pelo pagador", now)
+
+	return dispute, nil
+}
+
+func (r DisputeReason) IsValid() bool {
+	switch r {
+	case DisputeReasonUnauthorized, DisputeReasonNotReceived, DisputeReasonDuplicate, DisputeReasonIncorrectAmount, DisputeReasonOther:
+		return true
+	}
+	return false
+}
+
+// Respond registra a defesa do lojista e envia a disputa para decisão
+func (d *Dispute) Respond(merchantID, description string, attachments []string, now time.Time) error {
+	if d.MerchantID != merchantID {
+		return ErrDisputeNotFound
+	}
+	if d.Status != DisputeStatusOpen {
+		return ErrDisputeNotOpen
+	}
+	if now.After(d.ResponseDueAt) {
+		return ErrDisputeResponseOverdue
+	}
+
+	if _, err := d.addEvidence(DisputePartyMerchant, merchantID, description, attachments, now); err != nil {
+		return err
+	}
+	d.transition(DisputeStatusUnderReview, DisputePartyMerchant, &merchantID, "resposta do lojista recebida", now)
+	return nil
+}
+
+// EscalateOverdue envia para decisão a disputa cujo prazo de resposta do lojista terminou
+func (d *Dispute) EscalateOverdue(now time.Time) bool {
+	if d.Status != DisputeStatusOpen || !now.After(d.ResponseDueAt) {
+		return false
+	}
+
+	d.transition(DisputeStatusUnderReview, DisputePartySystem, nil, "prazo de resposta do lojista expirado", now)
+	return true
+}
+
+// EscalateResolutionOverdue marca a disputa que passou do prazo de decisão sem ser resolvida. Uma
+// disputa ainda aberta vai para análise; as demais ficam no status e ganham o registro no histórico.
+func (d *Dispute) EscalateResolutionOverdue(now time.Time) bool {
+	if !d.IsOverdue(now) || d.EscalatedAt != nil {
+		return false
+	}
+
+	d.EscalatedAt = &now
+	d.transition(DisputeStatusUnderReview, DisputePartySystem, nil, "prazo de decisão expirado", now)
+	return true
+}
+
+// Resolve decide a disputa a favor do pagador ou do lojista
+func (d *Dispute) Resolve(winner DisputeParty, note string, now time.Time) error {
+	if d.IsResolved() {
+		return ErrDisputeAlreadyResolved
+	}
+
+	var status DisputeStatus
+	switch winner {
+	case DisputePartyPayer:
+		status = DisputeStatusWonByPayer
+	case DisputePartyMerchant:
+		status = DisputeStatusWonByMerchant
+	default:
+		return ErrInvalidDisputeWinner
+	}
+
+	note = strings.TrimSpace(note)
+	if note == "" {
+		note = "disputa decidida a favor do " + winner.Description()
+	}
+
+	d.ResolutionNote = &note
+	d.ResolvedAt = &now
+	d.transition(status, DisputePartyAdmin, nil, note, now)
+	return nil
+}
+
+func (d *Dispute) IsResolved() bool {
+	return d.Status == DisputeStatusWonByPayer || d.Status == DisputeStatusWonByMerchant
+}
+
+// IsOverdue informa se a disputa passou do prazo de decisão sem ser resolvida
+func (d *Dispute) IsOverdue(now time.Time) bool {
+	return !d.IsResolved() && now.After(d.ResolutionDueAt)
+}
+
+// IsVisibleTo informa se o usuário é o pagador ou o lojista da disputa
+func (d *Dispute) IsVisibleTo(userID string) bool {
+	return d.PayerID == userID || d.MerchantID == userID
+}
+
+// LastEvent retorna a mudança de status mais recente
+func (d *Dispute) LastEvent() *DisputeEvent {
+	if len(d.History) == 0 {
+		return nil
+	}
+	return d.History[len(d.History)-1]
+}
+
+// LastEvidence retorna a evidência enviada mais recentemente
+func (d *Dispute) LastEvidence() *DisputeEvidence {
+	if len(d.Evidence) == 0 {
+		return nil
+	}
+	return d.Evidence[len(d.Evidence)-1]
+}
+
+func (d *Dispute) GetStatusDescription() string {
+	switch d.Status {
+	case DisputeStatusOpen:
+		return "Aguardando resposta do lojista"
+	case DisputeStatusUnderReview:
+		return "Em análise"
+	case DisputeStatusWonByPayer:
+		return "Decidida a favor do pagador"
+	case DisputeStatusWonByMerchant:
+		return "Decidida a favor do lojista"
+	default:
+		return "Status desconhecido"
+	}
+}
+
+func (p DisputeParty) Description() string {
+	switch p {
+	case DisputePartyPayer:
+		return "pagador"
+	case DisputePartyMerchant:
+		return "lojista"
+	case DisputePartyAdmin:
+		return "administrador"
+	default:
+		return "sistema"
+	}
+}
+
+func (d *Dispute) addEvidence(party DisputeParty, submittedBy, description string, attachments []string, now time.Time) (*DisputeEvidence, error) {
+	description = strings.TrimSpace(description)
+	if len(description) < 10 {
+		return nil, errors.New("descrição da evidência deve ter pelo menos 10 caracteres")
+	}
+	if len(attachments) > MaxDisputeAttachments {
+		return nil, errors.New("evidência excede a quantidade máxima de anexos")
+	}
+	for _, attachment := range attachments {
+		if !strings.HasPrefix(attachment, "https://") {
+			return nil, errors.New("anexos devem ser URLs https")
+		}
+	}
+	if attachments == nil {
+		attachments = []string{}
+	}
+
+	evidence := &DisputeEvidence{
+		ID:          uuid.New().String(),
+		DisputeID:   d.ID,
+		Party:       party,
+		SubmittedBy: submittedBy,
+		Description: description,
+		Attachments: attachments,
+		CreatedAt:   now,
+	}
+	d.Evidence = append(d.Evidence, evidence)
+	return evidence, nil
+}
+
+func (d *Dispute) transition(to DisputeStatus, actor DisputeParty, actorID *string, note string, now time.Time) {
+	from := d.Status
+	d.Status = to
+	d.UpdatedAt = now
+	d.record(&from, to, actor, actorID, note, now)
+}
+
+func (d *Dispute) record(from *DisputeStatus, to DisputeStatus, actor DisputeParty, actorID *string, note string, now time.Time) {
+	d.History = append(d.History, &DisputeEvent{
+		ID:         uuid.New().String(),
+		DisputeID:  d.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		Actor:      actor,
+		ActorID:    actorID,
+		Note:       note,
+		CreatedAt:  now,
+	})
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -24,14 +25,17 @@ type CreateUserResponse struct {
 }
 
 type GetUserResponse struct {
-	ID        string    `json:"id"`
-	FullName  string    `json:"full_name"`
-	Document  string    `json:"document"`
-	Email     string    `json:"email"`
-	UserType  UserType  `json:"user_type"`
-	Balance   string    `json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                string        `json:"id"`
+	FullName          string        `json:"full_name"`
+	Document          string        `json:"document"`
+	Email             string        `json:"email"`
+	UserType          UserType      `json:"user_type"`
+	Balance           string        `json:"balance"`
+	Status            AccountStatus `json:"status"`
+	StatusDescription string        `json:"status_description"`
+	StatusReason      *string       `json:"status_reason,omitempty"`
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
 }
 
 type UpdateUserRequest struct {
@@ -45,18 +49,24 @@ type ChangePasswordRequest struct {
 }
 
 type CreateTransactionRequest struct {
-	PayeeID string          `json:"payee_id" validate:"required,uuid"`
-	Amount  decimal.Decimal `json:"amount" validate:"required,gt=0"`
+	PayeeID      string          `json:"payee_id,omitempty" validate:"required_without=PayeeKey,omitempty,uuid"`
+	PayeeKey     string          `json:"payee_key,omitempty"`
+	Amount       decimal.Decimal `json:"amount" validate:"required,gt=0"`
+	ScheduledFor *time.Time      `json:"scheduled_for,omitempty"`
 }
 
 type CreateTransactionResponse struct {
-	ID         string            `json:"id"`
-	PayerID    string            `json:"payer_id"`
-	PayeeID    string            `json:"payee_id"`
-	Amount     string            `json:"amount"`
-	Status     TransactionStatus `json:"status"`
-	StatusDesc string            `json:"status_description"`
-	CreatedAt  time.Time         `json:"created_at"`
+	ID           string            `json:"id"`
+	PayerID      string            `json:"payer_id"`
+	PayeeID      string            `json:"payee_id"`
+	Amount       string            `json:"amount"`
+	GrossAmount  string            `json:"gross_amount"`
+	FeeAmount    string            `json:"fee_amount"`
+	NetAmount    string            `json:"net_amount"`
+	Status       TransactionStatus `json:"status"`
+	StatusDesc   string            `json:"status_description"`
+	ScheduledFor *time.Time        `json:"scheduled_for,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
 }
 
 type GetTransactionResponse struct {
@@ -64,11 +74,14 @@ type GetTransactionResponse struct {
 	PayerID          string            `json:"payer_id"`
 	PayeeID          string            `json:"payee_id"`
 	Amount           string            `json:"amount"`
+	FeeAmount        string            `json:"fee_amount"`
+	NetAmount        string            `json:"net_amount"`
 	Status           TransactionStatus `json:"status"`
 	StatusDesc       string            `json:"status_description"`
 	AuthorizationID  *string           `json:"authorization_id,omitempty"`
 	NotificationSent bool              `json:"notification_sent"`
 	FailureReason    *string           `json:"failure_reason,omitempty"`
+	ScheduledFor     *time.Time        `json:"scheduled_for,omitempty"`
 	CreatedAt        time.Time         `json:"created_at"`
 	UpdatedAt        time.Time         `json:"updated_at"`
 	CompletedAt      *time.Time        `json:"completed_at,omitempty"`
@@ -92,6 +105,31 @@ type ListTransactionsResponse struct {
 	TotalPages   int                      `json:"total_pages"`
 }
 
+type ChangeAccountStatusRequest struct {
+	Status AccountStatus `json:"status" validate:"required,oneof=active blocked frozen closed"`
+	Reason string        `json:"reason" validate:"required,min=3"`
+}
+
+type AccountStatusHistoryResponse struct {
+	UserID  string                 `json:"user_id"`
+	Status  AccountStatus          `json:"status"`
+	Changes []*AccountStatusChange `json:"changes"`
+}
+
+type DataExportResponse struct {
+	ID                string           `json:"id"`
+	Status            DataExportStatus `json:"status"`
+	StatusDescription string           `json:"status_description"`
+	SizeBytes         int              `json:"size_bytes,omitempty"`
+	FailureReason     *string          `json:"failure_reason,omitempty"`
+	// DownloadURL é o link assinado de uso único, presente enquanto a exportação está pronta
+	DownloadURL  *string    `json:"download_url,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ReadyAt      *time.Time `json:"ready_at,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	DownloadedAt *time.Time `json:"downloaded_at,omitempty"`
+}
+
 type ListUsersResponse struct {
 	Users      []GetUserResponse `json:"users"`
 	Total      int               `json:"total"`
@@ -103,6 +141,451 @@ type ListUsersResponse struct {
 type BalanceResponse struct {
 	UserID    string    `json:"user_id"`
 	Balance   string    `json:"balance"`
+	Available string    `json:"available"`
+	Held      string    `json:"held"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TransactionStreamUpdate é a mudança de status de uma transferência enviada no stream do usuário.
+// Direction indica se o dono do stream enviou ou recebeu a transferência.
+type TransactionStreamUpdate struct {
+	TransactionID  string            `json:"transaction_id"`
+	Status         TransactionStatus `json:"status"`
+	Direction      string            `json:"direction"`
+	CounterpartyID string            `json:"counterparty_id"`
+	Amount         string            `json:"amount"`
+	NetAmount      string            `json:"net_amount,omitempty"`
+	Reason         *string           `json:"reason,omitempty"`
+	OccurredAt     time.Time         `json:"occurred_at"`
+}
+
+type CancelTransactionRequest struct {
+	Reason string `json:"reason,omitempty" validate:"omitempty,max=255"`
+}
+
+type CreateRecurringPaymentRequest struct {
+	PayeeID        string              `json:"payee_id" validate:"required,uuid"`
+	Amount         decimal.Decimal     `json:"amount" validate:"required,gt=0"`
+	Description    string              `json:"description,omitempty" validate:"max=140"`
+	Frequency      RecurrenceFrequency `json:"frequency" validate:"required,oneof=weekly monthly cron"`
+	CronExpression *string             `json:"cron_expression,omitempty"`
+	StartDate      time.Time           `json:"start_date" validate:"required"`
+	EndDate        *time.Time          `json:"end_date,omitempty"`
+	MaxOccurrences *int                `json:"max_occurrences,omitempty" validate:"omitempty,gt=0"`
+}
+
+type RecurringPaymentResponse struct {
+	ID             string              `json:"id"`
+	PayerID        string              `json:"payer_id"`
+	PayeeID        string              `json:"payee_id"`
+	Amount         string              `json:"amount"`
+	Description    string              `json:"description,omitempty"`
+	Frequency      RecurrenceFrequency `json:"frequency"`
+	CronExpression *string             `json:"cron_expression,omitempty"`
+	StartDate      time.Time           `json:"start_date"`
+	EndDate        *time.Time          `json:"end_date,omitempty"`
+	MaxOccurrences *int                `json:"max_occurrences,omitempty"`
+	Occurrences    int                 `json:"occurrences"`
+	NextRunAt      *time.Time          `json:"next_run_at,omitempty"`
+	LastRunAt      *time.Time          `json:"last_run_at,omitempty"`
+	Status         MandateStatus       `json:"status"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+type ListRecurringPaymentsResponse struct {
+	RecurringPayments []RecurringPaymentResponse `json:"recurring_payments"`
+	Total             int                        `json:"total"`
+}
+
+type ListMandateRunsResponse struct {
+	Runs  []MandateRun `json:"runs"`
+	Total int          `json:"total"`
+}
+
+type BatchItemRequest struct {
+	PayeeID  string          `json:"payee_id,omitempty"`
+	PayeeKey string          `json:"payee_key,omitempty"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+type CreateBatchRequest struct {
+	Mode  BatchMode          `json:"mode,omitempty" validate:"omitempty,oneof=all_or_nothing best_effort"`
+	Items []BatchItemRequest `json:"items" validate:"required,min=1"`
+}
+
+type BatchItemResponse struct {
+	Position      int             `json:"position"`
+	PayeeID       string          `json:"payee_id"`
+	PayeeKey      *string         `json:"payee_key,omitempty"`
+	Amount        string          `json:"amount"`
+	Status        BatchItemStatus `json:"status"`
+	TransactionID *string         `json:"transaction_id,omitempty"`
+	FailureReason *string         `json:"failure_reason,omitempty"`
+}
+
+type TransferBatchResponse struct {
+	ID             string              `json:"id"`
+	PayerID        string              `json:"payer_id"`
+	Mode           BatchMode           `json:"mode"`
+	Status         BatchStatus         `json:"status"`
+	TotalItems     int                 `json:"total_items"`
+	TotalAmount    string              `json:"total_amount"`
+	SucceededCount int                 `json:"succeeded_count"`
+	FailedCount    int                 `json:"failed_count"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	CompletedAt    *time.Time          `json:"completed_at,omitempty"`
+	Items          []BatchItemResponse `json:"items,omitempty"`
+}
+
+type SplitLegRequest struct {
+	PayeeID   string          `json:"payee_id,omitempty"`
+	PayeeKey  string          `json:"payee_key,omitempty"`
+	Platform  bool            `json:"platform,omitempty"`
+	SplitType SplitType       `json:"type" validate:"required,oneof=percentage fixed"`
+	Value     decimal.Decimal `json:"value" validate:"required,gt=0"`
+}
+
+type CreateSplitPaymentRequest struct {
+	Amount      decimal.Decimal   `json:"amount" validate:"required,gt=0"`
+	Description string            `json:"description,omitempty"`
+	Legs        []SplitLegRequest `json:"legs" validate:"required,min=2"`
+}
+
+type RefundSplitPaymentRequest struct {
+	Amount *decimal.Decimal `json:"amount,omitempty"`
+	Reason string           `json:"reason,omitempty"`
+}
+
+type SplitLegResponse struct {
+	ID             string    `json:"id"`
+	Position       int       `json:"position"`
+	PayeeID        *string   `json:"payee_id,omitempty"`
+	PayeeKey       *string   `json:"payee_key,omitempty"`
+	Platform       bool      `json:"platform"`
+	SplitType      SplitType `json:"type"`
+	Value          string    `json:"value"`
+	Amount         string    `json:"amount"`
+	RefundedAmount string    `json:"refunded_amount"`
+	TransactionID  *string   `json:"transaction_id,omitempty"`
+}
+
+type SplitRefundLegResponse struct {
+	LegID    string `json:"leg_id"`
+	Position int    `json:"position"`
+	Amount   string `json:"amount"`
+	Fee      string `json:"fee"`
+}
+
+type SplitRefundResponse struct {
+	ID        string                   `json:"id"`
+	Amount    string                   `json:"amount"`
+	Reason    string                   `json:"reason,omitempty"`
+	Legs      []SplitRefundLegResponse `json:"legs"`
+	CreatedAt time.Time                `json:"created_at"`
+}
+
+type SplitPaymentResponse struct {
+	ID                string                `json:"id"`
+	PayerID           string                `json:"payer_id"`
+	Amount            string                `json:"amount"`
+	Description       string                `json:"description,omitempty"`
+	Status            SplitPaymentStatus    `json:"status"`
+	StatusDescription string                `json:"status_description"`
+	RefundedAmount    string                `json:"refunded_amount"`
+	FailureReason     *string               `json:"failure_reason,omitempty"`
+	Legs              []SplitLegResponse    `json:"legs"`
+	Refunds           []SplitRefundResponse `json:"refunds,omitempty"`
+	CreatedAt         time.Time             `json:"created_at"`
+	UpdatedAt         time.Time             `json:"updated_at"`
+	CompletedAt       *time.Time            `json:"completed_at,omitempty"`
+}
+
+type OpenDisputeRequest struct {
+	TransactionID string        `json:"transaction_id" validate:"required,uuid"`
+	Reason        DisputeReason `json:"reason" validate:"required,oneof=unauthorized not_received duplicate incorrect_amount other"`
+	Description   string        `json:"description" validate:"required,min=10"`
+	Attachments   []string      `json:"attachments,omitempty" validate:"max=10,dive,url"`
+}
+
+type RespondDisputeRequest struct {
+	Description string   `json:"description" validate:"required,min=10"`
+	Attachments []string `json:"attachments,omitempty" validate:"max=10,dive,url"`
+}
+
+type ResolveDisputeRequest struct {
+	Winner DisputeParty `json:"winner" validate:"required,oneof=payer merchant"`
+	Note   string       `json:"note,omitempty"`
+}
+
+type DisputeResponse struct {
+	ID                string             `json:"id"`
+	TransactionID     string             `json:"transaction_id"`
+	PayerID           string             `json:"payer_id"`
+	MerchantID        string             `json:"merchant_id"`
+	Reason            DisputeReason      `json:"reason"`
+	Status            DisputeStatus      `json:"status"`
+	StatusDescription string             `json:"status_description"`
+	ResponseDueAt     time.Time          `json:"response_due_at"`
+	ResolutionDueAt   time.Time          `json:"resolution_due_at"`
+	Overdue           bool               `json:"overdue"`
+	ResolutionNote    *string            `json:"resolution_note,omitempty"`
+	Evidence          []*DisputeEvidence `json:"evidence,omitempty"`
+	History           []*DisputeEvent    `json:"history,omitempty"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+	ResolvedAt        *time.Time         `json:"resolved_at,omitempty"`
+	EscalatedAt       *time.Time         `json:"escalated_at,omitempty"`
+}
+
+type ListDisputesResponse struct {
+	Disputes   []DisputeResponse `json:"disputes"`
+	Total      int               `json:"total"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	TotalPages int               `json:"total_pages"`
+}
+
+type CreateWebhookEndpointRequest struct {
+	URL         string             `json:"url" validate:"required,url"`
+	Description string             `json:"description,omitempty" validate:"max=140"`
+	EventTypes  []WebhookEventType `json:"event_types" validate:"required,min=1"`
+}
+
+// UpdateWebhookEndpointRequest altera apenas os campos enviados
+type UpdateWebhookEndpointRequest struct {
+	URL         *string            `json:"url,omitempty" validate:"omitempty,url"`
+	Description *string            `json:"description,omitempty" validate:"omitempty,max=140"`
+	EventTypes  []WebhookEventType `json:"event_types,omitempty"`
+	Active      *bool              `json:"active,omitempty"`
+}
+
+type WebhookEndpointResponse struct {
+	ID          string             `json:"id"`
+	URL         string             `json:"url"`
+	Description string             `json:"description,omitempty"`
+	EventTypes  []WebhookEventType `json:"event_types"`
+	Active      bool               `json:"active"`
+	// Secret só é retornado na criação; o lojista deve guardá-lo para conferir as assinaturas
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ListWebhookEndpointsResponse struct {
+	Endpoints    []WebhookEndpointResponse `json:"endpoints"`
+	Total        int                       `json:"total"`
+	MaxEndpoints int                       `json:"max_endpoints"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID                 string                `json:"id"`
+	EndpointID         string                `json:"endpoint_id"`
+	EventID            string                `json:"event_id"`
+	EventType          WebhookEventType      `json:"event_type"`
+	Status             WebhookDeliveryStatus `json:"status"`
+	StatusDescription  string                `json:"status_description"`
+	Attempts           int                   `json:"attempts"`
+	RedeliveryOf       *string               `json:"redelivery_of,omitempty"`
+	NextAttemptAt      *time.Time            `json:"next_attempt_at,omitempty"`
+	LastResponseStatus *int                  `json:"last_response_status,omitempty"`
+	LastError          *string               `json:"last_error,omitempty"`
+	// Payload é o corpo enviado, presente apenas na consulta de uma entrega
+	Payload     json.RawMessage   `json:"payload,omitempty"`
+	AttemptLog  []*WebhookAttempt `json:"attempt_log,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	DeliveredAt *time.Time        `json:"delivered_at,omitempty"`
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	Total      int                       `json:"total"`
+	Page       int                       `json:"page"`
+	Limit      int                       `json:"limit"`
+	TotalPages int                       `json:"total_pages"`
+}
+
+type DecideRiskReviewRequest struct {
+	Note string `json:"note,omitempty"`
+}
+
+type RiskReviewResponse struct {
+	ID                string                  `json:"id"`
+	TransactionID     string                  `json:"transaction_id"`
+	PayerID           string                  `json:"payer_id"`
+	PayeeID           string                  `json:"payee_id"`
+	Amount            decimal.Decimal         `json:"amount"`
+	Decision          RiskDecision            `json:"decision"`
+	Reasons           []RiskReason            `json:"reasons"`
+	Status            RiskReviewStatus        `json:"status"`
+	StatusDescription string                  `json:"status_description"`
+	DueAt             *time.Time              `json:"due_at,omitempty"`
+	ClaimedBy         *string                 `json:"claimed_by,omitempty"`
+	ClaimedAt         *time.Time              `json:"claimed_at,omitempty"`
+	DecidedBy         *string                 `json:"decided_by,omitempty"`
+	DecidedAt         *time.Time              `json:"decided_at,omitempty"`
+	DecisionNote      *string                 `json:"decision_note,omitempty"`
+	Events            []*RiskReviewEvent      `json:"events,omitempty"`
+	Transaction       *GetTransactionResponse `json:"transaction,omitempty"`
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+}
+
+type ListRiskReviewsResponse struct {
+	Reviews    []RiskReviewResponse `json:"reviews"`
+	Total      int                  `json:"total"`
+	Page       int                  `json:"page"`
+	Limit      int                  `json:"limit"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+type ListAuditLogsResponse struct {
+	Entries    []*AuditEntry `json:"entries"`
+	Total      int           `json:"total"`
+	Page       int           `json:"page"`
+	Limit      int           `json:"limit"`
+	TotalPages int           `json:"total_pages"`
+}
+
+type CreatePixKeyRequest struct {
+	KeyType PixKeyType `json:"key_type" validate:"required,oneof=cpf cnpj email phone evp"`
+	Key     string     `json:"key,omitempty"`
+}
+
+type PixKeyResponse struct {
+	ID        string     `json:"id"`
+	KeyType   PixKeyType `json:"key_type"`
+	Key       string     `json:"key"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type ListPixKeysResponse struct {
+	Keys    []PixKeyResponse `json:"keys"`
+	Total   int              `json:"total"`
+	MaxKeys int              `json:"max_keys"`
+}
+
+type PixKeyLookupResponse struct {
+	KeyType    PixKeyType `json:"key_type"`
+	Key        string     `json:"key"`
+	HolderName string     `json:"holder_name"`
+	Document   string     `json:"document"`
+	UserType   UserType   `json:"user_type"`
+}
+
+type CreatePaymentRequestRequest struct {
+	PayerID     *string         `json:"payer_id,omitempty" validate:"omitempty,uuid"`
+	Amount      decimal.Decimal `json:"amount" validate:"required,gt=0"`
+	Description string          `json:"description,omitempty" validate:"max=140"`
+	ExpiresAt   time.Time       `json:"expires_at" validate:"required"`
+}
+
+type PaymentRequestResponse struct {
+	ID                string               `json:"id"`
+	TxID              string               `json:"txid"`
+	MerchantID        string               `json:"merchant_id"`
+	PayerID           *string              `json:"payer_id,omitempty"`
+	Amount            string               `json:"amount"`
+	Description       string               `json:"description,omitempty"`
+	Status            PaymentRequestStatus `json:"status"`
+	StatusDescription string               `json:"status_description"`
+	ExpiresAt         time.Time            `json:"expires_at"`
+	TransactionID     *string              `json:"transaction_id,omitempty"`
+	PaidBy            *string              `json:"paid_by,omitempty"`
+	PaidAt            *time.Time           `json:"paid_at,omitempty"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+type BRCodeResponse struct {
+	PaymentRequestID string `json:"payment_request_id"`
+	Type             string `json:"type"`
+	TxID             string `json:"txid"`
+	Payload          string `json:"payload"`
+}
+
+type ParseBRCodeRequest struct {
+	Payload string `json:"payload" validate:"required"`
+}
+
+type ParseBRCodeResponse struct {
+	Type           string                  `json:"type"`
+	Key            string                  `json:"key,omitempty"`
+	URL            string                  `json:"url,omitempty"`
+	Amount         string                  `json:"amount,omitempty"`
+	MerchantName   string                  `json:"merchant_name"`
+	MerchantCity   string                  `json:"merchant_city"`
+	TxID           string                  `json:"txid,omitempty"`
+	PaymentRequest *PaymentRequestResponse `json:"payment_request,omitempty"`
+}
+
+type PayPaymentRequestResponse struct {
+	PaymentRequest PaymentRequestResponse `json:"payment_request"`
+	Transaction    GetTransactionResponse `json:"transaction"`
+}
+
+type ListPaymentRequestsResponse struct {
+	PaymentRequests []PaymentRequestResponse `json:"payment_requests"`
+	Total           int                      `json:"total"`
+	Page            int                      `json:"page"`
+	Limit           int                      `json:"limit"`
+	TotalPages      int                      `json:"total_pages"`
+}
+
+type UpdateLimitsRequest struct {
+	PerTransaction *decimal.Decimal `json:"per_transaction,omitempty"`
+	Daily          *decimal.Decimal `json:"daily,omitempty"`
+	Monthly        *decimal.Decimal `json:"monthly,omitempty"`
+	Nightly        *decimal.Decimal `json:"nightly,omitempty"`
+}
+
+type LimitValuesResponse struct {
+	PerTransaction string `json:"per_transaction,omitempty"`
+	Daily          string `json:"daily,omitempty"`
+	Monthly        string `json:"monthly,omitempty"`
+	Nightly        string `json:"nightly,omitempty"`
+}
+
+type UserLimitsResponse struct {
+	UserID    string               `json:"user_id"`
+	UserType  UserType             `json:"user_type"`
+	Effective LimitValuesResponse  `json:"effective"`
+	Defaults  LimitValuesResponse  `json:"defaults"`
+	Override  *LimitValuesResponse `json:"override,omitempty"`
+	Usage     LimitValuesResponse  `json:"usage"`
+}
+
+type DefaultLimitsResponse struct {
+	UserType UserType            `json:"user_type"`
+	Limits   LimitValuesResponse `json:"limits"`
+}
+
+type CreatePricingPlanRequest struct {
+	Name        string           `json:"name" validate:"required,min=3"`
+	FeeType     FeeType          `json:"fee_type" validate:"required,oneof=fixed percentage tiered"`
+	FixedAmount decimal.Decimal  `json:"fixed_amount"`
+	Percentage  decimal.Decimal  `json:"percentage"`
+	Tiers       []FeeTier        `json:"tiers,omitempty"`
+	MinFee      *decimal.Decimal `json:"min_fee,omitempty"`
+	MaxFee      *decimal.Decimal `json:"max_fee,omitempty"`
+}
+
+type AssignPricingPlanRequest struct {
+	PricingPlanID string `json:"pricing_plan_id" validate:"required,uuid"`
+}
+
+type FeeQuoteResponse struct {
+	PayeeID       string  `json:"payee_id"`
+	GrossAmount   string  `json:"gross_amount"`
+	FeeAmount     string  `json:"fee_amount"`
+	NetAmount     string  `json:"net_amount"`
+	PricingPlanID *string `json:"pricing_plan_id,omitempty"`
+}
+
+type PlatformAccountResponse struct {
+	Code      string    `json:"code"`
+	Balance   string    `json:"balance"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
@@ -145,9 +628,51 @@ type TransactionFilters struct {
 	MaxAmount *decimal.Decimal  `json:"max_amount,omitempty"`
 }
 
+type PaymentRequestFilters struct {
+	PaginationParams
+	MerchantID string               `json:"merchant_id,omitempty"`
+	Status     PaymentRequestStatus `json:"status,omitempty"`
+}
+
 type UserFilters struct {
 	PaginationParams
-	UserType UserType `json:"user_type,omitempty"`
-	Email    string   `json:"email,omitempty"`
-	Document string   `json:"document,omitempty"`
+	UserType UserType      `json:"user_type,omitempty"`
+	Email    string        `json:"email,omitempty"`
+	Document string        `json:"document,omitempty"`
+	Status   AccountStatus `json:"status,omitempty"`
+}
+
+type DisputeFilters struct {
+	PaginationParams
+	// UserID restringe às disputas em que o usuário é pagador ou lojista
+	UserID string        `json:"user_id,omitempty"`
+	Status DisputeStatus `json:"status,omitempty"`
+	// OverdueAt restringe às disputas não decididas cujo prazo de decisão terminou antes da data
+	OverdueAt *time.Time `json:"-"`
+}
+
+type RiskReviewFilters struct {
+	PaginationParams
+	// Status vazio lista os itens abertos (pending e claimed)
+	Status RiskReviewStatus `json:"status,omitempty"`
+}
+
+type AuditLogFilters struct {
+	PaginationParams
+	ActorType    AuditActorType `json:"actor_type,omitempty"`
+	ActorID      string         `json:"actor_id,omitempty"`
+	Action       string         `json:"action,omitempty"`
+	ResourceType string         `json:"resource_type,omitempty"`
+	ResourceID   string         `json:"resource_id,omitempty"`
+	RequestID    string         `json:"request_id,omitempty"`
+	DateFrom     *time.Time     `json:"date_from,omitempty"`
+	DateTo       *time.Time     `json:"date_to,omitempty"`
+}
+
+type WebhookDeliveryFilters struct {
+	PaginationParams
+	MerchantID string                `json:"merchant_id"`
+	EndpointID string                `json:"endpoint_id,omitempty"`
+	EventType  WebhookEventType      `json:"event_type,omitempty"`
+	Status     WebhookDeliveryStatus `json:"status,omitempty"`
 }
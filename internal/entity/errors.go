@@ -9,9 +9,67 @@ var (
 	ErrInvalidUserType     = errors.New("tipo de usuário inválido")
 	ErrMerchantCannotSend  = errors.New("lojistas não podem enviar dinheiro")
 	ErrInsufficientBalance = errors.New("saldo insuficiente")
-	ErrInvalidCredentials  = errors.New("credenciais inválidas")
+	ErrInvalidCredentials  = errors.New("e-mail ou senha inválidos")
 	ErrWeakPassword        = errors.New("senha muito fraca")
 
+	// Erros de status da conta
+	ErrInvalidAccountStatus        = errors.New("status de conta inválido")
+	ErrAccountStatusUnchanged      = errors.New("conta já está no status informado")
+	ErrAccountStatusReasonRequired = errors.New("motivo da mudança de status deve ter pelo menos 3 caracteres")
+	ErrAccountBlocked              = errors.New("conta bloqueada para envio de dinheiro")
+	ErrAccountFrozen               = errors.New("conta congelada")
+	ErrAccountClosed               = errors.New("conta encerrada")
+	ErrAccountHasBalance           = errors.New("conta só pode ser encerrada com saldo zerado e sem reservas")
+	ErrPayeeCannotReceive          = errors.New("recebedor não pode receber transferências no momento")
+
+	// Erros de exclusão e anonimização
+	ErrAccountNotClosed      = errors.New("conta precisa estar encerrada para ser excluída")
+	ErrUserNotDeleted        = errors.New("usuário precisa estar excluído para ser anonimizado")
+	ErrUserAlreadyAnonymized = errors.New("usuário já foi anonimizado")
+	ErrRetentionPeriodActive = errors.New("dados ainda estão no prazo legal de retenção")
+
+	// Erros de autenticação
+	ErrInvalidAuthToken = errors.New("token de acesso inválido")
+	ErrAuthTokenExpired = errors.New("token de acesso expirado")
+
+	// Erros de exportação de dados
+	ErrDataExportNotFound          = errors.New("exportação de dados não encontrada")
+	ErrDataExportInProgress        = errors.New("já existe uma exportação de dados em andamento")
+	ErrDataExportNotProcessing     = errors.New("exportação de dados não está em processamento")
+	ErrDataExportNotReady          = errors.New("exportação de dados ainda não está pronta")
+	ErrDataExportAlreadyDownloaded = errors.New("exportação de dados já foi baixada")
+	ErrDataExportExpired           = errors.New("link de download expirado")
+	ErrInvalidDownloadSignature    = errors.New("assinatura do link de download inválida")
+
+	// Erros de extrato
+	ErrInvalidStatementFormat = errors.New("formato de extrato inválido")
+	ErrInvalidStatementPeriod = errors.New("período do extrato inválido")
+	ErrStatementPeriodTooLong = errors.New("período do extrato excede o máximo de 366 dias")
+
+	// Erros de comprovantes
+	ErrReceiptNotFound      = errors.New("comprovante não encontrado")
+	ErrInvalidReceiptFormat = errors.New("formato de comprovante inválido")
+
+	// Erros de webhooks
+	ErrWebhookEndpointNotFound     = errors.New("endpoint de webhook não encontrado")
+	ErrWebhookDeliveryNotFound     = errors.New("entrega de webhook não encontrada")
+	ErrOnlyMerchantsCanUseWebhooks = errors.New("apenas lojistas podem cadastrar webhooks")
+	ErrWebhookEndpointLimitReached = errors.New("limite de endpoints de webhook atingido")
+	ErrInvalidWebhookURL           = errors.New("URL do webhook inválida")
+	ErrInvalidWebhookEventType     = errors.New("tipo de evento de webhook inválido")
+	ErrWebhookDeliveryPending      = errors.New("entrega de webhook ainda está em andamento")
+	ErrInvalidWebhookSignature     = errors.New("assinatura do webhook inválida")
+	ErrWebhookTimestampExpired     = errors.New("timestamp do webhook fora da tolerância")
+
+	// Erros de eventos de domínio
+	ErrOutboxEventNotFound       = errors.New("evento do outbox não encontrado")
+	ErrEventPublisherUnavailable = errors.New("broker de eventos indisponível")
+
+	// Erros do stream de eventos do usuário
+	ErrTooManyUserStreams = errors.New("limite de conexões simultâneas ao stream de eventos atingido")
+	ErrInvalidLastEventID = errors.New("Last-Event-ID inválido")
+	ErrUserStreamClosed   = errors.New("stream de eventos encerrado")
+
 	// Erros de documento
 	ErrInvalidCPF            = errors.New("CPF inválido")
 	ErrInvalidCNPJ           = errors.New("CNPJ inválido")
@@ -23,9 +81,89 @@ var (
 	ErrInvalidAmount               = errors.New("valor inválido")
 	ErrSelfTransfer                = errors.New("não é possível transferir para si mesmo")
 	ErrTransactionNotPending       = errors.New("transação não está pendente")
+	ErrTransactionNotUnderReview   = errors.New("transação não está em análise de risco")
 	ErrTransactionNotAuthorized    = errors.New("transação não está autorizada")
 	ErrTransactionAlreadyCompleted = errors.New("transação já foi concluída")
+	ErrTransactionNotCompleted     = errors.New("transação não foi concluída")
 	ErrAmountExceedsLimit          = errors.New("valor excede o limite máximo")
+	ErrLimitsNotConfigured         = errors.New("limites de transação não configurados")
+	ErrTransactionNotScheduled     = errors.New("transação não está agendada")
+	ErrScheduleInPast              = errors.New("data de agendamento deve ser futura")
+	ErrScheduleTooFar              = errors.New("data de agendamento excede o prazo máximo de um ano")
+
+	// Erros de chaves Pix
+	ErrPixKeyNotFound        = errors.New("chave Pix não encontrada")
+	ErrPixKeyAlreadyExists   = errors.New("chave Pix já cadastrada")
+	ErrPixKeyLimitReached    = errors.New("limite de chaves Pix da conta atingido")
+	ErrInvalidPixKey         = errors.New("chave Pix inválida")
+	ErrInvalidPixKeyType     = errors.New("tipo de chave Pix inválido")
+	ErrPixKeyNotOwned        = errors.New("chave Pix não pertence ao usuário")
+	ErrMerchantWithoutPixKey = errors.New("lojista não possui chave Pix cadastrada")
+
+	// Erros de lotes de transferências
+	ErrBatchNotFound    = errors.New("lote de transferências não encontrado")
+	ErrBatchNotPending  = errors.New("lote de transferências não está pendente")
+	ErrInvalidBatch     = errors.New("lote de transferências inválido")
+	ErrInvalidBatchMode = errors.New("modo do lote inválido")
+	ErrBatchTooLarge    = errors.New("lote excede a quantidade máxima de transferências")
+	ErrBatchEmpty       = errors.New("lote deve ter ao menos uma transferência")
+	ErrBatchAborted     = errors.New("lote cancelado por falha em outra transferência")
+
+	// Erros de pagamentos divididos
+	ErrSplitPaymentNotFound      = errors.New("pagamento dividido não encontrado")
+	ErrInvalidSplitType          = errors.New("tipo de divisão inválido")
+	ErrSplitPaymentNotRefundable = errors.New("pagamento dividido não pode ser estornado")
+	ErrRefundExceedsAmount       = errors.New("valor do estorno excede o valor disponível para estorno")
+	ErrPayeeCannotCoverRefund    = errors.New("recebedor não tem saldo disponível para o estorno")
+	ErrSplitLegReversed          = errors.New("parte do pagamento dividido já foi revertida")
+
+	// Erros de disputas
+	ErrDisputeNotFound        = errors.New("disputa não encontrada")
+	ErrDisputeAlreadyExists   = errors.New("transação já possui disputa")
+	ErrDisputeNotOpen         = errors.New("disputa não está aguardando resposta do lojista")
+	ErrDisputeAlreadyResolved = errors.New("disputa já foi decidida")
+	ErrDisputeWindowClosed    = errors.New("prazo para abrir disputa da transação encerrado")
+	ErrDisputeResponseOverdue = errors.New("prazo de resposta do lojista encerrado")
+	ErrInvalidDisputeReason   = errors.New("motivo da disputa inválido")
+	ErrInvalidDisputeWinner   = errors.New("vencedor da disputa deve ser payer ou merchant")
+	ErrDisputeSplitRefunded   = errors.New("parte do pagamento dividido já tem estorno; a disputa não pode reverter a transação")
+
+	// Erros da análise de risco
+	ErrRiskDenied               = errors.New("transferência negada pela análise de risco")
+	ErrRiskReviewNotFound       = errors.New("item da fila de análise não encontrado")
+	ErrRiskReviewNotOpen        = errors.New("item da fila de análise já foi decidido")
+	ErrRiskReviewClaimedByOther = errors.New("item da fila de análise está com outro analista")
+	ErrRiskReviewNotClaimed     = errors.New("item da fila de análise precisa ser assumido pelo analista antes da decisão")
+	ErrRiskReviewExpired        = errors.New("prazo de análise encerrado")
+	ErrRiskReviewerRequired     = errors.New("analista é obrigatório")
+
+	// Erros de cobranças
+	ErrPaymentRequestNotFound    = errors.New("cobrança não encontrada")
+	ErrPaymentRequestNotOpen     = errors.New("cobrança não está aberta")
+	ErrPaymentRequestAlreadyPaid = errors.New("cobrança já foi paga")
+	ErrPaymentRequestExpired     = errors.New("cobrança expirada")
+	ErrPaymentRequestInProgress  = errors.New("pagamento da cobrança já está em andamento")
+	ErrOnlyMerchantsCanCharge    = errors.New("apenas lojistas podem emitir cobranças")
+
+	// Erros de pagamentos recorrentes
+	ErrMandateNotFound         = errors.New("pagamento recorrente não encontrado")
+	ErrMandateNotActive        = errors.New("pagamento recorrente não está ativo")
+	ErrMandateNotPaused        = errors.New("pagamento recorrente não está pausado")
+	ErrInvalidRecurrence       = errors.New("regra de recorrência inválida")
+	ErrMandatePeriodAlreadyRun = errors.New("período do pagamento recorrente já foi executado")
+	ErrMandatePayeeNotMerchant = errors.New("pagamentos recorrentes só podem ter lojistas como recebedores")
+
+	// Erros de tarifas
+	ErrPricingPlanNotFound = errors.New("plano de tarifas não encontrado")
+	ErrInvalidFeeType      = errors.New("tipo de tarifa inválido")
+	ErrFeeExceedsAmount    = errors.New("tarifa maior ou igual ao valor da transação")
+
+	ErrPlatformAccountNotFound = errors.New("conta da plataforma não encontrada")
+
+	// Erros de reserva de saldo
+	ErrHoldNotFound        = errors.New("reserva de saldo não encontrada")
+	ErrHoldNotActive       = errors.New("reserva de saldo não está ativa")
+	ErrHoldExceedsReserved = errors.New("valor excede o saldo reservado")
 
 	// Erros de autorização
 	ErrAuthorizationFailed  = errors.New("autorização negada")
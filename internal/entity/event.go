@@ -0,0 +1,230 @@
+package entity
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// DomainEventType identifica um fato do domínio, como "transfer.completed"
+type DomainEventType string
+
+const (
+	EventUserCreated        DomainEventType = "user.created"
+	EventTransferAuthorized DomainEventType = "transfer.authorized"
+	EventTransferCompleted  DomainEventType = "transfer.completed"
+	EventTransferFailed     DomainEventType = "transfer.failed"
+	EventTransferReversed   DomainEventType = "transfer.reversed"
+	EventBalanceChanged     DomainEventType = "balance.changed"
+)
+
+// Tipos de agregado que originam os eventos
+const (
+	eventAggregateUser        = "user"
+	eventAggregateTransaction = "transaction"
+)
+
+// DomainEvent é um fato ocorrido em uma entidade. Data guarda a carga tipada em JSON, já no
+// formato gravado no outbox; os assinantes a lêem com Decode.
+type DomainEvent struct {
+	ID            string          `json:"id"`
+	Type          DomainEventType `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Decode lê a carga do evento no tipo correspondente, como *TransferCompleted
+func (e *DomainEvent) Decode(payload interface{}) error {
+	return json.Unmarshal(e.Data, payload)
+}
+
+// UserCreated é emitido no cadastro; não leva dados pessoais
+type UserCreated struct {
+	UserID    string    `json:"user_id"`
+	UserType  UserType  `json:"user_type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TransferAuthorized é emitido quando o autorizador externo aprova a transferência
+type TransferAuthorized struct {
+	TransactionID   string          `json:"transaction_id"`
+	PayerID         string          `json:"payer_id"`
+	PayeeID         string          `json:"payee_id"`
+	Amount          decimal.Decimal `json:"amount"`
+	AuthorizationID string          `json:"authorization_id"`
+}
+
+// TransferCompleted é emitido quando os saldos são efetivados
+type TransferCompleted struct {
+	TransactionID string          `json:"transaction_id"`
+	PayerID       string          `json:"payer_id"`
+	PayeeID       string          `json:"payee_id"`
+	Amount        decimal.Decimal `json:"amount"`
+	FeeAmount     decimal.Decimal `json:"fee_amount"`
+	NetAmount     decimal.Decimal `json:"net_amount"`
+	CompletedAt   time.Time       `json:"completed_at"`
+}
+
+// TransferFailed é emitido quando a transferência é negada ou a reserva é liberada
+type TransferFailed struct {
+	TransactionID string          `json:"transaction_id"`
+	PayerID       string          `json:"payer_id"`
+	PayeeID       string          `json:"payee_id"`
+	Amount        decimal.Decimal `json:"amount"`
+	Reason        string          `json:"reason"`
+}
+
+// TransferReversed é emitido no estorno de uma transferência concluída
+type TransferReversed struct {
+	TransactionID string          `json:"transaction_id"`
+	PayerID       string          `json:"payer_id"`
+	PayeeID       string          `json:"payee_id"`
+	Amount        decimal.Decimal `json:"amount"`
+	NetAmount     decimal.Decimal `json:"net_amount"`
+	Reason        string          `json:"reason"`
+	ReversedAt    time.Time       `json:"reversed_at"`
+}
+
+// BalanceChanged é emitido com o saldo resultante sempre que o saldo ou a reserva mudam
+type BalanceChanged struct {
+	UserID      string          `json:"user_id"`
+	Balance     decimal.Decimal `json:"balance"`
+	HeldBalance decimal.Decimal `json:"held_balance"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// EventSource é uma entidade que acumula eventos de domínio
+type EventSource interface {
+	// PullEvents retorna os eventos acumulados e esvazia a lista
+	PullEvents() []*DomainEvent
+}
+
+// EventRecorder acumula os eventos de uma entidade até o caso de uso gravá-los no outbox.
+// Embutido nas entidades, não aparece no JSON nem na trilha de auditoria.
+type EventRecorder struct {
+	events []*DomainEvent
+}
+
+func (r *EventRecorder) recordEvent(eventType DomainEventType, aggregateType, aggregateID string, payload interface{}, at time.Time) {
+	// As cargas são structs simples, a serialização não falha
+	data, _ := json.Marshal(payload)
+	r.events = append(r.events, &DomainEvent{
+		ID:            uuid.New().String(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		OccurredAt:    at.UTC(),
+		Data:          data,
+	})
+}
+
+// discardEvents remove os eventos ainda não publicados do tipo, quando só o último estado importa
+func (r *EventRecorder) discardEvents(eventType DomainEventType) {
+	kept := r.events[:0]
+	for _, event := range r.events {
+		if event.Type != eventType {
+			kept = append(kept, event)
+		}
+	}
+	r.events = kept
+}
+
+// PullEvents retorna os eventos acumulados e esvazia a lista
+func (r *EventRecorder) PullEvents() []*DomainEvent {
+	events := r.events
+	r.events = nil
+	return events
+}
+
+// OutboxRetryPolicy define a espera entre as tentativas de despacho de um evento do outbox.
+// Não há limite de tentativas: o evento fica pendente até todos os assinantes o processarem.
+type OutboxRetryPolicy struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// NextDelay retorna a espera antes da próxima tentativa, depois de attempts tentativas com falha
+func (p OutboxRetryPolicy) NextDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempts-1))
+	if delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// OutboxEvent é o evento gravado na mesma transação da mudança que o originou. HandledBy lista os
+// assinantes que já o processaram, para que uma nova tentativa chame apenas os que falharam.
+type OutboxEvent struct {
+	DomainEvent
+	HandledBy     []string   `json:"handled_by" db:"handled_by"`
+	Attempts      int        `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     *string    `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	DispatchedAt  *time.Time `json:"dispatched_at,omitempty" db:"dispatched_at"`
+	// PublishedAt é quando o evento foi publicado no broker, independente dos assinantes do processo
+	PublishedAt *time.Time `json:"published_at,omitempty" db:"published_at"`
+}
+
+// OutboxBacklog é o tamanho das filas do outbox: eventos que algum assinante do processo ainda não
+// processou e eventos ainda não publicados no broker
+type OutboxBacklog struct {
+	Undispatched int `json:"undispatched"`
+	Unpublished  int `json:"unpublished"`
+}
+
+// NewOutboxEvent prepara o evento para o outbox. O despacho logo após o commit é feito pelo próprio
+// processo; o relay só assume o evento depois de grace, se ele ainda não tiver sido despachado.
+func NewOutboxEvent(event *DomainEvent, grace time.Duration, now time.Time) *OutboxEvent {
+	return &OutboxEvent{
+		DomainEvent:   *event,
+		HandledBy:     []string{},
+		NextAttemptAt: now.Add(grace),
+		CreatedAt:     now,
+	}
+}
+
+// IsHandledBy informa se o assinante já processou o evento
+func (e *OutboxEvent) IsHandledBy(subscriber string) bool {
+	for _, name := range e.HandledBy {
+		if name == subscriber {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkHandled registra que o assinante processou o evento
+func (e *OutboxEvent) MarkHandled(subscriber string) {
+	if !e.IsHandledBy(subscriber) {
+		e.HandledBy = append(e.HandledBy, subscriber)
+	}
+}
+
+// RecordDispatch encerra a tentativa: sem erro, o evento é dado como despachado; com erro, fica
+// pendente para o relay com espera crescente.
+func (e *OutboxEvent) RecordDispatch(dispatchErr error, policy OutboxRetryPolicy, now time.Time) {
+	e.Attempts++
+	if dispatchErr == nil {
+		e.DispatchedAt = &now
+		e.LastError = nil
+		return
+	}
+
+	message := dispatchErr.Error()
+	e.LastError = &message
+	e.NextAttemptAt = now.Add(policy.NextDelay(e.Attempts))
+}
+
+// IsDispatched informa se todos os assinantes já processaram o evento
+func (e *OutboxEvent) IsDispatched() bool {
+	return e.DispatchedAt != nil
+}
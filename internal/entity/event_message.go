@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// EventSchemaVersion é a versão do envelope publicado no broker. Campos novos no envelope ou nas
+// cargas não mudam a versão; remover ou mudar o significado de um campo exige uma versão nova.
+const EventSchemaVersion = 1
+
+// EventMessage é o envelope de um evento de domínio publicado para outros sistemas, descrito em
+// schemas/events/v1.json
+type EventMessage struct {
+	SchemaVersion int             `json:"schema_version"`
+	ID            string          `json:"id"`
+	Type          DomainEventType `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	// PartitionKey é o ID do usuário que originou o evento; eventos com a mesma chave vão para a
+	// mesma partição, na ordem em que foram gravados
+	PartitionKey string          `json:"partition_key"`
+	OccurredAt   time.Time       `json:"occurred_at"`
+	Data         json.RawMessage `json:"data"`
+}
+
+// eventPartitionFields são os campos das cargas que identificam o usuário do evento
+type eventPartitionFields struct {
+	PayerID string `json:"payer_id"`
+	UserID  string `json:"user_id"`
+}
+
+// NewEventMessage monta o envelope publicado de um evento de domínio. A chave de partição é o
+// pagador nas transferências e o próprio usuário nos demais eventos.
+func NewEventMessage(event *DomainEvent) (*EventMessage, error) {
+	var fields eventPartitionFields
+	if err := event.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("erro ao ler usuário do evento %s: %w", event.ID, err)
+	}
+
+	partitionKey := fields.PayerID
+	if partitionKey == "" {
+		partitionKey = fields.UserID
+	}
+	if partitionKey == "" {
+		partitionKey = event.AggregateID
+	}
+
+	return &EventMessage{
+		SchemaVersion: EventSchemaVersion,
+		ID:            event.ID,
+		Type:          event.Type,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		PartitionKey:  partitionKey,
+		OccurredAt:    event.OccurredAt,
+		Data:          event.Data,
+	}, nil
+}
+
+// Encode serializa o envelope em JSON
+func (m *EventMessage) Encode() ([]byte, error) {
+	content, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao codificar evento %s: %w", m.ID, err)
+	}
+	return content, nil
+}
+
+// Partition retorna a partição da chave entre partitions partições, estável entre instâncias
+func (m *EventMessage) Partition(partitions int) int {
+	if partitions <= 1 {
+		return 0
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(m.PartitionKey))
+	return int(hash.Sum32() % uint32(partitions))
+}
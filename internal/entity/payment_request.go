@@ -0,0 +1,221 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type PaymentRequestStatus string
+
+const (
+	PaymentRequestStatusOpen      PaymentRequestStatus = "open"
+	PaymentRequestStatusPaid      PaymentRequestStatus = "paid"
+	PaymentRequestStatusExpired   PaymentRequestStatus = "expired"
+	PaymentRequestStatusCancelled PaymentRequestStatus = "cancelled"
+)
+
+// BRCodeSettings define os dados da plataforma usados nos QR Codes das cobranças
+type BRCodeSettings struct {
+	// LocationURL é a base (sem esquema) das URLs de cobranças dinâmicas
+	LocationURL  string
+	MerchantCity string
+}
+
+// PaymentRequestURL retorna a URL dinâmica da cobrança
+func (s BRCodeSettings) PaymentRequestURL(txID string) string {
+	return strings.TrimSuffix(s.LocationURL, "/") + "/" + txID
+}
+
+// TxIDFromURL extrai o txid de uma URL dinâmica emitida pela plataforma
+func (s BRCodeSettings) TxIDFromURL(url string) (string, bool) {
+	prefix := strings.TrimSuffix(s.LocationURL, "/") + "/"
+	if !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(url, prefix), true
+}
+
+// MaxPaymentRequestExpiry é o prazo máximo de validade de uma cobrança
+const MaxPaymentRequestExpiry = 90 * 24 * time.Hour
+
+// PaymentRequest é uma cobrança emitida por um lojista. Quando PayerID é informado,
+// apenas esse usuário pode pagá-la. TransactionID aponta para a última tentativa de pagamento.
+// TxID identifica a cobrança nos QR Codes (BR Code).
+type PaymentRequest struct {
+	ID            string               `json:"id" db:"id"`
+	TxID          string               `json:"txid" db:"txid"`
+	MerchantID    string               `json:"merchant_id" db:"merchant_id"`
+	PayerID       *string              `json:"payer_id,omitempty" db:"payer_id"`
+	Amount        decimal.Decimal      `json:"amount" db:"amount"`
+	Description   string               `json:"description" db:"description"`
+	Status        PaymentRequestStatus `json:"status" db:"status"`
+	ExpiresAt     time.Time            `json:"expires_at" db:"expires_at"`
+	TransactionID *string              `json:"transaction_id,omitempty" db:"transaction_id"`
+	PaidBy        *string              `json:"paid_by,omitempty" db:"paid_by"`
+	PaidAt        *time.Time           `json:"paid_at,omitempty" db:"paid_at"`
+	CreatedAt     time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+func NewPaymentRequest(merchantID string, payerID *string, amount decimal.Decimal, description string, expiresAt time.Time) (*PaymentRequest, error) {
+	id := uuid.New().String()
+	request := &PaymentRequest{
+		ID:          id,
+		TxID:        newTxID(id),
+		MerchantID:  merchantID,
+		PayerID:     payerID,
+		Amount:      amount,
+		Description: description,
+		Status:      PaymentRequestStatusOpen,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (p *PaymentRequest) Validate() error {
+	if p.MerchantID == "" {
+		return errors.New("lojista é obrigatório")
+	}
+
+	if p.PayerID != nil && *p.PayerID == p.MerchantID {
+		return ErrSelfTransfer
+	}
+
+	if p.Amount.LessThanOrEqual(decimal.Zero) {
+		return errors.New("valor da cobrança deve ser maior que zero")
+	}
+
+	if len(p.Description) > 140 {
+		return errors.New("descrição deve ter no máximo 140 caracteres")
+	}
+
+	if !p.ExpiresAt.After(p.CreatedAt) {
+		return errors.New("data de expiração deve ser futura")
+	}
+
+	if p.ExpiresAt.Sub(p.CreatedAt) > MaxPaymentRequestExpiry {
+		return errors.New("data de expiração excede o prazo máximo de 90 dias")
+	}
+
+	return nil
+}
+
+// ValidateMerchant garante que apenas lojistas emitem cobranças
+func (p *PaymentRequest) ValidateMerchant(merchant *User) error {
+	if !merchant.IsMerchant() {
+		return ErrOnlyMerchantsCanCharge
+	}
+	return nil
+}
+
+// CanBePaidBy verifica se a cobrança está aberta, dentro da validade e destinada ao pagador
+func (p *PaymentRequest) CanBePaidBy(payerID string, now time.Time) error {
+	switch p.Status {
+	case PaymentRequestStatusOpen:
+	case PaymentRequestStatusPaid:
+		return ErrPaymentRequestAlreadyPaid
+	case PaymentRequestStatusExpired:
+		return ErrPaymentRequestExpired
+	default:
+		return ErrPaymentRequestNotOpen
+	}
+
+	if p.IsExpired(now) {
+		return ErrPaymentRequestExpired
+	}
+
+	if p.PayerID != nil && *p.PayerID != payerID {
+		return ErrPaymentRequestNotFound
+	}
+
+	return nil
+}
+
+// AttachTransaction vincula a tentativa de pagamento em andamento
+func (p *PaymentRequest) AttachTransaction(transactionID string) {
+	p.TransactionID = &transactionID
+	p.UpdatedAt = time.Now()
+}
+
+// MarkPaid registra a transação concluída que quitou a cobrança
+func (p *PaymentRequest) MarkPaid(transaction *Transaction) error {
+	if p.Status != PaymentRequestStatusOpen {
+		return ErrPaymentRequestNotOpen
+	}
+	if !transaction.IsCompleted() {
+		return ErrTransactionNotCompleted
+	}
+
+	now := time.Now()
+	p.Status = PaymentRequestStatusPaid
+	p.TransactionID = &transaction.ID
+	p.PaidBy = &transaction.PayerID
+	p.PaidAt = &now
+	p.UpdatedAt = now
+	return nil
+}
+
+func (p *PaymentRequest) Cancel() error {
+	if p.Status != PaymentRequestStatusOpen {
+		return ErrPaymentRequestNotOpen
+	}
+	p.Status = PaymentRequestStatusCancelled
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+func (p *PaymentRequest) Expire() error {
+	if p.Status != PaymentRequestStatusOpen {
+		return ErrPaymentRequestNotOpen
+	}
+	p.Status = PaymentRequestStatusExpired
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+func (p *PaymentRequest) IsExpired(now time.Time) bool {
+	return !now.Before(p.ExpiresAt)
+}
+
+// IsVisibleTo indica se o usuário pode consultar a cobrança: o lojista emissor,
+// o pagador indicado ou qualquer usuário quando a cobrança não tem pagador definido
+func (p *PaymentRequest) IsVisibleTo(userID string) bool {
+	if p.MerchantID == userID {
+		return true
+	}
+	if p.PaidBy != nil && *p.PaidBy == userID {
+		return true
+	}
+	return p.PayerID == nil || *p.PayerID == userID
+}
+
+func (p *PaymentRequest) GetStatusDescription() string {
+	switch p.Status {
+	case PaymentRequestStatusOpen:
+		return "Aberta"
+	case PaymentRequestStatusPaid:
+		return "Paga"
+	case PaymentRequestStatusExpired:
+		return "Expirada"
+	case PaymentRequestStatusCancelled:
+		return "Cancelada"
+	default:
+		return "Status desconhecido"
+	}
+}
+
+// newTxID deriva do ID um txid alfanumérico de 25 caracteres, o limite do BR Code estático
+func newTxID(id string) string {
+	return strings.ToUpper(strings.ReplaceAll(id, "-", ""))[:25]
+}
@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PixKeyType string
+
+const (
+	PixKeyTypeCPF   PixKeyType = "cpf"
+	PixKeyTypeCNPJ  PixKeyType = "cnpj"
+	PixKeyTypeEmail PixKeyType = "email"
+	PixKeyTypePhone PixKeyType = "phone"
+	PixKeyTypeEVP   PixKeyType = "evp"
+)
+
+// Quantidade máxima de chaves por tipo de conta, como no DICT
+const (
+	MaxPixKeysCommon   = 5
+	MaxPixKeysMerchant = 20
+)
+
+var phoneE164Regex = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// PixKey é uma chave que identifica o usuário como recebedor de transferências
+type PixKey struct {
+	ID        string     `json:"id" db:"id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	KeyType   PixKeyType `json:"key_type" db:"key_type"`
+	KeyValue  string     `json:"key" db:"key_value"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NewPixKey cria uma chave para o usuário, validando que CPF, CNPJ e email pertencem a ele.
+// Para chaves aleatórias (EVP) o valor informado é ignorado e um UUID é gerado.
+func NewPixKey(owner *User, keyType PixKeyType, value string) (*PixKey, error) {
+	key := &PixKey{
+		ID:        uuid.New().String(),
+		UserID:    owner.ID,
+		KeyType:   keyType,
+		CreatedAt: time.Now(),
+	}
+
+	switch keyType {
+	case PixKeyTypeCPF, PixKeyTypeCNPJ:
+		key.KeyValue = cleanDocument(value)
+		expectedLength := 11
+		if keyType == PixKeyTypeCNPJ {
+			expectedLength = 14
+		}
+		if len(key.KeyValue) != expectedLength {
+			return nil, ErrInvalidPixKey
+		}
+		if key.KeyValue != owner.Document {
+			return nil, ErrPixKeyNotOwned
+		}
+	case PixKeyTypeEmail:
+		key.KeyValue = strings.ToLower(strings.TrimSpace(value))
+		if key.KeyValue != owner.Email {
+			return nil, ErrPixKeyNotOwned
+		}
+	case PixKeyTypePhone:
+		key.KeyValue = NormalizePixKey(value)
+		if !phoneE164Regex.MatchString(key.KeyValue) {
+			return nil, ErrInvalidPixKey
+		}
+	case PixKeyTypeEVP:
+		key.KeyValue = uuid.New().String()
+	default:
+		return nil, ErrInvalidPixKeyType
+	}
+
+	return key, nil
+}
+
+// MaxPixKeys retorna quantas chaves o tipo de conta pode cadastrar
+func MaxPixKeys(userType UserType) int {
+	if userType == UserTypeMerchant {
+		return MaxPixKeysMerchant
+	}
+	return MaxPixKeysCommon
+}
+
+// NormalizePixKey coloca a chave no formato armazenado, para que a busca não dependa
+// de pontuação, espaços ou caixa
+func NormalizePixKey(value string) string {
+	value = strings.TrimSpace(value)
+
+	switch {
+	case strings.Contains(value, "@"):
+		return strings.ToLower(value)
+	case strings.HasPrefix(value, "+"):
+		return "+" + cleanDocument(value)
+	case uuid.Validate(value) == nil:
+		return strings.ToLower(value)
+	default:
+		if digits := cleanDocument(value); len(digits) == 11 || len(digits) == 14 {
+			return digits
+		}
+		return value
+	}
+}
@@ -0,0 +1,190 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type FeeType string
+
+const (
+	FeeTypeFixed      FeeType = "fixed"
+	FeeTypePercentage FeeType = "percentage"
+	FeeTypeTiered     FeeType = "tiered"
+)
+
+// RevenueAccountCode identifica a conta da plataforma que recebe as tarifas
+const RevenueAccountCode = "revenue"
+
+// FeeTier é uma faixa de valor da tarifa escalonada; UpTo nulo indica faixa sem teto
+type FeeTier struct {
+	UpTo        *decimal.Decimal `json:"up_to,omitempty"`
+	FixedAmount decimal.Decimal  `json:"fixed_amount"`
+	Percentage  decimal.Decimal  `json:"percentage"`
+}
+
+// PricingPlan define como a tarifa é calculada sobre o valor recebido pelo recebedor (MDR)
+type PricingPlan struct {
+	ID          string           `json:"id" db:"id"`
+	Name        string           `json:"name" db:"name"`
+	FeeType     FeeType          `json:"fee_type" db:"fee_type"`
+	FixedAmount decimal.Decimal  `json:"fixed_amount" db:"fixed_amount"`
+	Percentage  decimal.Decimal  `json:"percentage" db:"percentage"`
+	Tiers       []FeeTier        `json:"tiers,omitempty" db:"tiers"`
+	MinFee      *decimal.Decimal `json:"min_fee,omitempty" db:"min_fee"`
+	MaxFee      *decimal.Decimal `json:"max_fee,omitempty" db:"max_fee"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// FeeEntry registra um lançamento na conta de receita da plataforma: a tarifa de uma transação
+// ou a parte da plataforma em um pagamento dividido
+type FeeEntry struct {
+	ID             string          `json:"id" db:"id"`
+	TransactionID  *string         `json:"transaction_id,omitempty" db:"transaction_id"`
+	SplitPaymentID *string         `json:"split_payment_id,omitempty" db:"split_payment_id"`
+	PricingPlanID  *string         `json:"pricing_plan_id,omitempty" db:"pricing_plan_id"`
+	AccountCode    string          `json:"account_code" db:"account_code"`
+	Amount         decimal.Decimal `json:"amount" db:"amount"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+}
+
+// PlatformAccount é uma conta contábil interna da plataforma
+type PlatformAccount struct {
+	Code      string          `json:"code" db:"code"`
+	Balance   decimal.Decimal `json:"balance" db:"balance"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+var hundred = decimal.NewFromInt(100)
+
+func NewPricingPlan(name string, feeType FeeType, fixedAmount, percentage decimal.Decimal, tiers []FeeTier, minFee, maxFee *decimal.Decimal) (*PricingPlan, error) {
+	plan := &PricingPlan{
+		ID:          uuid.New().String(),
+		Name:        name,
+		FeeType:     feeType,
+		FixedAmount: fixedAmount,
+		Percentage:  percentage,
+		Tiers:       tiers,
+		MinFee:      minFee,
+		MaxFee:      maxFee,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (p *PricingPlan) Validate() error {
+	if len(p.Name) < 3 {
+		return errors.New("nome do plano deve ter pelo menos 3 caracteres")
+	}
+
+	switch p.FeeType {
+	case FeeTypeFixed:
+		if p.FixedAmount.LessThan(decimal.Zero) {
+			return errors.New("tarifa fixa não pode ser negativa")
+		}
+	case FeeTypePercentage:
+		if err := validatePercentage(p.Percentage); err != nil {
+			return err
+		}
+	case FeeTypeTiered:
+		if len(p.Tiers) == 0 {
+			return errors.New("plano escalonado precisa de ao menos uma faixa")
+		}
+		for i, tier := range p.Tiers {
+			if err := validatePercentage(tier.Percentage); err != nil {
+				return err
+			}
+			if tier.FixedAmount.LessThan(decimal.Zero) {
+				return errors.New("tarifa fixa da faixa não pode ser negativa")
+			}
+			isLast := i == len(p.Tiers)-1
+			if tier.UpTo == nil && !isLast {
+				return errors.New("apenas a última faixa pode ficar sem teto")
+			}
+			if i > 0 && tier.UpTo != nil && !tier.UpTo.GreaterThan(*p.Tiers[i-1].UpTo) {
+				return errors.New("faixas devem estar em ordem crescente de valor")
+			}
+		}
+	default:
+		return ErrInvalidFeeType
+	}
+
+	if p.MinFee != nil && p.MaxFee != nil && p.MinFee.GreaterThan(*p.MaxFee) {
+		return errors.New("tarifa mínima não pode ser maior que a máxima")
+	}
+
+	return nil
+}
+
+// CalculateFee calcula a tarifa sobre o valor bruto, arredondada em centavos
+func (p *PricingPlan) CalculateFee(amount decimal.Decimal) decimal.Decimal {
+	var fee decimal.Decimal
+
+	switch p.FeeType {
+	case FeeTypeFixed:
+		fee = p.FixedAmount
+	case FeeTypePercentage:
+		fee = amount.Mul(p.Percentage).Div(hundred)
+	case FeeTypeTiered:
+		tier := p.tierFor(amount)
+		fee = tier.FixedAmount.Add(amount.Mul(tier.Percentage).Div(hundred))
+	}
+
+	if p.MinFee != nil && fee.LessThan(*p.MinFee) {
+		fee = *p.MinFee
+	}
+	if p.MaxFee != nil && fee.GreaterThan(*p.MaxFee) {
+		fee = *p.MaxFee
+	}
+
+	return fee.Round(2)
+}
+
+func (p *PricingPlan) tierFor(amount decimal.Decimal) FeeTier {
+	for _, tier := range p.Tiers {
+		if tier.UpTo == nil || amount.LessThanOrEqual(*tier.UpTo) {
+			return tier
+		}
+	}
+	return p.Tiers[len(p.Tiers)-1]
+}
+
+func validatePercentage(percentage decimal.Decimal) error {
+	if percentage.LessThan(decimal.Zero) || percentage.GreaterThan(hundred) {
+		return errors.New("percentual da tarifa deve estar entre 0 e 100")
+	}
+	return nil
+}
+
+func NewFeeEntry(transaction *Transaction) *FeeEntry {
+	return &FeeEntry{
+		ID:            uuid.New().String(),
+		TransactionID: &transaction.ID,
+		PricingPlanID: transaction.PricingPlanID,
+		AccountCode:   RevenueAccountCode,
+		Amount:        transaction.FeeAmount,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// NewFeeReversalEntry estorna da conta de receita a tarifa de uma transação revertida
+func NewFeeReversalEntry(transaction *Transaction) *FeeEntry {
+	return NewFeeRefundEntry(transaction, transaction.FeeAmount)
+}
+
+// NewFeeRefundEntry estorna da conta de receita parte da tarifa de uma transação
+func NewFeeRefundEntry(transaction *Transaction, fee decimal.Decimal) *FeeEntry {
+	entry := NewFeeEntry(transaction)
+	entry.Amount = fee.Neg()
+	return entry
+}
@@ -0,0 +1,410 @@
+package entity
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"payflow-api/pkg/pdf"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReceiptVersion identifica o layout do conteúdo assinado
+const ReceiptVersion = "1"
+
+// ReceiptAlgorithm é o algoritmo das assinaturas dos comprovantes
+const ReceiptAlgorithm = "Ed25519"
+
+// ReceiptFormat é o formato de saída do comprovante
+type ReceiptFormat string
+
+const (
+	ReceiptFormatJSON ReceiptFormat = "json"
+	ReceiptFormatPDF  ReceiptFormat = "pdf"
+)
+
+// ParseReceiptFormat valida o formato pedido; vazio significa JSON
+func ParseReceiptFormat(value string) (ReceiptFormat, error) {
+	switch format := ReceiptFormat(strings.ToLower(value)); format {
+	case "":
+		return ReceiptFormatJSON, nil
+	case ReceiptFormatJSON, ReceiptFormatPDF:
+		return format, nil
+	default:
+		return "", ErrInvalidReceiptFormat
+	}
+}
+
+// ReceiptParty identifica pagador ou recebedor com nome e documento mascarados, já que o
+// comprovante assinado não pode ser anonimizado depois da exclusão da conta
+type ReceiptParty struct {
+	Name     string `json:"name"`
+	Document string `json:"document"`
+}
+
+// ReceiptPayload é o conteúdo assinado do comprovante
+type ReceiptPayload struct {
+	Version            string       `json:"version"`
+	AuthenticationCode string       `json:"authentication_code"`
+	TransactionID      string       `json:"transaction_id"`
+	Amount             string       `json:"amount"`
+	Currency           string       `json:"currency"`
+	Payer              ReceiptParty `json:"payer"`
+	Payee              ReceiptParty `json:"payee"`
+	AuthorizationID    string       `json:"authorization_id,omitempty"`
+	CreatedAt          time.Time    `json:"created_at"`
+	CompletedAt        time.Time    `json:"completed_at"`
+	IssuedAt           time.Time    `json:"issued_at"`
+}
+
+// NewReceiptPayload monta o conteúdo do comprovante de uma transferência concluída. O código de
+// autenticação é aleatório; a unicidade é garantida pelo banco.
+func NewReceiptPayload(transaction *Transaction, payer, payee *User, now time.Time) (*ReceiptPayload, error) {
+	if transaction.CompletedAt == nil || !(transaction.IsCompleted() || transaction.IsReversed()) {
+		return nil, ErrTransactionNotCompleted
+	}
+
+	code, err := newAuthenticationCode()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &ReceiptPayload{
+		Version:            ReceiptVersion,
+		AuthenticationCode: code,
+		TransactionID:      transaction.ID,
+		Amount:             transaction.Amount.StringFixed(2),
+		Currency:           "BRL",
+		Payer:              ReceiptParty{Name: payer.maskName(), Document: payer.maskDocument()},
+		Payee:              ReceiptParty{Name: payee.maskName(), Document: payee.maskDocument()},
+		CreatedAt:          transaction.CreatedAt.UTC().Truncate(time.Microsecond),
+		CompletedAt:        transaction.CompletedAt.UTC().Truncate(time.Microsecond),
+		IssuedAt:           now.UTC().Truncate(time.Microsecond),
+	}
+	if transaction.AuthorizationID != nil {
+		payload.AuthorizationID = *transaction.AuthorizationID
+	}
+
+	return payload, nil
+}
+
+// newAuthenticationCode gera 80 bits aleatórios em cinco grupos hexadecimais (XXXX-XXXX-XXXX-XXXX-XXXX)
+func newAuthenticationCode() (string, error) {
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("erro ao gerar código de autenticação: %w", err)
+	}
+
+	encoded := strings.ToUpper(hex.EncodeToString(raw))
+	groups := make([]string, 0, 5)
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// NormalizeAuthenticationCode aceita o código em minúsculas e sem hífens, como digitado
+func NormalizeAuthenticationCode(code string) string {
+	compact := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(compact) != 20 {
+		return strings.ToUpper(strings.TrimSpace(code))
+	}
+
+	groups := make([]string, 0, 5)
+	for i := 0; i < len(compact); i += 4 {
+		groups = append(groups, compact[i:i+4])
+	}
+	return strings.Join(groups, "-")
+}
+
+// Receipt é o comprovante emitido: os bytes exatos que foram assinados e a assinatura.
+// É gravado uma única vez, para que o comprovante não mude se os dados cadastrais mudarem.
+type Receipt struct {
+	TransactionID      string    `json:"transaction_id" db:"transaction_id"`
+	AuthenticationCode string    `json:"authentication_code" db:"authentication_code"`
+	Payload            []byte    `json:"-" db:"payload"`
+	Signature          []byte    `json:"-" db:"signature"`
+	KeyID              string    `json:"key_id" db:"key_id"`
+	IssuedAt           time.Time `json:"issued_at" db:"issued_at"`
+}
+
+// Content decodifica o conteúdo assinado
+func (r *Receipt) Content() (*ReceiptPayload, error) {
+	payload := &ReceiptPayload{}
+	if err := json.Unmarshal(r.Payload, payload); err != nil {
+		return nil, fmt.Errorf("erro ao ler conteúdo do comprovante: %w", err)
+	}
+	return payload, nil
+}
+
+// ReceiptSigner assina comprovantes com a chave Ed25519 atual e os confere com ela ou com as
+// chaves públicas já aposentadas, para que os comprovantes emitidos antes da troca continuem válidos
+type ReceiptSigner struct {
+	privateKey ed25519.PrivateKey
+	keyID      string
+	keys       map[string]ed25519.PublicKey
+}
+
+// NewReceiptSigner recebe a chave atual e as chaves públicas das chaves anteriores
+func NewReceiptSigner(privateKey ed25519.PrivateKey, previous ...ed25519.PublicKey) *ReceiptSigner {
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	signer := &ReceiptSigner{
+		privateKey: privateKey,
+		keyID:      ReceiptKeyID(publicKey),
+		keys:       map[string]ed25519.PublicKey{},
+	}
+	for _, key := range previous {
+		signer.keys[ReceiptKeyID(key)] = key
+	}
+	signer.keys[signer.keyID] = publicKey
+	return signer
+}
+
+// ReceiptKeyID identifica a chave pública pelos primeiros 8 bytes do seu SHA-256
+func ReceiptKeyID(publicKey ed25519.PublicKey) string {
+	digest := sha256.Sum256(publicKey)
+	return hex.EncodeToString(digest[:8])
+}
+
+// ParseReceiptSigningKey lê a chave em base64: a semente de 32 bytes ou a chave privada de 64 bytes
+func ParseReceiptSigningKey(encoded string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("chave de assinatura de comprovantes não está em base64: %w", err)
+	}
+
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("chave de assinatura de comprovantes deve ter %d ou %d bytes", ed25519.SeedSize, ed25519.PrivateKeySize)
+	}
+}
+
+// ParseReceiptPublicKeys lê as chaves públicas anteriores, em base64 e separadas por vírgula
+func ParseReceiptPublicKeys(encoded string) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	for _, item := range strings.Split(encoded, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(item)
+		if err != nil {
+			return nil, fmt.Errorf("chave pública de comprovantes não está em base64: %w", err)
+		}
+		if len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("chave pública de comprovantes deve ter %d bytes", ed25519.PublicKeySize)
+		}
+		keys = append(keys, ed25519.PublicKey(raw))
+	}
+	return keys, nil
+}
+
+// KeyID identifica a chave pública atual, gravada nos comprovantes emitidos
+func (s *ReceiptSigner) KeyID() string {
+	return s.keyID
+}
+
+func (s *ReceiptSigner) PublicKey() ed25519.PublicKey {
+	return s.keys[s.keyID]
+}
+
+// KnowsKey informa se a chave faz parte das chaves conhecidas, atual ou anteriores
+func (s *ReceiptSigner) KnowsKey(keyID string) bool {
+	_, ok := s.keys[keyID]
+	return ok
+}
+
+// PublicKeys retorna todas as chaves conhecidas, a atual primeiro e as anteriores por key_id
+func (s *ReceiptSigner) PublicKeys() []ReceiptPublicKeyResponse {
+	keys := []ReceiptPublicKeyResponse{newReceiptPublicKeyResponse(s.keyID, s.PublicKey())}
+	var previous []string
+	for keyID := range s.keys {
+		if keyID != s.keyID {
+			previous = append(previous, keyID)
+		}
+	}
+	sort.Strings(previous)
+	for _, keyID := range previous {
+		keys = append(keys, newReceiptPublicKeyResponse(keyID, s.keys[keyID]))
+	}
+	return keys
+}
+
+// Issue serializa e assina o conteúdo
+func (s *ReceiptSigner) Issue(payload *ReceiptPayload) (*Receipt, error) {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar comprovante: %w", err)
+	}
+
+	return &Receipt{
+		TransactionID:      payload.TransactionID,
+		AuthenticationCode: payload.AuthenticationCode,
+		Payload:            content,
+		Signature:          ed25519.Sign(s.privateKey, content),
+		KeyID:              s.keyID,
+		IssuedAt:           payload.IssuedAt,
+	}, nil
+}
+
+// Verify confere a assinatura sobre os bytes exatos do conteúdo
+// com a chave indicada por keyID
+func (s *ReceiptSigner) Verify(keyID string, payload, signature []byte) bool {
+	publicKey, ok := s.keys[keyID]
+	if !ok {
+		return false
+	}
+	return ed25519.Verify(publicKey, payload, signature)
+}
+
+// ReceiptDocument é o comprovante em JSON: o conteúdo legível e, para conferência, os bytes assinados
+// e a assinatura em base64
+type ReceiptDocument struct {
+	Receipt   *ReceiptPayload `json:"receipt"`
+	Payload   string          `json:"payload"`
+	Signature string          `json:"signature"`
+	KeyID     string          `json:"key_id"`
+	Algorithm string          `json:"algorithm"`
+}
+
+func (r *Receipt) ToReceiptDocument() (*ReceiptDocument, error) {
+	content, err := r.Content()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReceiptDocument{
+		Receipt:   content,
+		Payload:   base64.StdEncoding.EncodeToString(r.Payload),
+		Signature: base64.StdEncoding.EncodeToString(r.Signature),
+		KeyID:     r.KeyID,
+		Algorithm: ReceiptAlgorithm,
+	}, nil
+}
+
+// VerifyReceiptRequest traz os campos payload, signature e key_id de um comprovante em JSON
+type VerifyReceiptRequest struct {
+	Payload   string `json:"payload" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+	KeyID     string `json:"key_id" binding:"required"`
+}
+
+// ReceiptVerificationResponse é o resultado da conferência; um comprovante inválido não é erro
+type ReceiptVerificationResponse struct {
+	Valid             bool              `json:"valid"`
+	Reason            string            `json:"reason,omitempty"`
+	TransactionStatus TransactionStatus `json:"transaction_status,omitempty"`
+	StatusDescription string            `json:"status_description,omitempty"`
+}
+
+// ReceiptPublicKeyResponse publica a chave usada para conferir os comprovantes fora da API e, em
+// previous_keys, as chaves anteriores que ainda conferem os comprovantes emitidos com elas
+type ReceiptPublicKeyResponse struct {
+	KeyID        string                     `json:"key_id"`
+	Algorithm    string                     `json:"algorithm"`
+	PublicKey    string                     `json:"public_key"`
+	PreviousKeys []ReceiptPublicKeyResponse `json:"previous_keys,omitempty"`
+}
+
+func newReceiptPublicKeyResponse(keyID string, publicKey ed25519.PublicKey) ReceiptPublicKeyResponse {
+	return ReceiptPublicKeyResponse{
+		KeyID:     keyID,
+		Algorithm: ReceiptAlgorithm,
+		PublicKey: base64.StdEncoding.EncodeToString(publicKey),
+	}
+}
+
+// Layout do PDF do comprovante
+const (
+	receiptPDFMargin     = 60.0
+	receiptPDFLabelX     = 60.0
+	receiptPDFValueX     = 200.0
+	receiptPDFLineHeight = 18.0
+)
+
+// PDF gera o comprovante em uma página A4, com o código de autenticação e a assinatura
+func (r *Receipt) PDF() ([]byte, error) {
+	content, err := r.Content()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := pdf.New()
+	doc.AddPage()
+	y := pdf.A4Height - receiptPDFMargin
+
+	doc.Text(receiptPDFMargin, y, pdf.HelveticaBold, 16, "PayFlow - Comprovante de transferência")
+	y -= 32
+
+	field := func(label, value string) {
+		doc.Text(receiptPDFLabelX, y, pdf.HelveticaBold, 10, label)
+		doc.Text(receiptPDFValueX, y, pdf.Helvetica, 10, value)
+		y -= receiptPDFLineHeight
+	}
+	section := func(title string) {
+		y -= 6
+		doc.Text(receiptPDFMargin, y, pdf.HelveticaBold, 12, title)
+		y -= 4
+		doc.Line(receiptPDFMargin, y, pdf.A4Width-receiptPDFMargin, y)
+		y -= receiptPDFLineHeight
+	}
+
+	amount, err := decimal.NewFromString(content.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler valor do comprovante: %w", err)
+	}
+	field("Valor", FormatAmount(amount))
+	field("Data da transferência", content.CompletedAt.UTC().Format("02/01/2006 15:04:05")+" UTC")
+	field("ID da transação", content.TransactionID)
+	if content.AuthorizationID != "" {
+		field("Autorização", content.AuthorizationID)
+	}
+
+	section("Pagador")
+	field("Nome", content.Payer.Name)
+	field("Documento", content.Payer.Document)
+
+	section("Recebedor")
+	field("Nome", content.Payee.Name)
+	field("Documento", content.Payee.Document)
+
+	section("Autenticação")
+	field("Código", content.AuthenticationCode)
+	field("Emitido em", content.IssuedAt.UTC().Format("02/01/2006 15:04:05")+" UTC")
+	field("Chave", r.KeyID+" ("+ReceiptAlgorithm+")")
+
+	// A assinatura em base64 não cabe em uma linha
+	signature := base64.StdEncoding.EncodeToString(r.Signature)
+	doc.Text(receiptPDFLabelX, y, pdf.HelveticaBold, 10, "Assinatura")
+	for len(signature) > 0 {
+		n := 60
+		if len(signature) < n {
+			n = len(signature)
+		}
+		doc.Text(receiptPDFValueX, y, pdf.Courier, 8, signature[:n])
+		signature = signature[n:]
+		y -= 12
+	}
+
+	y -= 12
+	doc.Text(receiptPDFMargin, y, pdf.Helvetica, 8, "Confira a autenticidade em /api/v1/receipts/"+content.AuthenticationCode)
+
+	return doc.Bytes(), nil
+}
+
+// FileName retorna o nome sugerido para o arquivo do comprovante
+func (r *Receipt) FileName(format ReceiptFormat) string {
+	return "comprovante-" + r.TransactionID + "." + string(format)
+}
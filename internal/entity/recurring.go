@@ -0,0 +1,317 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"payflow-api/pkg/cron"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceMonthly RecurrenceFrequency = "monthly"
+	RecurrenceCron    RecurrenceFrequency = "cron"
+)
+
+type MandateStatus string
+
+const (
+	MandateStatusActive    MandateStatus = "active"
+	MandateStatusPaused    MandateStatus = "paused"
+	MandateStatusCancelled MandateStatus = "cancelled"
+	MandateStatusCompleted MandateStatus = "completed"
+)
+
+// RecurringMandate é uma ordem de pagamento recorrente de um usuário comum para um lojista.
+// As ocorrências são calculadas em UTC a partir da data de início.
+type RecurringMandate struct {
+	ID             string              `json:"id" db:"id"`
+	PayerID        string              `json:"payer_id" db:"payer_id"`
+	PayeeID        string              `json:"payee_id" db:"payee_id"`
+	Amount         decimal.Decimal     `json:"amount" db:"amount"`
+	Description    string              `json:"description" db:"description"`
+	Frequency      RecurrenceFrequency `json:"frequency" db:"frequency"`
+	CronExpression *string             `json:"cron_expression,omitempty" db:"cron_expression"`
+	StartDate      time.Time           `json:"start_date" db:"start_date"`
+	EndDate        *time.Time          `json:"end_date,omitempty" db:"end_date"`
+	MaxOccurrences *int                `json:"max_occurrences,omitempty" db:"max_occurrences"`
+	Occurrences    int                 `json:"occurrences" db:"occurrences"`
+	NextRunAt      *time.Time          `json:"next_run_at,omitempty" db:"next_run_at"`
+	LastRunAt      *time.Time          `json:"last_run_at,omitempty" db:"last_run_at"`
+	RetryAt        *time.Time          `json:"retry_at,omitempty" db:"retry_at"`
+	Status         MandateStatus       `json:"status" db:"status"`
+	CreatedAt      time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at" db:"updated_at"`
+}
+
+// MandateRun registra a execução de um período do mandato e a transação gerada
+type MandateRun struct {
+	ID            string    `json:"id" db:"id"`
+	MandateID     string    `json:"mandate_id" db:"mandate_id"`
+	PeriodKey     string    `json:"period_key" db:"period_key"`
+	OccurrenceAt  time.Time `json:"occurrence_at" db:"occurrence_at"`
+	TransactionID string    `json:"transaction_id" db:"transaction_id"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+
+	TransactionStatus TransactionStatus `json:"transaction_status" db:"-"`
+	FailureReason     *string           `json:"failure_reason,omitempty" db:"-"`
+}
+
+func NewRecurringMandate(payerID, payeeID string, amount decimal.Decimal, description string, frequency RecurrenceFrequency, cronExpression *string, startDate time.Time, endDate *time.Time, maxOccurrences *int) (*RecurringMandate, error) {
+	now := time.Now()
+	mandate := &RecurringMandate{
+		ID:             uuid.New().String(),
+		PayerID:        payerID,
+		PayeeID:        payeeID,
+		Amount:         amount,
+		Description:    description,
+		Frequency:      frequency,
+		CronExpression: cronExpression,
+		StartDate:      startDate.UTC(),
+		EndDate:        endDate,
+		MaxOccurrences: maxOccurrences,
+		Status:         MandateStatusActive,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := mandate.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Ocorrências anteriores à criação não são cobradas
+	from := mandate.StartDate
+	if from.Before(now) {
+		from = now
+	}
+	if err := mandate.scheduleFrom(from.Add(-time.Nanosecond)); err != nil {
+		return nil, err
+	}
+
+	return mandate, nil
+}
+
+func (m *RecurringMandate) Validate() error {
+	if m.PayerID == "" || m.PayeeID == "" {
+		return errors.New("pagador e recebedor são obrigatórios")
+	}
+
+	if m.PayerID == m.PayeeID {
+		return ErrSelfTransfer
+	}
+
+	if m.Amount.LessThanOrEqual(decimal.Zero) {
+		return errors.New("valor da recorrência deve ser maior que zero")
+	}
+
+	if len(m.Description) > 140 {
+		return errors.New("descrição deve ter no máximo 140 caracteres")
+	}
+
+	switch m.Frequency {
+	case RecurrenceWeekly, RecurrenceMonthly:
+	case RecurrenceCron:
+		if m.CronExpression == nil {
+			return errors.New("expressão cron é obrigatória para frequência cron")
+		}
+		if _, err := cron.Parse(*m.CronExpression); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+		}
+	default:
+		return ErrInvalidRecurrence
+	}
+
+	if m.EndDate != nil && m.EndDate.Before(m.StartDate) {
+		return errors.New("data final deve ser posterior à data de início")
+	}
+
+	if m.MaxOccurrences != nil && *m.MaxOccurrences <= 0 {
+		return errors.New("quantidade máxima de ocorrências deve ser maior que zero")
+	}
+
+	return nil
+}
+
+// ValidateParties garante que o mandato vai de um usuário comum para um lojista
+func (m *RecurringMandate) ValidateParties(payer, payee *User) error {
+	if err := payer.ValidateCanSend(); err != nil {
+		return err
+	}
+
+	if !payee.IsMerchant() {
+		return ErrMandatePayeeNotMerchant
+	}
+
+	if err := payee.ValidateCanReceive(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// OccurrenceAfter retorna a primeira ocorrência estritamente posterior a t
+func (m *RecurringMandate) OccurrenceAfter(t time.Time) time.Time {
+	t = t.UTC()
+	start := m.StartDate.UTC()
+
+	if t.Before(start) && m.Frequency != RecurrenceCron {
+		return start
+	}
+
+	switch m.Frequency {
+	case RecurrenceWeekly:
+		week := 7 * 24 * time.Hour
+		n := t.Sub(start)/week + 1
+		return start.Add(n * week)
+	case RecurrenceMonthly:
+		for n := 1; ; n++ {
+			occurrence := addMonthsClamped(start, n)
+			if occurrence.After(t) {
+				return occurrence
+			}
+		}
+	case RecurrenceCron:
+		schedule, err := cron.Parse(*m.CronExpression)
+		if err != nil {
+			return time.Time{}
+		}
+		if t.Before(start) {
+			t = start.Add(-time.Nanosecond)
+		}
+		return schedule.Next(t)
+	}
+
+	return time.Time{}
+}
+
+// PeriodKey identifica o período de uma ocorrência; um período nunca é cobrado duas vezes
+func (m *RecurringMandate) PeriodKey(occurrence time.Time) string {
+	occurrence = occurrence.UTC()
+
+	switch m.Frequency {
+	case RecurrenceWeekly:
+		year, week := occurrence.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case RecurrenceMonthly:
+		return occurrence.Format("2006-01")
+	default:
+		return occurrence.Format("2006-01-02T15:04")
+	}
+}
+
+// RecordRun contabiliza a ocorrência executada e agenda a próxima, encerrando o mandato se necessário
+func (m *RecurringMandate) RecordRun(occurrence time.Time) error {
+	if !m.IsActive() {
+		return ErrMandateNotActive
+	}
+
+	m.Occurrences++
+	m.LastRunAt = &occurrence
+
+	if m.MaxOccurrences != nil && m.Occurrences >= *m.MaxOccurrences {
+		m.complete()
+		return nil
+	}
+
+	return m.scheduleFrom(occurrence)
+}
+
+// SkipOccurrence avança a agenda sem contabilizar a ocorrência, usada quando o período já foi executado
+func (m *RecurringMandate) SkipOccurrence(occurrence time.Time) error {
+	if !m.IsActive() {
+		return ErrMandateNotActive
+	}
+
+	return m.scheduleFrom(occurrence)
+}
+
+// DeferRun adia a nova tentativa de gerar a ocorrência atual sem trocar de período, para que um
+// mandato com falha não impeça os demais de serem gerados
+func (m *RecurringMandate) DeferRun(retryAt time.Time) {
+	m.RetryAt = &retryAt
+	m.UpdatedAt = time.Now()
+}
+
+func (m *RecurringMandate) Pause() error {
+	if !m.IsActive() {
+		return ErrMandateNotActive
+	}
+	m.Status = MandateStatusPaused
+	m.UpdatedAt = time.Now()
+	return nil
+}
+
+// Resume reativa o mandato a partir da próxima ocorrência futura; períodos perdidos na pausa não são cobrados
+func (m *RecurringMandate) Resume() error {
+	if m.Status != MandateStatusPaused {
+		return ErrMandateNotPaused
+	}
+	m.Status = MandateStatusActive
+	m.UpdatedAt = time.Now()
+	return m.scheduleFrom(time.Now())
+}
+
+func (m *RecurringMandate) Cancel() error {
+	if m.Status == MandateStatusCancelled || m.Status == MandateStatusCompleted {
+		return ErrMandateNotActive
+	}
+	m.Status = MandateStatusCancelled
+	m.NextRunAt = nil
+	m.RetryAt = nil
+	m.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *RecurringMandate) IsActive() bool {
+	return m.Status == MandateStatusActive
+}
+
+func (m *RecurringMandate) scheduleFrom(after time.Time) error {
+	next := m.OccurrenceAfter(after)
+	if next.IsZero() || (m.EndDate != nil && next.After(*m.EndDate)) {
+		m.complete()
+		return nil
+	}
+
+	m.NextRunAt = &next
+	m.RetryAt = nil
+	m.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *RecurringMandate) complete() {
+	m.Status = MandateStatusCompleted
+	m.NextRunAt = nil
+	m.RetryAt = nil
+	m.UpdatedAt = time.Now()
+}
+
+func NewMandateRun(mandate *RecurringMandate, occurrence time.Time, transactionID string) *MandateRun {
+	return &MandateRun{
+		ID:            uuid.New().String(),
+		MandateID:     mandate.ID,
+		PeriodKey:     mandate.PeriodKey(occurrence),
+		OccurrenceAt:  occurrence,
+		TransactionID: transactionID,
+		CreatedAt:     time.Now(),
+	}
+}
+
+// addMonthsClamped soma meses mantendo o dia, limitado ao último dia do mês (31/01 + 1 mês = 29/02)
+func addMonthsClamped(t time.Time, months int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
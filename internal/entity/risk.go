@@ -0,0 +1,378 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type RiskDecision string
+
+const (
+	RiskDecisionAllow  RiskDecision = "allow"
+	RiskDecisionReview RiskDecision = "review"
+	RiskDecisionDeny   RiskDecision = "deny"
+)
+
+type RiskRule string
+
+const (
+	// RiskRuleVelocity limita a quantidade de transferências do pagador por minuto
+	RiskRuleVelocity RiskRule = "velocity"
+	// RiskRuleNewPayee sinaliza a primeira transferência para um recebedor acima do limite
+	RiskRuleNewPayee RiskRule = "new_payee"
+	// RiskRuleAmountAnomaly compara o valor com a média do histórico do pagador
+	RiskRuleAmountAnomaly RiskRule = "amount_anomaly"
+	// RiskRuleNewAccount sinaliza transferências de contas criadas recentemente
+	RiskRuleNewAccount RiskRule = "new_account"
+)
+
+type RiskReviewStatus string
+
+const (
+	// RiskReviewStatusPending aguarda a decisão de um analista com o saldo reservado
+	RiskReviewStatusPending RiskReviewStatus = "pending"
+	// RiskReviewStatusClaimed foi assumida por um analista
+	RiskReviewStatusClaimed RiskReviewStatus = "claimed"
+	// RiskReviewStatusApproved foi liberada pelo analista e seguiu para o autorizador
+	RiskReviewStatusApproved RiskReviewStatus = "approved"
+	// RiskReviewStatusRejected foi rejeitada pelo analista e a transação falhou
+	RiskReviewStatusRejected RiskReviewStatus = "rejected"
+	// RiskReviewStatusExpired não foi decidida no prazo e a transação falhou
+	RiskReviewStatusExpired RiskReviewStatus = "expired"
+	// RiskReviewStatusDenied foi negada automaticamente pelo motor e fica na fila apenas para consulta
+	RiskReviewStatusDenied RiskReviewStatus = "denied"
+)
+
+type RiskReviewAction string
+
+const (
+	RiskReviewActionCreated             RiskReviewAction = "created"
+	RiskReviewActionClaimed             RiskReviewAction = "claimed"
+	RiskReviewActionApproved            RiskReviewAction = "approved"
+	RiskReviewActionRejected            RiskReviewAction = "rejected"
+	RiskReviewActionExpired             RiskReviewAction = "expired"
+	RiskReviewActionAuthorizationFailed RiskReviewAction = "authorization_failed"
+)
+
+// RiskReviewSystemActor identifica ações automáticas no histórico da fila
+const RiskReviewSystemActor = "system"
+
+// RiskPolicy reúne os parâmetros das regras de risco; um limite zerado desativa a regra
+type RiskPolicy struct {
+	VelocityMaxPerMinute int
+	VelocityAction       RiskDecision
+
+	NewPayeeThreshold decimal.Decimal
+	NewPayeeAction    RiskDecision
+
+	AnomalyMultiplier decimal.Decimal
+	AnomalyMinHistory int
+	AnomalyAction     RiskDecision
+
+	NewAccountAge       time.Duration
+	NewAccountThreshold decimal.Decimal
+	NewAccountAction    RiskDecision
+
+	// ReviewTTL é o prazo para um analista decidir uma transferência retida
+	ReviewTTL time.Duration
+}
+
+// RiskProfile é o histórico do pagador consultado pelas regras
+type RiskProfile struct {
+	AccountCreatedAt time.Time
+	// RecentTransfers conta as outras transferências do pagador no último minuto
+	RecentTransfers int
+	// KnownPayee indica se o pagador já concluiu alguma transferência para o recebedor
+	KnownPayee     bool
+	CompletedCount int
+	AverageAmount  decimal.Decimal
+}
+
+type RiskReason struct {
+	Rule     RiskRule     `json:"rule"`
+	Decision RiskDecision `json:"decision"`
+	Message  string       `json:"message"`
+}
+
+// RiskAssessment é o resultado da avaliação: a decisão mais severa entre as regras acionadas
+type RiskAssessment struct {
+	Decision RiskDecision `json:"decision"`
+	Reasons  []RiskReason `json:"reasons"`
+}
+
+// RiskReview é o registro de uma transferência retida ou negada pelo motor de risco
+type RiskReview struct {
+	ID            string           `json:"id" db:"id"`
+	TransactionID string           `json:"transaction_id" db:"transaction_id"`
+	PayerID       string           `json:"payer_id" db:"payer_id"`
+	PayeeID       string           `json:"payee_id" db:"payee_id"`
+	Amount        decimal.Decimal  `json:"amount" db:"amount"`
+	Decision      RiskDecision     `json:"decision" db:"decision"`
+	Reasons       []RiskReason     `json:"reasons" db:"reasons"`
+	Status        RiskReviewStatus `json:"status" db:"status"`
+	DueAt         *time.Time       `json:"due_at,omitempty" db:"due_at"`
+	ClaimedBy     *string          `json:"claimed_by,omitempty" db:"claimed_by"`
+	ClaimedAt     *time.Time       `json:"claimed_at,omitempty" db:"claimed_at"`
+	DecidedBy     *string          `json:"decided_by,omitempty" db:"decided_by"`
+	DecidedAt     *time.Time       `json:"decided_at,omitempty" db:"decided_at"`
+	DecisionNote  *string          `json:"decision_note,omitempty" db:"decision_note"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at" db:"updated_at"`
+
+	Events []*RiskReviewEvent `json:"events,omitempty" db:"-"`
+}
+
+// RiskReviewEvent registra cada ação sobre um item da fila de análise
+type RiskReviewEvent struct {
+	ID        string           `json:"id" db:"id"`
+	ReviewID  string           `json:"review_id" db:"review_id"`
+	Action    RiskReviewAction `json:"action" db:"action"`
+	Actor     string           `json:"actor" db:"actor"`
+	Note      string           `json:"note" db:"note"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+}
+
+// Validate confere se as ações configuradas para as regras são decisões válidas
+func (p RiskPolicy) Validate() error {
+	for rule, action := range map[RiskRule]RiskDecision{
+		RiskRuleVelocity:      p.VelocityAction,
+		RiskRuleNewPayee:      p.NewPayeeAction,
+		RiskRuleAmountAnomaly: p.AnomalyAction,
+		RiskRuleNewAccount:    p.NewAccountAction,
+	} {
+		if action != RiskDecisionReview && action != RiskDecisionDeny {
+			return fmt.Errorf("ação da regra de risco %s deve ser review ou deny", rule)
+		}
+	}
+
+	if p.ReviewTTL <= 0 {
+		return fmt.Errorf("prazo de análise de risco deve ser maior que zero")
+	}
+
+	return nil
+}
+
+// Assess executa as regras sobre a transferência e o histórico do pagador
+func (p RiskPolicy) Assess(transaction *Transaction, profile *RiskProfile, now time.Time) *RiskAssessment {
+	assessment := &RiskAssessment{Decision: RiskDecisionAllow, Reasons: []RiskReason{}}
+	amount := transaction.Amount
+
+	if p.VelocityMaxPerMinute > 0 && profile.RecentTransfers >= p.VelocityMaxPerMinute {
+		assessment.add(RiskRuleVelocity, p.VelocityAction,
+			fmt.Sprintf("mais de %d transferências no último minuto", p.VelocityMaxPerMinute))
+	}
+
+	if p.NewPayeeThreshold.IsPositive() && !profile.KnownPayee && amount.GreaterThan(p.NewPayeeThreshold) {
+		assessment.add(RiskRuleNewPayee, p.NewPayeeAction,
+			fmt.Sprintf("primeira transferência para o recebedor acima de R$ %s", p.NewPayeeThreshold.StringFixed(2)))
+	}
+
+	if p.AnomalyMultiplier.IsPositive() && profile.CompletedCount >= p.AnomalyMinHistory && profile.AverageAmount.IsPositive() {
+		if amount.GreaterThan(profile.AverageAmount.Mul(p.AnomalyMultiplier)) {
+			assessment.add(RiskRuleAmountAnomaly, p.AnomalyAction,
+				fmt.Sprintf("valor %sx acima da média de R$ %s do pagador", p.AnomalyMultiplier.String(), profile.AverageAmount.StringFixed(2)))
+		}
+	}
+
+	if p.NewAccountAge > 0 && now.Sub(profile.AccountCreatedAt) < p.NewAccountAge && amount.GreaterThan(p.NewAccountThreshold) {
+		assessment.add(RiskRuleNewAccount, p.NewAccountAction,
+			fmt.Sprintf("conta criada há menos de %d dias", int(p.NewAccountAge.Hours()/24)))
+	}
+
+	return assessment
+}
+
+func (a *RiskAssessment) add(rule RiskRule, decision RiskDecision, message string) {
+	a.Reasons = append(a.Reasons, RiskReason{Rule: rule, Decision: decision, Message: message})
+	if decision.severity() > a.Decision.severity() {
+		a.Decision = decision
+	}
+}
+
+func (a *RiskAssessment) IsAllowed() bool {
+	return a.Decision == RiskDecisionAllow
+}
+
+// EscalateToDeny nega a transferência que seria retida, para fluxos que não podem aguardar um analista
+func (a *RiskAssessment) EscalateToDeny() {
+	if a.Decision == RiskDecisionReview {
+		a.Decision = RiskDecisionDeny
+	}
+}
+
+// Summary descreve os motivos em uma linha
+func (a *RiskAssessment) Summary() string {
+	messages := make([]string, 0, len(a.Reasons))
+	for _, reason := range a.Reasons {
+		messages = append(messages, reason.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (d RiskDecision) severity() int {
+	switch d {
+	case RiskDecisionDeny:
+		return 2
+	case RiskDecisionReview:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// NewRiskReview registra na fila a transferência retida ou negada
+func NewRiskReview(transaction *Transaction, assessment *RiskAssessment, ttl time.Duration, now time.Time) *RiskReview {
+	review := &RiskReview{
+		ID:            uuid.New().String(),
+		TransactionID: transaction.ID,
+		PayerID:       transaction.PayerID,
+		PayeeID:       transaction.PayeeID,
+		Amount:        transaction.Amount,
+		Decision:      assessment.Decision,
+		Reasons:       assessment.Reasons,
+		Status:        RiskReviewStatusDenied,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if assessment.Decision == RiskDecisionReview {
+		dueAt := now.Add(ttl)
+		review.Status = RiskReviewStatusPending
+		review.DueAt = &dueAt
+	}
+
+	review.Record(RiskReviewActionCreated, RiskReviewSystemActor, assessment.Summary(), now)
+	return review
+}
+
+// Claim atribui o item ao analista; assumir de novo o próprio item não tem efeito
+func (r *RiskReview) Claim(reviewer string, now time.Time) error {
+	if strings.TrimSpace(reviewer) == "" {
+		return ErrRiskReviewerRequired
+	}
+	if err := r.checkOpen(now); err != nil {
+		return err
+	}
+	if r.Status == RiskReviewStatusClaimed {
+		if *r.ClaimedBy != reviewer {
+			return ErrRiskReviewClaimedByOther
+		}
+		return nil
+	}
+
+	r.Status = RiskReviewStatusClaimed
+	r.ClaimedBy = &reviewer
+	r.ClaimedAt = &now
+	r.UpdatedAt = now
+	r.Record(RiskReviewActionClaimed, reviewer, "", now)
+	return nil
+}
+
+// Approve libera a transferência; só o analista que assumiu o item pode decidir
+func (r *RiskReview) Approve(reviewer, note string, now time.Time) error {
+	return r.decide(RiskReviewStatusApproved, RiskReviewActionApproved, reviewer, note, now)
+}
+
+// Reject recusa a transferência; só o analista que assumiu o item pode decidir
+func (r *RiskReview) Reject(reviewer, note string, now time.Time) error {
+	return r.decide(RiskReviewStatusRejected, RiskReviewActionRejected, reviewer, note, now)
+}
+
+// Expire encerra o item que não foi decidido dentro do prazo
+func (r *RiskReview) Expire(now time.Time) bool {
+	if !r.IsOpen() || r.DueAt == nil || now.Before(*r.DueAt) {
+		return false
+	}
+
+	note := "prazo de análise expirado"
+	r.Status = RiskReviewStatusExpired
+	r.DecidedAt = &now
+	r.DecisionNote = &note
+	r.UpdatedAt = now
+	r.Record(RiskReviewActionExpired, RiskReviewSystemActor, note, now)
+	return true
+}
+
+// IsOpen informa se o item ainda aguarda decisão
+func (r *RiskReview) IsOpen() bool {
+	return r.Status == RiskReviewStatusPending || r.Status == RiskReviewStatusClaimed
+}
+
+// Record acrescenta uma ação ao histórico do item
+func (r *RiskReview) Record(action RiskReviewAction, actor, note string, now time.Time) {
+	r.Events = append(r.Events, &RiskReviewEvent{
+		ID:        uuid.New().String(),
+		ReviewID:  r.ID,
+		Action:    action,
+		Actor:     actor,
+		Note:      note,
+		CreatedAt: now,
+	})
+}
+
+// LastEvent retorna a ação mais recente do histórico
+func (r *RiskReview) LastEvent() *RiskReviewEvent {
+	if len(r.Events) == 0 {
+		return nil
+	}
+	return r.Events[len(r.Events)-1]
+}
+
+func (r *RiskReview) GetStatusDescription() string {
+	switch r.Status {
+	case RiskReviewStatusPending:
+		return "Aguardando analista"
+	case RiskReviewStatusClaimed:
+		return "Em análise"
+	case RiskReviewStatusApproved:
+		return "Aprovada"
+	case RiskReviewStatusRejected:
+		return "Rejeitada"
+	case RiskReviewStatusExpired:
+		return "Expirada sem decisão"
+	case RiskReviewStatusDenied:
+		return "Negada automaticamente"
+	default:
+		return "Status desconhecido"
+	}
+}
+
+func (r *RiskReview) decide(status RiskReviewStatus, action RiskReviewAction, reviewer, note string, now time.Time) error {
+	if strings.TrimSpace(reviewer) == "" {
+		return ErrRiskReviewerRequired
+	}
+	if err := r.checkOpen(now); err != nil {
+		return err
+	}
+	if r.Status != RiskReviewStatusClaimed {
+		return ErrRiskReviewNotClaimed
+	}
+	if *r.ClaimedBy != reviewer {
+		return ErrRiskReviewClaimedByOther
+	}
+
+	note = strings.TrimSpace(note)
+	r.Status = status
+	r.DecidedBy = &reviewer
+	r.DecidedAt = &now
+	if note != "" {
+		r.DecisionNote = &note
+	}
+	r.UpdatedAt = now
+	r.Record(action, reviewer, note, now)
+	return nil
+}
+
+func (r *RiskReview) checkOpen(now time.Time) error {
+	if !r.IsOpen() {
+		return ErrRiskReviewNotOpen
+	}
+	if r.DueAt != nil && !now.Before(*r.DueAt) {
+		return ErrRiskReviewExpired
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+package entity
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type SplitType string
+
+const (
+	// SplitTypePercentage divide o valor restante após as partes fixas
+	SplitTypePercentage SplitType = "percentage"
+	// SplitTypeFixed reserva um valor exato para o recebedor
+	SplitTypeFixed SplitType = "fixed"
+)
+
+type SplitPaymentStatus string
+
+const (
+	SplitPaymentStatusPending           SplitPaymentStatus = "pending"
+	SplitPaymentStatusCompleted         SplitPaymentStatus = "completed"
+	SplitPaymentStatusFailed            SplitPaymentStatus = "failed"
+	SplitPaymentStatusPartiallyRefunded SplitPaymentStatus = "partially_refunded"
+	SplitPaymentStatusRefunded          SplitPaymentStatus = "refunded"
+)
+
+// MaxSplitLegs é a quantidade máxima de partes em um pagamento dividido
+const MaxSplitLegs = 10
+
+var cent = decimal.New(1, -2)
+
+// SplitPayment é um débito único do pagador dividido entre vários recebedores e a plataforma
+type SplitPayment struct {
+	ID             string             `json:"id" db:"id"`
+	PayerID        string             `json:"payer_id" db:"payer_id"`
+	Amount         decimal.Decimal    `json:"amount" db:"amount"`
+	Description    string             `json:"description" db:"description"`
+	Status         SplitPaymentStatus `json:"status" db:"status"`
+	RefundedAmount decimal.Decimal    `json:"refunded_amount" db:"refunded_amount"`
+	FailureReason  *string            `json:"failure_reason,omitempty" db:"failure_reason"`
+	CreatedAt      time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at" db:"updated_at"`
+	CompletedAt    *time.Time         `json:"completed_at,omitempty" db:"completed_at"`
+
+	Legs    []*SplitLeg    `json:"legs,omitempty" db:"-"`
+	Refunds []*SplitRefund `json:"refunds,omitempty" db:"-"`
+}
+
+// SplitLeg é a parte de um recebedor; PayeeID nil indica a parte da plataforma.
+// As partes de usuários são liquidadas por transações comuns, com tarifa do recebedor.
+type SplitLeg struct {
+	ID             string          `json:"id" db:"id"`
+	SplitPaymentID string          `json:"split_payment_id" db:"split_payment_id"`
+	Position       int             `json:"position" db:"position"`
+	PayeeID        *string         `json:"payee_id,omitempty" db:"payee_id"`
+	PayeeKey       *string         `json:"payee_key,omitempty" db:"payee_key"`
+	SplitType      SplitType       `json:"split_type" db:"split_type"`
+	Value          decimal.Decimal `json:"value" db:"value"`
+	Amount         decimal.Decimal `json:"amount" db:"amount"`
+	RefundedAmount decimal.Decimal `json:"refunded_amount" db:"refunded_amount"`
+	TransactionID  *string         `json:"transaction_id,omitempty" db:"transaction_id"`
+}
+
+// SplitLegSpec descreve uma parte com o recebedor já resolvido
+type SplitLegSpec struct {
+	PayeeID   *string
+	PayeeKey  *string
+	SplitType SplitType
+	Value     decimal.Decimal
+}
+
+// SplitRefund registra um estorno e quanto coube a cada parte
+type SplitRefund struct {
+	ID             string           `json:"id" db:"id"`
+	SplitPaymentID string           `json:"split_payment_id" db:"split_payment_id"`
+	Amount         decimal.Decimal  `json:"amount" db:"amount"`
+	Reason         string           `json:"reason" db:"reason"`
+	Legs           []SplitRefundLeg `json:"legs" db:"legs"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+}
+
+// SplitRefundLeg é a parte de um estorno; Fee é a parte da tarifa do recebedor devolvida junto,
+// então o recebedor devolve Amount - Fee e o pagador recebe Amount
+type SplitRefundLeg struct {
+	LegID    string          `json:"leg_id"`
+	Position int             `json:"position"`
+	Amount   decimal.Decimal `json:"amount"`
+	Fee      decimal.Decimal `json:"fee"`
+}
+
+// NewSplitPayment calcula o valor de cada parte. Os percentuais incidem sobre o que sobra após as
+// partes fixas e precisam somar 100; sem partes percentuais, as fixas precisam somar o total.
+func NewSplitPayment(payerID string, amount decimal.Decimal, description string, specs []SplitLegSpec) (*SplitPayment, error) {
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, errors.New("valor do pagamento deve ser maior que zero")
+	}
+	if amount.Exponent() < -2 {
+		return nil, errors.New("valor do pagamento deve ter no máximo duas casas decimais")
+	}
+	if len(specs) < 2 {
+		return nil, errors.New("pagamento dividido precisa de ao menos duas partes")
+	}
+	if len(specs) > MaxSplitLegs {
+		return nil, errors.New("pagamento dividido excede a quantidade máxima de partes")
+	}
+
+	now := time.Now()
+	split := &SplitPayment{
+		ID:             uuid.New().String(),
+		PayerID:        payerID,
+		Amount:         amount,
+		Description:    description,
+		Status:         SplitPaymentStatusPending,
+		RefundedAmount: decimal.Zero,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	fixedTotal := decimal.Zero
+	percentageTotal := decimal.Zero
+	var percentages []decimal.Decimal
+	var percentageLegs []*SplitLeg
+	payees := make(map[string]bool)
+	platformLegs, userLegs := 0, 0
+
+	for i, spec := range specs {
+		if spec.PayeeID == nil {
+			platformLegs++
+		} else {
+			if *spec.PayeeID == payerID {
+				return nil, ErrSelfTransfer
+			}
+			if payees[*spec.PayeeID] {
+				return nil, errors.New("recebedor repetido no pagamento dividido")
+			}
+			payees[*spec.PayeeID] = true
+			userLegs++
+		}
+
+		if spec.Value.LessThanOrEqual(decimal.Zero) {
+			return nil, errors.New("valor de cada parte deve ser maior que zero")
+		}
+
+		leg := &SplitLeg{
+			ID:             uuid.New().String(),
+			SplitPaymentID: split.ID,
+			Position:       i + 1,
+			PayeeID:        spec.PayeeID,
+			PayeeKey:       spec.PayeeKey,
+			SplitType:      spec.SplitType,
+			Value:          spec.Value,
+			RefundedAmount: decimal.Zero,
+		}
+
+		switch spec.SplitType {
+		case SplitTypeFixed:
+			if spec.Value.Exponent() < -2 {
+				return nil, errors.New("parte fixa deve ter no máximo duas casas decimais")
+			}
+			leg.Amount = spec.Value
+			fixedTotal = fixedTotal.Add(spec.Value)
+		case SplitTypePercentage:
+			percentageTotal = percentageTotal.Add(spec.Value)
+			percentages = append(percentages, spec.Value)
+			percentageLegs = append(percentageLegs, leg)
+		default:
+			return nil, ErrInvalidSplitType
+		}
+
+		split.Legs = append(split.Legs, leg)
+	}
+
+	if platformLegs > 1 {
+		return nil, errors.New("pagamento dividido aceita apenas uma parte da plataforma")
+	}
+	if userLegs == 0 {
+		return nil, errors.New("pagamento dividido precisa de ao menos um recebedor")
+	}
+
+	remainder := amount.Sub(fixedTotal)
+	if len(percentageLegs) == 0 {
+		if !remainder.IsZero() {
+			return nil, errors.New("soma das partes fixas deve ser igual ao valor do pagamento")
+		}
+		return split, nil
+	}
+
+	if !percentageTotal.Equal(hundred) {
+		return nil, errors.New("soma dos percentuais deve ser 100")
+	}
+	if remainder.LessThanOrEqual(decimal.Zero) {
+		return nil, errors.New("partes fixas não deixam valor para as partes percentuais")
+	}
+
+	for i, share := range AllocateCents(remainder, percentages) {
+		if share.IsZero() {
+			return nil, errors.New("parte percentual ficou sem valor após o arredondamento")
+		}
+		percentageLegs[i].Amount = share
+	}
+
+	return split, nil
+}
+
+// AllocateCents divide total entre os pesos pelo método do maior resto: cada parte é
+// arredondada para baixo em centavos e os centavos que sobram vão para as maiores frações,
+// em caso de empate para a primeira. A soma das partes é sempre exatamente igual a total.
+func AllocateCents(total decimal.Decimal, weights []decimal.Decimal) []decimal.Decimal {
+	shares := make([]decimal.Decimal, len(weights))
+	sum := decimal.Zero
+	for _, weight := range weights {
+		sum = sum.Add(weight)
+	}
+	if sum.IsZero() {
+		return shares
+	}
+
+	type fraction struct {
+		index     int
+		remainder decimal.Decimal
+	}
+
+	allocated := decimal.Zero
+	fractions := make([]fraction, len(weights))
+	for i, weight := range weights {
+		exact := total.Mul(weight).Div(sum)
+		shares[i] = exact.RoundFloor(2)
+		allocated = allocated.Add(shares[i])
+		fractions[i] = fraction{index: i, remainder: exact.Sub(shares[i])}
+	}
+
+	sort.SliceStable(fractions, func(a, b int) bool {
+		return fractions[a].remainder.GreaterThan(fractions[b].remainder)
+	})
+
+	for i := 0; allocated.LessThan(total); i++ {
+		index := fractions[i%len(fractions)].index
+		shares[index] = shares[index].Add(cent)
+		allocated = allocated.Add(cent)
+	}
+
+	return shares
+}
+
+func (l *SplitLeg) IsPlatform() bool {
+	return l.PayeeID == nil
+}
+
+// RefundableAmount retorna quanto da parte ainda pode ser estornado
+func (l *SplitLeg) RefundableAmount() decimal.Decimal {
+	return l.Amount.Sub(l.RefundedAmount)
+}
+
+func (l *SplitLeg) IsFullyRefunded() bool {
+	return l.RefundableAmount().IsZero()
+}
+
+// RefundFee calcula quanto da tarifa fee da transação da parte volta com o estorno de share, já somado
+// em RefundedAmount. A tarifa devolvida acompanha a proporção estornada da parte; o cálculo é
+// acumulado, então ao fim do estorno completo a tarifa inteira terá sido devolvida.
+func (l *SplitLeg) RefundFee(fee, share decimal.Decimal) decimal.Decimal {
+	if l.Amount.IsZero() {
+		return decimal.Zero
+	}
+	after := fee.Mul(l.RefundedAmount).Div(l.Amount).Round(2)
+	before := fee.Mul(l.RefundedAmount.Sub(share)).Div(l.Amount).Round(2)
+	return after.Sub(before)
+}
+
+// UserLegs retorna as partes liquidadas por transações para usuários
+func (s *SplitPayment) UserLegs() []*SplitLeg {
+	var legs []*SplitLeg
+	for _, leg := range s.Legs {
+		if !leg.IsPlatform() {
+			legs = append(legs, leg)
+		}
+	}
+	return legs
+}
+
+// PlatformLeg retorna a parte da plataforma ou nil se não houver
+func (s *SplitPayment) PlatformLeg() *SplitLeg {
+	for _, leg := range s.Legs {
+		if leg.IsPlatform() {
+			return leg
+		}
+	}
+	return nil
+}
+
+// PlatformAmount retorna o valor destinado à plataforma
+func (s *SplitPayment) PlatformAmount() decimal.Decimal {
+	if leg := s.PlatformLeg(); leg != nil {
+		return leg.Amount
+	}
+	return decimal.Zero
+}
+
+func (s *SplitPayment) Complete() {
+	now := time.Now()
+	s.Status = SplitPaymentStatusCompleted
+	s.CompletedAt = &now
+	s.UpdatedAt = now
+}
+
+func (s *SplitPayment) Fail(reason string) {
+	s.Status = SplitPaymentStatusFailed
+	s.FailureReason = &reason
+	s.UpdatedAt = time.Now()
+}
+
+// RefundableAmount retorna quanto do pagamento ainda pode ser estornado
+func (s *SplitPayment) RefundableAmount() decimal.Decimal {
+	return s.Amount.Sub(s.RefundedAmount)
+}
+
+// Refund distribui o estorno entre as partes na proporção do que cada uma ainda tem a estornar.
+// O estorno do saldo restante devolve exatamente o restante de cada parte.
+func (s *SplitPayment) Refund(amount decimal.Decimal, reason string) (*SplitRefund, error) {
+	if s.Status != SplitPaymentStatusCompleted && s.Status != SplitPaymentStatusPartiallyRefunded {
+		return nil, ErrSplitPaymentNotRefundable
+	}
+	if amount.LessThanOrEqual(decimal.Zero) || amount.Exponent() < -2 {
+		return nil, ErrInvalidAmount
+	}
+	if amount.GreaterThan(s.RefundableAmount()) {
+		return nil, ErrRefundExceedsAmount
+	}
+
+	weights := make([]decimal.Decimal, len(s.Legs))
+	for i, leg := range s.Legs {
+		weights[i] = leg.RefundableAmount()
+	}
+
+	refund := &SplitRefund{
+		ID:             uuid.New().String(),
+		SplitPaymentID: s.ID,
+		Amount:         amount,
+		Reason:         reason,
+		CreatedAt:      time.Now(),
+	}
+
+	for i, share := range AllocateCents(amount, weights) {
+		if share.IsZero() {
+			continue
+		}
+		leg := s.Legs[i]
+		leg.RefundedAmount = leg.RefundedAmount.Add(share)
+		refund.Legs = append(refund.Legs, SplitRefundLeg{LegID: leg.ID, Position: leg.Position, Amount: share})
+	}
+
+	s.RefundedAmount = s.RefundedAmount.Add(amount)
+	s.Status = SplitPaymentStatusPartiallyRefunded
+	if s.RefundableAmount().IsZero() {
+		s.Status = SplitPaymentStatusRefunded
+	}
+	s.UpdatedAt = refund.CreatedAt
+	s.Refunds = append(s.Refunds, refund)
+
+	return refund, nil
+}
+
+// Leg retorna a parte pelo ID
+func (s *SplitPayment) Leg(id string) *SplitLeg {
+	for _, leg := range s.Legs {
+		if leg.ID == id {
+			return leg
+		}
+	}
+	return nil
+}
+
+// IsVisibleTo informa se o usuário é o pagador ou um dos recebedores
+func (s *SplitPayment) IsVisibleTo(userID string) bool {
+	if s.PayerID == userID {
+		return true
+	}
+	for _, leg := range s.Legs {
+		if leg.PayeeID != nil && *leg.PayeeID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SplitPayment) GetStatusDescription() string {
+	switch s.Status {
+	case SplitPaymentStatusPending:
+		return "Pendente"
+	case SplitPaymentStatusCompleted:
+		return "Concluído"
+	case SplitPaymentStatusFailed:
+		return "Falhou"
+	case SplitPaymentStatusPartiallyRefunded:
+		return "Estornado parcialmente"
+	case SplitPaymentStatusRefunded:
+		return "Estornado"
+	default:
+		return "Status desconhecido"
+	}
+}
+
+// NewSplitPlatformEntry lança a parte da plataforma na conta de receita; valores negativos registram estornos
+func NewSplitPlatformEntry(split *SplitPayment, amount decimal.Decimal) *FeeEntry {
+	return &FeeEntry{
+		ID:             uuid.New().String(),
+		SplitPaymentID: &split.ID,
+		AccountCode:    RevenueAccountCode,
+		Amount:         amount,
+		CreatedAt:      time.Now(),
+	}
+}
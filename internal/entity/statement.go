@@ -0,0 +1,306 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxStatementPeriod é o maior intervalo aceito em um extrato
+const MaxStatementPeriod = 366 * 24 * time.Hour
+
+// StatementFormat é o formato de saída do extrato
+type StatementFormat string
+
+const (
+	StatementFormatJSON StatementFormat = "json"
+	StatementFormatCSV  StatementFormat = "csv"
+	StatementFormatOFX  StatementFormat = "ofx"
+	StatementFormatPDF  StatementFormat = "pdf"
+)
+
+// ParseStatementFormat valida o formato pedido; vazio significa JSON
+func ParseStatementFormat(value string) (StatementFormat, error) {
+	switch format := StatementFormat(strings.ToLower(value)); format {
+	case "":
+		return StatementFormatJSON, nil
+	case StatementFormatJSON, StatementFormatCSV, StatementFormatOFX, StatementFormatPDF:
+		return format, nil
+	default:
+		return "", ErrInvalidStatementFormat
+	}
+}
+
+// ContentType retorna o tipo MIME do arquivo gerado no formato
+func (f StatementFormat) ContentType() string {
+	switch f {
+	case StatementFormatCSV:
+		return "text/csv; charset=utf-8"
+	case StatementFormatOFX:
+		return "application/x-ofx"
+	case StatementFormatPDF:
+		return "application/pdf"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// StatementMovementKind identifica a origem de um lançamento no saldo
+type StatementMovementKind string
+
+const (
+	StatementTransferSent        StatementMovementKind = "transfer_sent"
+	StatementTransferReceived    StatementMovementKind = "transfer_received"
+	StatementTransferReversed    StatementMovementKind = "transfer_reversed"
+	StatementTransferReturned    StatementMovementKind = "transfer_returned"
+	StatementSplitPlatformShare  StatementMovementKind = "split_platform_share"
+	StatementSplitPlatformRefund StatementMovementKind = "split_platform_refund"
+	StatementSplitRefundReceived StatementMovementKind = "split_refund_received"
+	StatementSplitRefundReturned StatementMovementKind = "split_refund_returned"
+)
+
+// Description retorna o histórico do lançamento como aparece no extrato
+func (k StatementMovementKind) Description() string {
+	switch k {
+	case StatementTransferSent:
+		return "Transferência enviada"
+	case StatementTransferReceived:
+		return "Transferência recebida"
+	case StatementTransferReversed:
+		return "Estorno de transferência enviada"
+	case StatementTransferReturned:
+		return "Devolução de transferência recebida"
+	case StatementSplitPlatformShare:
+		return "Pagamento dividido - parte da plataforma"
+	case StatementSplitPlatformRefund:
+		return "Estorno da parte da plataforma"
+	case StatementSplitRefundReceived:
+		return "Estorno de pagamento dividido"
+	case StatementSplitRefundReturned:
+		return "Devolução de pagamento dividido recebido"
+	default:
+		return "Lançamento"
+	}
+}
+
+// StatementMovement é uma alteração do saldo do usuário; valores positivos são créditos
+type StatementMovement struct {
+	ID             string                `db:"id"`
+	Kind           StatementMovementKind `db:"kind"`
+	ReferenceID    string                `db:"reference_id"`
+	CounterpartyID *string               `db:"counterparty_id"`
+	OccurredAt     time.Time             `db:"occurred_at"`
+	Amount         decimal.Decimal       `db:"amount"`
+}
+
+// StatementEntry é um lançamento do extrato com o saldo logo após ele
+type StatementEntry struct {
+	ID             string
+	Date           time.Time
+	Kind           StatementMovementKind
+	Description    string
+	ReferenceID    string
+	CounterpartyID *string
+	Amount         decimal.Decimal
+	Balance        decimal.Decimal
+}
+
+// IsCredit indica se o lançamento aumentou o saldo
+func (e *StatementEntry) IsCredit() bool {
+	return e.Amount.IsPositive()
+}
+
+// EntryType retorna "credit" ou "debit"
+func (e *StatementEntry) EntryType() string {
+	if e.IsCredit() {
+		return "credit"
+	}
+	return "debit"
+}
+
+// Statement é o extrato de um período: saldo inicial, lançamentos com saldo corrente e saldo final.
+// To é exclusivo.
+type Statement struct {
+	UserID         string
+	HolderName     string
+	From           time.Time
+	To             time.Time
+	OpeningBalance decimal.Decimal
+	ClosingBalance decimal.Decimal
+	Entries        []*StatementEntry
+	GeneratedAt    time.Time
+}
+
+// NewStatement monta o extrato a partir do saldo no fim do período e dos lançamentos do período,
+// em ordem cronológica. O saldo inicial é o final menos a soma dos lançamentos.
+func NewStatement(user *User, from, to time.Time, closingBalance decimal.Decimal, movements []*StatementMovement, now time.Time) *Statement {
+	statement := &Statement{
+		UserID:         user.ID,
+		HolderName:     user.FullName,
+		From:           from,
+		To:             to,
+		ClosingBalance: closingBalance,
+		Entries:        make([]*StatementEntry, 0, len(movements)),
+		GeneratedAt:    now,
+	}
+
+	opening := closingBalance
+	for _, movement := range movements {
+		opening = opening.Sub(movement.Amount)
+	}
+	statement.OpeningBalance = opening
+
+	balance := opening
+	for _, movement := range movements {
+		balance = balance.Add(movement.Amount)
+		statement.Entries = append(statement.Entries, &StatementEntry{
+			ID:             movement.ID,
+			Date:           movement.OccurredAt,
+			Kind:           movement.Kind,
+			Description:    movement.Kind.Description(),
+			ReferenceID:    movement.ReferenceID,
+			CounterpartyID: movement.CounterpartyID,
+			Amount:         movement.Amount,
+			Balance:        balance,
+		})
+	}
+
+	return statement
+}
+
+// TotalCredits soma os lançamentos que aumentaram o saldo
+func (s *Statement) TotalCredits() decimal.Decimal {
+	total := decimal.Zero
+	for _, entry := range s.Entries {
+		if entry.IsCredit() {
+			total = total.Add(entry.Amount)
+		}
+	}
+	return total
+}
+
+// TotalDebits soma, em valor absoluto, os lançamentos que reduziram o saldo
+func (s *Statement) TotalDebits() decimal.Decimal {
+	total := decimal.Zero
+	for _, entry := range s.Entries {
+		if !entry.IsCredit() {
+			total = total.Sub(entry.Amount)
+		}
+	}
+	return total
+}
+
+// LastDay retorna o último dia coberto pelo extrato
+func (s *Statement) LastDay() time.Time {
+	return s.To.Add(-time.Nanosecond)
+}
+
+// FileName retorna o nome sugerido para o arquivo do extrato
+func (s *Statement) FileName(format StatementFormat) string {
+	return "extrato-" + s.UserID + "-" + s.From.Format("20060102") + "-" + s.LastDay().Format("20060102") + "." + string(format)
+}
+
+// ParseStatementPeriod interpreta from e to como data (2006-01-02, em UTC) ou RFC3339. Uma data
+// em to inclui o dia inteiro. Sem from, o extrato começa no primeiro dia do mês corrente;
+// sem to, termina agora. O fim nunca passa de now.
+func ParseStatementPeriod(fromValue, toValue string, now time.Time) (time.Time, time.Time, error) {
+	now = now.UTC()
+
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if fromValue != "" {
+		parsed, _, err := parseStatementDate(fromValue)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsed
+	}
+
+	to := now
+	if toValue != "" {
+		parsed, dateOnly, err := parseStatementDate(toValue)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsed
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+	if to.After(now) {
+		to = now
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, ErrInvalidStatementPeriod
+	}
+	if to.Sub(from) > MaxStatementPeriod {
+		return time.Time{}, time.Time{}, ErrStatementPeriodTooLong
+	}
+
+	return from, to, nil
+}
+
+func parseStatementDate(value string) (time.Time, bool, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, true, nil
+	}
+	if instant, err := time.Parse(time.RFC3339, value); err == nil {
+		return instant.UTC(), false, nil
+	}
+	return time.Time{}, false, ErrInvalidStatementPeriod
+}
+
+// StatementResponse é o extrato em JSON, com os valores formatados em reais
+type StatementResponse struct {
+	UserID         string                   `json:"user_id"`
+	From           time.Time                `json:"from"`
+	To             time.Time                `json:"to"`
+	OpeningBalance string                   `json:"opening_balance"`
+	TotalCredits   string                   `json:"total_credits"`
+	TotalDebits    string                   `json:"total_debits"`
+	ClosingBalance string                   `json:"closing_balance"`
+	Entries        []StatementEntryResponse `json:"entries"`
+	GeneratedAt    time.Time                `json:"generated_at"`
+}
+
+type StatementEntryResponse struct {
+	ID             string                `json:"id"`
+	Date           time.Time             `json:"date"`
+	Type           string                `json:"type"`
+	Kind           StatementMovementKind `json:"kind"`
+	Description    string                `json:"description"`
+	ReferenceID    string                `json:"reference_id"`
+	CounterpartyID *string               `json:"counterparty_id,omitempty"`
+	Amount         string                `json:"amount"`
+	Balance        string                `json:"balance"`
+}
+
+func (s *Statement) ToStatementResponse() *StatementResponse {
+	entries := make([]StatementEntryResponse, 0, len(s.Entries))
+	for _, entry := range s.Entries {
+		entries = append(entries, StatementEntryResponse{
+			ID:             entry.ID,
+			Date:           entry.Date,
+			Type:           entry.EntryType(),
+			Kind:           entry.Kind,
+			Description:    entry.Description,
+			ReferenceID:    entry.ReferenceID,
+			CounterpartyID: entry.CounterpartyID,
+			Amount:         FormatAmount(entry.Amount),
+			Balance:        FormatAmount(entry.Balance),
+		})
+	}
+
+	return &StatementResponse{
+		UserID:         s.UserID,
+		From:           s.From,
+		To:             s.To,
+		OpeningBalance: FormatAmount(s.OpeningBalance),
+		TotalCredits:   FormatAmount(s.TotalCredits()),
+		TotalDebits:    FormatAmount(s.TotalDebits()),
+		ClosingBalance: FormatAmount(s.ClosingBalance),
+		Entries:        entries,
+		GeneratedAt:    s.GeneratedAt,
+	}
+}
@@ -0,0 +1,255 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"payflow-api/pkg/pdf"
+)
+
+// Identificação da conta nos arquivos OFX
+const (
+	ofxBankID    = "PAYFLOW"
+	ofxCurrency  = "BRL"
+	ofxAcctIDMax = 22
+	ofxNameMax   = 32
+)
+
+// Render gera o extrato no formato pedido
+func (s *Statement) Render(format StatementFormat) ([]byte, error) {
+	switch format {
+	case StatementFormatJSON:
+		return json.Marshal(s.ToStatementResponse())
+	case StatementFormatCSV:
+		return s.CSV()
+	case StatementFormatOFX:
+		return s.OFX()
+	case StatementFormatPDF:
+		return s.PDF(), nil
+	default:
+		return nil, ErrInvalidStatementFormat
+	}
+}
+
+// CSV gera uma linha por lançamento, entre as linhas de saldo inicial e saldo final
+func (s *Statement) CSV() ([]byte, error) {
+	rows := [][]string{
+		{"date", "id", "type", "description", "reference_id", "counterparty_id", "amount", "balance"},
+		{s.From.UTC().Format(time.RFC3339), "", "", "Saldo inicial", "", "", "", FormatAmount(s.OpeningBalance)},
+	}
+	for _, entry := range s.Entries {
+		rows = append(rows, []string{
+			entry.Date.UTC().Format(time.RFC3339),
+			entry.ID,
+			entry.EntryType(),
+			entry.Description,
+			entry.ReferenceID,
+			stringValue(entry.CounterpartyID),
+			FormatAmount(entry.Amount),
+			FormatAmount(entry.Balance),
+		})
+	}
+	rows = append(rows, []string{s.To.UTC().Format(time.RFC3339), "", "", "Saldo final", "", "", "", FormatAmount(s.ClosingBalance)})
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), writer.Error()
+}
+
+// Estrutura OFX 2.x: resposta de login e extrato bancário (STMTTRNRS)
+type ofxDocument struct {
+	XMLName xml.Name `xml:"OFX"`
+	SignOn  struct {
+		Response struct {
+			Status   ofxStatus `xml:"STATUS"`
+			DTServer string    `xml:"DTSERVER"`
+			Language string    `xml:"LANGUAGE"`
+		} `xml:"SONRS"`
+	} `xml:"SIGNONMSGSRSV1"`
+	Bank struct {
+		Transaction struct {
+			TrnUID    string          `xml:"TRNUID"`
+			Status    ofxStatus       `xml:"STATUS"`
+			Statement ofxStatementRes `xml:"STMTRS"`
+		} `xml:"STMTTRNRS"`
+	} `xml:"BANKMSGSRSV1"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxStatementRes struct {
+	CurDef  string `xml:"CURDEF"`
+	Account struct {
+		BankID   string `xml:"BANKID"`
+		AcctID   string `xml:"ACCTID"`
+		AcctType string `xml:"ACCTTYPE"`
+	} `xml:"BANKACCTFROM"`
+	TranList struct {
+		DTStart      string           `xml:"DTSTART"`
+		DTEnd        string           `xml:"DTEND"`
+		Transactions []ofxTransaction `xml:"STMTTRN"`
+	} `xml:"BANKTRANLIST"`
+	LedgerBal struct {
+		BalAmt string `xml:"BALAMT"`
+		DTAsOf string `xml:"DTASOF"`
+	} `xml:"LEDGERBAL"`
+}
+
+type ofxTransaction struct {
+	TrnType  string `xml:"TRNTYPE"`
+	DTPosted string `xml:"DTPOSTED"`
+	TrnAmt   string `xml:"TRNAMT"`
+	FITID    string `xml:"FITID"`
+	Name     string `xml:"NAME"`
+	Memo     string `xml:"MEMO,omitempty"`
+}
+
+// OFX gera o extrato no formato OFX 2.2 (XML), aceito pelos programas de contabilidade.
+// Os valores usam ponto decimal e sinal, como exige o padrão; o saldo informado é o final.
+func (s *Statement) OFX() ([]byte, error) {
+	doc := ofxDocument{}
+	doc.SignOn.Response.Status = ofxStatus{Code: 0, Severity: "INFO"}
+	doc.SignOn.Response.DTServer = ofxTime(s.GeneratedAt)
+	doc.SignOn.Response.Language = "POR"
+
+	doc.Bank.Transaction.TrnUID = "0"
+	doc.Bank.Transaction.Status = ofxStatus{Code: 0, Severity: "INFO"}
+
+	stmt := &doc.Bank.Transaction.Statement
+	stmt.CurDef = ofxCurrency
+	stmt.Account.BankID = ofxBankID
+	stmt.Account.AcctID = ofxAccountID(s.UserID)
+	stmt.Account.AcctType = "CHECKING"
+	stmt.TranList.DTStart = ofxTime(s.From)
+	stmt.TranList.DTEnd = ofxTime(s.To)
+	stmt.TranList.Transactions = make([]ofxTransaction, 0, len(s.Entries))
+	for _, entry := range s.Entries {
+		trnType := "DEBIT"
+		if entry.IsCredit() {
+			trnType = "CREDIT"
+		}
+		stmt.TranList.Transactions = append(stmt.TranList.Transactions, ofxTransaction{
+			TrnType:  trnType,
+			DTPosted: ofxTime(entry.Date),
+			TrnAmt:   entry.Amount.StringFixed(2),
+			FITID:    entry.ID,
+			Name:     truncateRunes(entry.Description, ofxNameMax),
+			Memo:     entry.Description + " " + entry.ReferenceID,
+		})
+	}
+	stmt.LedgerBal.BalAmt = s.ClosingBalance.StringFixed(2)
+	stmt.LedgerBal.DTAsOf = ofxTime(s.To)
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar OFX: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	buf.WriteString(`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	buf.Write(body)
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// ofxTime formata no padrão de datas do OFX, sempre em UTC
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+// ofxAccountID deriva da conta um identificador dentro do limite de 22 caracteres do ACCTID
+func ofxAccountID(userID string) string {
+	return truncateRunes(strings.ReplaceAll(userID, "-", ""), ofxAcctIDMax)
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
+
+// Layout do PDF: margens, altura de linha e colunas, em pontos
+const (
+	statementPDFMargin       = 50.0
+	statementPDFLineHeight   = 14.0
+	statementPDFFontSize     = 9.0
+	statementPDFDateX        = 50.0
+	statementPDFDescX        = 140.0
+	statementPDFAmountRight  = 450.0
+	statementPDFBalanceRight = 545.0
+	statementPDFDescMax      = 40
+)
+
+// PDF gera o extrato paginado em A4, com cabeçalho em cada página e totais ao final
+func (s *Statement) PDF() []byte {
+	doc := pdf.New()
+	y := 0.0
+
+	newPage := func() {
+		doc.AddPage()
+		y = pdf.A4Height - statementPDFMargin
+		doc.Text(statementPDFMargin, y, pdf.HelveticaBold, 14, "PayFlow - Extrato de conta")
+		y -= 20
+		doc.Text(statementPDFMargin, y, pdf.Helvetica, 10, "Titular: "+s.HolderName)
+		y -= statementPDFLineHeight
+		doc.Text(statementPDFMargin, y, pdf.Helvetica, 10, "Conta: "+s.UserID)
+		y -= statementPDFLineHeight
+		doc.Text(statementPDFMargin, y, pdf.Helvetica, 10, fmt.Sprintf("Período: %s a %s (UTC)",
+			s.From.UTC().Format("02/01/2006 15:04"), s.LastDay().UTC().Format("02/01/2006 15:04")))
+		y -= 22
+
+		doc.Text(statementPDFDateX, y, pdf.HelveticaBold, statementPDFFontSize, "Data")
+		doc.Text(statementPDFDescX, y, pdf.HelveticaBold, statementPDFFontSize, "Histórico")
+		doc.Text(statementPDFAmountRight-30, y, pdf.HelveticaBold, statementPDFFontSize, "Valor")
+		doc.Text(statementPDFBalanceRight-30, y, pdf.HelveticaBold, statementPDFFontSize, "Saldo")
+		y -= 5
+		doc.Line(statementPDFMargin, y, pdf.A4Width-statementPDFMargin, y)
+		y -= statementPDFLineHeight
+	}
+
+	row := func(date, description, amount, balance string) {
+		if y < statementPDFMargin+statementPDFLineHeight {
+			newPage()
+		}
+		doc.Text(statementPDFDateX, y, pdf.Helvetica, statementPDFFontSize, date)
+		doc.Text(statementPDFDescX, y, pdf.Helvetica, statementPDFFontSize, truncateRunes(description, statementPDFDescMax))
+		if amount != "" {
+			doc.TextRight(statementPDFAmountRight, y, statementPDFFontSize, amount)
+		}
+		doc.TextRight(statementPDFBalanceRight, y, statementPDFFontSize, balance)
+		y -= statementPDFLineHeight
+	}
+
+	newPage()
+	row(s.From.UTC().Format("02/01/2006"), "Saldo inicial", "", FormatAmount(s.OpeningBalance))
+	for _, entry := range s.Entries {
+		row(entry.Date.UTC().Format("02/01/2006 15:04"), entry.Description, FormatAmount(entry.Amount), FormatAmount(entry.Balance))
+	}
+	row(s.LastDay().UTC().Format("02/01/2006"), "Saldo final", "", FormatAmount(s.ClosingBalance))
+
+	y -= 6
+	if y < statementPDFMargin+3*statementPDFLineHeight {
+		newPage()
+	}
+	doc.Text(statementPDFMargin, y, pdf.Helvetica, 10, "Total de créditos: "+FormatAmount(s.TotalCredits()))
+	y -= statementPDFLineHeight
+	doc.Text(statementPDFMargin, y, pdf.Helvetica, 10, "Total de débitos: "+FormatAmount(s.TotalDebits()))
+	y -= statementPDFLineHeight
+	doc.Text(statementPDFMargin, y, pdf.Helvetica, 8, "Gerado em "+s.GeneratedAt.UTC().Format("02/01/2006 15:04:05")+" UTC")
+
+	return doc.Bytes()
+}
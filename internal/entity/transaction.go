@@ -16,23 +16,36 @@ const (
 	TransactionStatusCompleted  TransactionStatus = "completed"
 	TransactionStatusFailed     TransactionStatus = "failed"
 	TransactionStatusReversed   TransactionStatus = "reversed"
+	TransactionStatusScheduled  TransactionStatus = "scheduled"
+	TransactionStatusCancelled  TransactionStatus = "cancelled"
+	// TransactionStatusUnderReview foi retida pelo motor de risco com o saldo reservado
+	TransactionStatusUnderReview TransactionStatus = "under_review"
 )
 
+// MaxScheduleAhead é o prazo máximo para agendar uma transferência
+const MaxScheduleAhead = 365 * 24 * time.Hour
+
 type Transaction struct {
 	ID               string            `json:"id" db:"id"`
 	PayerID          string            `json:"payer_id" db:"payer_id"`
 	PayeeID          string            `json:"payee_id" db:"payee_id"`
 	Amount           decimal.Decimal   `json:"amount" db:"amount"`
+	FeeAmount        decimal.Decimal   `json:"fee_amount" db:"fee_amount"`
+	PricingPlanID    *string           `json:"pricing_plan_id,omitempty" db:"pricing_plan_id"`
 	Status           TransactionStatus `json:"status" db:"status"`
 	AuthorizationID  *string           `json:"authorization_id,omitempty" db:"authorization_id"`
 	NotificationSent bool              `json:"notification_sent" db:"notification_sent"`
 	FailureReason    *string           `json:"failure_reason,omitempty" db:"failure_reason"`
+	ScheduledFor     *time.Time        `json:"scheduled_for,omitempty" db:"scheduled_for"`
 	CreatedAt        time.Time         `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time         `json:"updated_at" db:"updated_at"`
 	CompletedAt      *time.Time        `json:"completed_at,omitempty" db:"completed_at"`
+	ReversedAt       *time.Time        `json:"reversed_at,omitempty" db:"reversed_at"`
 
 	Payer *User `json:"payer,omitempty" db:"-"`
 	Payee *User `json:"payee,omitempty" db:"-"`
+
+	EventRecorder `json:"-" db:"-"`
 }
 
 type TransactionRequest struct {
@@ -47,6 +60,7 @@ func NewTransaction(payerID, payeeID string, amount decimal.Decimal) (*Transacti
 		PayerID:          payerID,
 		PayeeID:          payeeID,
 		Amount:           amount,
+		FeeAmount:        decimal.Zero,
 		Status:           TransactionStatusPending,
 		NotificationSent: false,
 		CreatedAt:        time.Now(),
@@ -77,35 +91,134 @@ func (t *Transaction) Validate() error {
 		return errors.New("valor da transação deve ser maior que zero")
 	}
 
-	maxAmount := decimal.NewFromFloat(10000.00)
-	if t.Amount.GreaterThan(maxAmount) {
-		return errors.New("valor da transação excede o limite máximo")
-	}
-
 	return nil
 }
 
 func (t *Transaction) ValidateBusinessRules(payer, payee *User) error {
-	// Lojistas não podem ser pagadores
-	if payer.IsMerchant() {
-		return errors.New("lojistas não podem realizar transferências")
+	if err := t.ValidateParties(payer, payee); err != nil {
+		return err
 	}
 
 	if !payer.HasSufficientBalance(t.Amount) {
-		return errors.New("saldo insuficiente")
+		return ErrInsufficientBalance
+	}
+
+	return nil
+}
+
+// ValidateParties verifica as regras que não dependem do saldo, usadas também no agendamento
+func (t *Transaction) ValidateParties(payer, payee *User) error {
+	// Lojistas e contas bloqueadas, congeladas ou encerradas não podem ser pagadores
+	if err := payer.ValidateCanSend(); err != nil {
+		return err
 	}
 
 	if payer.ID == payee.ID {
-		return errors.New("não é possível transferir para si mesmo")
+		return ErrSelfTransfer
+	}
+
+	if err := payee.ValidateCanReceive(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ApplyFee registra a tarifa cobrada do recebedor e o plano que a originou
+func (t *Transaction) ApplyFee(fee decimal.Decimal, pricingPlanID *string) error {
+	if fee.LessThan(decimal.Zero) {
+		return errors.New("tarifa não pode ser negativa")
+	}
+
+	if fee.GreaterThanOrEqual(t.Amount) {
+		return ErrFeeExceedsAmount
+	}
+
+	t.FeeAmount = fee
+	t.PricingPlanID = pricingPlanID
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// NetAmount retorna o valor que chega ao recebedor após a tarifa
+func (t *Transaction) NetAmount() decimal.Decimal {
+	return t.Amount.Sub(t.FeeAmount)
+}
+
+// Schedule agenda a transferência para uma data futura
+func (t *Transaction) Schedule(at time.Time) error {
+	if !t.IsPending() {
+		return ErrTransactionNotPending
+	}
+
+	now := time.Now()
+	if !at.After(now) {
+		return ErrScheduleInPast
+	}
+
+	if at.After(now.Add(MaxScheduleAhead)) {
+		return ErrScheduleTooFar
+	}
+
+	t.Status = TransactionStatusScheduled
+	t.ScheduledFor = &at
+	t.UpdatedAt = now
+	return nil
+}
+
+// ScheduleOccurrence agenda a transferência gerada por um pagamento recorrente.
+// Diferente de Schedule, aceita a data da ocorrência mesmo que já tenha passado.
+func (t *Transaction) ScheduleOccurrence(at time.Time) error {
+	if !t.IsPending() {
+		return ErrTransactionNotPending
+	}
+
+	t.Status = TransactionStatusScheduled
+	t.ScheduledFor = &at
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// Activate libera uma transferência agendada para execução
+func (t *Transaction) Activate() error {
+	if !t.IsScheduled() {
+		return ErrTransactionNotScheduled
 	}
+	t.Status = TransactionStatusPending
+	t.UpdatedAt = time.Now()
+	return nil
+}
 
+// Cancel cancela uma transferência agendada antes da execução
+func (t *Transaction) Cancel(reason string) error {
+	if !t.IsScheduled() {
+		return ErrTransactionNotScheduled
+	}
+	t.Status = TransactionStatusCancelled
+	t.FailureReason = &reason
+	t.UpdatedAt = time.Now()
 	return nil
 }
 
+// EffectiveDate é a data usada para apurar limites: a do agendamento ou a da criação
+func (t *Transaction) EffectiveDate() time.Time {
+	if t.ScheduledFor != nil {
+		return *t.ScheduledFor
+	}
+	return t.CreatedAt
+}
+
 func (t *Transaction) Authorize(authorizationID string) {
 	t.Status = TransactionStatusAuthorized
 	t.AuthorizationID = &authorizationID
 	t.UpdatedAt = time.Now()
+	t.recordEvent(EventTransferAuthorized, eventAggregateTransaction, t.ID, TransferAuthorized{
+		TransactionID:   t.ID,
+		PayerID:         t.PayerID,
+		PayeeID:         t.PayeeID,
+		Amount:          t.Amount,
+		AuthorizationID: authorizationID,
+	}, t.UpdatedAt)
 }
 
 func (t *Transaction) Complete() {
@@ -113,18 +226,67 @@ func (t *Transaction) Complete() {
 	now := time.Now()
 	t.CompletedAt = &now
 	t.UpdatedAt = now
+	t.recordEvent(EventTransferCompleted, eventAggregateTransaction, t.ID, TransferCompleted{
+		TransactionID: t.ID,
+		PayerID:       t.PayerID,
+		PayeeID:       t.PayeeID,
+		Amount:        t.Amount,
+		FeeAmount:     t.FeeAmount,
+		NetAmount:     t.NetAmount(),
+		CompletedAt:   now,
+	}, now)
 }
 
 func (t *Transaction) Fail(reason string) {
 	t.Status = TransactionStatusFailed
 	t.FailureReason = &reason
 	t.UpdatedAt = time.Now()
+	t.recordEvent(EventTransferFailed, eventAggregateTransaction, t.ID, TransferFailed{
+		TransactionID: t.ID,
+		PayerID:       t.PayerID,
+		PayeeID:       t.PayeeID,
+		Amount:        t.Amount,
+		Reason:        reason,
+	}, t.UpdatedAt)
+}
+
+// HoldForReview retém a transação reservada até a decisão de um analista
+func (t *Transaction) HoldForReview(reason string) error {
+	if !t.IsPending() {
+		return ErrTransactionNotPending
+	}
+	t.Status = TransactionStatusUnderReview
+	t.FailureReason = &reason
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// ResumeFromReview devolve a transação aprovada na análise manual ao fluxo de autorização
+func (t *Transaction) ResumeFromReview() error {
+	if !t.IsUnderReview() {
+		return ErrTransactionNotUnderReview
+	}
+	t.Status = TransactionStatusPending
+	t.FailureReason = nil
+	t.UpdatedAt = time.Now()
+	return nil
 }
 
 func (t *Transaction) Reverse(reason string) {
+	now := time.Now()
 	t.Status = TransactionStatusReversed
 	t.FailureReason = &reason
-	t.UpdatedAt = time.Now()
+	t.UpdatedAt = now
+	t.ReversedAt = &now
+	t.recordEvent(EventTransferReversed, eventAggregateTransaction, t.ID, TransferReversed{
+		TransactionID: t.ID,
+		PayerID:       t.PayerID,
+		PayeeID:       t.PayeeID,
+		Amount:        t.Amount,
+		NetAmount:     t.NetAmount(),
+		Reason:        reason,
+		ReversedAt:    now,
+	}, now)
 }
 
 func (t *Transaction) MarkNotificationSent() {
@@ -132,6 +294,11 @@ func (t *Transaction) MarkNotificationSent() {
 	t.UpdatedAt = time.Now()
 }
 
+// IsVisibleTo informa se o usuário é o pagador ou o recebedor da transação
+func (t *Transaction) IsVisibleTo(userID string) bool {
+	return t.PayerID == userID || t.PayeeID == userID
+}
+
 func (t *Transaction) CanBeAuthorized() bool {
 	return t.Status == TransactionStatusPending
 }
@@ -164,6 +331,18 @@ func (t *Transaction) IsReversed() bool {
 	return t.Status == TransactionStatusReversed
 }
 
+func (t *Transaction) IsScheduled() bool {
+	return t.Status == TransactionStatusScheduled
+}
+
+func (t *Transaction) IsUnderReview() bool {
+	return t.Status == TransactionStatusUnderReview
+}
+
+func (t *Transaction) IsCancelled() bool {
+	return t.Status == TransactionStatusCancelled
+}
+
 func (t *Transaction) GetStatusDescription() string {
 	switch t.Status {
 	case TransactionStatusPending:
@@ -176,11 +355,22 @@ func (t *Transaction) GetStatusDescription() string {
 		return "Falhou"
 	case TransactionStatusReversed:
 		return "Revertida"
+	case TransactionStatusScheduled:
+		return "Agendada"
+	case TransactionStatusCancelled:
+		return "Cancelada"
+	case TransactionStatusUnderReview:
+		return "Em análise de risco"
 	default:
 		return "Status desconhecido"
 	}
 }
 
 func (t *Transaction) GetAmountFormatted() string {
-	return "R$ " + t.Amount.StringFixed(2)
+	return FormatAmount(t.Amount)
+}
+
+// FormatAmount formata um valor em reais com duas casas decimais
+func FormatAmount(amount decimal.Decimal) string {
+	return "R$ " + amount.StringFixed(2)
 }
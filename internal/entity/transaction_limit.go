@@ -0,0 +1,151 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type LimitPeriod string
+
+const (
+	LimitPeriodDaily   LimitPeriod = "daily"
+	LimitPeriodMonthly LimitPeriod = "monthly"
+	LimitPeriodNightly LimitPeriod = "nightly"
+)
+
+// TransactionLimits reúne os limites aplicados às transferências de um usuário
+type TransactionLimits struct {
+	PerTransaction decimal.Decimal `json:"per_transaction" db:"per_transaction"`
+	Daily          decimal.Decimal `json:"daily" db:"daily"`
+	Monthly        decimal.Decimal `json:"monthly" db:"monthly"`
+	Nightly        decimal.Decimal `json:"nightly" db:"nightly"`
+}
+
+// UserLimitOverride sobrescreve os limites padrão do tipo de usuário; campos nulos herdam o padrão
+type UserLimitOverride struct {
+	UserID         string           `json:"user_id" db:"user_id"`
+	PerTransaction *decimal.Decimal `json:"per_transaction,omitempty" db:"per_transaction"`
+	Daily          *decimal.Decimal `json:"daily,omitempty" db:"daily"`
+	Monthly        *decimal.Decimal `json:"monthly,omitempty" db:"monthly"`
+	Nightly        *decimal.Decimal `json:"nightly,omitempty" db:"nightly"`
+	UpdatedAt      time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// LimitUsage acumula o valor já transferido em cada período
+type LimitUsage struct {
+	Daily   decimal.Decimal `json:"daily"`
+	Monthly decimal.Decimal `json:"monthly"`
+	Nightly decimal.Decimal `json:"nightly"`
+}
+
+// LimitPolicy define o fuso horário e a janela noturna usados para apurar os períodos
+type LimitPolicy struct {
+	Location       *time.Location
+	NightStartHour int
+	NightEndHour   int
+}
+
+func (l TransactionLimits) Validate() error {
+	values := map[string]decimal.Decimal{
+		"por transação": l.PerTransaction,
+		"diário":        l.Daily,
+		"mensal":        l.Monthly,
+		"noturno":       l.Nightly,
+	}
+	for name, value := range values {
+		if value.LessThan(decimal.Zero) {
+			return fmt.Errorf("limite %s não pode ser negativo", name)
+		}
+	}
+
+	if l.Daily.GreaterThan(l.Monthly) {
+		return errors.New("limite diário não pode ser maior que o mensal")
+	}
+
+	if l.Nightly.GreaterThan(l.Daily) {
+		return errors.New("limite noturno não pode ser maior que o diário")
+	}
+
+	return nil
+}
+
+// Apply combina os limites padrão com a sobrescrita do usuário
+func (l TransactionLimits) Apply(override *UserLimitOverride) TransactionLimits {
+	if override == nil {
+		return l
+	}
+	if override.PerTransaction != nil {
+		l.PerTransaction = *override.PerTransaction
+	}
+	if override.Daily != nil {
+		l.Daily = *override.Daily
+	}
+	if override.Monthly != nil {
+		l.Monthly = *override.Monthly
+	}
+	if override.Nightly != nil {
+		l.Nightly = *override.Nightly
+	}
+	return l
+}
+
+// Check verifica se um novo valor cabe nos limites considerando o uso acumulado
+func (l TransactionLimits) Check(amount decimal.Decimal, usage LimitUsage, night bool) error {
+	if amount.GreaterThan(l.PerTransaction) {
+		return fmt.Errorf("%w: por transação (R$ %s)", ErrAmountExceedsLimit, l.PerTransaction.StringFixed(2))
+	}
+
+	if usage.Daily.Add(amount).GreaterThan(l.Daily) {
+		return fmt.Errorf("%w: diário (R$ %s)", ErrAmountExceedsLimit, l.Daily.StringFixed(2))
+	}
+
+	if usage.Monthly.Add(amount).GreaterThan(l.Monthly) {
+		return fmt.Errorf("%w: mensal (R$ %s)", ErrAmountExceedsLimit, l.Monthly.StringFixed(2))
+	}
+
+	if night && usage.Nightly.Add(amount).GreaterThan(l.Nightly) {
+		return fmt.Errorf("%w: noturno (R$ %s)", ErrAmountExceedsLimit, l.Nightly.StringFixed(2))
+	}
+
+	return nil
+}
+
+// IsNight indica se o instante está dentro da janela noturna
+func (p LimitPolicy) IsNight(t time.Time) bool {
+	hour := t.In(p.location()).Hour()
+	if p.NightStartHour > p.NightEndHour {
+		return hour >= p.NightStartHour || hour < p.NightEndHour
+	}
+	return hour >= p.NightStartHour && hour < p.NightEndHour
+}
+
+// PeriodKeys retorna a chave de cada período que contém o instante.
+// A noite é identificada pela data em que começou, mesmo após a meia-noite.
+func (p LimitPolicy) PeriodKeys(t time.Time) map[LimitPeriod]string {
+	local := t.In(p.location())
+
+	keys := map[LimitPeriod]string{
+		LimitPeriodDaily:   local.Format("2006-01-02"),
+		LimitPeriodMonthly: local.Format("2006-01"),
+	}
+
+	if p.IsNight(t) {
+		nightStart := local
+		if p.NightStartHour > p.NightEndHour && local.Hour() < p.NightEndHour {
+			nightStart = local.AddDate(0, 0, -1)
+		}
+		keys[LimitPeriodNightly] = nightStart.Format("2006-01-02")
+	}
+
+	return keys
+}
+
+func (p LimitPolicy) location() *time.Location {
+	if p.Location == nil {
+		return time.UTC
+	}
+	return p.Location
+}
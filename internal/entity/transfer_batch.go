@@ -0,0 +1,272 @@
+package entity
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type BatchMode string
+
+const (
+	// BatchModeAllOrNothing conclui todas as transferências ou nenhuma
+	BatchModeAllOrNothing BatchMode = "all_or_nothing"
+	// BatchModeBestEffort processa cada transferência de forma independente
+	BatchModeBestEffort BatchMode = "best_effort"
+)
+
+type BatchStatus string
+
+const (
+	BatchStatusPending            BatchStatus = "pending"
+	BatchStatusProcessing         BatchStatus = "processing"
+	BatchStatusCompleted          BatchStatus = "completed"
+	BatchStatusPartiallyCompleted BatchStatus = "partially_completed"
+	BatchStatusFailed             BatchStatus = "failed"
+)
+
+type BatchItemStatus string
+
+const (
+	BatchItemStatusPending   BatchItemStatus = "pending"
+	BatchItemStatusCompleted BatchItemStatus = "completed"
+	BatchItemStatusFailed    BatchItemStatus = "failed"
+)
+
+// TransferBatch agrupa transferências de um mesmo pagador processadas em segundo plano
+type TransferBatch struct {
+	ID             string          `json:"id" db:"id"`
+	PayerID        string          `json:"payer_id" db:"payer_id"`
+	Mode           BatchMode       `json:"mode" db:"mode"`
+	Status         BatchStatus     `json:"status" db:"status"`
+	TotalItems     int             `json:"total_items" db:"total_items"`
+	TotalAmount    decimal.Decimal `json:"total_amount" db:"total_amount"`
+	SucceededCount int             `json:"succeeded_count" db:"succeeded_count"`
+	FailedCount    int             `json:"failed_count" db:"failed_count"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+	CompletedAt    *time.Time      `json:"completed_at,omitempty" db:"completed_at"`
+
+	Items []*BatchItem `json:"items,omitempty" db:"-"`
+}
+
+// BatchItem é uma transferência do lote; Position é a ordem de envio (linha do CSV, a partir de 1)
+type BatchItem struct {
+	ID            string          `json:"id" db:"id"`
+	BatchID       string          `json:"batch_id" db:"batch_id"`
+	Position      int             `json:"position" db:"position"`
+	PayeeID       string          `json:"payee_id" db:"payee_id"`
+	PayeeKey      *string         `json:"payee_key,omitempty" db:"payee_key"`
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	Status        BatchItemStatus `json:"status" db:"status"`
+	TransactionID *string         `json:"transaction_id,omitempty" db:"transaction_id"`
+	FailureReason *string         `json:"failure_reason,omitempty" db:"failure_reason"`
+	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// BatchItemError descreve por que um item foi rejeitado na validação do lote
+type BatchItemError struct {
+	Position int    `json:"position"`
+	Message  string `json:"message"`
+}
+
+// BatchValidationError reúne os erros de todos os itens inválidos do lote
+type BatchValidationError struct {
+	Items []BatchItemError
+}
+
+func (e *BatchValidationError) Error() string {
+	return fmt.Sprintf("lote com %d itens inválidos", len(e.Items))
+}
+
+func (e *BatchValidationError) Unwrap() error {
+	return ErrInvalidBatch
+}
+
+func NewTransferBatch(payerID string, mode BatchMode) (*TransferBatch, error) {
+	if mode == "" {
+		mode = BatchModeBestEffort
+	}
+	if mode != BatchModeAllOrNothing && mode != BatchModeBestEffort {
+		return nil, ErrInvalidBatchMode
+	}
+
+	now := time.Now()
+	return &TransferBatch{
+		ID:          uuid.New().String(),
+		PayerID:     payerID,
+		Mode:        mode,
+		Status:      BatchStatusPending,
+		TotalAmount: decimal.Zero,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// AddItem inclui uma transferência no lote com o recebedor já resolvido
+func (b *TransferBatch) AddItem(position int, payeeID string, payeeKey *string, amount decimal.Decimal) error {
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return errors.New("valor deve ser maior que zero")
+	}
+	if amount.Exponent() < -2 {
+		return errors.New("valor deve ter no máximo duas casas decimais")
+	}
+	if payeeID == b.PayerID {
+		return ErrSelfTransfer
+	}
+
+	b.Items = append(b.Items, &BatchItem{
+		ID:        uuid.New().String(),
+		BatchID:   b.ID,
+		Position:  position,
+		PayeeID:   payeeID,
+		PayeeKey:  payeeKey,
+		Amount:    amount,
+		Status:    BatchItemStatusPending,
+		UpdatedAt: time.Now(),
+	})
+	b.TotalItems = len(b.Items)
+	b.TotalAmount = b.TotalAmount.Add(amount)
+	return nil
+}
+
+// Start marca o lote como em processamento
+func (b *TransferBatch) Start() error {
+	if b.Status != BatchStatusPending {
+		return ErrBatchNotPending
+	}
+	b.Status = BatchStatusProcessing
+	b.UpdatedAt = time.Now()
+	return nil
+}
+
+// Finish consolida o resultado dos itens no status do lote
+func (b *TransferBatch) Finish() {
+	b.SucceededCount, b.FailedCount = 0, 0
+	for _, item := range b.Items {
+		switch item.Status {
+		case BatchItemStatusCompleted:
+			b.SucceededCount++
+		case BatchItemStatusFailed:
+			b.FailedCount++
+		}
+	}
+
+	switch {
+	case b.SucceededCount == b.TotalItems:
+		b.Status = BatchStatusCompleted
+	case b.SucceededCount == 0:
+		b.Status = BatchStatusFailed
+	default:
+		b.Status = BatchStatusPartiallyCompleted
+	}
+
+	now := time.Now()
+	b.CompletedAt = &now
+	b.UpdatedAt = now
+}
+
+// PendingItems retorna os itens ainda sem resultado; num lote retomado, os já concluídos ficam de fora
+func (b *TransferBatch) PendingItems() []*BatchItem {
+	var items []*BatchItem
+	for _, item := range b.Items {
+		if item.Status == BatchItemStatusPending {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Abort encerra o lote após um erro no processamento: os itens sem resultado falham com o motivo
+func (b *TransferBatch) Abort(reason string) {
+	for _, item := range b.PendingItems() {
+		item.Fail(nil, reason)
+	}
+	b.Finish()
+}
+
+func (i *BatchItem) Complete(transactionID string) {
+	i.Status = BatchItemStatusCompleted
+	i.TransactionID = &transactionID
+	i.FailureReason = nil
+	i.UpdatedAt = time.Now()
+}
+
+// Fail registra a falha do item; transactionID é nil quando a transação não chegou a ser gravada
+func (i *BatchItem) Fail(transactionID *string, reason string) {
+	i.Status = BatchItemStatusFailed
+	i.TransactionID = transactionID
+	i.FailureReason = &reason
+	i.UpdatedAt = time.Now()
+}
+
+// ParseBatchCSV lê os itens de um lote em CSV com cabeçalho. As colunas aceitas são
+// payee_id, payee_key e amount, em qualquer ordem; cada linha precisa de payee_id ou payee_key.
+func ParseBatchCSV(r io.Reader) ([]BatchItemRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: cabeçalho do CSV ausente", ErrInvalidBatch)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	amountColumn, ok := columns["amount"]
+	if !ok {
+		return nil, fmt.Errorf("%w: coluna amount é obrigatória", ErrInvalidBatch)
+	}
+	payeeIDColumn, hasPayeeID := columns["payee_id"]
+	payeeKeyColumn, hasPayeeKey := columns["payee_key"]
+	if !hasPayeeID && !hasPayeeKey {
+		return nil, fmt.Errorf("%w: coluna payee_id ou payee_key é obrigatória", ErrInvalidBatch)
+	}
+
+	var items []BatchItemRequest
+	validation := &BatchValidationError{}
+
+	for position := 1; ; position++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			validation.Items = append(validation.Items, BatchItemError{Position: position, Message: err.Error()})
+			continue
+		}
+
+		var item BatchItemRequest
+		if hasPayeeID && payeeIDColumn < len(record) {
+			item.PayeeID = strings.TrimSpace(record[payeeIDColumn])
+		}
+		if hasPayeeKey && payeeKeyColumn < len(record) {
+			item.PayeeKey = strings.TrimSpace(record[payeeKeyColumn])
+		}
+
+		if amountColumn < len(record) {
+			amount, err := decimal.NewFromString(strings.TrimSpace(record[amountColumn]))
+			if err != nil {
+				validation.Items = append(validation.Items, BatchItemError{Position: position, Message: "valor inválido"})
+			}
+			item.Amount = amount
+		}
+
+		items = append(items, item)
+	}
+
+	if len(validation.Items) > 0 {
+		return nil, validation
+	}
+
+	return items, nil
+}
@@ -18,34 +18,51 @@ const (
 )
 
 type User struct {
-	ID        string          `json:"id" db:"id"`
-	FullName  string          `json:"full_name" db:"full_name"`
-	Document  string          `json:"document" db:"document"`
-	Email     string          `json:"email" db:"email"`
-	Password  string          `json:"-" db:"password"`
-	UserType  UserType        `json:"user_type" db:"user_type"`
-	Balance   decimal.Decimal `json:"balance" db:"balance"`
-	CreatedAt time.Time       `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+	ID          string          `json:"id" db:"id"`
+	FullName    string          `json:"full_name" db:"full_name"`
+	Document    string          `json:"document" db:"document"`
+	Email       string          `json:"email" db:"email"`
+	Password    string          `json:"-" db:"password"`
+	UserType    UserType        `json:"user_type" db:"user_type"`
+	Balance     decimal.Decimal `json:"balance" db:"balance"`
+	HeldBalance decimal.Decimal `json:"held_balance" db:"held_balance"`
+	// Status é a situação da conta; StatusReason explica a última mudança
+	Status       AccountStatus `json:"status" db:"status"`
+	StatusReason *string       `json:"status_reason,omitempty" db:"status_reason"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at" db:"updated_at"`
+	// DeletedAt marca a exclusão lógica; AnonymizedAt, quando os dados pessoais foram apagados
+	DeletedAt    *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty" db:"anonymized_at"`
+
+	EventRecorder `json:"-" db:"-"`
 }
 
 func NewUser(fullName, document, email, password string, userType UserType) (*User, error) {
 	user := &User{
-		ID:        uuid.New().String(),
-		FullName:  strings.TrimSpace(fullName),
-		Document:  cleanDocument(document),
-		Email:     strings.ToLower(strings.TrimSpace(email)),
-		Password:  password,
-		UserType:  userType,
-		Balance:   decimal.Zero,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:          uuid.New().String(),
+		FullName:    strings.TrimSpace(fullName),
+		Document:    cleanDocument(document),
+		Email:       strings.ToLower(strings.TrimSpace(email)),
+		Password:    password,
+		UserType:    userType,
+		Balance:     decimal.Zero,
+		HeldBalance: decimal.Zero,
+		Status:      AccountStatusActive,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
 	}
 
 	if err := user.Validate(); err != nil {
 		return nil, err
 	}
 
+	user.recordEvent(EventUserCreated, eventAggregateUser, user.ID, UserCreated{
+		UserID:    user.ID,
+		UserType:  user.UserType,
+		CreatedAt: user.CreatedAt,
+	}, user.CreatedAt)
+
 	return user, nil
 }
 
@@ -106,11 +123,81 @@ func (u *User) IsCommon() bool {
 
 func (u *User) CanSendMoney() bool {
 	// Lojistas não podem ser pagadores
-	return u.UserType == UserTypeCommon
+	return u.UserType == UserTypeCommon && u.Status.CanSend()
+}
+
+// CanReceiveMoney informa se a conta pode receber transferências
+func (u *User) CanReceiveMoney() bool {
+	return u.Status.CanReceive()
+}
+
+// ValidateCanSend retorna o motivo pelo qual o usuário não pode enviar dinheiro
+func (u *User) ValidateCanSend() error {
+	if !u.IsCommon() {
+		return ErrMerchantCannotSend
+	}
+
+	switch u.Status {
+	case AccountStatusBlocked:
+		return ErrAccountBlocked
+	case AccountStatusFrozen:
+		return ErrAccountFrozen
+	case AccountStatusClosed:
+		return ErrAccountClosed
+	}
+
+	return nil
+}
+
+// ValidateCanReceive impede créditos em contas congeladas ou encerradas sem revelar o motivo ao pagador
+func (u *User) ValidateCanReceive() error {
+	if !u.CanReceiveMoney() {
+		return ErrPayeeCannotReceive
+	}
+	return nil
+}
+
+// AvailableBalance retorna o saldo livre, descontando os valores reservados
+func (u *User) AvailableBalance() decimal.Decimal {
+	return u.Balance.Sub(u.HeldBalance)
 }
 
 func (u *User) HasSufficientBalance(amount decimal.Decimal) bool {
-	return u.Balance.GreaterThanOrEqual(amount)
+	return u.AvailableBalance().GreaterThanOrEqual(amount)
+}
+
+// PlaceHold reserva parte do saldo disponível para uma transferência em andamento
+func (u *User) PlaceHold(amount decimal.Decimal) error {
+	if !u.HasSufficientBalance(amount) {
+		return ErrInsufficientBalance
+	}
+	u.HeldBalance = u.HeldBalance.Add(amount)
+	u.UpdatedAt = time.Now()
+	u.recordBalanceChanged()
+	return nil
+}
+
+// CaptureHold efetiva uma reserva, debitando o valor do saldo
+func (u *User) CaptureHold(amount decimal.Decimal) error {
+	if u.HeldBalance.LessThan(amount) {
+		return ErrHoldExceedsReserved
+	}
+	u.HeldBalance = u.HeldBalance.Sub(amount)
+	u.Balance = u.Balance.Sub(amount)
+	u.UpdatedAt = time.Now()
+	u.recordBalanceChanged()
+	return nil
+}
+
+// ReleaseHold devolve ao saldo disponível um valor previamente reservado
+func (u *User) ReleaseHold(amount decimal.Decimal) error {
+	if u.HeldBalance.LessThan(amount) {
+		return ErrHoldExceedsReserved
+	}
+	u.HeldBalance = u.HeldBalance.Sub(amount)
+	u.UpdatedAt = time.Now()
+	u.recordBalanceChanged()
+	return nil
 }
 
 func (u *User) DebitBalance(amount decimal.Decimal) error {
@@ -119,11 +206,25 @@ func (u *User) DebitBalance(amount decimal.Decimal) error {
 	}
 	u.Balance = u.Balance.Sub(amount)
 	u.UpdatedAt = time.Now()
+	u.recordBalanceChanged()
 	return nil
 }
 func (u *User) CreditBalance(amount decimal.Decimal) {
 	u.Balance = u.Balance.Add(amount)
 	u.UpdatedAt = time.Now()
+	u.recordBalanceChanged()
+}
+
+// recordBalanceChanged registra o saldo resultante; mudanças seguidas antes da publicação
+// resultam em um único evento com o estado final
+func (u *User) recordBalanceChanged() {
+	u.discardEvents(EventBalanceChanged)
+	u.recordEvent(EventBalanceChanged, eventAggregateUser, u.ID, BalanceChanged{
+		UserID:      u.ID,
+		Balance:     u.Balance,
+		HeldBalance: u.HeldBalance,
+		UpdatedAt:   u.UpdatedAt,
+	}, u.UpdatedAt)
 }
 
 func (u *User) UpdatePassword(newPassword string) error {
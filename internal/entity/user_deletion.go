@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"time"
+)
+
+// AnonymizedUserName substitui o nome dos titulares anonimizados
+const AnonymizedUserName = "Usuário anonimizado"
+
+// RetentionPolicy define por quanto tempo os dados pessoais de uma conta excluída são guardados
+// antes da anonimização. A LGPD permite manter os dados para cumprir obrigação legal (art. 16, I),
+// como a guarda de cadastros de clientes por cinco anos exigida pela Lei 9.613/98.
+type RetentionPolicy struct {
+	Period time.Duration
+}
+
+// AnonymizeImmediately informa se a anonimização deve acontecer junto com a exclusão
+func (p RetentionPolicy) AnonymizeImmediately() bool {
+	return p.Period <= 0
+}
+
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+func (u *User) IsAnonymized() bool {
+	return u.AnonymizedAt != nil
+}
+
+// SoftDelete marca a conta encerrada como excluída, mantendo a linha para o histórico financeiro
+func (u *User) SoftDelete(now time.Time) error {
+	if u.IsDeleted() {
+		return ErrUserNotFound
+	}
+	if !u.IsClosed() {
+		return ErrAccountNotClosed
+	}
+
+	u.DeletedAt = &now
+	u.UpdatedAt = now
+	return nil
+}
+
+// AnonymizationDueAt retorna quando termina a retenção dos dados pessoais da conta excluída
+func (u *User) AnonymizationDueAt(policy RetentionPolicy) *time.Time {
+	if !u.IsDeleted() {
+		return nil
+	}
+	dueAt := u.DeletedAt.Add(policy.Period)
+	return &dueAt
+}
+
+// Anonymize apaga nome, email, documento e senha de uma conta excluída cuja retenção terminou.
+// O ID, o tipo e os saldos são mantidos para que transações e lançamentos continuem consistentes.
+func (u *User) Anonymize(policy RetentionPolicy, now time.Time) error {
+	if !u.IsDeleted() {
+		return ErrUserNotDeleted
+	}
+	if u.IsAnonymized() {
+		return ErrUserAlreadyAnonymized
+	}
+	if now.Before(*u.AnonymizationDueAt(policy)) {
+		return ErrRetentionPeriodActive
+	}
+
+	u.FullName = AnonymizedUserName
+	u.Email = "anonimizado-" + u.ID + "@payflow.invalid"
+	u.Document = ""
+	u.Password = ""
+	u.AnonymizedAt = &now
+	u.UpdatedAt = now
+	return nil
+}
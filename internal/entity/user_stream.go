@@ -0,0 +1,151 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// UserStreamEventType é o nome do evento enviado no stream SSE do usuário
+type UserStreamEventType string
+
+const (
+	UserStreamBalanceUpdated     UserStreamEventType = "balance.updated"
+	UserStreamTransactionUpdated UserStreamEventType = "transaction.updated"
+	// UserStreamReset avisa que o Last-Event-ID é mais antigo que os eventos retidos: o cliente
+	// deve recarregar saldo e transações, porque eventos intermediários se perderam
+	UserStreamReset UserStreamEventType = "reset"
+)
+
+// Direções de uma transferência do ponto de vista do dono do stream
+const (
+	TransferDirectionSent     = "sent"
+	TransferDirectionReceived = "received"
+)
+
+// UserStreamEvent é uma mensagem do stream de um usuário. Os eventos ficam guardados por tempo
+// limitado para que o cliente retome a conexão a partir do último ID recebido.
+type UserStreamEvent struct {
+	ID            int64               `json:"id" db:"id"`
+	UserID        string              `json:"user_id" db:"user_id"`
+	Type          UserStreamEventType `json:"type" db:"event_type"`
+	SourceEventID string              `json:"source_event_id" db:"source_event_id"`
+	Data          json.RawMessage     `json:"data" db:"data"`
+	CreatedAt     time.Time           `json:"created_at" db:"created_at"`
+}
+
+func newUserStreamEvent(source *DomainEvent, userID string, eventType UserStreamEventType, data interface{}) (*UserStreamEvent, error) {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao codificar evento do stream: %w", err)
+	}
+	return &UserStreamEvent{
+		UserID:        userID,
+		Type:          eventType,
+		SourceEventID: source.ID,
+		Data:          content,
+		CreatedAt:     source.OccurredAt,
+	}, nil
+}
+
+// transferEventFields são os campos comuns às cargas dos eventos de transferência
+type transferEventFields struct {
+	TransactionID string `json:"transaction_id"`
+	PayerID       string `json:"payer_id"`
+	PayeeID       string `json:"payee_id"`
+}
+
+// UserStreamEventsFrom converte um evento de domínio nas mensagens dos streams dos usuários
+// envolvidos. Eventos que não interessam aos clientes resultam em lista vazia.
+func UserStreamEventsFrom(event *DomainEvent) ([]*UserStreamEvent, error) {
+	switch event.Type {
+	case EventBalanceChanged:
+		var payload BalanceChanged
+		if err := event.Decode(&payload); err != nil {
+			return nil, err
+		}
+		balance := &User{ID: payload.UserID, Balance: payload.Balance, HeldBalance: payload.HeldBalance, UpdatedAt: payload.UpdatedAt}
+		streamEvent, err := newUserStreamEvent(event, payload.UserID, UserStreamBalanceUpdated, balance.ToBalanceResponse())
+		if err != nil {
+			return nil, err
+		}
+		return []*UserStreamEvent{streamEvent}, nil
+
+	case EventTransferAuthorized, EventTransferFailed:
+		// O recebedor só fica sabendo da transferência quando o dinheiro chega
+		return transferStreamEvents(event, false)
+
+	case EventTransferCompleted, EventTransferReversed:
+		return transferStreamEvents(event, true)
+	}
+
+	return nil, nil
+}
+
+func transferStreamEvents(event *DomainEvent, notifyPayee bool) ([]*UserStreamEvent, error) {
+	var fields transferEventFields
+	if err := event.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	update := &TransactionStreamUpdate{
+		TransactionID: fields.TransactionID,
+		OccurredAt:    event.OccurredAt,
+	}
+	switch event.Type {
+	case EventTransferAuthorized:
+		var payload TransferAuthorized
+		if err := event.Decode(&payload); err != nil {
+			return nil, err
+		}
+		update.Status = TransactionStatusAuthorized
+		update.Amount = payload.Amount.StringFixed(2)
+	case EventTransferCompleted:
+		var payload TransferCompleted
+		if err := event.Decode(&payload); err != nil {
+			return nil, err
+		}
+		update.Status = TransactionStatusCompleted
+		update.Amount = payload.Amount.StringFixed(2)
+		update.NetAmount = payload.NetAmount.StringFixed(2)
+	case EventTransferFailed:
+		var payload TransferFailed
+		if err := event.Decode(&payload); err != nil {
+			return nil, err
+		}
+		update.Status = TransactionStatusFailed
+		update.Amount = payload.Amount.StringFixed(2)
+		update.Reason = &payload.Reason
+	case EventTransferReversed:
+		var payload TransferReversed
+		if err := event.Decode(&payload); err != nil {
+			return nil, err
+		}
+		update.Status = TransactionStatusReversed
+		update.Amount = payload.Amount.StringFixed(2)
+		update.NetAmount = payload.NetAmount.StringFixed(2)
+		update.Reason = &payload.Reason
+	}
+
+	sent := *update
+	sent.Direction = TransferDirectionSent
+	sent.CounterpartyID = fields.PayeeID
+	payerEvent, err := newUserStreamEvent(event, fields.PayerID, UserStreamTransactionUpdated, &sent)
+	if err != nil {
+		return nil, err
+	}
+	events := []*UserStreamEvent{payerEvent}
+
+	if notifyPayee {
+		received := *update
+		received.Direction = TransferDirectionReceived
+		received.CounterpartyID = fields.PayerID
+		payeeEvent, err := newUserStreamEvent(event, fields.PayeeID, UserStreamTransactionUpdated, &received)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, payeeEvent)
+	}
+
+	return events, nil
+}
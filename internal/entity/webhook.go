@@ -0,0 +1,450 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cabeçalhos enviados em cada entrega de webhook
+const (
+	WebhookSignatureHeader = "X-PayFlow-Signature"
+	WebhookTimestampHeader = "X-PayFlow-Timestamp"
+	WebhookEventHeader     = "X-PayFlow-Event"
+	WebhookDeliveryHeader  = "X-PayFlow-Delivery"
+
+	// webhookSignaturePrefix identifica o algoritmo no cabeçalho de assinatura
+	webhookSignaturePrefix = "sha256="
+	// webhookSecretPrefix facilita reconhecer o segredo em arquivos de configuração do lojista
+	webhookSecretPrefix = "whsec_"
+	// WebhookMaxEndpoints é o número máximo de endpoints por lojista
+	WebhookMaxEndpoints = 10
+	// webhookErrorMax limita o tamanho do erro guardado em cada tentativa
+	webhookErrorMax = 500
+)
+
+type WebhookEventType string
+
+const (
+	WebhookEventTransactionCompleted WebhookEventType = "transaction.completed"
+	WebhookEventTransactionReversed  WebhookEventType = "transaction.reversed"
+	WebhookEventDisputeOpened        WebhookEventType = "dispute.opened"
+	WebhookEventDisputeResolved      WebhookEventType = "dispute.resolved"
+)
+
+// WebhookEventTypes são os eventos que um endpoint pode assinar
+var WebhookEventTypes = []WebhookEventType{
+	WebhookEventTransactionCompleted,
+	WebhookEventTransactionReversed,
+	WebhookEventDisputeOpened,
+	WebhookEventDisputeResolved,
+}
+
+func (t WebhookEventType) IsValid() bool {
+	for _, eventType := range WebhookEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryStatusPending aguarda a próxima tentativa
+	WebhookDeliveryStatusPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryStatusSucceeded foi recebida pelo endpoint com status 2xx
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryStatusFailed esgotou as tentativas ou o endpoint foi desativado
+	WebhookDeliveryStatusFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookRetryPolicy define quantas vezes uma entrega é tentada e o intervalo entre as tentativas,
+// que dobra a cada falha a partir de BaseDelay até o teto MaxDelay
+type WebhookRetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// NextDelay retorna a espera antes da próxima tentativa, depois de attempts tentativas com falha
+func (p WebhookRetryPolicy) NextDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempts-1))
+	if delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// WebhookEndpoint é a URL do lojista que recebe os eventos assinados
+type WebhookEndpoint struct {
+	ID          string             `json:"id" db:"id"`
+	MerchantID  string             `json:"merchant_id" db:"merchant_id"`
+	URL         string             `json:"url" db:"url"`
+	Description string             `json:"description,omitempty" db:"description"`
+	EventTypes  []WebhookEventType `json:"event_types" db:"event_types"`
+	// Secret assina as entregas; só é exibido na criação
+	Secret    string    `json:"-" db:"secret"`
+	Active    bool      `json:"active" db:"active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookURLPolicy define quais URLs de endpoint são aceitas. Fora do ambiente de desenvolvimento só
+// https para endereços públicos; AllowLocalhost libera http://localhost para o receptor de testes local.
+type WebhookURLPolicy struct {
+	AllowLocalhost bool
+}
+
+func NewWebhookEndpoint(merchant *User, rawURL, description string, eventTypes []WebhookEventType, policy WebhookURLPolicy, now time.Time) (*WebhookEndpoint, error) {
+	if !merchant.IsMerchant() {
+		return nil, ErrOnlyMerchantsCanUseWebhooks
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &WebhookEndpoint{
+		ID:          uuid.New().String(),
+		MerchantID:  merchant.ID,
+		Description: strings.TrimSpace(description),
+		Secret:      secret,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := endpoint.setURL(rawURL, policy); err != nil {
+		return nil, err
+	}
+	if err := endpoint.setEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+// ApplyUpdateWebhookEndpointRequest altera apenas os campos informados
+func (e *WebhookEndpoint) ApplyUpdateWebhookEndpointRequest(req *UpdateWebhookEndpointRequest, policy WebhookURLPolicy, now time.Time) error {
+	if req.URL != nil {
+		if err := e.setURL(*req.URL, policy); err != nil {
+			return err
+		}
+	}
+	if req.EventTypes != nil {
+		if err := e.setEventTypes(req.EventTypes); err != nil {
+			return err
+		}
+	}
+	if req.Description != nil {
+		e.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Active != nil {
+		e.Active = *req.Active
+	}
+	e.UpdatedAt = now
+	return nil
+}
+
+// Subscribes informa se o endpoint ativo deve receber o evento
+func (e *WebhookEndpoint) Subscribes(eventType WebhookEventType) bool {
+	if !e.Active {
+		return false
+	}
+	for _, subscribed := range e.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// setURL aceita apenas URLs https absolutas que não apontem para a rede interna. Nomes só são
+// resolvidos no envio, que confere de novo o endereço de cada conexão.
+func (e *WebhookEndpoint) setURL(rawURL string, policy WebhookURLPolicy) error {
+	rawURL = strings.TrimSpace(rawURL)
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" || parsed.User != nil {
+		return ErrInvalidWebhookURL
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	local := host == "localhost" || (net.ParseIP(host) != nil && net.ParseIP(host).IsLoopback())
+	switch {
+	case local && policy.AllowLocalhost:
+		if parsed.Scheme != "https" && parsed.Scheme != "http" {
+			return ErrInvalidWebhookURL
+		}
+	case parsed.Scheme != "https" || local:
+		return ErrInvalidWebhookURL
+	}
+
+	if ip := net.ParseIP(host); ip != nil && !local && !IsPublicIP(ip) {
+		return ErrInvalidWebhookURL
+	}
+
+	e.URL = rawURL
+	return nil
+}
+
+// IsPublicIP informa se o endereço pode receber webhooks: recusa loopback, redes privadas,
+// link-local (incluindo o serviço de metadados das nuvens), multicast e o endereço não especificado
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// setEventTypes grava os eventos sem repetição, na ordem informada
+func (e *WebhookEndpoint) setEventTypes(eventTypes []WebhookEventType) error {
+	if len(eventTypes) == 0 {
+		return ErrInvalidWebhookEventType
+	}
+
+	seen := make(map[WebhookEventType]bool, len(eventTypes))
+	unique := make([]WebhookEventType, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !eventType.IsValid() {
+			return fmt.Errorf("%w: %s", ErrInvalidWebhookEventType, eventType)
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			unique = append(unique, eventType)
+		}
+	}
+	e.EventTypes = unique
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("erro ao gerar segredo do webhook: %w", err)
+	}
+	return webhookSecretPrefix + hex.EncodeToString(raw), nil
+}
+
+// WebhookEvent é o corpo enviado ao lojista; o mesmo evento tem o mesmo ID em todas as entregas
+type WebhookEvent struct {
+	ID         string           `json:"id"`
+	Type       WebhookEventType `json:"type"`
+	MerchantID string           `json:"merchant_id"`
+	CreatedAt  time.Time        `json:"created_at"`
+	Data       interface{}      `json:"data"`
+}
+
+func NewWebhookEvent(eventType WebhookEventType, merchantID string, data interface{}, now time.Time) *WebhookEvent {
+	return &WebhookEvent{
+		ID:         "evt_" + uuid.New().String(),
+		Type:       eventType,
+		MerchantID: merchantID,
+		CreatedAt:  now.UTC(),
+		Data:       data,
+	}
+}
+
+// WebhookDelivery é o envio de um evento para um endpoint, com as tentativas e o resultado
+type WebhookDelivery struct {
+	ID         string                `json:"id" db:"id"`
+	EndpointID string                `json:"endpoint_id" db:"endpoint_id"`
+	MerchantID string                `json:"merchant_id" db:"merchant_id"`
+	EventID    string                `json:"event_id" db:"event_id"`
+	EventType  WebhookEventType      `json:"event_type" db:"event_type"`
+	Payload    []byte                `json:"-" db:"payload"`
+	Status     WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts   int                   `json:"attempts" db:"attempts"`
+	// RedeliveryOf aponta a entrega original quando o lojista pede o reenvio manual
+	RedeliveryOf       *string    `json:"redelivery_of,omitempty" db:"redelivery_of"`
+	NextAttemptAt      *time.Time `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastResponseStatus *int       `json:"last_response_status,omitempty" db:"last_response_status"`
+	LastError          *string    `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+	DeliveredAt        *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+
+	AttemptLog []*WebhookAttempt `json:"attempt_log,omitempty" db:"-"`
+}
+
+// WebhookAttempt registra uma tentativa de entrega, com a resposta do endpoint ou o erro de rede
+type WebhookAttempt struct {
+	ID             string    `json:"id" db:"id"`
+	DeliveryID     string    `json:"delivery_id" db:"delivery_id"`
+	Number         int       `json:"number" db:"number"`
+	ResponseStatus *int      `json:"response_status,omitempty" db:"response_status"`
+	Error          *string   `json:"error,omitempty" db:"error"`
+	DurationMs     int64     `json:"duration_ms" db:"duration_ms"`
+	AttemptedAt    time.Time `json:"attempted_at" db:"attempted_at"`
+}
+
+// NewWebhookDelivery agenda a primeira tentativa de envio do evento para o endpoint
+func NewWebhookDelivery(endpoint *WebhookEndpoint, event *WebhookEvent, now time.Time) (*WebhookDelivery, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao montar evento do webhook: %w", err)
+	}
+
+	return &WebhookDelivery{
+		ID:            uuid.New().String(),
+		EndpointID:    endpoint.ID,
+		MerchantID:    endpoint.MerchantID,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Payload:       payload,
+		Status:        WebhookDeliveryStatusPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// RecordAttempt registra o resultado de uma tentativa. Status 2xx conclui a entrega; nos demais casos
+// a próxima tentativa é agendada com espera exponencial até esgotar policy.MaxAttempts.
+func (d *WebhookDelivery) RecordAttempt(responseStatus int, sendErr error, duration time.Duration, policy WebhookRetryPolicy, now time.Time) *WebhookAttempt {
+	d.Attempts++
+	attempt := &WebhookAttempt{
+		ID:          uuid.New().String(),
+		DeliveryID:  d.ID,
+		Number:      d.Attempts,
+		DurationMs:  duration.Milliseconds(),
+		AttemptedAt: now,
+	}
+	if responseStatus > 0 {
+		attempt.ResponseStatus = &responseStatus
+	}
+
+	var failure string
+	switch {
+	case sendErr != nil:
+		failure = sendErr.Error()
+	case responseStatus < 200 || responseStatus > 299:
+		failure = fmt.Sprintf("endpoint respondeu com status %d", responseStatus)
+	}
+
+	d.LastResponseStatus = attempt.ResponseStatus
+	d.UpdatedAt = now
+
+	if failure == "" {
+		d.Status = WebhookDeliveryStatusSucceeded
+		d.LastError = nil
+		d.NextAttemptAt = nil
+		d.DeliveredAt = &now
+		return attempt
+	}
+
+	failure = truncateRunes(failure, webhookErrorMax)
+	attempt.Error = &failure
+	d.LastError = &failure
+
+	if d.Attempts >= policy.MaxAttempts {
+		d.Status = WebhookDeliveryStatusFailed
+		d.NextAttemptAt = nil
+		return attempt
+	}
+
+	next := now.Add(policy.NextDelay(d.Attempts))
+	d.Status = WebhookDeliveryStatusPending
+	d.NextAttemptAt = &next
+	return attempt
+}
+
+// Abandon encerra a entrega sem nova tentativa, quando o endpoint foi desativado
+func (d *WebhookDelivery) Abandon(reason string, now time.Time) {
+	d.Status = WebhookDeliveryStatusFailed
+	d.LastError = &reason
+	d.NextAttemptAt = nil
+	d.UpdatedAt = now
+}
+
+// Redeliver cria uma nova entrega do mesmo evento, com o mesmo corpo e um novo ciclo de tentativas.
+// A entrega original fica no histórico como estava.
+func (d *WebhookDelivery) Redeliver(now time.Time) (*WebhookDelivery, error) {
+	if d.Status == WebhookDeliveryStatusPending {
+		return nil, ErrWebhookDeliveryPending
+	}
+
+	originalID := d.ID
+	return &WebhookDelivery{
+		ID:            uuid.New().String(),
+		EndpointID:    d.EndpointID,
+		MerchantID:    d.MerchantID,
+		EventID:       d.EventID,
+		EventType:     d.EventType,
+		Payload:       d.Payload,
+		Status:        WebhookDeliveryStatusPending,
+		RedeliveryOf:  &originalID,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+func (d *WebhookDelivery) GetStatusDescription() string {
+	switch d.Status {
+	case WebhookDeliveryStatusPending:
+		return "Aguardando envio"
+	case WebhookDeliveryStatusSucceeded:
+		return "Entregue"
+	case WebhookDeliveryStatusFailed:
+		return "Falhou"
+	default:
+		return "Status desconhecido"
+	}
+}
+
+// SignWebhookPayload calcula o HMAC-SHA256 de "<timestamp>.<corpo>" com o segredo do endpoint.
+// Incluir o timestamp na assinatura impede que uma entrega capturada seja reenviada depois.
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookHeaders monta os cabeçalhos de uma tentativa, assinada no instante do envio
+func WebhookHeaders(delivery *WebhookDelivery, secret string, now time.Time) map[string]string {
+	timestamp := now.Unix()
+	return map[string]string{
+		"Content-Type":         "application/json",
+		WebhookEventHeader:     string(delivery.EventType),
+		WebhookDeliveryHeader:  delivery.ID,
+		WebhookTimestampHeader: strconv.FormatInt(timestamp, 10),
+		WebhookSignatureHeader: SignWebhookPayload(secret, timestamp, delivery.Payload),
+	}
+}
+
+// VerifyWebhookSignature é a conferência que o lojista faz ao receber uma entrega: a assinatura em
+// tempo constante e o timestamp dentro da tolerância, para recusar entregas antigas reenviadas.
+func VerifyWebhookSignature(secret, timestampHeader, signatureHeader string, payload []byte, tolerance time.Duration, now time.Time) error {
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidWebhookSignature
+	}
+
+	expected := SignWebhookPayload(secret, timestamp, payload)
+	if !hmac.Equal([]byte(expected), []byte(signatureHeader)) {
+		return ErrInvalidWebhookSignature
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrWebhookTimestampExpired
+	}
+	return nil
+}
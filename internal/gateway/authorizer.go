@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"payflow-api/internal/entity"
+
+	"github.com/google/uuid"
+)
+
+// Authorizer consulta o serviço externo que aprova ou nega transferências.
+type Authorizer interface {
+	// Authorize retorna o identificador da autorização ou um erro se a transferência for negada.
+	Authorize(ctx context.Context, transaction *entity.Transaction) (string, error)
+}
+
+type httpAuthorizer struct {
+	url    string
+	client *http.Client
+}
+
+type authorizerResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Authorization bool `json:"authorization"`
+	} `json:"data"`
+}
+
+// NewHTTPAuthorizer cria um cliente para o autorizador externo
+func NewHTTPAuthorizer(url string, timeout time.Duration) Authorizer {
+	return &httpAuthorizer{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (a *httpAuthorizer) Authorize(ctx context.Context, transaction *entity.Transaction) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url, nil)
+	if err != nil {
+		return "", fmt.Errorf("erro ao montar requisição de autorização: %w", err)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) || isTimeout(err) {
+			return "", entity.ErrAuthorizationTimeout
+		}
+		return "", fmt.Errorf("%w: %v", entity.ErrAuthorizationService, err)
+	}
+	defer resp.Body.Close()
+
+	var body authorizerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("%w: resposta inválida (status %d)", entity.ErrAuthorizationService, resp.StatusCode)
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return "", fmt.Errorf("%w: status %d", entity.ErrAuthorizationService, resp.StatusCode)
+	}
+
+	if !body.Data.Authorization {
+		return "", entity.ErrAuthorizationFailed
+	}
+
+	return "auth_" + uuid.New().String(), nil
+}
+
+func isTimeout(err error) bool {
+	var timeoutErr interface{ Timeout() bool }
+	return errors.As(err, &timeoutErr) && timeoutErr.Timeout()
+}
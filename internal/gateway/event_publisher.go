@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+
+	"payflow-api/internal/entity"
+)
+
+// Cabeçalhos das mensagens publicadas no broker
+const (
+	eventIDHeader            = "Event-ID"
+	eventTypeHeader          = "Event-Type"
+	eventSchemaVersionHeader = "Event-Schema-Version"
+	eventPartitionKeyHeader  = "Event-Partition-Key"
+)
+
+// EventPublisher publica os eventos de domínio em um broker para outros sistemas.
+type EventPublisher interface {
+	// Publish envia as mensagens em ordem e retorna quantas, a partir da primeira, o broker
+	// confirmou. O erro explica por que as demais não foram confirmadas.
+	Publish(ctx context.Context, messages []*entity.EventMessage) (int, error)
+	// Close libera a conexão com o broker.
+	Close() error
+}
+
+// MemoryEventPublisher guarda as mensagens em memória, para desenvolvimento e testes
+type MemoryEventPublisher struct {
+	mu       sync.Mutex
+	messages []*entity.EventMessage
+	// FailAfter, se maior ou igual a zero, faz a publicação falhar depois de tantas mensagens
+	FailAfter int
+}
+
+// NewMemoryEventPublisher cria um publicador em memória que aceita todas as mensagens
+func NewMemoryEventPublisher() *MemoryEventPublisher {
+	return &MemoryEventPublisher{FailAfter: -1}
+}
+
+func (p *MemoryEventPublisher) Publish(ctx context.Context, messages []*entity.EventMessage) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, message := range messages {
+		if p.FailAfter >= 0 && len(p.messages) >= p.FailAfter {
+			return i, entity.ErrEventPublisherUnavailable
+		}
+		p.messages = append(p.messages, message)
+	}
+	return len(messages), nil
+}
+
+func (p *MemoryEventPublisher) Close() error {
+	return nil
+}
+
+// Messages retorna as mensagens publicadas, na ordem de publicação
+func (p *MemoryEventPublisher) Messages() []*entity.EventMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]*entity.EventMessage(nil), p.messages...)
+}
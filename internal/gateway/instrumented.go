@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/metrics"
+)
+
+// Nomes dos serviços externos nas métricas
+const (
+	authorizerService = "authorizer"
+	notifierService   = "notifier"
+)
+
+type instrumentedAuthorizer struct {
+	next    Authorizer
+	metrics *metrics.Metrics
+}
+
+// NewInstrumentedAuthorizer mede a latência e o resultado das consultas ao autorizador
+func NewInstrumentedAuthorizer(next Authorizer, m *metrics.Metrics) Authorizer {
+	return &instrumentedAuthorizer{
+		next:    next,
+		metrics: m,
+	}
+}
+
+func (a *instrumentedAuthorizer) Authorize(ctx context.Context, transaction *entity.Transaction) (string, error) {
+	start := time.Now()
+	authorizationID, err := a.next.Authorize(ctx, transaction)
+
+	outcome := metrics.OutcomeSuccess
+	switch {
+	case err == nil:
+	case errors.Is(err, entity.ErrAuthorizationFailed):
+		outcome = metrics.OutcomeDenied
+	case errors.Is(err, entity.ErrAuthorizationTimeout):
+		outcome = metrics.OutcomeTimeout
+	default:
+		outcome = metrics.OutcomeError
+	}
+	a.metrics.ObserveExternalCall(authorizerService, outcome, time.Since(start))
+
+	return authorizationID, err
+}
+
+type instrumentedNotifier struct {
+	next    Notifier
+	metrics *metrics.Metrics
+}
+
+// NewInstrumentedNotifier mede a latência e o resultado dos envios ao serviço de notificação
+func NewInstrumentedNotifier(next Notifier, m *metrics.Metrics) Notifier {
+	return &instrumentedNotifier{
+		next:    next,
+		metrics: m,
+	}
+}
+
+func (n *instrumentedNotifier) Notify(ctx context.Context, payee *entity.User, transaction *entity.Transaction) error {
+	start := time.Now()
+	err := n.next.Notify(ctx, payee, transaction)
+
+	outcome := metrics.OutcomeSuccess
+	switch {
+	case err == nil:
+	case errors.Is(err, entity.ErrNotificationTimeout):
+		outcome = metrics.OutcomeTimeout
+	default:
+		outcome = metrics.OutcomeError
+	}
+	n.metrics.ObserveExternalCall(notifierService, outcome, time.Since(start))
+
+	return err
+}
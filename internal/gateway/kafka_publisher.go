@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"payflow-api/internal/entity"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type kafkaEventPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaEventPublisher publica no tópico com a chave de partição como chave da mensagem. O
+// particionamento usa murmur2, o mesmo do cliente Java, então outros produtores com a mesma chave
+// caem na mesma partição. O Kafka não descarta reenvios: os consumidores usam o cabeçalho Event-ID.
+func NewKafkaEventPublisher(brokers []string, topic string, timeout time.Duration) EventPublisher {
+	return &kafkaEventPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Murmur2Balancer{},
+			RequiredAcks: kafka.RequireAll,
+			// O relay já agrupa os eventos; esperar lotes maiores só atrasaria a confirmação
+			BatchTimeout: 10 * time.Millisecond,
+			WriteTimeout: timeout,
+			ReadTimeout:  timeout,
+		},
+	}
+}
+
+func (p *kafkaEventPublisher) Publish(ctx context.Context, messages []*entity.EventMessage) (int, error) {
+	records := make([]kafka.Message, 0, len(messages))
+	for i, message := range messages {
+		content, err := message.Encode()
+		if err != nil {
+			return i, err
+		}
+		records = append(records, kafka.Message{
+			Key:   []byte(message.PartitionKey),
+			Value: content,
+			Headers: []kafka.Header{
+				{Key: eventIDHeader, Value: []byte(message.ID)},
+				{Key: eventTypeHeader, Value: []byte(message.Type)},
+				{Key: eventSchemaVersionHeader, Value: []byte(strconv.Itoa(message.SchemaVersion))},
+			},
+			Time: message.OccurredAt,
+		})
+	}
+
+	err := p.writer.WriteMessages(ctx, records...)
+	if err == nil {
+		return len(messages), nil
+	}
+
+	// Com falha parcial, só conta como publicado o trecho inicial confirmado
+	var writeErrors kafka.WriteErrors
+	if errors.As(err, &writeErrors) {
+		for i, writeErr := range writeErrors {
+			if writeErr != nil {
+				return i, fmt.Errorf("%w: %v", entity.ErrEventPublisherUnavailable, writeErr)
+			}
+		}
+		return len(messages), nil
+	}
+	return 0, fmt.Errorf("%w: %v", entity.ErrEventPublisherUnavailable, err)
+}
+
+func (p *kafkaEventPublisher) Close() error {
+	return p.writer.Close()
+}
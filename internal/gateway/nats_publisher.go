@@ -0,0 +1,79 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"payflow-api/internal/entity"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type natsEventPublisher struct {
+	conn       *nats.Conn
+	js         jetstream.JetStream
+	subject    string
+	partitions int
+	timeout    time.Duration
+}
+
+// NewNATSEventPublisher publica no JetStream no assunto <subject>.<partição>.<tipo do evento>, por
+// exemplo payflow.events.3.transfer.completed. O stream deve existir e cobrir <subject>.>; o ID do
+// evento vai como Nats-Msg-Id, então reenvios dentro da janela de duplicatas do stream são descartados.
+func NewNATSEventPublisher(url, subject string, partitions int, timeout time.Duration) (EventPublisher, error) {
+	// Sem o broker no ar a API sobe mesmo assim: os eventos esperam no outbox até a conexão voltar
+	conn, err := nats.Connect(url,
+		nats.Name("payflow-api"),
+		nats.Timeout(timeout),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar no NATS: %w", err)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("erro ao abrir o JetStream: %w", err)
+	}
+
+	return &natsEventPublisher{
+		conn:       conn,
+		js:         js,
+		subject:    subject,
+		partitions: partitions,
+		timeout:    timeout,
+	}, nil
+}
+
+func (p *natsEventPublisher) Publish(ctx context.Context, messages []*entity.EventMessage) (int, error) {
+	for i, message := range messages {
+		content, err := message.Encode()
+		if err != nil {
+			return i, err
+		}
+
+		msg := nats.NewMsg(fmt.Sprintf("%s.%d.%s", p.subject, message.Partition(p.partitions), message.Type))
+		msg.Data = content
+		msg.Header.Set(eventTypeHeader, string(message.Type))
+		msg.Header.Set(eventSchemaVersionHeader, strconv.Itoa(message.SchemaVersion))
+		msg.Header.Set(eventPartitionKeyHeader, message.PartitionKey)
+
+		// Uma mensagem por vez preserva a ordem dentro da partição
+		publishCtx, cancel := context.WithTimeout(ctx, p.timeout)
+		_, err = p.js.PublishMsg(publishCtx, msg, jetstream.WithMsgID(message.ID))
+		cancel()
+		if err != nil {
+			return i, fmt.Errorf("%w: %v", entity.ErrEventPublisherUnavailable, err)
+		}
+	}
+	return len(messages), nil
+}
+
+func (p *natsEventPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"payflow-api/internal/entity"
+)
+
+// Notifier envia a notificação de recebimento ao recebedor de uma transferência.
+type Notifier interface {
+	// Notify envia a notificação; o serviço pode estar instável, então o chamador decide se tenta de novo.
+	Notify(ctx context.Context, payee *entity.User, transaction *entity.Transaction) error
+}
+
+type httpNotifier struct {
+	url    string
+	client *http.Client
+}
+
+type notificationPayload struct {
+	Email   string `json:"email"`
+	Message string `json:"message"`
+}
+
+// NewHTTPNotifier cria um cliente para o serviço externo de notificação
+func NewHTTPNotifier(url string, timeout time.Duration) Notifier {
+	return &httpNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *httpNotifier) Notify(ctx context.Context, payee *entity.User, transaction *entity.Transaction) error {
+	payload, err := json.Marshal(notificationPayload{
+		Email:   payee.Email,
+		Message: fmt.Sprintf("Você recebeu uma transferência de %s", transaction.GetAmountFormatted()),
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao montar notificação: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("erro ao montar requisição de notificação: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		if isTimeout(err) {
+			return entity.ErrNotificationTimeout
+		}
+		return fmt.Errorf("%w: %v", entity.ErrNotificationService, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: status %d", entity.ErrNotificationFailed, resp.StatusCode)
+	}
+
+	return nil
+}
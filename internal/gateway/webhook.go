@@ -0,0 +1,100 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"payflow-api/internal/entity"
+)
+
+// webhookMaxRedirects evita que o endpoint do lojista encadeie redirecionamentos sem fim
+const webhookMaxRedirects = 3
+
+// WebhookSender envia as entregas de webhook para os endpoints dos lojistas.
+type WebhookSender interface {
+	// Send faz o POST do corpo com os cabeçalhos e retorna o status HTTP da resposta.
+	// O erro indica falha de rede ou timeout, quando não há status.
+	Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error)
+}
+
+type httpWebhookSender struct {
+	client *http.Client
+}
+
+// errBlockedAddress indica uma conexão recusada por apontar para a rede interna
+var errBlockedAddress = errors.New("endereço do webhook não é público")
+
+// NewHTTPWebhookSender cria um cliente para os endpoints de webhook. O endereço de cada conexão é
+// conferido depois da resolução do nome, o que vale também para redirecionamentos e impede que um
+// DNS que muda de resposta leve o envio para a rede interna. allowLoopback libera o receptor de
+// testes local.
+func NewHTTPWebhookSender(timeout time.Duration, allowLoopback bool) WebhookSender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !(entity.IsPublicIP(ip) || allowLoopback && ip.IsLoopback()) {
+				return fmt.Errorf("%w: %s", errBlockedAddress, host)
+			}
+			return nil
+		},
+	}
+
+	return &httpWebhookSender{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				// Sem proxy: a conexão precisa ir direto ao endereço conferido
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConnsPerHost: 2,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= webhookMaxRedirects {
+					return fmt.Errorf("excesso de redirecionamentos")
+				}
+				if req.URL.Scheme != "https" && !allowLoopback {
+					return fmt.Errorf("redirecionamento para URL sem https")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func (s *httpWebhookSender) Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("erro ao montar requisição do webhook: %w", err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("User-Agent", "PayFlow-Webhooks/1.0")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if isTimeout(err) {
+			return 0, fmt.Errorf("timeout ao enviar webhook")
+		}
+		return 0, fmt.Errorf("erro ao enviar webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Ler um pouco da resposta para que a conexão possa ser reaproveitada
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditUseCase usecase.AuditUseCase
+}
+
+func NewAuditHandler(auditUseCase usecase.AuditUseCase) *AuditHandler {
+	return &AuditHandler{
+		auditUseCase: auditUseCase,
+	}
+}
+
+// ListEntries consulta a trilha de auditoria por ator, ação, recurso, requisição e período
+func (h *AuditHandler) ListEntries(c *gin.Context) {
+	filters := &entity.AuditLogFilters{
+		ActorType:    entity.AuditActorType(c.Query("actor_type")),
+		ActorID:      c.Query("actor_id"),
+		Action:       c.Query("action"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+		RequestID:    c.Query("request_id"),
+	}
+
+	if page := c.Query("page"); page != "" {
+		if p, err := strconv.Atoi(page); err == nil {
+			filters.Page = p
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil {
+			filters.Limit = l
+		}
+	}
+
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		if d, err := time.Parse(time.RFC3339, dateFrom); err == nil {
+			filters.DateFrom = &d
+		}
+	}
+
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		if d, err := time.Parse(time.RFC3339, dateTo); err == nil {
+			filters.DateTo = &d
+		}
+	}
+
+	response, err := h.auditUseCase.ListEntries(c.Request.Context(), filters)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// VerifyChain confere a cadeia de hashes; uma cadeia quebrada é resultado da conferência, não erro
+func (h *AuditHandler) VerifyChain(c *gin.Context) {
+	response, err := h.auditUseCase.VerifyChain(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	authUseCase usecase.AuthUseCase
+}
+
+func NewAuthHandler(authUseCase usecase.AuthUseCase) *AuthHandler {
+	return &AuthHandler{
+		authUseCase: authUseCase,
+	}
+}
+
+// CreateToken troca e-mail e senha pelo token de acesso
+func (h *AuthHandler) CreateToken(c *gin.Context) {
+	var req entity.CreateAuthTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.authUseCase.CreateToken(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DataExportHandler struct {
+	dataExportUseCase usecase.DataExportUseCase
+}
+
+func NewDataExportHandler(dataExportUseCase usecase.DataExportUseCase) *DataExportHandler {
+	return &DataExportHandler{
+		dataExportUseCase: dataExportUseCase,
+	}
+}
+
+// RequestExport enfileira a exportação dos dados do usuário e responde 202
+func (h *DataExportHandler) RequestExport(c *gin.Context) {
+	response, err := h.dataExportUseCase.RequestExport(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+func (h *DataExportHandler) GetExport(c *gin.Context) {
+	response, err := h.dataExportUseCase.GetExport(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Download entrega o zip pelo link assinado; o link é a credencial, então não exige o token de acesso
+func (h *DataExportHandler) Download(c *gin.Context) {
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		respondError(c, entity.ErrInvalidDownloadSignature)
+		return
+	}
+
+	file, err := h.dataExportUseCase.Download(c.Request.Context(), c.Param("id"), expires, c.Query("signature"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", file.Content)
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DisputeHandler struct {
+	disputeUseCase usecase.DisputeUseCase
+}
+
+func NewDisputeHandler(disputeUseCase usecase.DisputeUseCase) *DisputeHandler {
+	return &DisputeHandler{
+		disputeUseCase: disputeUseCase,
+	}
+}
+
+func (h *DisputeHandler) OpenDispute(c *gin.Context) {
+	var req entity.OpenDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.disputeUseCase.OpenDispute(c.Request.Context(), currentUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListDisputes lista as disputas em que o usuário é pagador ou lojista
+func (h *DisputeHandler) ListDisputes(c *gin.Context) {
+	filters := disputeFilters(c)
+	filters.UserID = currentUserID(c)
+
+	response, err := h.disputeUseCase.ListDisputes(c.Request.Context(), filters)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListAllDisputes lista as disputas de todos os usuários; ?overdue=true traz só as com prazo de decisão vencido
+func (h *DisputeHandler) ListAllDisputes(c *gin.Context) {
+	filters := disputeFilters(c)
+
+	if overdue, _ := strconv.ParseBool(c.Query("overdue")); overdue {
+		now := time.Now()
+		filters.OverdueAt = &now
+	}
+
+	response, err := h.disputeUseCase.ListDisputes(c.Request.Context(), filters)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *DisputeHandler) GetDispute(c *gin.Context) {
+	response, err := h.disputeUseCase.GetDispute(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *DisputeHandler) RespondDispute(c *gin.Context) {
+	var req entity.RespondDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.disputeUseCase.RespondDispute(c.Request.Context(), currentUserID(c), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *DisputeHandler) ResolveDispute(c *gin.Context) {
+	var req entity.ResolveDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.disputeUseCase.ResolveDispute(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func disputeFilters(c *gin.Context) *entity.DisputeFilters {
+	filters := &entity.DisputeFilters{}
+
+	if page := c.Query("page"); page != "" {
+		if p, err := strconv.Atoi(page); err == nil {
+			filters.Page = p
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil {
+			filters.Limit = l
+		}
+	}
+
+	if status := c.Query("status"); status != "" {
+		filters.Status = entity.DisputeStatus(status)
+	}
+
+	return filters
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"payflow-api/internal/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// errorMappings relaciona os erros de domínio com o status HTTP e o código de resposta
+var errorMappings = []errorMapping{
+	{entity.ErrUserNotFound, http.StatusNotFound, "USER_NOT_FOUND"},
+	{entity.ErrTransactionNotFound, http.StatusNotFound, "TRANSACTION_NOT_FOUND"},
+	{entity.ErrHoldNotFound, http.StatusNotFound, "HOLD_NOT_FOUND"},
+	{entity.ErrMerchantCannotSend, http.StatusForbidden, "MERCHANT_CANNOT_SEND"},
+	{entity.ErrInvalidAccountStatus, http.StatusBadRequest, "INVALID_ACCOUNT_STATUS"},
+	{entity.ErrAccountStatusUnchanged, http.StatusConflict, "ACCOUNT_STATUS_UNCHANGED"},
+	{entity.ErrAccountStatusReasonRequired, http.StatusBadRequest, "STATUS_REASON_REQUIRED"},
+	{entity.ErrAccountBlocked, http.StatusForbidden, "ACCOUNT_BLOCKED"},
+	{entity.ErrAccountFrozen, http.StatusForbidden, "ACCOUNT_FROZEN"},
+	{entity.ErrAccountClosed, http.StatusForbidden, "ACCOUNT_CLOSED"},
+	{entity.ErrAccountHasBalance, http.StatusConflict, "ACCOUNT_HAS_BALANCE"},
+	{entity.ErrPayeeCannotReceive, http.StatusUnprocessableEntity, "PAYEE_CANNOT_RECEIVE"},
+	{entity.ErrAccountNotClosed, http.StatusConflict, "ACCOUNT_NOT_CLOSED"},
+	{entity.ErrUserNotDeleted, http.StatusConflict, "USER_NOT_DELETED"},
+	{entity.ErrUserAlreadyAnonymized, http.StatusConflict, "USER_ALREADY_ANONYMIZED"},
+	{entity.ErrRetentionPeriodActive, http.StatusConflict, "RETENTION_PERIOD_ACTIVE"},
+	{entity.ErrTooManyUserStreams, http.StatusTooManyRequests, "TOO_MANY_STREAMS"},
+	{entity.ErrInvalidLastEventID, http.StatusBadRequest, "INVALID_LAST_EVENT_ID"},
+	{entity.ErrInvalidCredentials, http.StatusUnauthorized, "INVALID_CREDENTIALS"},
+	{entity.ErrInvalidAuthToken, http.StatusUnauthorized, entity.ErrorCodeUnauthorized},
+	{entity.ErrAuthTokenExpired, http.StatusUnauthorized, "AUTH_TOKEN_EXPIRED"},
+	{entity.ErrDataExportNotFound, http.StatusNotFound, "DATA_EXPORT_NOT_FOUND"},
+	{entity.ErrDataExportInProgress, http.StatusConflict, "DATA_EXPORT_IN_PROGRESS"},
+	{entity.ErrDataExportNotReady, http.StatusConflict, "DATA_EXPORT_NOT_READY"},
+	{entity.ErrDataExportAlreadyDownloaded, http.StatusGone, "DATA_EXPORT_ALREADY_DOWNLOADED"},
+	{entity.ErrDataExportExpired, http.StatusGone, "DATA_EXPORT_EXPIRED"},
+	{entity.ErrInvalidDownloadSignature, http.StatusForbidden, "INVALID_DOWNLOAD_SIGNATURE"},
+	{entity.ErrInvalidStatementFormat, http.StatusBadRequest, "INVALID_STATEMENT_FORMAT"},
+	{entity.ErrInvalidStatementPeriod, http.StatusBadRequest, "INVALID_STATEMENT_PERIOD"},
+	{entity.ErrStatementPeriodTooLong, http.StatusBadRequest, "INVALID_STATEMENT_PERIOD"},
+	{entity.ErrReceiptNotFound, http.StatusNotFound, "RECEIPT_NOT_FOUND"},
+	{entity.ErrInvalidReceiptFormat, http.StatusBadRequest, "INVALID_RECEIPT_FORMAT"},
+	{entity.ErrWebhookEndpointNotFound, http.StatusNotFound, "WEBHOOK_ENDPOINT_NOT_FOUND"},
+	{entity.ErrWebhookDeliveryNotFound, http.StatusNotFound, "WEBHOOK_DELIVERY_NOT_FOUND"},
+	{entity.ErrOnlyMerchantsCanUseWebhooks, http.StatusForbidden, "ONLY_MERCHANTS_CAN_USE_WEBHOOKS"},
+	{entity.ErrWebhookEndpointLimitReached, http.StatusUnprocessableEntity, "WEBHOOK_ENDPOINT_LIMIT_REACHED"},
+	{entity.ErrInvalidWebhookURL, http.StatusBadRequest, "INVALID_WEBHOOK_URL"},
+	{entity.ErrInvalidWebhookEventType, http.StatusBadRequest, "INVALID_WEBHOOK_EVENT_TYPE"},
+	{entity.ErrWebhookDeliveryPending, http.StatusConflict, "WEBHOOK_DELIVERY_PENDING"},
+	{entity.ErrInsufficientBalance, http.StatusUnprocessableEntity, "INSUFFICIENT_BALANCE"},
+	{entity.ErrSelfTransfer, http.StatusBadRequest, "SELF_TRANSFER"},
+	{entity.ErrAmountExceedsLimit, http.StatusUnprocessableEntity, "LIMIT_EXCEEDED"},
+	{entity.ErrLimitsNotConfigured, http.StatusNotFound, "LIMITS_NOT_CONFIGURED"},
+	{entity.ErrInvalidUserType, http.StatusBadRequest, "INVALID_USER_TYPE"},
+	{entity.ErrInvalidAmount, http.StatusBadRequest, "INVALID_AMOUNT"},
+	{entity.ErrTransactionNotScheduled, http.StatusConflict, "TRANSACTION_NOT_SCHEDULED"},
+	{entity.ErrScheduleInPast, http.StatusBadRequest, "INVALID_SCHEDULE"},
+	{entity.ErrScheduleTooFar, http.StatusBadRequest, "INVALID_SCHEDULE"},
+	{entity.ErrPixKeyNotFound, http.StatusNotFound, "PIX_KEY_NOT_FOUND"},
+	{entity.ErrPixKeyAlreadyExists, http.StatusConflict, "PIX_KEY_ALREADY_EXISTS"},
+	{entity.ErrPixKeyLimitReached, http.StatusUnprocessableEntity, "PIX_KEY_LIMIT_REACHED"},
+	{entity.ErrInvalidPixKey, http.StatusBadRequest, "INVALID_PIX_KEY"},
+	{entity.ErrInvalidPixKeyType, http.StatusBadRequest, "INVALID_PIX_KEY_TYPE"},
+	{entity.ErrPixKeyNotOwned, http.StatusUnprocessableEntity, "PIX_KEY_NOT_OWNED"},
+	{entity.ErrMerchantWithoutPixKey, http.StatusUnprocessableEntity, "MERCHANT_WITHOUT_PIX_KEY"},
+	{entity.ErrPaymentRequestNotFound, http.StatusNotFound, "PAYMENT_REQUEST_NOT_FOUND"},
+	{entity.ErrPaymentRequestNotOpen, http.StatusConflict, "PAYMENT_REQUEST_NOT_OPEN"},
+	{entity.ErrPaymentRequestAlreadyPaid, http.StatusConflict, "PAYMENT_REQUEST_ALREADY_PAID"},
+	{entity.ErrPaymentRequestExpired, http.StatusGone, "PAYMENT_REQUEST_EXPIRED"},
+	{entity.ErrPaymentRequestInProgress, http.StatusConflict, "PAYMENT_IN_PROGRESS"},
+	{entity.ErrOnlyMerchantsCanCharge, http.StatusForbidden, "ONLY_MERCHANTS_CAN_CHARGE"},
+	{entity.ErrBatchNotFound, http.StatusNotFound, "BATCH_NOT_FOUND"},
+	{entity.ErrInvalidBatch, http.StatusUnprocessableEntity, "INVALID_BATCH"},
+	{entity.ErrInvalidBatchMode, http.StatusBadRequest, "INVALID_BATCH_MODE"},
+	{entity.ErrBatchTooLarge, http.StatusRequestEntityTooLarge, "BATCH_TOO_LARGE"},
+	{entity.ErrBatchEmpty, http.StatusBadRequest, "BATCH_EMPTY"},
+	{entity.ErrSplitPaymentNotFound, http.StatusNotFound, "SPLIT_PAYMENT_NOT_FOUND"},
+	{entity.ErrInvalidSplitType, http.StatusBadRequest, "INVALID_SPLIT_TYPE"},
+	{entity.ErrSplitPaymentNotRefundable, http.StatusConflict, "SPLIT_PAYMENT_NOT_REFUNDABLE"},
+	{entity.ErrRefundExceedsAmount, http.StatusUnprocessableEntity, "REFUND_EXCEEDS_AMOUNT"},
+	{entity.ErrPayeeCannotCoverRefund, http.StatusUnprocessableEntity, "PAYEE_CANNOT_COVER_REFUND"},
+	{entity.ErrSplitLegReversed, http.StatusConflict, "SPLIT_LEG_REVERSED"},
+	{entity.ErrDisputeNotFound, http.StatusNotFound, "DISPUTE_NOT_FOUND"},
+	{entity.ErrDisputeAlreadyExists, http.StatusConflict, "DISPUTE_ALREADY_EXISTS"},
+	{entity.ErrDisputeNotOpen, http.StatusConflict, "DISPUTE_NOT_OPEN"},
+	{entity.ErrDisputeAlreadyResolved, http.StatusConflict, "DISPUTE_ALREADY_RESOLVED"},
+	{entity.ErrDisputeWindowClosed, http.StatusUnprocessableEntity, "DISPUTE_WINDOW_CLOSED"},
+	{entity.ErrDisputeResponseOverdue, http.StatusUnprocessableEntity, "DISPUTE_RESPONSE_OVERDUE"},
+	{entity.ErrInvalidDisputeReason, http.StatusBadRequest, "INVALID_DISPUTE_REASON"},
+	{entity.ErrInvalidDisputeWinner, http.StatusBadRequest, "INVALID_DISPUTE_WINNER"},
+	{entity.ErrDisputeSplitRefunded, http.StatusConflict, "DISPUTE_SPLIT_REFUNDED"},
+	{entity.ErrTransactionNotCompleted, http.StatusConflict, "TRANSACTION_NOT_COMPLETED"},
+	{entity.ErrMandateNotFound, http.StatusNotFound, "RECURRING_PAYMENT_NOT_FOUND"},
+	{entity.ErrMandateNotActive, http.StatusConflict, "RECURRING_PAYMENT_NOT_ACTIVE"},
+	{entity.ErrMandateNotPaused, http.StatusConflict, "RECURRING_PAYMENT_NOT_PAUSED"},
+	{entity.ErrMandatePayeeNotMerchant, http.StatusUnprocessableEntity, "PAYEE_NOT_MERCHANT"},
+	{entity.ErrPricingPlanNotFound, http.StatusNotFound, "PRICING_PLAN_NOT_FOUND"},
+	{entity.ErrPlatformAccountNotFound, http.StatusNotFound, "PLATFORM_ACCOUNT_NOT_FOUND"},
+	{entity.ErrFeeExceedsAmount, http.StatusUnprocessableEntity, "FEE_EXCEEDS_AMOUNT"},
+	{entity.ErrRiskDenied, http.StatusForbidden, "RISK_DENIED"},
+	{entity.ErrRiskReviewNotFound, http.StatusNotFound, "RISK_REVIEW_NOT_FOUND"},
+	{entity.ErrRiskReviewNotOpen, http.StatusConflict, "RISK_REVIEW_NOT_OPEN"},
+	{entity.ErrRiskReviewClaimedByOther, http.StatusConflict, "RISK_REVIEW_CLAIMED_BY_OTHER"},
+	{entity.ErrRiskReviewNotClaimed, http.StatusConflict, "RISK_REVIEW_NOT_CLAIMED"},
+	{entity.ErrRiskReviewExpired, http.StatusGone, "RISK_REVIEW_EXPIRED"},
+	{entity.ErrRiskReviewerRequired, http.StatusBadRequest, "REVIEWER_REQUIRED"},
+	{entity.ErrTransactionNotUnderReview, http.StatusConflict, "TRANSACTION_NOT_UNDER_REVIEW"},
+	{entity.ErrAuthorizationFailed, http.StatusForbidden, "AUTHORIZATION_DENIED"},
+	{entity.ErrAuthorizationTimeout, http.StatusGatewayTimeout, "AUTHORIZATION_TIMEOUT"},
+	{entity.ErrAuthorizationService, http.StatusBadGateway, "AUTHORIZATION_UNAVAILABLE"},
+}
+
+// respondError converte um erro do use case em uma resposta JSON padronizada
+func respondError(c *gin.Context, err error) {
+	statusCode := http.StatusInternalServerError
+	code := entity.ErrorCodeInternal
+
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.err) {
+			statusCode = mapping.status
+			code = mapping.code
+			break
+		}
+	}
+
+	if statusCode == http.StatusInternalServerError && strings.Contains(err.Error(), "validar dados") {
+		statusCode = http.StatusBadRequest
+		code = entity.ErrorCodeValidation
+	}
+	if statusCode == http.StatusInternalServerError {
+		slog.ErrorContext(c.Request.Context(), "erro interno na requisição", "error", err)
+	}
+
+	c.JSON(statusCode, entity.NewErrorResponse(
+		err.Error(),
+		code,
+		"",
+		"",
+		nil,
+	))
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LimitHandler struct {
+	limitUseCase usecase.LimitUseCase
+}
+
+func NewLimitHandler(limitUseCase usecase.LimitUseCase) *LimitHandler {
+	return &LimitHandler{
+		limitUseCase: limitUseCase,
+	}
+}
+
+func (h *LimitHandler) GetUserLimits(c *gin.Context) {
+	response, err := h.limitUseCase.GetUserLimits(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *LimitHandler) UpdateUserLimits(c *gin.Context) {
+	var req entity.UpdateLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.limitUseCase.UpdateUserLimits(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *LimitHandler) ResetUserLimits(c *gin.Context) {
+	if err := h.limitUseCase.ResetUserLimits(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *LimitHandler) GetDefaultLimits(c *gin.Context) {
+	userType := entity.UserType(c.Param("user_type"))
+
+	response, err := h.limitUseCase.GetDefaultLimits(c.Request.Context(), userType)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *LimitHandler) UpdateDefaultLimits(c *gin.Context) {
+	var req entity.UpdateLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	userType := entity.UserType(c.Param("user_type"))
+
+	response, err := h.limitUseCase.UpdateDefaultLimits(c.Request.Context(), userType, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/metrics"
+	"payflow-api/internal/usecase"
+	"payflow-api/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// AuthorizationHeader carrega o token de acesso do usuário: Bearer <token>
+	AuthorizationHeader = "Authorization"
+
+	// accessTokenQuery carrega o token nas rotas de stream, já que o EventSource do navegador não
+	// envia cabeçalhos
+	accessTokenQuery = "access_token"
+
+	// AdminKeyHeader carrega a chave de acesso às rotas administrativas
+	AdminKeyHeader = "X-Admin-Key"
+
+	// RequestIDHeader correlaciona a requisição com os logs e os registros de auditoria
+	RequestIDHeader = "X-Request-ID"
+
+	userIDKey        = "user_id"
+	adminIDKey       = "admin_id"
+	auditMetadataKey = "audit_metadata"
+)
+
+// requestIDPattern restringe o X-Request-ID recebido a caracteres seguros em logs e cabeçalhos
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,100}$`)
+
+// RequestID propaga o X-Request-ID recebido, ou gera um novo, na resposta e no contexto da
+// requisição. Deve ser o primeiro middleware, para que todos os logs da requisição o tragam.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.New().String()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// RequestLogger registra uma linha por requisição com rota, status e duração. A query string fica
+// de fora porque links de download assinados carregam o token nela.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		slog.Log(c.Request.Context(), level, "requisição HTTP",
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"path", c.Request.URL.Path,
+			"status", status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
+
+// RequestMetrics mede a duração das requisições pelo padrão da rota e pelo status da resposta
+func RequestMetrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		m.ObserveHTTPRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start))
+	}
+}
+
+// Recovery responde 500 quando um handler entra em pânico e registra o pânico com a pilha
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		slog.ErrorContext(c.Request.Context(), "pânico na requisição",
+			"panic", fmt.Sprint(recovered),
+			"stack", string(debug.Stack()),
+		)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.NewErrorResponse(
+			"Erro interno",
+			entity.ErrorCodeInternal,
+			"",
+			"",
+			nil,
+		))
+	})
+}
+
+// RequireUser exige um token de acesso válido no cabeçalho Authorization e disponibiliza para os
+// handlers o usuário a que ele foi emitido
+func RequireUser(tokens *entity.AuthTokenSigner) gin.HandlerFunc {
+	return requireUser(tokens, false)
+}
+
+// RequireStreamUser é RequireUser que também aceita o token em ?access_token=
+func RequireStreamUser(tokens *entity.AuthTokenSigner) gin.HandlerFunc {
+	return requireUser(tokens, true)
+}
+
+func requireUser(tokens *entity.AuthTokenSigner, allowQuery bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader(AuthorizationHeader), entity.AuthTokenType+" ")
+		if !found && allowQuery {
+			token, found = c.Query(accessTokenQuery), c.Query(accessTokenQuery) != ""
+		}
+		if !found {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, entity.NewErrorResponse(
+				"Usuário não identificado",
+				entity.ErrorCodeUnauthorized,
+				"Informe o token de acesso no cabeçalho "+AuthorizationHeader,
+				AuthorizationHeader,
+				nil,
+			))
+			return
+		}
+
+		userID, err := tokens.Verify(strings.TrimSpace(token), time.Now())
+		if err != nil {
+			c.Abort()
+			respondError(c, err)
+			return
+		}
+
+		c.Set(userIDKey, userID)
+		markUserActor(c, userID)
+		c.Next()
+	}
+}
+
+func currentUserID(c *gin.Context) string {
+	return c.GetString(userIDKey)
+}
+
+// RequireAdmin identifica o administrador pela chave pessoal configurada em ADMIN_API_KEYS.
+// Sem chaves configuradas as rotas administrativas ficam bloqueadas.
+func RequireAdmin(keys *entity.AdminKeyring) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, ok := keys.Identify(c.GetHeader(AdminKeyHeader))
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, entity.NewErrorResponse(
+				"Acesso administrativo negado",
+				entity.ErrorCodeForbidden,
+				"Informe uma chave válida no cabeçalho "+AdminKeyHeader,
+				AdminKeyHeader,
+				nil,
+			))
+			return
+		}
+
+		c.Set(adminIDKey, adminID)
+		markAdminActor(c, adminID)
+		c.Next()
+	}
+}
+
+// currentAdminID é o administrador identificado por RequireAdmin
+func currentAdminID(c *gin.Context) string {
+	return c.GetString(adminIDKey)
+}
+
+// AuditContext prepara os dados da requisição para a trilha de auditoria. Roda depois de RequestID
+// e antes da autenticação, então o ator começa anônimo; RequireUser e RequireAdmin o identificam
+// depois de conferir a credencial.
+func AuditContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		meta := entity.AuditMetadata{
+			ActorType: entity.AuditActorAnonymous,
+			RequestID: logger.RequestID(c.Request.Context()),
+			IP:        c.ClientIP(),
+		}
+
+		c.Set(auditMetadataKey, meta)
+		c.Request = c.Request.WithContext(usecase.WithAuditMetadata(c.Request.Context(), meta))
+		c.Next()
+	}
+}
+
+// markUserActor registra o usuário como ator depois que o token foi conferido
+func markUserActor(c *gin.Context, userID string) {
+	setAuditActor(c, entity.AuditActorUser, userID)
+}
+
+// markAdminActor registra o administrador como ator depois que a chave foi conferida
+func markAdminActor(c *gin.Context, adminID string) {
+	setAuditActor(c, entity.AuditActorAdmin, adminID)
+}
+
+func setAuditActor(c *gin.Context, actorType entity.AuditActorType, actorID string) {
+	value, ok := c.Get(auditMetadataKey)
+	if !ok {
+		return
+	}
+
+	meta := value.(entity.AuditMetadata)
+	meta.ActorType = actorType
+	meta.ActorID = actorID
+	c.Set(auditMetadataKey, meta)
+	c.Request = c.Request.WithContext(usecase.WithAuditMetadata(c.Request.Context(), meta))
+}
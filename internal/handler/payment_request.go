@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+	"payflow-api/pkg/brcode"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentRequestHandler struct {
+	paymentRequestUseCase usecase.PaymentRequestUseCase
+}
+
+func NewPaymentRequestHandler(paymentRequestUseCase usecase.PaymentRequestUseCase) *PaymentRequestHandler {
+	return &PaymentRequestHandler{
+		paymentRequestUseCase: paymentRequestUseCase,
+	}
+}
+
+func (h *PaymentRequestHandler) CreatePaymentRequest(c *gin.Context) {
+	var req entity.CreatePaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.paymentRequestUseCase.CreatePaymentRequest(c.Request.Context(), currentUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *PaymentRequestHandler) ListPaymentRequests(c *gin.Context) {
+	filters := &entity.PaymentRequestFilters{MerchantID: currentUserID(c)}
+
+	if page := c.Query("page"); page != "" {
+		if p, err := strconv.Atoi(page); err == nil {
+			filters.Page = p
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil {
+			filters.Limit = l
+		}
+	}
+
+	if status := c.Query("status"); status != "" {
+		filters.Status = entity.PaymentRequestStatus(status)
+	}
+
+	response, err := h.paymentRequestUseCase.ListPaymentRequests(c.Request.Context(), filters)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PaymentRequestHandler) GetPaymentRequest(c *gin.Context) {
+	response, err := h.paymentRequestUseCase.GetPaymentRequest(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PaymentRequestHandler) CancelPaymentRequest(c *gin.Context) {
+	response, err := h.paymentRequestUseCase.CancelPaymentRequest(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PaymentRequestHandler) PayPaymentRequest(c *gin.Context) {
+	response, err := h.paymentRequestUseCase.PayPaymentRequest(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetBRCode retorna o payload BR Code da cobrança em JSON ou, com format=png, a imagem do QR Code
+func (h *PaymentRequestHandler) GetBRCode(c *gin.Context) {
+	static := c.Query("type") == "static"
+
+	response, err := h.paymentRequestUseCase.GetBRCode(c.Request.Context(), currentUserID(c), c.Param("id"), static)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if c.Query("format") != "png" {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	size := 256
+	if value := c.Query("size"); value != "" {
+		if s, err := strconv.Atoi(value); err == nil && s >= 128 && s <= 1024 {
+			size = s
+		}
+	}
+
+	image, err := brcode.QRCodePNG(response.Payload, size)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", image)
+}
+
+func (h *PaymentRequestHandler) ParseBRCode(c *gin.Context) {
+	var req entity.ParseBRCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.paymentRequestUseCase.ParseBRCode(c.Request.Context(), currentUserID(c), req.Payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PixKeyHandler struct {
+	pixKeyUseCase usecase.PixKeyUseCase
+}
+
+func NewPixKeyHandler(pixKeyUseCase usecase.PixKeyUseCase) *PixKeyHandler {
+	return &PixKeyHandler{
+		pixKeyUseCase: pixKeyUseCase,
+	}
+}
+
+func (h *PixKeyHandler) RegisterKey(c *gin.Context) {
+	var req entity.CreatePixKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.pixKeyUseCase.RegisterKey(c.Request.Context(), currentUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *PixKeyHandler) ListKeys(c *gin.Context) {
+	response, err := h.pixKeyUseCase.ListKeys(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PixKeyHandler) DeleteKey(c *gin.Context) {
+	if err := h.pixKeyUseCase.DeleteKey(c.Request.Context(), currentUserID(c), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PixKeyHandler) LookupKey(c *gin.Context) {
+	response, err := h.pixKeyUseCase.LookupKey(c.Request.Context(), c.Query("key"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type PricingHandler struct {
+	pricingUseCase usecase.PricingUseCase
+}
+
+func NewPricingHandler(pricingUseCase usecase.PricingUseCase) *PricingHandler {
+	return &PricingHandler{
+		pricingUseCase: pricingUseCase,
+	}
+}
+
+func (h *PricingHandler) CreatePlan(c *gin.Context) {
+	var req entity.CreatePricingPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	plan, err := h.pricingUseCase.CreatePlan(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, plan)
+}
+
+func (h *PricingHandler) ListPlans(c *gin.Context) {
+	plans, err := h.pricingUseCase.ListPlans(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plans)
+}
+
+func (h *PricingHandler) AssignPlan(c *gin.Context) {
+	var req entity.AssignPricingPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	if err := h.pricingUseCase.AssignPlan(c.Request.Context(), c.Param("id"), &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PricingHandler) QuoteFee(c *gin.Context) {
+	amount, err := decimal.NewFromString(c.Query("amount"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Valor inválido",
+			"INVALID_REQUEST",
+			err.Error(),
+			"amount",
+			c.Query("amount"),
+		))
+		return
+	}
+
+	response, err := h.pricingUseCase.QuoteFee(c.Request.Context(), c.Query("payee_id"), amount)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *PricingHandler) GetRevenueAccount(c *gin.Context) {
+	response, err := h.pricingUseCase.GetRevenueAccount(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReceiptHandler struct {
+	receiptUseCase usecase.ReceiptUseCase
+}
+
+func NewReceiptHandler(receiptUseCase usecase.ReceiptUseCase) *ReceiptHandler {
+	return &ReceiptHandler{
+		receiptUseCase: receiptUseCase,
+	}
+}
+
+// GetReceipt entrega o comprovante da transação em JSON ou PDF (?format=)
+func (h *ReceiptHandler) GetReceipt(c *gin.Context) {
+	format, err := entity.ParseReceiptFormat(c.Query("format"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	receipt, err := h.receiptUseCase.GetReceipt(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if format == entity.ReceiptFormatJSON {
+		document, err := receipt.ToReceiptDocument()
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, document)
+		return
+	}
+
+	content, err := receipt.PDF()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+receipt.FileName(format)+`"`)
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+// VerifyByCode confere o comprovante pelo código de autenticação impresso no PDF
+func (h *ReceiptHandler) VerifyByCode(c *gin.Context) {
+	response, err := h.receiptUseCase.VerifyByCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Verify confere um comprovante em JSON; um comprovante inválido é resultado da conferência, não erro
+func (h *ReceiptHandler) Verify(c *gin.Context) {
+	var req entity.VerifyReceiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.receiptUseCase.Verify(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *ReceiptHandler) GetPublicKey(c *gin.Context) {
+	c.JSON(http.StatusOK, h.receiptUseCase.PublicKey())
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"net/http"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecurringHandler struct {
+	recurringUseCase usecase.RecurringUseCase
+}
+
+func NewRecurringHandler(recurringUseCase usecase.RecurringUseCase) *RecurringHandler {
+	return &RecurringHandler{
+		recurringUseCase: recurringUseCase,
+	}
+}
+
+func (h *RecurringHandler) CreateRecurringPayment(c *gin.Context) {
+	var req entity.CreateRecurringPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.recurringUseCase.CreateRecurringPayment(c.Request.Context(), currentUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *RecurringHandler) ListRecurringPayments(c *gin.Context) {
+	response, err := h.recurringUseCase.ListRecurringPayments(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RecurringHandler) GetRecurringPayment(c *gin.Context) {
+	response, err := h.recurringUseCase.GetRecurringPayment(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RecurringHandler) PauseRecurringPayment(c *gin.Context) {
+	response, err := h.recurringUseCase.PauseRecurringPayment(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RecurringHandler) ResumeRecurringPayment(c *gin.Context) {
+	response, err := h.recurringUseCase.ResumeRecurringPayment(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RecurringHandler) CancelRecurringPayment(c *gin.Context) {
+	response, err := h.recurringUseCase.CancelRecurringPayment(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RecurringHandler) ListRuns(c *gin.Context) {
+	response, err := h.recurringUseCase.ListRuns(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RiskReviewHandler struct {
+	riskReviewUseCase usecase.RiskReviewUseCase
+}
+
+func NewRiskReviewHandler(riskReviewUseCase usecase.RiskReviewUseCase) *RiskReviewHandler {
+	return &RiskReviewHandler{
+		riskReviewUseCase: riskReviewUseCase,
+	}
+}
+
+// ListReviews lista a fila de análise; sem ?status=, traz os itens pendentes e assumidos
+func (h *RiskReviewHandler) ListReviews(c *gin.Context) {
+	filters := &entity.RiskReviewFilters{}
+
+	if page := c.Query("page"); page != "" {
+		if p, err := strconv.Atoi(page); err == nil {
+			filters.Page = p
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil {
+			filters.Limit = l
+		}
+	}
+
+	if status := c.Query("status"); status != "" {
+		filters.Status = entity.RiskReviewStatus(status)
+	}
+
+	response, err := h.riskReviewUseCase.ListReviews(c.Request.Context(), filters)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RiskReviewHandler) GetReview(c *gin.Context) {
+	response, err := h.riskReviewUseCase.GetReview(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ClaimReview atribui o item ao administrador identificado pela chave
+func (h *RiskReviewHandler) ClaimReview(c *gin.Context) {
+	response, err := h.riskReviewUseCase.ClaimReview(c.Request.Context(), c.Param("id"), currentAdminID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RiskReviewHandler) ApproveReview(c *gin.Context) {
+	var req entity.DecideRiskReviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+				"Dados inválidos",
+				"INVALID_REQUEST",
+				err.Error(),
+				"",
+				nil,
+			))
+			return
+		}
+	}
+
+	response, err := h.riskReviewUseCase.ApproveReview(c.Request.Context(), c.Param("id"), currentAdminID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *RiskReviewHandler) RejectReview(c *gin.Context) {
+	var req entity.DecideRiskReviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+				"Dados inválidos",
+				"INVALID_REQUEST",
+				err.Error(),
+				"",
+				nil,
+			))
+			return
+		}
+	}
+
+	response, err := h.riskReviewUseCase.RejectReview(c.Request.Context(), c.Param("id"), currentAdminID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
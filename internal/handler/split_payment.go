@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SplitPaymentHandler struct {
+	splitPaymentUseCase usecase.SplitPaymentUseCase
+}
+
+func NewSplitPaymentHandler(splitPaymentUseCase usecase.SplitPaymentUseCase) *SplitPaymentHandler {
+	return &SplitPaymentHandler{
+		splitPaymentUseCase: splitPaymentUseCase,
+	}
+}
+
+func (h *SplitPaymentHandler) CreateSplitPayment(c *gin.Context) {
+	var req entity.CreateSplitPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.splitPaymentUseCase.CreateSplitPayment(c.Request.Context(), currentUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *SplitPaymentHandler) GetSplitPayment(c *gin.Context) {
+	response, err := h.splitPaymentUseCase.GetSplitPayment(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RefundSplitPayment estorna o pagamento; sem corpo ou sem amount, estorna todo o restante
+func (h *SplitPaymentHandler) RefundSplitPayment(c *gin.Context) {
+	var req entity.RefundSplitPaymentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+				"Dados inválidos",
+				"INVALID_REQUEST",
+				err.Error(),
+				"",
+				nil,
+			))
+			return
+		}
+	}
+
+	response, err := h.splitPaymentUseCase.RefundSplitPayment(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatementHandler struct {
+	statementUseCase usecase.StatementUseCase
+}
+
+func NewStatementHandler(statementUseCase usecase.StatementUseCase) *StatementHandler {
+	return &StatementHandler{
+		statementUseCase: statementUseCase,
+	}
+}
+
+// GetStatement gera o extrato do período em JSON, CSV, OFX ou PDF (?format=)
+func (h *StatementHandler) GetStatement(c *gin.Context) {
+	format, err := entity.ParseStatementFormat(c.Query("format"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	from, to, err := entity.ParseStatementPeriod(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	statement, err := h.statementUseCase.GetStatement(c.Request.Context(), currentUserID(c), c.Param("id"), from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if format == entity.StatementFormatJSON {
+		c.JSON(http.StatusOK, statement.ToStatementResponse())
+		return
+	}
+
+	content, err := statement.Render(format)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+statement.FileName(format)+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, format.ContentType(), content)
+}
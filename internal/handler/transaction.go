@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type TransactionHandler struct {
+	transactionUseCase usecase.TransactionUseCase
+}
+
+func NewTransactionHandler(transactionUseCase usecase.TransactionUseCase) *TransactionHandler {
+	return &TransactionHandler{
+		transactionUseCase: transactionUseCase,
+	}
+}
+
+func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
+	var req entity.CreateTransactionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.transactionUseCase.CreateTransaction(c.Request.Context(), currentUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// Retida para análise de risco: aceita, mas ainda sem decisão
+	if response.Status == entity.TransactionStatusUnderReview {
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *TransactionHandler) GetTransaction(c *gin.Context) {
+	id := c.Param("id")
+
+	if id == "" {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"ID da transação é obrigatório",
+			"MISSING_ID",
+			"",
+			"id",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.transactionUseCase.GetTransaction(c.Request.Context(), currentUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *TransactionHandler) CancelTransaction(c *gin.Context) {
+	var req entity.CancelTransactionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+				"Dados inválidos",
+				"INVALID_REQUEST",
+				err.Error(),
+				"",
+				nil,
+			))
+			return
+		}
+	}
+
+	response, err := h.transactionUseCase.CancelTransaction(c.Request.Context(), currentUserID(c), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *TransactionHandler) ListTransactions(c *gin.Context) {
+	filters := &entity.TransactionFilters{}
+
+	if page := c.Query("page"); page != "" {
+		if p, err := strconv.Atoi(page); err == nil {
+			filters.Page = p
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil {
+			filters.Limit = l
+		}
+	}
+
+	if status := c.Query("status"); status != "" {
+		filters.Status = entity.TransactionStatus(status)
+	}
+
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		if d, err := time.Parse(time.RFC3339, dateFrom); err == nil {
+			filters.DateFrom = &d
+		}
+	}
+
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		if d, err := time.Parse(time.RFC3339, dateTo); err == nil {
+			filters.DateTo = &d
+		}
+	}
+
+	if minAmount := c.Query("min_amount"); minAmount != "" {
+		if a, err := decimal.NewFromString(minAmount); err == nil {
+			filters.MinAmount = &a
+		}
+	}
+
+	if maxAmount := c.Query("max_amount"); maxAmount != "" {
+		if a, err := decimal.NewFromString(maxAmount); err == nil {
+			filters.MaxAmount = &a
+		}
+	}
+
+	response, err := h.transactionUseCase.ListTransactions(c.Request.Context(), currentUserID(c), filters)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
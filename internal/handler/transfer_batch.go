@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransferBatchHandler struct {
+	batchUseCase usecase.TransferBatchUseCase
+}
+
+func NewTransferBatchHandler(batchUseCase usecase.TransferBatchUseCase) *TransferBatchHandler {
+	return &TransferBatchHandler{
+		batchUseCase: batchUseCase,
+	}
+}
+
+// CreateBatch aceita o lote em JSON ou CSV (corpo text/csv ou arquivo "file" em multipart);
+// no CSV o modo é informado por ?mode= ou pelo campo "mode" do formulário
+func (h *TransferBatchHandler) CreateBatch(c *gin.Context) {
+	req, err := bindBatchRequest(c)
+	if err != nil && !errors.Is(err, entity.ErrInvalidBatch) {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+
+	response, err := h.batchUseCase.CreateBatch(c.Request.Context(), currentUserID(c), req)
+	if err != nil {
+		respondBatchError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+func (h *TransferBatchHandler) GetBatch(c *gin.Context) {
+	response, err := h.batchUseCase.GetBatch(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func bindBatchRequest(c *gin.Context) (*entity.CreateBatchRequest, error) {
+	var csvBody io.Reader
+
+	switch c.ContentType() {
+	case "text/csv":
+		csvBody = c.Request.Body
+	case "multipart/form-data":
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		csvBody = file
+	default:
+		var req entity.CreateBatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return nil, err
+		}
+		return &req, nil
+	}
+
+	items, err := entity.ParseBatchCSV(csvBody)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := c.Query("mode")
+	if mode == "" {
+		mode = c.PostForm("mode")
+	}
+
+	return &entity.CreateBatchRequest{
+		Mode:  entity.BatchMode(mode),
+		Items: items,
+	}, nil
+}
+
+// respondBatchError inclui na resposta os erros de cada item inválido do lote
+func respondBatchError(c *gin.Context, err error) {
+	var validationErr *entity.BatchValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusUnprocessableEntity, entity.NewErrorResponse(
+			err.Error(),
+			"INVALID_BATCH",
+			"",
+			"items",
+			validationErr.Items,
+		))
+		return
+	}
+
+	respondError(c, err)
+}
@@ -172,6 +172,10 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		filters.Email = email
 	}
 
+	if status := c.Query("status"); status != "" {
+		filters.Status = entity.AccountStatus(status)
+	}
+
 	response, err := h.userUseCase.ListUsers(c.Request.Context(), filters)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, entity.NewErrorResponse(
@@ -201,23 +205,9 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	err := h.userUseCase.DeleteUser(c.Request.Context(), id)
+	err := h.userUseCase.DeleteUser(c.Request.Context(), currentUserID(c), id)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		code := "INTERNAL_ERROR"
-
-		if contains(err.Error(), "não encontrado") {
-			statusCode = http.StatusNotFound
-			code = "USER_NOT_FOUND"
-		}
-
-		c.JSON(statusCode, entity.NewErrorResponse(
-			err.Error(),
-			code,
-			"",
-			"",
-			nil,
-		))
+		respondError(c, err)
 		return
 	}
 
@@ -261,6 +251,39 @@ func (h *UserHandler) GetBalance(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// ChangeAccountStatus bloqueia, congela, reativa ou encerra a conta com um motivo obrigatório
+func (h *UserHandler) ChangeAccountStatus(c *gin.Context) {
+	var req entity.ChangeAccountStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.userUseCase.ChangeAccountStatus(c.Request.Context(), c.Param("id"), currentAdminID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) GetAccountStatusHistory(c *gin.Context) {
+	response, err := h.userUseCase.GetAccountStatusHistory(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 func contains(str, substr string) bool {
 	return len(str) >= len(substr) && (str == substr || str[0:len(substr)] == substr || str[len(str)-len(substr):] == substr)
 }
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// LastEventIDHeader é enviado pelo EventSource ao reconectar com o ID do último evento recebido
+	LastEventIDHeader = "Last-Event-ID"
+
+	// streamRetry é a espera sugerida ao cliente antes de reconectar, em milissegundos
+	streamRetry = 3000
+)
+
+type UserStreamHandler struct {
+	userStreamUseCase usecase.UserStreamUseCase
+	heartbeat         time.Duration
+}
+
+func NewUserStreamHandler(userStreamUseCase usecase.UserStreamUseCase, heartbeat time.Duration) *UserStreamHandler {
+	return &UserStreamHandler{
+		userStreamUseCase: userStreamUseCase,
+		heartbeat:         heartbeat,
+	}
+}
+
+// Stream mantém aberto o stream SSE de saldo e transações do usuário. Aceita o Last-Event-ID no
+// cabeçalho ou em ?last_event_id= para clientes que não conseguem enviar cabeçalhos.
+func (h *UserStreamHandler) Stream(c *gin.Context) {
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	stream, err := h.userStreamUseCase.Open(ctx, currentUserID(c), c.Param("id"), lastEventID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer stream.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Impede que proxies como o nginx acumulem os eventos
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+	if stream.Reset {
+		writeServerSentEvent(w, "", string(entity.UserStreamReset), []byte("{}"))
+	}
+	if stream.Snapshot != nil {
+		// Sem ID: o saldo atual não entra no replay e não muda o Last-Event-ID do cliente
+		data, _ := json.Marshal(stream.Snapshot)
+		writeServerSentEvent(w, "", string(entity.UserStreamBalanceUpdated), data)
+	}
+	w.Flush()
+
+	for {
+		events, err := stream.Next(ctx, h.heartbeat)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) && !errors.Is(err, entity.ErrUserStreamClosed) {
+				slog.ErrorContext(ctx, "erro no stream de eventos do usuário", "user_id", c.Param("id"), "error", err)
+			}
+			return
+		}
+
+		if len(events) == 0 {
+			io.WriteString(w, ": ping\n\n")
+		}
+		for _, event := range events {
+			writeServerSentEvent(w, strconv.FormatInt(event.ID, 10), string(event.Type), event.Data)
+		}
+		w.Flush()
+	}
+}
+
+func parseLastEventID(c *gin.Context) (int64, error) {
+	value := strings.TrimSpace(c.GetHeader(LastEventIDHeader))
+	if value == "" {
+		value = strings.TrimSpace(c.Query("last_event_id"))
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, entity.ErrInvalidLastEventID
+	}
+	return id, nil
+}
+
+// writeServerSentEvent escreve um evento no formato text/event-stream. Os dados são JSON
+// compacto, sem quebras de linha.
+func writeServerSentEvent(w io.Writer, id, event string, data []byte) {
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookUseCase usecase.WebhookUseCase
+}
+
+func NewWebhookHandler(webhookUseCase usecase.WebhookUseCase) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+// CreateEndpoint cadastra o endpoint; o segredo de assinatura vem apenas nesta resposta
+func (h *WebhookHandler) CreateEndpoint(c *gin.Context) {
+	var req entity.CreateWebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.webhookUseCase.CreateEndpoint(c.Request.Context(), currentUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *WebhookHandler) ListEndpoints(c *gin.Context) {
+	response, err := h.webhookUseCase.ListEndpoints(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *WebhookHandler) GetEndpoint(c *gin.Context) {
+	response, err := h.webhookUseCase.GetEndpoint(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *WebhookHandler) UpdateEndpoint(c *gin.Context) {
+	var req entity.UpdateWebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, entity.NewErrorResponse(
+			"Dados inválidos",
+			"INVALID_REQUEST",
+			err.Error(),
+			"",
+			nil,
+		))
+		return
+	}
+
+	response, err := h.webhookUseCase.UpdateEndpoint(c.Request.Context(), currentUserID(c), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *WebhookHandler) DeleteEndpoint(c *gin.Context) {
+	if err := h.webhookUseCase.DeleteEndpoint(c.Request.Context(), currentUserID(c), c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries lista o log de entregas; ?endpoint_id=, ?event_type= e ?status= filtram o resultado
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	filters := &entity.WebhookDeliveryFilters{
+		MerchantID: currentUserID(c),
+		EndpointID: c.Query("endpoint_id"),
+		EventType:  entity.WebhookEventType(c.Query("event_type")),
+		Status:     entity.WebhookDeliveryStatus(c.Query("status")),
+	}
+
+	if page := c.Query("page"); page != "" {
+		if p, err := strconv.Atoi(page); err == nil {
+			filters.Page = p
+		}
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil {
+			filters.Limit = l
+		}
+	}
+
+	response, err := h.webhookUseCase.ListDeliveries(c.Request.Context(), filters)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	response, err := h.webhookUseCase.GetDelivery(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Redeliver agenda o reenvio do evento como uma nova entrega e responde 202
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	response, err := h.webhookUseCase.Redeliver(c.Request.Context(), currentUserID(c), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
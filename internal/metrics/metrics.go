@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixa todas as métricas da API
+const namespace = "payflow"
+
+// backlogTimeout limita a consulta do backlog do outbox feita a cada coleta
+const backlogTimeout = 5 * time.Second
+
+// Resultados das chamadas aos serviços externos
+const (
+	OutcomeSuccess = "success"
+	// OutcomeDenied é a resposta válida de recusa do autorizador; não conta como falha do serviço
+	OutcomeDenied  = "denied"
+	OutcomeTimeout = "timeout"
+	OutcomeError   = "error"
+)
+
+// Metrics reúne as métricas expostas em /metrics no formato do Prometheus
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpDuration     *prometheus.HistogramVec
+	transfers        *prometheus.CounterVec
+	transferAmount   *prometheus.CounterVec
+	externalDuration *prometheus.HistogramVec
+	externalRequests *prometheus.CounterVec
+}
+
+// New cria as métricas da API junto com as do runtime do Go e do processo
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duração das requisições HTTP por método, rota e status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		transfers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transfers_total",
+			Help:      "Transferências que chegaram a cada status.",
+		}, []string{"status"}),
+		transferAmount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transfer_amount_reais_total",
+			Help:      "Soma dos valores, em reais, das transferências que chegaram a cada status.",
+		}, []string{"status"}),
+		externalDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "external_request_duration_seconds",
+			Help:      "Duração das chamadas aos serviços externos.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"service"}),
+		externalRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "external_requests_total",
+			Help:      "Chamadas aos serviços externos por resultado (success, denied, timeout ou error).",
+		}, []string{"service", "outcome"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration,
+		m.transfers,
+		m.transferAmount,
+		m.externalDuration,
+		m.externalRequests,
+	)
+	return m
+}
+
+// Handler responde a coleta do Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveHTTPRequest registra uma requisição atendida. route é o padrão da rota, como
+// /api/v1/users/:id, para que os IDs não multipliquem as séries.
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	if route == "" {
+		route = "unmatched"
+	}
+	m.httpDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// ObserveTransfer registra a chegada de uma transferência ao status com o seu valor
+func (m *Metrics) ObserveTransfer(status string, amount float64) {
+	m.transfers.WithLabelValues(status).Inc()
+	m.transferAmount.WithLabelValues(status).Add(amount)
+}
+
+// ObserveExternalCall registra uma chamada a um serviço externo e o seu resultado
+func (m *Metrics) ObserveExternalCall(service, outcome string, duration time.Duration) {
+	m.externalDuration.WithLabelValues(service).Observe(duration.Seconds())
+	m.externalRequests.WithLabelValues(service, outcome).Inc()
+}
+
+// RegisterDBStats expõe as estatísticas do pool de conexões de sql.DB.Stats()
+func (m *Metrics) RegisterDBStats(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterOutboxBacklog expõe o tamanho das filas do outbox, consultado a cada coleta. count
+// retorna a quantidade de eventos pendentes por fila.
+func (m *Metrics) RegisterOutboxBacklog(count func(ctx context.Context) (map[string]int, error)) {
+	m.registry.MustRegister(&backlogCollector{
+		count: count,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "outbox", "backlog"),
+			"Eventos do outbox ainda pendentes por fila (dispatch: assinantes do processo; publish: broker).",
+			[]string{"queue"}, nil,
+		),
+	})
+}
+
+type backlogCollector struct {
+	count func(ctx context.Context) (map[string]int, error)
+	desc  *prometheus.Desc
+}
+
+func (c *backlogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *backlogCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), backlogTimeout)
+	defer cancel()
+
+	backlog, err := c.count(ctx)
+	if err != nil {
+		// Sem o valor a série some da coleta, em vez de aparecer zerada
+		slog.Error("erro ao consultar backlog do outbox para métricas", "error", err)
+		return
+	}
+	for queue, size := range backlog {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(size), queue)
+	}
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+const auditColumns = "id, sequence, actor_type, actor_id, action, resource_type, resource_id, changes, request_id, ip, created_at, prev_hash, hash"
+
+// auditLockKey identifica o advisory lock que serializa as gravações na trilha
+const auditLockKey = 4242001
+
+type auditPostgresRepository struct {
+	db *database.Database
+}
+
+func NewAuditPostgresRepository(db *database.Database) AuditRepository {
+	return &auditPostgresRepository{
+		db: db,
+	}
+}
+
+func scanAuditEntry(row rowScanner) (*entity.AuditEntry, error) {
+	entry := &entity.AuditEntry{}
+	var changes []byte
+	err := row.Scan(
+		&entry.ID,
+		&entry.Sequence,
+		&entry.ActorType,
+		&entry.ActorID,
+		&entry.Action,
+		&entry.ResourceType,
+		&entry.ResourceID,
+		&changes,
+		&entry.RequestID,
+		&entry.IP,
+		&entry.CreatedAt,
+		&entry.PrevHash,
+		&entry.Hash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+		return nil, fmt.Errorf("erro ao ler mudanças do registro de auditoria: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (r *auditPostgresRepository) Append(ctx context.Context, entry *entity.AuditEntry) error {
+	// O encadeamento roda no fim da transação: o lock global vale até o commit e não pode ser
+	// segurado enquanto a transação ainda bloqueia linhas de usuários e transações
+	return r.db.BeforeCommit(ctx, func(ctx context.Context) error {
+		// O próximo registro sempre enxerga este como o último
+		if _, err := r.db.Conn(ctx).ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", auditLockKey); err != nil {
+			return fmt.Errorf("erro ao bloquear trilha de auditoria: %w", err)
+		}
+
+		var sequence int64
+		prevHash := ""
+		err := r.db.Conn(ctx).QueryRowContext(ctx, "SELECT sequence, hash FROM audit_log ORDER BY sequence DESC LIMIT 1").Scan(&sequence, &prevHash)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("erro ao buscar último registro de auditoria: %w", err)
+		}
+
+		entry.Seal(sequence+1, prevHash)
+
+		changes, err := json.Marshal(entry.Changes)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar mudanças do registro de auditoria: %w", err)
+		}
+
+		query := `
+			INSERT INTO audit_log (` + auditColumns + `)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		`
+
+		_, err = r.db.Conn(ctx).ExecContext(ctx, query,
+			entry.ID,
+			entry.Sequence,
+			entry.ActorType,
+			entry.ActorID,
+			entry.Action,
+			entry.ResourceType,
+			entry.ResourceID,
+			changes,
+			entry.RequestID,
+			entry.IP,
+			entry.CreatedAt,
+			entry.PrevHash,
+			entry.Hash,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao gravar registro de auditoria: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *auditPostgresRepository) List(ctx context.Context, filters *entity.AuditLogFilters) ([]*entity.AuditEntry, int, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	argCount := 0
+
+	addFilter := func(condition string, value interface{}) {
+		argCount++
+		where += fmt.Sprintf(" AND "+condition, argCount)
+		args = append(args, value)
+	}
+
+	if filters.ActorType != "" {
+		addFilter("actor_type = $%d", filters.ActorType)
+	}
+	if filters.ActorID != "" {
+		addFilter("actor_id = $%d", filters.ActorID)
+	}
+	if filters.Action != "" {
+		addFilter("action = $%d", filters.Action)
+	}
+	if filters.ResourceType != "" {
+		addFilter("resource_type = $%d", filters.ResourceType)
+	}
+	if filters.ResourceID != "" {
+		addFilter("resource_id = $%d", filters.ResourceID)
+	}
+	if filters.RequestID != "" {
+		addFilter("request_id = $%d", filters.RequestID)
+	}
+	if filters.DateFrom != nil {
+		addFilter("created_at >= $%d", *filters.DateFrom)
+	}
+	if filters.DateTo != nil {
+		addFilter("created_at <= $%d", *filters.DateTo)
+	}
+
+	var total int
+	err := r.db.Conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_log"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar registros de auditoria: %w", err)
+	}
+
+	query := "SELECT " + auditColumns + " FROM audit_log" + where + " ORDER BY sequence DESC"
+	argCount++
+	query += fmt.Sprintf(" LIMIT $%d", argCount)
+	args = append(args, filters.Limit)
+
+	argCount++
+	query += fmt.Sprintf(" OFFSET $%d", argCount)
+	args = append(args, filters.Offset())
+
+	entries, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+func (r *auditPostgresRepository) ListAfter(ctx context.Context, afterSequence int64, limit int) ([]*entity.AuditEntry, error) {
+	query := "SELECT " + auditColumns + " FROM audit_log WHERE sequence > $1 ORDER BY sequence LIMIT $2"
+	return r.query(ctx, query, afterSequence, limit)
+}
+
+func (r *auditPostgresRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.AuditEntry, error) {
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar registros de auditoria: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*entity.AuditEntry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do registro de auditoria: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+const balanceHoldColumns = "id, user_id, transaction_id, amount, status, expires_at, created_at, updated_at, settled_at"
+
+type balanceHoldPostgresRepository struct {
+	db *database.Database
+}
+
+func NewBalanceHoldPostgresRepository(db *database.Database) BalanceHoldRepository {
+	return &balanceHoldPostgresRepository{
+		db: db,
+	}
+}
+
+func scanBalanceHold(row rowScanner) (*entity.BalanceHold, error) {
+	hold := &entity.BalanceHold{}
+	err := row.Scan(
+		&hold.ID,
+		&hold.UserID,
+		&hold.TransactionID,
+		&hold.Amount,
+		&hold.Status,
+		&hold.ExpiresAt,
+		&hold.CreatedAt,
+		&hold.UpdatedAt,
+		&hold.SettledAt,
+	)
+	return hold, err
+}
+
+func (r *balanceHoldPostgresRepository) Create(ctx context.Context, hold *entity.BalanceHold) error {
+	query := `
+		INSERT INTO balance_holds (id, user_id, transaction_id, amount, status, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		hold.ID,
+		hold.UserID,
+		hold.TransactionID,
+		hold.Amount,
+		hold.Status,
+		hold.ExpiresAt,
+		hold.CreatedAt,
+		hold.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao criar reserva de saldo: %w", err)
+	}
+
+	return nil
+}
+
+func (r *balanceHoldPostgresRepository) GetByTransactionID(ctx context.Context, transactionID string) (*entity.BalanceHold, error) {
+	query := "SELECT " + balanceHoldColumns + " FROM balance_holds WHERE transaction_id = $1 FOR UPDATE"
+
+	hold, err := scanBalanceHold(r.db.Conn(ctx).QueryRowContext(ctx, query, transactionID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrHoldNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar reserva de saldo: %w", err)
+	}
+
+	return hold, nil
+}
+
+func (r *balanceHoldPostgresRepository) Update(ctx context.Context, hold *entity.BalanceHold) error {
+	query := `
+		UPDATE balance_holds
+		SET status = $2, settled_at = $3, expires_at = $4, updated_at = $5
+		WHERE id = $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		hold.ID,
+		hold.Status,
+		hold.SettledAt,
+		hold.ExpiresAt,
+		time.Now(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar reserva de saldo: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrHoldNotFound)
+}
+
+func (r *balanceHoldPostgresRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*entity.BalanceHold, error) {
+	query := "SELECT " + balanceHoldColumns + ` FROM balance_holds
+		WHERE status = 'active' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2`
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar reservas expiradas: %w", err)
+	}
+	defer rows.Close()
+
+	var holds []*entity.BalanceHold
+	for rows.Next() {
+		hold, err := scanBalanceHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan da reserva de saldo: %w", err)
+		}
+		holds = append(holds, hold)
+	}
+
+	return holds, rows.Err()
+}
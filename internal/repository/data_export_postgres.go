@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+// dataExportColumns não inclui o arquivo, lido apenas no download
+const dataExportColumns = "id, user_id, status, size_bytes, failure_reason, created_at, updated_at, ready_at, expires_at, downloaded_at"
+
+type dataExportPostgresRepository struct {
+	db *database.Database
+}
+
+func NewDataExportPostgresRepository(db *database.Database) DataExportRepository {
+	return &dataExportPostgresRepository{
+		db: db,
+	}
+}
+
+func scanDataExport(row rowScanner, extra ...interface{}) (*entity.DataExport, error) {
+	export := &entity.DataExport{}
+	dest := []interface{}{
+		&export.ID,
+		&export.UserID,
+		&export.Status,
+		&export.SizeBytes,
+		&export.FailureReason,
+		&export.CreatedAt,
+		&export.UpdatedAt,
+		&export.ReadyAt,
+		&export.ExpiresAt,
+		&export.DownloadedAt,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return export, err
+}
+
+func (r *dataExportPostgresRepository) Create(ctx context.Context, export *entity.DataExport) error {
+	query := `
+		INSERT INTO data_exports (id, user_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		export.ID,
+		export.UserID,
+		export.Status,
+		export.CreatedAt,
+		export.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao criar exportação de dados: %w", err)
+	}
+
+	return nil
+}
+
+func (r *dataExportPostgresRepository) GetByID(ctx context.Context, id string) (*entity.DataExport, error) {
+	query := "SELECT " + dataExportColumns + " FROM data_exports WHERE id = $1"
+
+	export, err := scanDataExport(r.db.Conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrDataExportNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar exportação de dados: %w", err)
+	}
+
+	return export, nil
+}
+
+func (r *dataExportPostgresRepository) GetByIDForUpdate(ctx context.Context, id string) (*entity.DataExport, error) {
+	query := "SELECT " + dataExportColumns + ", archive FROM data_exports WHERE id = $1 FOR UPDATE"
+
+	var archive []byte
+	export, err := scanDataExport(r.db.Conn(ctx).QueryRowContext(ctx, query, id), &archive)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrDataExportNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar exportação de dados: %w", err)
+	}
+	export.Archive = archive
+
+	return export, nil
+}
+
+func (r *dataExportPostgresRepository) HasInProgress(ctx context.Context, userID string, now time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM data_exports
+			WHERE user_id = $1 AND (status = 'pending' OR (status = 'processing' AND lease_expires_at > $2))
+		)
+	`
+
+	var exists bool
+	if err := r.db.Conn(ctx).QueryRowContext(ctx, query, userID, now).Scan(&exists); err != nil {
+		return false, fmt.Errorf("erro ao verificar exportações em andamento: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *dataExportPostgresRepository) ClaimNextPending(ctx context.Context, now time.Time, lease time.Duration) (*entity.DataExport, error) {
+	query := `
+		UPDATE data_exports
+		SET status = 'processing', lease_expires_at = $2, updated_at = $1
+		WHERE id = (
+			SELECT id FROM data_exports
+			WHERE status = 'pending' OR (status = 'processing' AND lease_expires_at <= $1)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + dataExportColumns
+
+	export, err := scanDataExport(r.db.Conn(ctx).QueryRowContext(ctx, query, now, now.Add(lease)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar exportação pendente: %w", err)
+	}
+
+	return export, nil
+}
+
+func (r *dataExportPostgresRepository) Update(ctx context.Context, export *entity.DataExport) error {
+	query := `
+		UPDATE data_exports
+		SET status = $2, archive = $3, size_bytes = $4, failure_reason = $5, updated_at = $6,
+			ready_at = $7, expires_at = $8, downloaded_at = $9
+		WHERE id = $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		export.ID,
+		export.Status,
+		export.Archive,
+		export.SizeBytes,
+		export.FailureReason,
+		export.UpdatedAt,
+		export.ReadyAt,
+		export.ExpiresAt,
+		export.DownloadedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar exportação de dados: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrDataExportNotFound)
+}
+
+func (r *dataExportPostgresRepository) ExpireReady(ctx context.Context, now time.Time) (int, error) {
+	query := `
+		UPDATE data_exports
+		SET status = 'expired', archive = NULL, updated_at = $1
+		WHERE status = 'ready' AND expires_at < $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao expirar exportações de dados: %w", err)
+	}
+
+	expired, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	return int(expired), nil
+}
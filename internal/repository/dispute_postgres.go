@@ -0,0 +1,351 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+
+	"github.com/lib/pq"
+)
+
+const (
+	disputeColumns         = "id, transaction_id, payer_id, merchant_id, reason, status, response_due_at, resolution_due_at, resolution_note, created_at, updated_at, resolved_at, escalated_at"
+	disputeEvidenceColumns = "id, dispute_id, party, submitted_by, description, attachments, created_at"
+	disputeEventColumns    = "id, dispute_id, from_status, to_status, actor, actor_id, note, created_at"
+)
+
+type disputePostgresRepository struct {
+	db *database.Database
+}
+
+func NewDisputePostgresRepository(db *database.Database) DisputeRepository {
+	return &disputePostgresRepository{
+		db: db,
+	}
+}
+
+func scanDispute(row rowScanner) (*entity.Dispute, error) {
+	dispute := &entity.Dispute{}
+	err := row.Scan(
+		&dispute.ID,
+		&dispute.TransactionID,
+		&dispute.PayerID,
+		&dispute.MerchantID,
+		&dispute.Reason,
+		&dispute.Status,
+		&dispute.ResponseDueAt,
+		&dispute.ResolutionDueAt,
+		&dispute.ResolutionNote,
+		&dispute.CreatedAt,
+		&dispute.UpdatedAt,
+		&dispute.ResolvedAt,
+		&dispute.EscalatedAt,
+	)
+	return dispute, err
+}
+
+func (r *disputePostgresRepository) Create(ctx context.Context, dispute *entity.Dispute) error {
+	return r.db.WithinTx(ctx, func(ctx context.Context) error {
+		query := `
+			INSERT INTO disputes (` + disputeColumns + `)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		`
+
+		_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+			dispute.ID,
+			dispute.TransactionID,
+			dispute.PayerID,
+			dispute.MerchantID,
+			dispute.Reason,
+			dispute.Status,
+			dispute.ResponseDueAt,
+			dispute.ResolutionDueAt,
+			dispute.ResolutionNote,
+			dispute.CreatedAt,
+			dispute.UpdatedAt,
+			dispute.ResolvedAt,
+			dispute.EscalatedAt,
+		)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+				return entity.ErrDisputeAlreadyExists
+			}
+			return fmt.Errorf("erro ao criar disputa: %w", err)
+		}
+
+		for _, evidence := range dispute.Evidence {
+			if err := r.AddEvidence(ctx, evidence); err != nil {
+				return err
+			}
+		}
+		for _, event := range dispute.History {
+			if err := r.AddEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *disputePostgresRepository) GetByID(ctx context.Context, id string) (*entity.Dispute, error) {
+	return r.getOne(ctx, "SELECT "+disputeColumns+" FROM disputes WHERE id = $1", id)
+}
+
+func (r *disputePostgresRepository) GetByIDForUpdate(ctx context.Context, id string) (*entity.Dispute, error) {
+	return r.getOne(ctx, "SELECT "+disputeColumns+" FROM disputes WHERE id = $1 FOR UPDATE", id)
+}
+
+func (r *disputePostgresRepository) getOne(ctx context.Context, query string, id string) (*entity.Dispute, error) {
+	dispute, err := scanDispute(r.db.Conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrDisputeNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar disputa: %w", err)
+	}
+
+	if dispute.Evidence, err = r.listEvidence(ctx, dispute.ID); err != nil {
+		return nil, err
+	}
+	if dispute.History, err = r.listEvents(ctx, dispute.ID); err != nil {
+		return nil, err
+	}
+
+	return dispute, nil
+}
+
+func (r *disputePostgresRepository) Update(ctx context.Context, dispute *entity.Dispute) error {
+	query := `
+		UPDATE disputes
+		SET status = $2, resolution_note = $3, resolved_at = $4, escalated_at = $5, updated_at = $6
+		WHERE id = $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		dispute.ID,
+		dispute.Status,
+		dispute.ResolutionNote,
+		dispute.ResolvedAt,
+		dispute.EscalatedAt,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar disputa: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrDisputeNotFound)
+}
+
+func (r *disputePostgresRepository) AddEvidence(ctx context.Context, evidence *entity.DisputeEvidence) error {
+	attachments, err := json.Marshal(evidence.Attachments)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar anexos: %w", err)
+	}
+
+	query := `
+		INSERT INTO dispute_evidence (` + disputeEvidenceColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.db.Conn(ctx).ExecContext(ctx, query,
+		evidence.ID,
+		evidence.DisputeID,
+		evidence.Party,
+		evidence.SubmittedBy,
+		evidence.Description,
+		attachments,
+		evidence.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar evidência: %w", err)
+	}
+
+	return nil
+}
+
+func (r *disputePostgresRepository) AddEvent(ctx context.Context, event *entity.DisputeEvent) error {
+	query := `
+		INSERT INTO dispute_events (` + disputeEventColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		event.ID,
+		event.DisputeID,
+		event.FromStatus,
+		event.ToStatus,
+		event.Actor,
+		event.ActorID,
+		event.Note,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar histórico da disputa: %w", err)
+	}
+
+	return nil
+}
+
+func (r *disputePostgresRepository) List(ctx context.Context, filters *entity.DisputeFilters) ([]*entity.Dispute, int, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	argCount := 0
+
+	if filters.UserID != "" {
+		argCount++
+		where += fmt.Sprintf(" AND (payer_id = $%d OR merchant_id = $%d)", argCount, argCount)
+		args = append(args, filters.UserID)
+	}
+
+	if filters.Status != "" {
+		argCount++
+		where += fmt.Sprintf(" AND status = $%d", argCount)
+		args = append(args, filters.Status)
+	}
+
+	if filters.OverdueAt != nil {
+		argCount++
+		where += fmt.Sprintf(" AND status IN ('open', 'under_review') AND resolution_due_at < $%d", argCount)
+		args = append(args, *filters.OverdueAt)
+	}
+
+	var total int
+	err := r.db.Conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM disputes"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar disputas: %w", err)
+	}
+
+	// Disputas com prazo de decisão mais próximo primeiro
+	query := "SELECT " + disputeColumns + " FROM disputes" + where + " ORDER BY resolution_due_at"
+	argCount++
+	query += fmt.Sprintf(" LIMIT $%d", argCount)
+	args = append(args, filters.Limit)
+
+	argCount++
+	query += fmt.Sprintf(" OFFSET $%d", argCount)
+	args = append(args, filters.Offset())
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar disputas: %w", err)
+	}
+	defer rows.Close()
+
+	var disputes []*entity.Dispute
+	for rows.Next() {
+		dispute, err := scanDispute(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("erro ao fazer scan da disputa: %w", err)
+		}
+		disputes = append(disputes, dispute)
+	}
+
+	return disputes, total, rows.Err()
+}
+
+func (r *disputePostgresRepository) ListAwaitingResponseBefore(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	query := "SELECT id FROM disputes WHERE status = 'open' AND response_due_at < $1 ORDER BY response_due_at LIMIT $2"
+	return r.listIDs(ctx, "erro ao listar disputas sem resposta", query, now, limit)
+}
+
+func (r *disputePostgresRepository) ListPastResolutionDue(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT id FROM disputes
+		WHERE status IN ('open', 'under_review') AND escalated_at IS NULL AND resolution_due_at < $1
+		ORDER BY resolution_due_at
+		LIMIT $2
+	`
+	return r.listIDs(ctx, "erro ao listar disputas com prazo de decisão vencido", query, now, limit)
+}
+
+func (r *disputePostgresRepository) listIDs(ctx context.Context, message, query string, now time.Time, limit int) ([]string, error) {
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", message, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan da disputa: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *disputePostgresRepository) listEvidence(ctx context.Context, disputeID string) ([]*entity.DisputeEvidence, error) {
+	query := "SELECT " + disputeEvidenceColumns + " FROM dispute_evidence WHERE dispute_id = $1 ORDER BY created_at"
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, disputeID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar evidências: %w", err)
+	}
+	defer rows.Close()
+
+	var evidence []*entity.DisputeEvidence
+	for rows.Next() {
+		item := &entity.DisputeEvidence{}
+		var attachments []byte
+		err := rows.Scan(
+			&item.ID,
+			&item.DisputeID,
+			&item.Party,
+			&item.SubmittedBy,
+			&item.Description,
+			&attachments,
+			&item.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan da evidência: %w", err)
+		}
+		if err := json.Unmarshal(attachments, &item.Attachments); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar anexos: %w", err)
+		}
+		evidence = append(evidence, item)
+	}
+
+	return evidence, rows.Err()
+}
+
+func (r *disputePostgresRepository) listEvents(ctx context.Context, disputeID string) ([]*entity.DisputeEvent, error) {
+	query := "SELECT " + disputeEventColumns + " FROM dispute_events WHERE dispute_id = $1 ORDER BY created_at"
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, disputeID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar histórico da disputa: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*entity.DisputeEvent
+	for rows.Next() {
+		event := &entity.DisputeEvent{}
+		err := rows.Scan(
+			&event.ID,
+			&event.DisputeID,
+			&event.FromStatus,
+			&event.ToStatus,
+			&event.Actor,
+			&event.ActorID,
+			&event.Note,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do histórico da disputa: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
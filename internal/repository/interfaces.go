@@ -3,24 +3,370 @@ package repository
 import (
 	"context"
 	"payflow-api/internal/entity"
+	"time"
+
+	"github.com/shopspring/decimal"
 )
 
+// TxManager executa operações de vários repositórios dentro de uma mesma transação de banco.
+type TxManager interface {
+	// WithinTx executa fn em uma transação, com rollback se fn retornar erro.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// AfterCommit agenda fn para depois do commit da transação do contexto; é descartada no rollback.
+	AfterCommit(ctx context.Context, fn func(ctx context.Context))
+}
+
 // UserRepository define métodos para manipulação de usuários no repositório.
 type UserRepository interface {
 	// Create insere um novo usuário.
 	Create(ctx context.Context, user *entity.User) error
 	// GetByID retorna um usuário pelo ID.
 	GetByID(ctx context.Context, id string) (*entity.User, error)
+	// GetByIDForUpdate retorna um usuário pelo ID bloqueando a linha até o fim da transação.
+	GetByIDForUpdate(ctx context.Context, id string) (*entity.User, error)
 	// GetByEmail retorna um usuário pelo e-mail.
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	// GetByDocument retorna um usuário pelo documento.
 	GetByDocument(ctx context.Context, document string) (*entity.User, error)
 	// Update atualiza os dados de um usuário.
 	Update(ctx context.Context, user *entity.User) error
+	// UpdateBalance atualiza o saldo e o saldo reservado de um usuário.
+	UpdateBalance(ctx context.Context, user *entity.User) error
+	// UpdateStatus atualiza o status da conta e o motivo da última mudança.
+	UpdateStatus(ctx context.Context, user *entity.User) error
+	// AddStatusChange registra uma mudança de status na trilha de auditoria.
+	AddStatusChange(ctx context.Context, change *entity.AccountStatusChange) error
+	// ListStatusChanges retorna o histórico de status da conta, do mais antigo ao mais recente.
+	ListStatusChanges(ctx context.Context, userID string) ([]*entity.AccountStatusChange, error)
 	// List retorna uma lista de usuários com filtros e total.
 	List(ctx context.Context, filters *entity.UserFilters) ([]*entity.User, int, error)
-	// Delete remove um usuário pelo ID.
-	Delete(ctx context.Context, id string) error
-	// ExistsByEmailOrDocument verifica se existe usuário por e-mail ou documento.
+	// Delete grava a exclusão lógica do usuário; retorna ErrUserNotFound se já estiver excluído.
+	Delete(ctx context.Context, user *entity.User) error
+	// Anonymize grava os dados pessoais apagados do usuário excluído.
+	Anonymize(ctx context.Context, user *entity.User) error
+	// ListPendingAnonymization retorna os IDs de usuários excluídos até a data e ainda não anonimizados.
+	ListPendingAnonymization(ctx context.Context, deletedBefore time.Time, limit int) ([]string, error)
+	// ExistsByEmailOrDocument verifica se existe usuário não excluído por e-mail ou documento.
 	ExistsByEmailOrDocument(ctx context.Context, email, document string) (bool, error)
 }
+
+// TransactionRepository define métodos para manipulação de transações no repositório.
+type TransactionRepository interface {
+	// Create insere uma nova transação.
+	Create(ctx context.Context, transaction *entity.Transaction) error
+	// GetByID retorna uma transação pelo ID.
+	GetByID(ctx context.Context, id string) (*entity.Transaction, error)
+	// GetByIDForUpdate retorna uma transação pelo ID bloqueando a linha até o fim da transação.
+	GetByIDForUpdate(ctx context.Context, id string) (*entity.Transaction, error)
+	// Update atualiza status e metadados de uma transação.
+	Update(ctx context.Context, transaction *entity.Transaction) error
+	// MarkNotificationSent registra que o recebedor foi notificado, sem tocar no status.
+	MarkNotificationSent(ctx context.Context, id string) error
+	// List retorna uma lista de transações com filtros e total.
+	List(ctx context.Context, filters *entity.TransactionFilters) ([]*entity.Transaction, int, error)
+	// LockNextDueScheduled retorna a transferência agendada vencida mais antiga bloqueando a linha até o
+	// fim da transação, ignorando as bloqueadas por outra instância; ErrTransactionNotFound se não houver.
+	LockNextDueScheduled(ctx context.Context, now time.Time) (*entity.Transaction, error)
+}
+
+// BalanceHoldRepository define métodos para manipulação de reservas de saldo.
+type BalanceHoldRepository interface {
+	// Create insere uma nova reserva.
+	Create(ctx context.Context, hold *entity.BalanceHold) error
+	// GetByTransactionID retorna a reserva vinculada a uma transação.
+	GetByTransactionID(ctx context.Context, transactionID string) (*entity.BalanceHold, error)
+	// Update atualiza o status de uma reserva.
+	Update(ctx context.Context, hold *entity.BalanceHold) error
+	// ListExpired retorna reservas ativas cuja validade terminou antes de now.
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*entity.BalanceHold, error)
+}
+
+// LimitRepository define métodos para manipulação de limites de transferência e seus contadores de uso.
+type LimitRepository interface {
+	// GetDefaults retorna os limites padrão de um tipo de usuário.
+	GetDefaults(ctx context.Context, userType entity.UserType) (*entity.TransactionLimits, error)
+	// UpsertDefaults grava os limites padrão de um tipo de usuário.
+	UpsertDefaults(ctx context.Context, userType entity.UserType, limits *entity.TransactionLimits) error
+	// GetOverride retorna a sobrescrita de limites de um usuário ou nil se não houver.
+	GetOverride(ctx context.Context, userID string) (*entity.UserLimitOverride, error)
+	// UpsertOverride grava a sobrescrita de limites de um usuário.
+	UpsertOverride(ctx context.Context, override *entity.UserLimitOverride) error
+	// DeleteOverride remove a sobrescrita de limites de um usuário.
+	DeleteOverride(ctx context.Context, userID string) error
+	// GetUsage retorna o uso acumulado nos períodos informados.
+	GetUsage(ctx context.Context, userID string, keys map[entity.LimitPeriod]string) (entity.LimitUsage, error)
+	// AddUsage soma (ou subtrai, se negativo) um valor ao contador de um período.
+	AddUsage(ctx context.Context, userID string, period entity.LimitPeriod, periodKey string, amount decimal.Decimal) error
+}
+
+// PricingRepository define métodos para planos de tarifas e lançamentos na conta de receita.
+type PricingRepository interface {
+	// CreatePlan insere um novo plano de tarifas.
+	CreatePlan(ctx context.Context, plan *entity.PricingPlan) error
+	// GetPlan retorna um plano pelo ID.
+	GetPlan(ctx context.Context, id string) (*entity.PricingPlan, error)
+	// ListPlans retorna todos os planos cadastrados.
+	ListPlans(ctx context.Context) ([]*entity.PricingPlan, error)
+	// GetPlanForUser retorna o plano do usuário, o padrão do seu tipo ou nil se não houver.
+	GetPlanForUser(ctx context.Context, user *entity.User) (*entity.PricingPlan, error)
+	// AssignPlan vincula um plano específico a um usuário.
+	AssignPlan(ctx context.Context, userID, planID string) error
+	// PostFee registra a tarifa e credita a conta da plataforma.
+	PostFee(ctx context.Context, entry *entity.FeeEntry) error
+	// GetAccount retorna uma conta interna da plataforma.
+	GetAccount(ctx context.Context, code string) (*entity.PlatformAccount, error)
+}
+
+// RecurringRepository define métodos para mandatos de pagamento recorrente e suas execuções.
+type RecurringRepository interface {
+	// Create insere um novo mandato.
+	Create(ctx context.Context, mandate *entity.RecurringMandate) error
+	// GetByID retorna um mandato pelo ID.
+	GetByID(ctx context.Context, id string) (*entity.RecurringMandate, error)
+	// GetByIDForUpdate retorna um mandato pelo ID bloqueando a linha até o fim da transação.
+	GetByIDForUpdate(ctx context.Context, id string) (*entity.RecurringMandate, error)
+	// Update atualiza status e agenda de um mandato.
+	Update(ctx context.Context, mandate *entity.RecurringMandate) error
+	// ListByPayer retorna os mandatos de um pagador.
+	ListByPayer(ctx context.Context, payerID string) ([]*entity.RecurringMandate, error)
+	// LockNextDue bloqueia o próximo mandato ativo vencido, ignorando os já bloqueados por outra instância.
+	LockNextDue(ctx context.Context, now time.Time) (*entity.RecurringMandate, error)
+	// RunExists informa se o período do mandato já foi executado.
+	RunExists(ctx context.Context, mandateID, periodKey string) (bool, error)
+	// CreateRun registra a execução de um período; falha com ErrMandatePeriodAlreadyRun se já existir.
+	CreateRun(ctx context.Context, run *entity.MandateRun) error
+	// ListRuns retorna o histórico de execuções com o status das transações geradas.
+	ListRuns(ctx context.Context, mandateID string) ([]entity.MandateRun, error)
+}
+
+// PaymentRequestRepository define métodos para cobranças emitidas por lojistas.
+type PaymentRequestRepository interface {
+	// Create insere uma nova cobrança.
+	Create(ctx context.Context, request *entity.PaymentRequest) error
+	// GetByID retorna uma cobrança pelo ID.
+	GetByID(ctx context.Context, id string) (*entity.PaymentRequest, error)
+	// GetByTxID retorna uma cobrança pelo txid usado no BR Code.
+	GetByTxID(ctx context.Context, txID string) (*entity.PaymentRequest, error)
+	// GetByIDForUpdate retorna uma cobrança pelo ID bloqueando a linha até o fim da transação.
+	GetByIDForUpdate(ctx context.Context, id string) (*entity.PaymentRequest, error)
+	// GetByTransactionIDForUpdate retorna a cobrança vinculada à transação bloqueando a linha.
+	GetByTransactionIDForUpdate(ctx context.Context, transactionID string) (*entity.PaymentRequest, error)
+	// Update atualiza status e vínculo de pagamento de uma cobrança.
+	Update(ctx context.Context, request *entity.PaymentRequest) error
+	// List retorna cobranças com filtros e paginação, junto com o total.
+	List(ctx context.Context, filters *entity.PaymentRequestFilters) ([]*entity.PaymentRequest, int, error)
+	// ExpireOverdue marca como expiradas as cobranças abertas vencidas sem pagamento em andamento.
+	ExpireOverdue(ctx context.Context, now time.Time) (int, error)
+}
+
+// PixKeyRepository define métodos para o diretório de chaves Pix.
+type PixKeyRepository interface {
+	// Create insere uma chave; falha com ErrPixKeyAlreadyExists se o valor já estiver cadastrado.
+	Create(ctx context.Context, key *entity.PixKey) error
+	// GetByValue retorna a chave pelo valor normalizado.
+	GetByValue(ctx context.Context, value string) (*entity.PixKey, error)
+	// ListByUser retorna as chaves de um usuário.
+	ListByUser(ctx context.Context, userID string) ([]*entity.PixKey, error)
+	// CountByUser retorna quantas chaves o usuário possui.
+	CountByUser(ctx context.Context, userID string) (int, error)
+	// Delete remove uma chave do usuário.
+	Delete(ctx context.Context, userID, id string) error
+	// DeleteByUser remove todas as chaves do usuário.
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+// TransferBatchRepository define métodos para lotes de transferências.
+type TransferBatchRepository interface {
+	// Create insere o lote e seus itens.
+	Create(ctx context.Context, batch *entity.TransferBatch) error
+	// GetByID retorna o lote com seus itens ordenados pela posição.
+	GetByID(ctx context.Context, id string) (*entity.TransferBatch, error)
+	// ClaimNextPending marca como em processamento o lote pendente mais antigo e o retorna com os itens,
+	// reservando-o por lease. Um lote em processamento cujo prazo venceu (a instância parou) também é
+	// retomado. Retorna nil quando não há lotes pendentes.
+	ClaimNextPending(ctx context.Context, now time.Time, lease time.Duration) (*entity.TransferBatch, error)
+	// RenewLease estende o prazo da instância que processa o lote.
+	RenewLease(ctx context.Context, id string, until time.Time) error
+	// Update atualiza status e contadores do lote.
+	Update(ctx context.Context, batch *entity.TransferBatch) error
+	// UpdateItem atualiza o resultado de um item.
+	UpdateItem(ctx context.Context, item *entity.BatchItem) error
+}
+
+// SplitPaymentRepository define métodos para pagamentos divididos e seus estornos.
+type SplitPaymentRepository interface {
+	// Create insere o pagamento dividido e suas partes.
+	Create(ctx context.Context, split *entity.SplitPayment) error
+	// GetByID retorna o pagamento com as partes ordenadas pela posição e os estornos.
+	GetByID(ctx context.Context, id string) (*entity.SplitPayment, error)
+	// GetByIDForUpdate retorna o pagamento pelo ID bloqueando a linha até o fim da transação.
+	GetByIDForUpdate(ctx context.Context, id string) (*entity.SplitPayment, error)
+	// Update atualiza status e valores estornados do pagamento e de suas partes.
+	Update(ctx context.Context, split *entity.SplitPayment) error
+	// CreateRefund registra um estorno com o valor de cada parte.
+	CreateRefund(ctx context.Context, refund *entity.SplitRefund) error
+	// HasRefundedLeg informa se a transação liquida uma parte de pagamento dividido que já teve estorno.
+	HasRefundedLeg(ctx context.Context, transactionID string) (bool, error)
+}
+
+// DisputeRepository define métodos para disputas, suas evidências e seu histórico de status.
+type DisputeRepository interface {
+	// Create insere a disputa com as evidências e o histórico iniciais; falha com ErrDisputeAlreadyExists
+	// se a transação já tiver disputa.
+	Create(ctx context.Context, dispute *entity.Dispute) error
+	// GetByID retorna a disputa com evidências e histórico.
+	GetByID(ctx context.Context, id string) (*entity.Dispute, error)
+	// GetByIDForUpdate retorna a disputa pelo ID bloqueando a linha até o fim da transação.
+	GetByIDForUpdate(ctx context.Context, id string) (*entity.Dispute, error)
+	// Update atualiza status e decisão da disputa.
+	Update(ctx context.Context, dispute *entity.Dispute) error
+	// AddEvidence registra uma evidência enviada por uma das partes.
+	AddEvidence(ctx context.Context, evidence *entity.DisputeEvidence) error
+	// AddEvent registra uma mudança de status no histórico.
+	AddEvent(ctx context.Context, event *entity.DisputeEvent) error
+	// List retorna disputas com filtros e paginação, junto com o total, sem evidências e histórico.
+	List(ctx context.Context, filters *entity.DisputeFilters) ([]*entity.Dispute, int, error)
+	// ListAwaitingResponseBefore retorna os IDs das disputas abertas cujo prazo de resposta terminou.
+	ListAwaitingResponseBefore(ctx context.Context, now time.Time, limit int) ([]string, error)
+	// ListPastResolutionDue retorna os IDs das disputas não decididas cujo prazo de decisão terminou
+	// e que ainda não foram escaladas.
+	ListPastResolutionDue(ctx context.Context, now time.Time, limit int) ([]string, error)
+}
+
+// RiskRepository define métodos para o histórico consultado pelas regras de risco e a fila de análise
+type RiskRepository interface {
+	// GetProfile retorna o histórico do pagador da transação; since delimita a janela de velocidade
+	GetProfile(ctx context.Context, transaction *entity.Transaction, since time.Time) (*entity.RiskProfile, error)
+	// CreateReview registra uma transferência retida ou negada na fila de análise, com o histórico inicial
+	CreateReview(ctx context.Context, review *entity.RiskReview) error
+	// GetReview retorna o item da fila com o histórico de ações
+	GetReview(ctx context.Context, id string) (*entity.RiskReview, error)
+	// GetReviewForUpdate retorna o item bloqueando a linha até o fim da transação
+	GetReviewForUpdate(ctx context.Context, id string) (*entity.RiskReview, error)
+	// UpdateReview atualiza status, atribuição e decisão do item
+	UpdateReview(ctx context.Context, review *entity.RiskReview) error
+	// AddReviewEvent registra uma ação na trilha de auditoria do item
+	AddReviewEvent(ctx context.Context, event *entity.RiskReviewEvent) error
+	// ListReviews lista itens da fila com filtros e paginação, sem o histórico
+	ListReviews(ctx context.Context, filters *entity.RiskReviewFilters) ([]*entity.RiskReview, int, error)
+	// ListOverdueReviews retorna os IDs dos itens abertos cujo prazo de análise terminou
+	ListOverdueReviews(ctx context.Context, now time.Time, limit int) ([]string, error)
+}
+
+// DataExportRepository define métodos para as exportações de dados dos titulares.
+type DataExportRepository interface {
+	// Create insere um pedido de exportação pendente.
+	Create(ctx context.Context, export *entity.DataExport) error
+	// GetByID retorna a exportação sem o arquivo.
+	GetByID(ctx context.Context, id string) (*entity.DataExport, error)
+	// GetByIDForUpdate retorna a exportação com o arquivo, bloqueando a linha até o fim da transação.
+	GetByIDForUpdate(ctx context.Context, id string) (*entity.DataExport, error)
+	// HasInProgress informa se o usuário tem exportação pendente ou em processamento dentro do prazo;
+	// uma exportação abandonada por uma instância que parou não bloqueia um novo pedido.
+	HasInProgress(ctx context.Context, userID string, now time.Time) (bool, error)
+	// ClaimNextPending marca como em processamento a exportação pendente mais antiga e a retorna,
+	// reservando-a por lease. Uma exportação em processamento cujo prazo venceu também é retomada.
+	// Retorna nil quando não há exportações pendentes.
+	ClaimNextPending(ctx context.Context, now time.Time, lease time.Duration) (*entity.DataExport, error)
+	// Update atualiza status, arquivo e datas da exportação.
+	Update(ctx context.Context, export *entity.DataExport) error
+	// ExpireReady expira as exportações prontas cujo link venceu, descartando os arquivos.
+	ExpireReady(ctx context.Context, now time.Time) (int, error)
+}
+
+// AuditRepository define métodos para a trilha de auditoria, que só aceita inserções.
+type AuditRepository interface {
+	// Append encadeia o registro ao último da trilha e o insere no fim da transação do contexto,
+	// junto com o commit. As gravações são serializadas para que a sequência e os hashes não se cruzem.
+	Append(ctx context.Context, entry *entity.AuditEntry) error
+	// List retorna registros com filtros e paginação, do mais recente ao mais antigo, junto com o total.
+	List(ctx context.Context, filters *entity.AuditLogFilters) ([]*entity.AuditEntry, int, error)
+	// ListAfter retorna até limit registros com sequência maior que afterSequence, em ordem crescente.
+	ListAfter(ctx context.Context, afterSequence int64, limit int) ([]*entity.AuditEntry, error)
+}
+
+// StatementRepository define métodos de leitura dos lançamentos que compõem o extrato.
+type StatementRepository interface {
+	// BalanceAt retorna o saldo do usuário no instante at: o saldo atual menos os lançamentos a partir de at.
+	BalanceAt(ctx context.Context, userID string, at time.Time) (decimal.Decimal, error)
+	// ListMovements retorna os lançamentos do usuário em [from, to), em ordem cronológica.
+	ListMovements(ctx context.Context, userID string, from, to time.Time) ([]*entity.StatementMovement, error)
+}
+
+// ReceiptRepository define métodos para os comprovantes de transferências, que não mudam depois de emitidos.
+type ReceiptRepository interface {
+	// Create grava o comprovante, se a transação ainda não tiver um, e retorna o comprovante vigente.
+	Create(ctx context.Context, receipt *entity.Receipt) (*entity.Receipt, error)
+	// GetByTransactionID retorna o comprovante de uma transação.
+	GetByTransactionID(ctx context.Context, transactionID string) (*entity.Receipt, error)
+	// GetByAuthenticationCode retorna o comprovante pelo código de autenticação.
+	GetByAuthenticationCode(ctx context.Context, code string) (*entity.Receipt, error)
+}
+
+// WebhookRepository define métodos para os endpoints de webhook dos lojistas e as entregas de eventos.
+type WebhookRepository interface {
+	// CreateEndpoint insere um novo endpoint.
+	CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	// GetEndpoint retorna um endpoint pelo ID.
+	GetEndpoint(ctx context.Context, id string) (*entity.WebhookEndpoint, error)
+	// ListEndpoints retorna os endpoints do lojista, do mais antigo ao mais recente.
+	ListEndpoints(ctx context.Context, merchantID string) ([]*entity.WebhookEndpoint, error)
+	// ListSubscribedEndpoints retorna os endpoints ativos do lojista que assinam o evento.
+	ListSubscribedEndpoints(ctx context.Context, merchantID string, eventType entity.WebhookEventType) ([]*entity.WebhookEndpoint, error)
+	// CountEndpoints retorna quantos endpoints o lojista possui.
+	CountEndpoints(ctx context.Context, merchantID string) (int, error)
+	// UpdateEndpoint atualiza URL, descrição, eventos e se o endpoint está ativo.
+	UpdateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	// DeleteEndpoint remove o endpoint junto com suas entregas.
+	DeleteEndpoint(ctx context.Context, id string) error
+	// CreateDelivery insere uma entrega pendente.
+	CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	// GetDelivery retorna uma entrega com o corpo enviado e o log de tentativas.
+	GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error)
+	// ListDeliveries retorna entregas do lojista com filtros e paginação, da mais recente à mais antiga, junto com o total.
+	ListDeliveries(ctx context.Context, filters *entity.WebhookDeliveryFilters) ([]*entity.WebhookDelivery, int, error)
+	// ClaimDueDeliveries reserva até limit entregas pendentes vencidas, adiando a próxima tentativa por lease
+	// para que outra instância não as envie ao mesmo tempo. Uma entrega reservada por uma instância que
+	// parou volta a ser elegível quando o prazo termina.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error)
+	// UpdateDelivery atualiza status, tentativas e resultado da última tentativa.
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	// AddAttempt registra uma tentativa no log da entrega.
+	AddAttempt(ctx context.Context, attempt *entity.WebhookAttempt) error
+}
+
+// OutboxRepository define métodos para o outbox dos eventos de domínio.
+type OutboxRepository interface {
+	// Append grava os eventos; deve ser chamado na transação da mudança que os originou.
+	Append(ctx context.Context, events []*entity.OutboxEvent) error
+	// ClaimDue reserva até limit eventos não despachados cuja próxima tentativa venceu, adiando-a
+	// pelo lease para que outra instância não os despache ao mesmo tempo.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.OutboxEvent, error)
+	// Update grava os assinantes que já processaram o evento e o resultado da tentativa.
+	Update(ctx context.Context, event *entity.OutboxEvent) error
+	// LockPublishing reserva a publicação no broker para esta transação; retorna false se outra
+	// instância já está publicando. Deve ser chamado dentro de uma transação.
+	LockPublishing(ctx context.Context) (bool, error)
+	// ListUnpublished retorna até limit eventos ainda não publicados no broker, pelo momento da
+	// gravação. Não é a ordem de commit das transações que gravaram os eventos.
+	ListUnpublished(ctx context.Context, limit int) ([]*entity.OutboxEvent, error)
+	// MarkPublished registra a publicação do evento no broker.
+	MarkPublished(ctx context.Context, id string, publishedAt time.Time) error
+	// CountBacklog conta os eventos ainda não despachados e os ainda não publicados.
+	CountBacklog(ctx context.Context) (entity.OutboxBacklog, error)
+}
+
+// UserStreamRepository define métodos para os eventos retidos do stream SSE dos usuários.
+type UserStreamRepository interface {
+	// Append grava os eventos ainda não gravados e avisa as instâncias pelo canal UserStreamChannel;
+	// o aviso só é entregue no commit da transação.
+	Append(ctx context.Context, events []*entity.UserStreamEvent) error
+	// ListAfter retorna até limit eventos do usuário posteriores ao ID, do mais antigo ao mais recente.
+	ListAfter(ctx context.Context, userID string, afterID int64, limit int) ([]*entity.UserStreamEvent, error)
+	// LatestID retorna o ID do evento mais recente do usuário, ou zero se não houver.
+	LatestID(ctx context.Context, userID string) (int64, error)
+	// OldestID retorna o ID do evento mais antigo ainda retido, ou zero se não houver.
+	OldestID(ctx context.Context) (int64, error)
+	// DeleteBefore apaga os eventos criados antes da data e retorna quantos foram apagados.
+	DeleteBefore(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+
+	"github.com/shopspring/decimal"
+)
+
+type limitPostgresRepository struct {
+	db *database.Database
+}
+
+func NewLimitPostgresRepository(db *database.Database) LimitRepository {
+	return &limitPostgresRepository{
+		db: db,
+	}
+}
+
+func (r *limitPostgresRepository) GetDefaults(ctx context.Context, userType entity.UserType) (*entity.TransactionLimits, error) {
+	query := `
+		SELECT per_transaction, daily, monthly, nightly
+		FROM default_transaction_limits
+		WHERE user_type = $1
+	`
+
+	limits := &entity.TransactionLimits{}
+	err := r.db.Conn(ctx).QueryRowContext(ctx, query, userType).Scan(
+		&limits.PerTransaction,
+		&limits.Daily,
+		&limits.Monthly,
+		&limits.Nightly,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrLimitsNotConfigured
+		}
+		return nil, fmt.Errorf("erro ao buscar limites padrão: %w", err)
+	}
+
+	return limits, nil
+}
+
+func (r *limitPostgresRepository) UpsertDefaults(ctx context.Context, userType entity.UserType, limits *entity.TransactionLimits) error {
+	query := `
+		INSERT INTO default_transaction_limits (user_type, per_transaction, daily, monthly, nightly)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_type) DO UPDATE
+		SET per_transaction = EXCLUDED.per_transaction,
+			daily = EXCLUDED.daily,
+			monthly = EXCLUDED.monthly,
+			nightly = EXCLUDED.nightly
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		userType,
+		limits.PerTransaction,
+		limits.Daily,
+		limits.Monthly,
+		limits.Nightly,
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao salvar limites padrão: %w", err)
+	}
+
+	return nil
+}
+
+func (r *limitPostgresRepository) GetOverride(ctx context.Context, userID string) (*entity.UserLimitOverride, error) {
+	query := `
+		SELECT user_id, per_transaction, daily, monthly, nightly, updated_at
+		FROM user_transaction_limits
+		WHERE user_id = $1
+	`
+
+	override := &entity.UserLimitOverride{}
+	var perTransaction, daily, monthly, nightly decimal.NullDecimal
+	err := r.db.Conn(ctx).QueryRowContext(ctx, query, userID).Scan(
+		&override.UserID,
+		&perTransaction,
+		&daily,
+		&monthly,
+		&nightly,
+		&override.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar limites do usuário: %w", err)
+	}
+
+	override.PerTransaction = nullDecimalPtr(perTransaction)
+	override.Daily = nullDecimalPtr(daily)
+	override.Monthly = nullDecimalPtr(monthly)
+	override.Nightly = nullDecimalPtr(nightly)
+
+	return override, nil
+}
+
+func (r *limitPostgresRepository) UpsertOverride(ctx context.Context, override *entity.UserLimitOverride) error {
+	query := `
+		INSERT INTO user_transaction_limits (user_id, per_transaction, daily, monthly, nightly, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET per_transaction = EXCLUDED.per_transaction,
+			daily = EXCLUDED.daily,
+			monthly = EXCLUDED.monthly,
+			nightly = EXCLUDED.nightly
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		override.UserID,
+		decimalPtrValue(override.PerTransaction),
+		decimalPtrValue(override.Daily),
+		decimalPtrValue(override.Monthly),
+		decimalPtrValue(override.Nightly),
+		override.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao salvar limites do usuário: %w", err)
+	}
+
+	return nil
+}
+
+func (r *limitPostgresRepository) DeleteOverride(ctx context.Context, userID string) error {
+	_, err := r.db.Conn(ctx).ExecContext(ctx, "DELETE FROM user_transaction_limits WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("erro ao remover limites do usuário: %w", err)
+	}
+
+	return nil
+}
+
+func (r *limitPostgresRepository) GetUsage(ctx context.Context, userID string, keys map[entity.LimitPeriod]string) (entity.LimitUsage, error) {
+	usage := entity.LimitUsage{
+		Daily:   decimal.Zero,
+		Monthly: decimal.Zero,
+		Nightly: decimal.Zero,
+	}
+
+	query := `
+		SELECT amount
+		FROM transaction_limit_usage
+		WHERE user_id = $1 AND period = $2 AND period_key = $3
+	`
+
+	for period, key := range keys {
+		var amount decimal.Decimal
+		err := r.db.Conn(ctx).QueryRowContext(ctx, query, userID, period, key).Scan(&amount)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return usage, fmt.Errorf("erro ao buscar uso de limite: %w", err)
+		}
+
+		switch period {
+		case entity.LimitPeriodDaily:
+			usage.Daily = amount
+		case entity.LimitPeriodMonthly:
+			usage.Monthly = amount
+		case entity.LimitPeriodNightly:
+			usage.Nightly = amount
+		}
+	}
+
+	return usage, nil
+}
+
+func (r *limitPostgresRepository) AddUsage(ctx context.Context, userID string, period entity.LimitPeriod, periodKey string, amount decimal.Decimal) error {
+	query := `
+		INSERT INTO transaction_limit_usage (user_id, period, period_key, amount)
+		VALUES ($1, $2, $3, GREATEST($4::DECIMAL, 0))
+		ON CONFLICT (user_id, period, period_key) DO UPDATE
+		SET amount = GREATEST(transaction_limit_usage.amount + $4::DECIMAL, 0),
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query, userID, period, periodKey, amount)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar uso de limite: %w", err)
+	}
+
+	return nil
+}
+
+func nullDecimalPtr(value decimal.NullDecimal) *decimal.Decimal {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Decimal
+}
+
+func decimalPtrValue(value *decimal.Decimal) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+const outboxEventColumns = `id, event_type, aggregate_type, aggregate_id, payload, occurred_at, handled_by, attempts,
+	next_attempt_at, last_error, created_at, dispatched_at, published_at`
+
+// outboxPublishLockKey identifica o advisory lock que garante um único relay publicando no broker
+const outboxPublishLockKey = 4242002
+
+type outboxPostgresRepository struct {
+	db *database.Database
+}
+
+func NewOutboxPostgresRepository(db *database.Database) OutboxRepository {
+	return &outboxPostgresRepository{
+		db: db,
+	}
+}
+
+func scanOutboxEvent(row rowScanner) (*entity.OutboxEvent, error) {
+	event := &entity.OutboxEvent{}
+	var payload, handledBy []byte
+	err := row.Scan(
+		&event.ID,
+		&event.Type,
+		&event.AggregateType,
+		&event.AggregateID,
+		&payload,
+		&event.OccurredAt,
+		&handledBy,
+		&event.Attempts,
+		&event.NextAttemptAt,
+		&event.LastError,
+		&event.CreatedAt,
+		&event.DispatchedAt,
+		&event.PublishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	event.Data = payload
+	if err := json.Unmarshal(handledBy, &event.HandledBy); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar assinantes do evento: %w", err)
+	}
+	return event, nil
+}
+
+func (r *outboxPostgresRepository) Append(ctx context.Context, events []*entity.OutboxEvent) error {
+	query := `
+		INSERT INTO outbox_events (` + outboxEventColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	for _, event := range events {
+		handledBy, err := json.Marshal(event.HandledBy)
+		if err != nil {
+			return fmt.Errorf("erro ao codificar assinantes do evento: %w", err)
+		}
+
+		_, err = r.db.Conn(ctx).ExecContext(ctx, query,
+			event.ID,
+			event.Type,
+			event.AggregateType,
+			event.AggregateID,
+			[]byte(event.Data),
+			event.OccurredAt,
+			handledBy,
+			event.Attempts,
+			event.NextAttemptAt,
+			event.LastError,
+			event.CreatedAt,
+			event.DispatchedAt,
+			event.PublishedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao gravar evento no outbox: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *outboxPostgresRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.OutboxEvent, error) {
+	query := `
+		UPDATE outbox_events
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE dispatched_at IS NULL AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + outboxEventColumns
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar eventos pendentes do outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*entity.OutboxEvent
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do evento do outbox: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *outboxPostgresRepository) Update(ctx context.Context, event *entity.OutboxEvent) error {
+	handledBy, err := json.Marshal(event.HandledBy)
+	if err != nil {
+		return fmt.Errorf("erro ao codificar assinantes do evento: %w", err)
+	}
+
+	query := `
+		UPDATE outbox_events
+		SET handled_by = $2, attempts = $3, next_attempt_at = $4, last_error = $5, dispatched_at = $6
+		WHERE id = $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		event.ID,
+		handledBy,
+		event.Attempts,
+		event.NextAttemptAt,
+		event.LastError,
+		event.DispatchedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar evento do outbox: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrOutboxEventNotFound)
+}
+
+func (r *outboxPostgresRepository) LockPublishing(ctx context.Context) (bool, error) {
+	var locked bool
+	err := r.db.Conn(ctx).QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", outboxPublishLockKey).Scan(&locked)
+	if err != nil {
+		return false, fmt.Errorf("erro ao reservar publicação de eventos: %w", err)
+	}
+	return locked, nil
+}
+
+func (r *outboxPostgresRepository) ListUnpublished(ctx context.Context, limit int) ([]*entity.OutboxEvent, error) {
+	query := `
+		SELECT ` + outboxEventColumns + ` FROM outbox_events
+		WHERE published_at IS NULL
+		ORDER BY created_at, occurred_at
+		LIMIT $1
+	`
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar eventos não publicados: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*entity.OutboxEvent
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do evento do outbox: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *outboxPostgresRepository) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `UPDATE outbox_events SET published_at = $2 WHERE id = $1`, id, publishedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao marcar evento como publicado: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrOutboxEventNotFound)
+}
+
+func (r *outboxPostgresRepository) CountBacklog(ctx context.Context) (entity.OutboxBacklog, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE dispatched_at IS NULL),
+			COUNT(*) FILTER (WHERE published_at IS NULL)
+		FROM outbox_events
+		WHERE dispatched_at IS NULL OR published_at IS NULL
+	`
+
+	var backlog entity.OutboxBacklog
+	err := r.db.Conn(ctx).QueryRowContext(ctx, query).Scan(&backlog.Undispatched, &backlog.Unpublished)
+	if err != nil {
+		return entity.OutboxBacklog{}, fmt.Errorf("erro ao contar eventos pendentes do outbox: %w", err)
+	}
+	return backlog, nil
+}
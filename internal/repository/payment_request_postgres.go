@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+const paymentRequestColumns = "id, txid, merchant_id, payer_id, amount, description, status, expires_at, transaction_id, paid_by, paid_at, created_at, updated_at"
+
+type paymentRequestPostgresRepository struct {
+	db *database.Database
+}
+
+func NewPaymentRequestPostgresRepository(db *database.Database) PaymentRequestRepository {
+	return &paymentRequestPostgresRepository{
+		db: db,
+	}
+}
+
+func scanPaymentRequest(row rowScanner) (*entity.PaymentRequest, error) {
+	request := &entity.PaymentRequest{}
+	err := row.Scan(
+		&request.ID,
+		&request.TxID,
+		&request.MerchantID,
+		&request.PayerID,
+		&request.Amount,
+		&request.Description,
+		&request.Status,
+		&request.ExpiresAt,
+		&request.TransactionID,
+		&request.PaidBy,
+		&request.PaidAt,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+	return request, err
+}
+
+func (r *paymentRequestPostgresRepository) Create(ctx context.Context, request *entity.PaymentRequest) error {
+	query := `
+		INSERT INTO payment_requests (` + paymentRequestColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		request.ID,
+		request.TxID,
+		request.MerchantID,
+		request.PayerID,
+		request.Amount,
+		request.Description,
+		request.Status,
+		request.ExpiresAt,
+		request.TransactionID,
+		request.PaidBy,
+		request.PaidAt,
+		request.CreatedAt,
+		request.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao criar cobrança: %w", err)
+	}
+
+	return nil
+}
+
+func (r *paymentRequestPostgresRepository) GetByID(ctx context.Context, id string) (*entity.PaymentRequest, error) {
+	return r.getOne(ctx, "SELECT "+paymentRequestColumns+" FROM payment_requests WHERE id = $1", id)
+}
+
+func (r *paymentRequestPostgresRepository) GetByTxID(ctx context.Context, txID string) (*entity.PaymentRequest, error) {
+	return r.getOne(ctx, "SELECT "+paymentRequestColumns+" FROM payment_requests WHERE txid = $1", txID)
+}
+
+func (r *paymentRequestPostgresRepository) GetByIDForUpdate(ctx context.Context, id string) (*entity.PaymentRequest, error) {
+	return r.getOne(ctx, "SELECT "+paymentRequestColumns+" FROM payment_requests WHERE id = $1 FOR UPDATE", id)
+}
+
+func (r *paymentRequestPostgresRepository) GetByTransactionIDForUpdate(ctx context.Context, transactionID string) (*entity.PaymentRequest, error) {
+	return r.getOne(ctx, "SELECT "+paymentRequestColumns+" FROM payment_requests WHERE transaction_id = $1 FOR UPDATE", transactionID)
+}
+
+func (r *paymentRequestPostgresRepository) getOne(ctx context.Context, query string, arg string) (*entity.PaymentRequest, error) {
+	request, err := scanPaymentRequest(r.db.Conn(ctx).QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrPaymentRequestNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar cobrança: %w", err)
+	}
+
+	return request, nil
+}
+
+func (r *paymentRequestPostgresRepository) Update(ctx context.Context, request *entity.PaymentRequest) error {
+	query := `
+		UPDATE payment_requests
+		SET status = $2, transaction_id = $3, paid_by = $4, paid_at = $5, updated_at = $6
+		WHERE id = $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		request.ID,
+		request.Status,
+		request.TransactionID,
+		request.PaidBy,
+		request.PaidAt,
+		time.Now(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar cobrança: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrPaymentRequestNotFound)
+}
+
+func (r *paymentRequestPostgresRepository) List(ctx context.Context, filters *entity.PaymentRequestFilters) ([]*entity.PaymentRequest, int, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	argCount := 0
+
+	if filters.MerchantID != "" {
+		argCount++
+		where += fmt.Sprintf(" AND merchant_id = $%d", argCount)
+		args = append(args, filters.MerchantID)
+	}
+
+	if filters.Status != "" {
+		argCount++
+		where += fmt.Sprintf(" AND status = $%d", argCount)
+		args = append(args, filters.Status)
+	}
+
+	var total int
+	err := r.db.Conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM payment_requests"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar cobranças: %w", err)
+	}
+
+	query := "SELECT " + paymentRequestColumns + " FROM payment_requests" + where + " ORDER BY created_at DESC"
+	argCount++
+	query += fmt.Sprintf(" LIMIT $%d", argCount)
+	args = append(args, filters.Limit)
+
+	argCount++
+	query += fmt.Sprintf(" OFFSET $%d", argCount)
+	args = append(args, filters.Offset())
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar cobranças: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []*entity.PaymentRequest
+	for rows.Next() {
+		request, err := scanPaymentRequest(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("erro ao fazer scan da cobrança: %w", err)
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, total, rows.Err()
+}
+
+func (r *paymentRequestPostgresRepository) ExpireOverdue(ctx context.Context, now time.Time) (int, error) {
+	// Cobranças com pagamento em andamento só expiram depois que a tentativa falhar
+	query := `
+		UPDATE payment_requests p
+		SET status = 'expired', updated_at = $1
+		WHERE p.status = 'open' AND p.expires_at <= $1
+		  AND NOT EXISTS (
+		      SELECT 1 FROM transactions t
+		      WHERE t.id = p.transaction_id AND t.status NOT IN ('failed', 'cancelled')
+		  )
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao expirar cobranças: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+
+	"github.com/lib/pq"
+)
+
+const pixKeyColumns = "id, user_id, key_type, key_value, created_at"
+
+// uniqueViolation é o código do PostgreSQL para violação de restrição UNIQUE
+const uniqueViolation = "23505"
+
+type pixKeyPostgresRepository struct {
+	db *database.Database
+}
+
+func NewPixKeyPostgresRepository(db *database.Database) PixKeyRepository {
+	return &pixKeyPostgresRepository{
+		db: db,
+	}
+}
+
+func scanPixKey(row rowScanner) (*entity.PixKey, error) {
+	key := &entity.PixKey{}
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.KeyType,
+		&key.KeyValue,
+		&key.CreatedAt,
+	)
+	return key, err
+}
+
+func (r *pixKeyPostgresRepository) Create(ctx context.Context, key *entity.PixKey) error {
+	query := `
+		INSERT INTO pix_keys (` + pixKeyColumns + `)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		key.ID,
+		key.UserID,
+		key.KeyType,
+		key.KeyValue,
+		key.CreatedAt,
+	)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return entity.ErrPixKeyAlreadyExists
+		}
+		return fmt.Errorf("erro ao criar chave Pix: %w", err)
+	}
+
+	return nil
+}
+
+func (r *pixKeyPostgresRepository) GetByValue(ctx context.Context, value string) (*entity.PixKey, error) {
+	query := "SELECT " + pixKeyColumns + " FROM pix_keys WHERE key_value = $1"
+
+	key, err := scanPixKey(r.db.Conn(ctx).QueryRowContext(ctx, query, value))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrPixKeyNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar chave Pix: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *pixKeyPostgresRepository) ListByUser(ctx context.Context, userID string) ([]*entity.PixKey, error) {
+	query := "SELECT " + pixKeyColumns + " FROM pix_keys WHERE user_id = $1 ORDER BY created_at"
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar chaves Pix: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*entity.PixKey
+	for rows.Next() {
+		key, err := scanPixKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan da chave Pix: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *pixKeyPostgresRepository) CountByUser(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.Conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM pix_keys WHERE user_id = $1", userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao contar chaves Pix: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *pixKeyPostgresRepository) Delete(ctx context.Context, userID, id string) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, "DELETE FROM pix_keys WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("erro ao remover chave Pix: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrPixKeyNotFound)
+}
+
+func (r *pixKeyPostgresRepository) DeleteByUser(ctx context.Context, userID string) error {
+	if _, err := r.db.Conn(ctx).ExecContext(ctx, "DELETE FROM pix_keys WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("erro ao remover chaves Pix do usuário: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+
+	"github.com/shopspring/decimal"
+)
+
+const pricingPlanColumns = "p.id, p.name, p.fee_type, p.fixed_amount, p.percentage, p.tiers, p.min_fee, p.max_fee, p.created_at, p.updated_at"
+
+type pricingPostgresRepository struct {
+	db *database.Database
+}
+
+func NewPricingPostgresRepository(db *database.Database) PricingRepository {
+	return &pricingPostgresRepository{
+		db: db,
+	}
+}
+
+func scanPricingPlan(row rowScanner) (*entity.PricingPlan, error) {
+	plan := &entity.PricingPlan{}
+	var tiers []byte
+	var minFee, maxFee decimal.NullDecimal
+
+	err := row.Scan(
+		&plan.ID,
+		&plan.Name,
+		&plan.FeeType,
+		&plan.FixedAmount,
+		&plan.Percentage,
+		&tiers,
+		&minFee,
+		&maxFee,
+		&plan.CreatedAt,
+		&plan.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tiers) > 0 {
+		if err := json.Unmarshal(tiers, &plan.Tiers); err != nil {
+			return nil, fmt.Errorf("erro ao ler faixas do plano: %w", err)
+		}
+	}
+	plan.MinFee = nullDecimalPtr(minFee)
+	plan.MaxFee = nullDecimalPtr(maxFee)
+
+	return plan, nil
+}
+
+func (r *pricingPostgresRepository) CreatePlan(ctx context.Context, plan *entity.PricingPlan) error {
+	query := `
+		INSERT INTO pricing_plans (id, name, fee_type, fixed_amount, percentage, tiers, min_fee, max_fee, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	var tiers interface{}
+	if len(plan.Tiers) > 0 {
+		encoded, err := json.Marshal(plan.Tiers)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar faixas do plano: %w", err)
+		}
+		tiers = encoded
+	}
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		plan.ID,
+		plan.Name,
+		plan.FeeType,
+		plan.FixedAmount,
+		plan.Percentage,
+		tiers,
+		decimalPtrValue(plan.MinFee),
+		decimalPtrValue(plan.MaxFee),
+		plan.CreatedAt,
+		plan.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao criar plano de tarifas: %w", err)
+	}
+
+	return nil
+}
+
+func (r *pricingPostgresRepository) GetPlan(ctx context.Context, id string) (*entity.PricingPlan, error) {
+	query := "SELECT " + pricingPlanColumns + " FROM pricing_plans p WHERE p.id = $1"
+
+	plan, err := scanPricingPlan(r.db.Conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrPricingPlanNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar plano de tarifas: %w", err)
+	}
+
+	return plan, nil
+}
+
+func (r *pricingPostgresRepository) ListPlans(ctx context.Context) ([]*entity.PricingPlan, error) {
+	query := "SELECT " + pricingPlanColumns + " FROM pricing_plans p ORDER BY p.name"
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar planos de tarifas: %w", err)
+	}
+	defer rows.Close()
+
+	var plans []*entity.PricingPlan
+	for rows.Next() {
+		plan, err := scanPricingPlan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do plano de tarifas: %w", err)
+		}
+		plans = append(plans, plan)
+	}
+
+	return plans, rows.Err()
+}
+
+func (r *pricingPostgresRepository) GetPlanForUser(ctx context.Context, user *entity.User) (*entity.PricingPlan, error) {
+	// O plano específico do usuário tem prioridade sobre o padrão do tipo
+	query := "SELECT " + pricingPlanColumns + ` FROM pricing_plans p
+		LEFT JOIN user_pricing_plans u ON u.pricing_plan_id = p.id AND u.user_id = $1
+		LEFT JOIN default_pricing_plans d ON d.pricing_plan_id = p.id AND d.user_type = $2
+		WHERE u.user_id IS NOT NULL OR d.user_type IS NOT NULL
+		ORDER BY (u.user_id IS NOT NULL) DESC
+		LIMIT 1`
+
+	plan, err := scanPricingPlan(r.db.Conn(ctx).QueryRowContext(ctx, query, user.ID, user.UserType))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar plano do usuário: %w", err)
+	}
+
+	return plan, nil
+}
+
+func (r *pricingPostgresRepository) AssignPlan(ctx context.Context, userID, planID string) error {
+	query := `
+		INSERT INTO user_pricing_plans (user_id, pricing_plan_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET pricing_plan_id = EXCLUDED.pricing_plan_id
+	`
+
+	if _, err := r.db.Conn(ctx).ExecContext(ctx, query, userID, planID); err != nil {
+		return fmt.Errorf("erro ao atribuir plano de tarifas: %w", err)
+	}
+
+	return nil
+}
+
+func (r *pricingPostgresRepository) PostFee(ctx context.Context, entry *entity.FeeEntry) error {
+	insert := `
+		INSERT INTO fee_entries (id, transaction_id, split_payment_id, pricing_plan_id, account_code, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, insert,
+		entry.ID,
+		entry.TransactionID,
+		entry.SplitPaymentID,
+		entry.PricingPlanID,
+		entry.AccountCode,
+		entry.Amount,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao lançar tarifa: %w", err)
+	}
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx,
+		"UPDATE platform_accounts SET balance = balance + $2 WHERE code = $1",
+		entry.AccountCode,
+		entry.Amount,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao creditar conta da plataforma: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrPlatformAccountNotFound)
+}
+
+func (r *pricingPostgresRepository) GetAccount(ctx context.Context, code string) (*entity.PlatformAccount, error) {
+	query := "SELECT code, balance, updated_at FROM platform_accounts WHERE code = $1"
+
+	account := &entity.PlatformAccount{}
+	err := r.db.Conn(ctx).QueryRowContext(ctx, query, code).Scan(
+		&account.Code,
+		&account.Balance,
+		&account.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrPlatformAccountNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar conta da plataforma: %w", err)
+	}
+
+	return account, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+const receiptColumns = "transaction_id, authentication_code, payload, signature, key_id, issued_at"
+
+type receiptPostgresRepository struct {
+	db *database.Database
+}
+
+func NewReceiptPostgresRepository(db *database.Database) ReceiptRepository {
+	return &receiptPostgresRepository{
+		db: db,
+	}
+}
+
+func scanReceipt(row rowScanner) (*entity.Receipt, error) {
+	receipt := &entity.Receipt{}
+	err := row.Scan(
+		&receipt.TransactionID,
+		&receipt.AuthenticationCode,
+		&receipt.Payload,
+		&receipt.Signature,
+		&receipt.KeyID,
+		&receipt.IssuedAt,
+	)
+	return receipt, err
+}
+
+func (r *receiptPostgresRepository) Create(ctx context.Context, receipt *entity.Receipt) (*entity.Receipt, error) {
+	// Em pedidos simultâneos só o primeiro comprovante é gravado; os demais recebem o já emitido
+	query := `
+		INSERT INTO transaction_receipts (` + receiptColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (transaction_id) DO NOTHING
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		receipt.TransactionID,
+		receipt.AuthenticationCode,
+		receipt.Payload,
+		receipt.Signature,
+		receipt.KeyID,
+		receipt.IssuedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gravar comprovante: %w", err)
+	}
+
+	return r.GetByTransactionID(ctx, receipt.TransactionID)
+}
+
+func (r *receiptPostgresRepository) GetByTransactionID(ctx context.Context, transactionID string) (*entity.Receipt, error) {
+	query := "SELECT " + receiptColumns + " FROM transaction_receipts WHERE transaction_id = $1"
+	return r.getOne(ctx, query, transactionID)
+}
+
+func (r *receiptPostgresRepository) GetByAuthenticationCode(ctx context.Context, code string) (*entity.Receipt, error) {
+	query := "SELECT " + receiptColumns + " FROM transaction_receipts WHERE authentication_code = $1"
+	return r.getOne(ctx, query, code)
+}
+
+func (r *receiptPostgresRepository) getOne(ctx context.Context, query string, arg string) (*entity.Receipt, error) {
+	receipt, err := scanReceipt(r.db.Conn(ctx).QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrReceiptNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar comprovante: %w", err)
+	}
+
+	return receipt, nil
+}
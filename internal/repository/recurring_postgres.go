@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+const recurringMandateColumns = "id, payer_id, payee_id, amount, description, frequency, cron_expression, start_date, end_date, max_occurrences, occurrences, next_run_at, last_run_at, retry_at, status, created_at, updated_at"
+
+type recurringPostgresRepository struct {
+	db *database.Database
+}
+
+func NewRecurringPostgresRepository(db *database.Database) RecurringRepository {
+	return &recurringPostgresRepository{
+		db: db,
+	}
+}
+
+func scanRecurringMandate(row rowScanner) (*entity.RecurringMandate, error) {
+	mandate := &entity.RecurringMandate{}
+	var maxOccurrences sql.NullInt64
+
+	err := row.Scan(
+		&mandate.ID,
+		&mandate.PayerID,
+		&mandate.PayeeID,
+		&mandate.Amount,
+		&mandate.Description,
+		&mandate.Frequency,
+		&mandate.CronExpression,
+		&mandate.StartDate,
+		&mandate.EndDate,
+		&maxOccurrences,
+		&mandate.Occurrences,
+		&mandate.NextRunAt,
+		&mandate.LastRunAt,
+		&mandate.RetryAt,
+		&mandate.Status,
+		&mandate.CreatedAt,
+		&mandate.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxOccurrences.Valid {
+		value := int(maxOccurrences.Int64)
+		mandate.MaxOccurrences = &value
+	}
+
+	return mandate, nil
+}
+
+func (r *recurringPostgresRepository) Create(ctx context.Context, mandate *entity.RecurringMandate) error {
+	query := `
+		INSERT INTO recurring_mandates (` + recurringMandateColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		mandate.ID,
+		mandate.PayerID,
+		mandate.PayeeID,
+		mandate.Amount,
+		mandate.Description,
+		mandate.Frequency,
+		mandate.CronExpression,
+		mandate.StartDate,
+		mandate.EndDate,
+		mandate.MaxOccurrences,
+		mandate.Occurrences,
+		mandate.NextRunAt,
+		mandate.LastRunAt,
+		mandate.RetryAt,
+		mandate.Status,
+		mandate.CreatedAt,
+		mandate.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao criar pagamento recorrente: %w", err)
+	}
+
+	return nil
+}
+
+func (r *recurringPostgresRepository) GetByID(ctx context.Context, id string) (*entity.RecurringMandate, error) {
+	return r.getOne(ctx, "SELECT "+recurringMandateColumns+" FROM recurring_mandates WHERE id = $1", id)
+}
+
+func (r *recurringPostgresRepository) GetByIDForUpdate(ctx context.Context, id string) (*entity.RecurringMandate, error) {
+	return r.getOne(ctx, "SELECT "+recurringMandateColumns+" FROM recurring_mandates WHERE id = $1 FOR UPDATE", id)
+}
+
+func (r *recurringPostgresRepository) getOne(ctx context.Context, query string, id string) (*entity.RecurringMandate, error) {
+	mandate, err := scanRecurringMandate(r.db.Conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrMandateNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar pagamento recorrente: %w", err)
+	}
+
+	return mandate, nil
+}
+
+func (r *recurringPostgresRepository) Update(ctx context.Context, mandate *entity.RecurringMandate) error {
+	query := `
+		UPDATE recurring_mandates
+		SET occurrences = $2, next_run_at = $3, last_run_at = $4, retry_at = $5, status = $6, updated_at = $7
+		WHERE id = $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		mandate.ID,
+		mandate.Occurrences,
+		mandate.NextRunAt,
+		mandate.LastRunAt,
+		mandate.RetryAt,
+		mandate.Status,
+		time.Now(),
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar pagamento recorrente: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrMandateNotFound)
+}
+
+func (r *recurringPostgresRepository) ListByPayer(ctx context.Context, payerID string) ([]*entity.RecurringMandate, error) {
+	query := "SELECT " + recurringMandateColumns + " FROM recurring_mandates WHERE payer_id = $1 ORDER BY created_at DESC"
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, payerID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar pagamentos recorrentes: %w", err)
+	}
+	defer rows.Close()
+
+	var mandates []*entity.RecurringMandate
+	for rows.Next() {
+		mandate, err := scanRecurringMandate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do pagamento recorrente: %w", err)
+		}
+		mandates = append(mandates, mandate)
+	}
+
+	return mandates, rows.Err()
+}
+
+func (r *recurringPostgresRepository) LockNextDue(ctx context.Context, now time.Time) (*entity.RecurringMandate, error) {
+	query := "SELECT " + recurringMandateColumns + ` FROM recurring_mandates
+		WHERE status = 'active' AND next_run_at <= $1 AND (retry_at IS NULL OR retry_at <= $1)
+		ORDER BY next_run_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`
+
+	mandate, err := scanRecurringMandate(r.db.Conn(ctx).QueryRowContext(ctx, query, now))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar pagamentos recorrentes vencidos: %w", err)
+	}
+
+	return mandate, nil
+}
+
+func (r *recurringPostgresRepository) RunExists(ctx context.Context, mandateID, periodKey string) (bool, error) {
+	query := "SELECT EXISTS(SELECT 1 FROM recurring_mandate_runs WHERE mandate_id = $1 AND period_key = $2)"
+
+	var exists bool
+	if err := r.db.Conn(ctx).QueryRowContext(ctx, query, mandateID, periodKey).Scan(&exists); err != nil {
+		return false, fmt.Errorf("erro ao verificar execução do pagamento recorrente: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *recurringPostgresRepository) CreateRun(ctx context.Context, run *entity.MandateRun) error {
+	query := `
+		INSERT INTO recurring_mandate_runs (id, mandate_id, period_key, occurrence_at, transaction_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (mandate_id, period_key) DO NOTHING
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		run.ID,
+		run.MandateID,
+		run.PeriodKey,
+		run.OccurrenceAt,
+		run.TransactionID,
+		run.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar execução do pagamento recorrente: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrMandatePeriodAlreadyRun)
+}
+
+func (r *recurringPostgresRepository) ListRuns(ctx context.Context, mandateID string) ([]entity.MandateRun, error) {
+	query := `
+		SELECT r.id, r.mandate_id, r.period_key, r.occurrence_at, r.transaction_id, r.created_at, t.status, t.failure_reason
+		FROM recurring_mandate_runs r
+		JOIN transactions t ON t.id = r.transaction_id
+		WHERE r.mandate_id = $1
+		ORDER BY r.occurrence_at DESC
+	`
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, mandateID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar execuções do pagamento recorrente: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []entity.MandateRun{}
+	for rows.Next() {
+		var run entity.MandateRun
+		err := rows.Scan(
+			&run.ID,
+			&run.MandateID,
+			&run.PeriodKey,
+			&run.OccurrenceAt,
+			&run.TransactionID,
+			&run.CreatedAt,
+			&run.TransactionStatus,
+			&run.FailureReason,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan da execução: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
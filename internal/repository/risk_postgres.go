@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+const (
+	riskReviewColumns      = "id, transaction_id, payer_id, payee_id, amount, decision, reasons, status, due_at, claimed_by, claimed_at, decided_by, decided_at, decision_note, created_at, updated_at"
+	riskReviewEventColumns = "id, review_id, action, actor, note, created_at"
+)
+
+type riskPostgresRepository struct {
+	db *database.Database
+}
+
+func NewRiskPostgresRepository(db *database.Database) RiskRepository {
+	return &riskPostgresRepository{
+		db: db,
+	}
+}
+
+func scanRiskReview(row rowScanner) (*entity.RiskReview, error) {
+	review := &entity.RiskReview{}
+	var reasons []byte
+	err := row.Scan(
+		&review.ID,
+		&review.TransactionID,
+		&review.PayerID,
+		&review.PayeeID,
+		&review.Amount,
+		&review.Decision,
+		&reasons,
+		&review.Status,
+		&review.DueAt,
+		&review.ClaimedBy,
+		&review.ClaimedAt,
+		&review.DecidedBy,
+		&review.DecidedAt,
+		&review.DecisionNote,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(reasons, &review.Reasons); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar motivos de risco: %w", err)
+	}
+
+	return review, nil
+}
+
+func (r *riskPostgresRepository) GetProfile(ctx context.Context, transaction *entity.Transaction, since time.Time) (*entity.RiskProfile, error) {
+	query := `
+		SELECT
+			u.created_at,
+			(SELECT COUNT(*) FROM transactions
+				WHERE payer_id = u.id AND id <> $3 AND created_at >= $4
+				AND status NOT IN ('scheduled', 'cancelled')),
+			EXISTS (SELECT 1 FROM transactions
+				WHERE payer_id = u.id AND payee_id = $2 AND status = 'completed'),
+			history.completed_count,
+			history.average_amount
+		FROM users u
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*) AS completed_count, COALESCE(AVG(amount), 0) AS average_amount
+			FROM transactions
+			WHERE payer_id = u.id AND status = 'completed'
+		) history
+		WHERE u.id = $1
+	`
+
+	profile := &entity.RiskProfile{}
+	err := r.db.Conn(ctx).QueryRowContext(ctx, query,
+		transaction.PayerID,
+		transaction.PayeeID,
+		transaction.ID,
+		since,
+	).Scan(
+		&profile.AccountCreatedAt,
+		&profile.RecentTransfers,
+		&profile.KnownPayee,
+		&profile.CompletedCount,
+		&profile.AverageAmount,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("erro ao consultar histórico de risco: %w", err)
+	}
+
+	return profile, nil
+}
+
+func (r *riskPostgresRepository) CreateReview(ctx context.Context, review *entity.RiskReview) error {
+	return r.db.WithinTx(ctx, func(ctx context.Context) error {
+		reasons, err := json.Marshal(review.Reasons)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar motivos de risco: %w", err)
+		}
+
+		query := `
+			INSERT INTO risk_reviews (` + riskReviewColumns + `)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		`
+
+		_, err = r.db.Conn(ctx).ExecContext(ctx, query,
+			review.ID,
+			review.TransactionID,
+			review.PayerID,
+			review.PayeeID,
+			review.Amount,
+			review.Decision,
+			reasons,
+			review.Status,
+			review.DueAt,
+			review.ClaimedBy,
+			review.ClaimedAt,
+			review.DecidedBy,
+			review.DecidedAt,
+			review.DecisionNote,
+			review.CreatedAt,
+			review.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao registrar análise de risco: %w", err)
+		}
+
+		for _, event := range review.Events {
+			if err := r.AddReviewEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *riskPostgresRepository) GetReview(ctx context.Context, id string) (*entity.RiskReview, error) {
+	return r.getReview(ctx, "SELECT "+riskReviewColumns+" FROM risk_reviews WHERE id = $1", id)
+}
+
+func (r *riskPostgresRepository) GetReviewForUpdate(ctx context.Context, id string) (*entity.RiskReview, error) {
+	return r.getReview(ctx, "SELECT "+riskReviewColumns+" FROM risk_reviews WHERE id = $1 FOR UPDATE", id)
+}
+
+func (r *riskPostgresRepository) getReview(ctx context.Context, query string, id string) (*entity.RiskReview, error) {
+	review, err := scanRiskReview(r.db.Conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrRiskReviewNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar item da fila de análise: %w", err)
+	}
+
+	review.Events, err = r.listReviewEvents(ctx, review.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (r *riskPostgresRepository) UpdateReview(ctx context.Context, review *entity.RiskReview) error {
+	query := `
+		UPDATE risk_reviews
+		SET status = $2, claimed_by = $3, claimed_at = $4, decided_by = $5, decided_at = $6,
+			decision_note = $7, updated_at = $8
+		WHERE id = $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		review.ID,
+		review.Status,
+		review.ClaimedBy,
+		review.ClaimedAt,
+		review.DecidedBy,
+		review.DecidedAt,
+		review.DecisionNote,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar item da fila de análise: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrRiskReviewNotFound)
+}
+
+func (r *riskPostgresRepository) AddReviewEvent(ctx context.Context, event *entity.RiskReviewEvent) error {
+	query := `
+		INSERT INTO risk_review_events (` + riskReviewEventColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		event.ID,
+		event.ReviewID,
+		event.Action,
+		event.Actor,
+		event.Note,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar histórico da análise: %w", err)
+	}
+
+	return nil
+}
+
+func (r *riskPostgresRepository) ListReviews(ctx context.Context, filters *entity.RiskReviewFilters) ([]*entity.RiskReview, int, error) {
+	where := " WHERE status IN ('pending', 'claimed')"
+	args := []interface{}{}
+	argCount := 0
+
+	if filters.Status != "" {
+		argCount++
+		where = fmt.Sprintf(" WHERE status = $%d", argCount)
+		args = append(args, filters.Status)
+	}
+
+	var total int
+	err := r.db.Conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM risk_reviews"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar itens da fila de análise: %w", err)
+	}
+
+	// Prazo mais próximo primeiro; negadas automaticamente não têm prazo
+	query := "SELECT " + riskReviewColumns + " FROM risk_reviews" + where + " ORDER BY due_at NULLS LAST, created_at DESC"
+	argCount++
+	query += fmt.Sprintf(" LIMIT $%d", argCount)
+	args = append(args, filters.Limit)
+
+	argCount++
+	query += fmt.Sprintf(" OFFSET $%d", argCount)
+	args = append(args, filters.Offset())
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar fila de análise: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []*entity.RiskReview
+	for rows.Next() {
+		review, err := scanRiskReview(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("erro ao fazer scan do item da fila de análise: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, total, rows.Err()
+}
+
+func (r *riskPostgresRepository) ListOverdueReviews(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	query := "SELECT id FROM risk_reviews WHERE status IN ('pending', 'claimed') AND due_at <= $1 ORDER BY due_at LIMIT $2"
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar itens vencidos da fila de análise: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do item da fila de análise: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *riskPostgresRepository) listReviewEvents(ctx context.Context, reviewID string) ([]*entity.RiskReviewEvent, error) {
+	query := "SELECT " + riskReviewEventColumns + " FROM risk_review_events WHERE review_id = $1 ORDER BY created_at"
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar histórico da análise: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*entity.RiskReviewEvent
+	for rows.Next() {
+		event := &entity.RiskReviewEvent{}
+		err := rows.Scan(
+			&event.ID,
+			&event.ReviewID,
+			&event.Action,
+			&event.Actor,
+			&event.Note,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do histórico da análise: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
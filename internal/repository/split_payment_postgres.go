@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+const (
+	splitPaymentColumns = "id, payer_id, amount, description, status, refunded_amount, failure_reason, created_at, updated_at, completed_at"
+	splitLegColumns     = "id, split_payment_id, position, payee_id, payee_key, split_type, value, amount, refunded_amount, transaction_id"
+	splitRefundColumns  = "id, split_payment_id, amount, reason, legs, created_at"
+)
+
+type splitPaymentPostgresRepository struct {
+	db *database.Database
+}
+
+func NewSplitPaymentPostgresRepository(db *database.Database) SplitPaymentRepository {
+	return &splitPaymentPostgresRepository{
+		db: db,
+	}
+}
+
+func scanSplitPayment(row rowScanner) (*entity.SplitPayment, error) {
+	split := &entity.SplitPayment{}
+	err := row.Scan(
+		&split.ID,
+		&split.PayerID,
+		&split.Amount,
+		&split.Description,
+		&split.Status,
+		&split.RefundedAmount,
+		&split.FailureReason,
+		&split.CreatedAt,
+		&split.UpdatedAt,
+		&split.CompletedAt,
+	)
+	return split, err
+}
+
+func (r *splitPaymentPostgresRepository) Create(ctx context.Context, split *entity.SplitPayment) error {
+	return r.db.WithinTx(ctx, func(ctx context.Context) error {
+		query := `
+			INSERT INTO split_payments (` + splitPaymentColumns + `)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`
+
+		_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+			split.ID,
+			split.PayerID,
+			split.Amount,
+			split.Description,
+			split.Status,
+			split.RefundedAmount,
+			split.FailureReason,
+			split.CreatedAt,
+			split.UpdatedAt,
+			split.CompletedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao criar pagamento dividido: %w", err)
+		}
+
+		legQuery := `
+			INSERT INTO split_payment_legs (` + splitLegColumns + `)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`
+
+		for _, leg := range split.Legs {
+			_, err := r.db.Conn(ctx).ExecContext(ctx, legQuery,
+				leg.ID,
+				leg.SplitPaymentID,
+				leg.Position,
+				leg.PayeeID,
+				leg.PayeeKey,
+				leg.SplitType,
+				leg.Value,
+				leg.Amount,
+				leg.RefundedAmount,
+				leg.TransactionID,
+			)
+			if err != nil {
+				return fmt.Errorf("erro ao criar parte do pagamento dividido: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *splitPaymentPostgresRepository) GetByID(ctx context.Context, id string) (*entity.SplitPayment, error) {
+	return r.getOne(ctx, "SELECT "+splitPaymentColumns+" FROM split_payments WHERE id = $1", id)
+}
+
+func (r *splitPaymentPostgresRepository) GetByIDForUpdate(ctx context.Context, id string) (*entity.SplitPayment, error) {
+	return r.getOne(ctx, "SELECT "+splitPaymentColumns+" FROM split_payments WHERE id = $1 FOR UPDATE", id)
+}
+
+func (r *splitPaymentPostgresRepository) getOne(ctx context.Context, query string, id string) (*entity.SplitPayment, error) {
+	split, err := scanSplitPayment(r.db.Conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrSplitPaymentNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar pagamento dividido: %w", err)
+	}
+
+	if split.Legs, err = r.listLegs(ctx, split.ID); err != nil {
+		return nil, err
+	}
+	if split.Refunds, err = r.listRefunds(ctx, split.ID); err != nil {
+		return nil, err
+	}
+
+	return split, nil
+}
+
+func (r *splitPaymentPostgresRepository) Update(ctx context.Context, split *entity.SplitPayment) error {
+	return r.db.WithinTx(ctx, func(ctx context.Context) error {
+		query := `
+			UPDATE split_payments
+			SET status = $2, refunded_amount = $3, failure_reason = $4, updated_at = $5, completed_at = $6
+			WHERE id = $1
+		`
+
+		result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+			split.ID,
+			split.Status,
+			split.RefundedAmount,
+			split.FailureReason,
+			time.Now(),
+			split.CompletedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao atualizar pagamento dividido: %w", err)
+		}
+		if err := checkRowsAffected(result, entity.ErrSplitPaymentNotFound); err != nil {
+			return err
+		}
+
+		legQuery := "UPDATE split_payment_legs SET refunded_amount = $2, transaction_id = $3 WHERE id = $1"
+		for _, leg := range split.Legs {
+			if _, err := r.db.Conn(ctx).ExecContext(ctx, legQuery, leg.ID, leg.RefundedAmount, leg.TransactionID); err != nil {
+				return fmt.Errorf("erro ao atualizar parte do pagamento dividido: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *splitPaymentPostgresRepository) CreateRefund(ctx context.Context, refund *entity.SplitRefund) error {
+	legs, err := json.Marshal(refund.Legs)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar partes do estorno: %w", err)
+	}
+
+	query := `
+		INSERT INTO split_payment_refunds (` + splitRefundColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err = r.db.Conn(ctx).ExecContext(ctx, query,
+		refund.ID,
+		refund.SplitPaymentID,
+		refund.Amount,
+		refund.Reason,
+		legs,
+		refund.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar estorno: %w", err)
+	}
+
+	return nil
+}
+
+func (r *splitPaymentPostgresRepository) HasRefundedLeg(ctx context.Context, transactionID string) (bool, error) {
+	query := "SELECT EXISTS (SELECT 1 FROM split_payment_legs WHERE transaction_id = $1 AND refunded_amount > 0)"
+
+	var refunded bool
+	if err := r.db.Conn(ctx).QueryRowContext(ctx, query, transactionID).Scan(&refunded); err != nil {
+		return false, fmt.Errorf("erro ao verificar estornos da parte do pagamento dividido: %w", err)
+	}
+
+	return refunded, nil
+}
+
+func (r *splitPaymentPostgresRepository) listLegs(ctx context.Context, splitID string) ([]*entity.SplitLeg, error) {
+	query := "SELECT " + splitLegColumns + " FROM split_payment_legs WHERE split_payment_id = $1 ORDER BY position"
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, splitID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar partes do pagamento dividido: %w", err)
+	}
+	defer rows.Close()
+
+	var legs []*entity.SplitLeg
+	for rows.Next() {
+		leg := &entity.SplitLeg{}
+		err := rows.Scan(
+			&leg.ID,
+			&leg.SplitPaymentID,
+			&leg.Position,
+			&leg.PayeeID,
+			&leg.PayeeKey,
+			&leg.SplitType,
+			&leg.Value,
+			&leg.Amount,
+			&leg.RefundedAmount,
+			&leg.TransactionID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan da parte do pagamento dividido: %w", err)
+		}
+		legs = append(legs, leg)
+	}
+
+	return legs, rows.Err()
+}
+
+func (r *splitPaymentPostgresRepository) listRefunds(ctx context.Context, splitID string) ([]*entity.SplitRefund, error) {
+	query := "SELECT " + splitRefundColumns + " FROM split_payment_refunds WHERE split_payment_id = $1 ORDER BY created_at"
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, splitID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar estornos do pagamento dividido: %w", err)
+	}
+	defer rows.Close()
+
+	var refunds []*entity.SplitRefund
+	for rows.Next() {
+		refund := &entity.SplitRefund{}
+		var legs []byte
+		err := rows.Scan(
+			&refund.ID,
+			&refund.SplitPaymentID,
+			&refund.Amount,
+			&refund.Reason,
+			&legs,
+			&refund.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do estorno: %w", err)
+		}
+		if err := json.Unmarshal(legs, &refund.Legs); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar partes do estorno: %w", err)
+		}
+		refunds = append(refunds, refund)
+	}
+
+	return refunds, rows.Err()
+}
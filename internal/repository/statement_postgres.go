@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+
+	"github.com/shopspring/decimal"
+)
+
+// statementMovementsQuery reúne tudo que altera o saldo do usuário $1, com valor positivo para créditos:
+// transferências concluídas (o recebedor recebe o líquido da tarifa), estornos de transferências,
+// a parte da plataforma nos pagamentos divididos e os estornos das partes dos recebedores (o
+// recebedor devolve o líquido da tarifa estornada junto).
+// Partes de pagamentos divididos revertidas pelo estorno do pagamento não entram como estorno de
+// transferência: os saldos dessas partes são movimentados pelas linhas de split_payment_refunds.
+// Uma parte revertida por disputa não tem estorno e entra como qualquer transferência revertida.
+const statementMovementsQuery = `
+	SELECT t.id::text || ':sent' AS id, 'transfer_sent' AS kind, t.id::text AS reference_id, t.payee_id::text AS counterparty_id,
+	       t.completed_at AS occurred_at, -t.amount AS amount
+	FROM transactions t
+	WHERE t.payer_id = $1 AND t.completed_at IS NOT NULL AND t.status IN ('completed', 'reversed')
+	UNION ALL
+	SELECT t.id::text || ':received', 'transfer_received', t.id::text, t.payer_id::text,
+	       t.completed_at, t.amount - t.fee_amount
+	FROM transactions t
+	WHERE t.payee_id = $1 AND t.completed_at IS NOT NULL AND t.status IN ('completed', 'reversed')
+	UNION ALL
+	SELECT t.id::text || ':reversed', 'transfer_reversed', t.id::text, t.payee_id::text,
+	       t.reversed_at, t.amount
+	FROM transactions t
+	WHERE t.payer_id = $1 AND t.status = 'reversed' AND t.reversed_at IS NOT NULL
+	  AND NOT EXISTS (SELECT 1 FROM split_payment_legs l WHERE l.transaction_id = t.id AND l.refunded_amount > 0)
+	UNION ALL
+	SELECT t.id::text || ':returned', 'transfer_returned', t.id::text, t.payer_id::text,
+	       t.reversed_at, -(t.amount - t.fee_amount)
+	FROM transactions t
+	WHERE t.payee_id = $1 AND t.status = 'reversed' AND t.reversed_at IS NOT NULL
+	  AND NOT EXISTS (SELECT 1 FROM split_payment_legs l WHERE l.transaction_id = t.id AND l.refunded_amount > 0)
+	UNION ALL
+	SELECT f.id::text, CASE WHEN f.amount > 0 THEN 'split_platform_share' ELSE 'split_platform_refund' END, s.id::text, NULL,
+	       f.created_at, -f.amount
+	FROM fee_entries f
+	JOIN split_payments s ON s.id = f.split_payment_id
+	WHERE s.payer_id = $1
+	UNION ALL
+	SELECT r.id::text || ':' || l.position || ':received', 'split_refund_received', s.id::text, l.payee_id::text,
+	       r.created_at, (e->>'amount')::numeric
+	FROM split_payment_refunds r
+	JOIN split_payments s ON s.id = r.split_payment_id
+	CROSS JOIN LATERAL jsonb_array_elements(r.legs) e
+	JOIN split_payment_legs l ON l.id = (e->>'leg_id')::uuid
+	WHERE s.payer_id = $1 AND l.payee_id IS NOT NULL
+	UNION ALL
+	SELECT r.id::text || ':' || l.position || ':returned', 'split_refund_returned', s.id::text, s.payer_id::text,
+	       r.created_at, -((e->>'amount')::numeric - COALESCE((e->>'fee')::numeric, 0))
+	FROM split_payment_refunds r
+	JOIN split_payments s ON s.id = r.split_payment_id
+	CROSS JOIN LATERAL jsonb_array_elements(r.legs) e
+	JOIN split_payment_legs l ON l.id = (e->>'leg_id')::uuid
+	WHERE l.payee_id = $1
+`
+
+type statementPostgresRepository struct {
+	db *database.Database
+}
+
+func NewStatementPostgresRepository(db *database.Database) StatementRepository {
+	return &statementPostgresRepository{
+		db: db,
+	}
+}
+
+func (r *statementPostgresRepository) BalanceAt(ctx context.Context, userID string, at time.Time) (decimal.Decimal, error) {
+	// Saldo e lançamentos lidos na mesma consulta, para que vejam o mesmo estado do banco
+	query := `
+		SELECT u.balance - COALESCE((SELECT SUM(m.amount) FROM (` + statementMovementsQuery + `) m WHERE m.occurred_at >= $2), 0)
+		FROM users u
+		WHERE u.id = $1
+	`
+
+	var balance decimal.Decimal
+	err := r.db.Conn(ctx).QueryRowContext(ctx, query, userID, at).Scan(&balance)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return decimal.Zero, entity.ErrUserNotFound
+		}
+		return decimal.Zero, fmt.Errorf("erro ao calcular saldo do extrato: %w", err)
+	}
+
+	return balance, nil
+}
+
+func (r *statementPostgresRepository) ListMovements(ctx context.Context, userID string, from, to time.Time) ([]*entity.StatementMovement, error) {
+	query := `
+		SELECT m.id, m.kind, m.reference_id, m.counterparty_id, m.occurred_at, m.amount
+		FROM (` + statementMovementsQuery + `) m
+		WHERE m.occurred_at >= $2 AND m.occurred_at < $3
+		ORDER BY m.occurred_at, m.id
+	`
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar lançamentos do extrato: %w", err)
+	}
+	defer rows.Close()
+
+	var movements []*entity.StatementMovement
+	for rows.Next() {
+		movement := &entity.StatementMovement{}
+		err := rows.Scan(
+			&movement.ID,
+			&movement.Kind,
+			&movement.ReferenceID,
+			&movement.CounterpartyID,
+			&movement.OccurredAt,
+			&movement.Amount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do lançamento do extrato: %w", err)
+		}
+		movements = append(movements, movement)
+	}
+
+	return movements, rows.Err()
+}
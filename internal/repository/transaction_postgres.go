@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+const transactionColumns = "id, payer_id, payee_id, amount, fee_amount, pricing_plan_id, status, authorization_id, notification_sent, failure_reason, scheduled_for, created_at, updated_at, completed_at, reversed_at"
+
+type transactionPostgresRepository struct {
+	db *database.Database
+}
+
+func NewTransactionPostgresRepository(db *database.Database) TransactionRepository {
+	return &transactionPostgresRepository{
+		db: db,
+	}
+}
+
+func scanTransaction(row rowScanner) (*entity.Transaction, error) {
+	transaction := &entity.Transaction{}
+	err := row.Scan(
+		&transaction.ID,
+		&transaction.PayerID,
+		&transaction.PayeeID,
+		&transaction.Amount,
+		&transaction.FeeAmount,
+		&transaction.PricingPlanID,
+		&transaction.Status,
+		&transaction.AuthorizationID,
+		&transaction.NotificationSent,
+		&transaction.FailureReason,
+		&transaction.ScheduledFor,
+		&transaction.CreatedAt,
+		&transaction.UpdatedAt,
+		&transaction.CompletedAt,
+		&transaction.ReversedAt,
+	)
+	return transaction, err
+}
+
+func (r *transactionPostgresRepository) Create(ctx context.Context, transaction *entity.Transaction) error {
+	query := `
+		INSERT INTO transactions (id, payer_id, payee_id, amount, fee_amount, pricing_plan_id, status, authorization_id, notification_sent, failure_reason, scheduled_for, created_at, updated_at, completed_at, reversed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		transaction.ID,
+		transaction.PayerID,
+		transaction.PayeeID,
+		transaction.Amount,
+		transaction.FeeAmount,
+		transaction.PricingPlanID,
+		transaction.Status,
+		transaction.AuthorizationID,
+		transaction.NotificationSent,
+		transaction.FailureReason,
+		transaction.ScheduledFor,
+		transaction.CreatedAt,
+		transaction.UpdatedAt,
+		transaction.CompletedAt,
+		transaction.ReversedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao criar transação: %w", err)
+	}
+
+	return nil
+}
+
+func (r *transactionPostgresRepository) GetByID(ctx context.Context, id string) (*entity.Transaction, error) {
+	query := "SELECT " + transactionColumns + " FROM transactions WHERE id = $1"
+
+	return r.getOne(ctx, query, id)
+}
+
+func (r *transactionPostgresRepository) GetByIDForUpdate(ctx context.Context, id string) (*entity.Transaction, error) {
+	query := "SELECT " + transactionColumns + " FROM transactions WHERE id = $1 FOR UPDATE"
+
+	return r.getOne(ctx, query, id)
+}
+
+func (r *transactionPostgresRepository) getOne(ctx context.Context, query string, id string) (*entity.Transaction, error) {
+	transaction, err := scanTransaction(r.db.Conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar transação: %w", err)
+	}
+
+	return transaction, nil
+}
+
+func (r *transactionPostgresRepository) Update(ctx context.Context, transaction *entity.Transaction) error {
+	query := `
+		UPDATE transactions
+		SET status = $2, authorization_id = $3, notification_sent = $4, failure_reason = $5, updated_at = $6, completed_at = $7, reversed_at = $8
+		WHERE id = $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		transaction.ID,
+		transaction.Status,
+		transaction.AuthorizationID,
+		transaction.NotificationSent,
+		transaction.FailureReason,
+		time.Now(),
+		transaction.CompletedAt,
+		transaction.ReversedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar transação: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrTransactionNotFound)
+}
+
+func (r *transactionPostgresRepository) MarkNotificationSent(ctx context.Context, id string) error {
+	query := `UPDATE transactions SET notification_sent = TRUE, updated_at = $2 WHERE id = $1`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query, id, time.Now())
+	if err != nil {
+		return fmt.Errorf("erro ao registrar notificação da transação: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrTransactionNotFound)
+}
+
+func (r *transactionPostgresRepository) List(ctx context.Context, filters *entity.TransactionFilters) ([]*entity.Transaction, int, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	argCount := 0
+
+	if filters.UserID != "" {
+		argCount++
+		where += fmt.Sprintf(" AND (payer_id = $%d OR payee_id = $%d)", argCount, argCount)
+		args = append(args, filters.UserID)
+	}
+
+	if filters.Status != "" {
+		argCount++
+		where += fmt.Sprintf(" AND status = $%d", argCount)
+		args = append(args, filters.Status)
+	}
+
+	if filters.DateFrom != nil {
+		argCount++
+		where += fmt.Sprintf(" AND created_at >= $%d", argCount)
+		args = append(args, *filters.DateFrom)
+	}
+
+	if filters.DateTo != nil {
+		argCount++
+		where += fmt.Sprintf(" AND created_at <= $%d", argCount)
+		args = append(args, *filters.DateTo)
+	}
+
+	if filters.MinAmount != nil {
+		argCount++
+		where += fmt.Sprintf(" AND amount >= $%d", argCount)
+		args = append(args, *filters.MinAmount)
+	}
+
+	if filters.MaxAmount != nil {
+		argCount++
+		where += fmt.Sprintf(" AND amount <= $%d", argCount)
+		args = append(args, *filters.MaxAmount)
+	}
+
+	var total int
+	err := r.db.Conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM transactions"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar transações: %w", err)
+	}
+
+	query := "SELECT " + transactionColumns + " FROM transactions" + where + " ORDER BY created_at DESC"
+	argCount++
+	query += fmt.Sprintf(" LIMIT $%d", argCount)
+	args = append(args, filters.Limit)
+
+	argCount++
+	query += fmt.Sprintf(" OFFSET $%d", argCount)
+	args = append(args, filters.Offset())
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar transações: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*entity.Transaction
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("erro ao fazer scan da transação: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, total, rows.Err()
+}
+
+func (r *transactionPostgresRepository) LockNextDueScheduled(ctx context.Context, now time.Time) (*entity.Transaction, error) {
+	// SKIP LOCKED permite que várias instâncias do agendador rodem sem executar a mesma transferência
+	query := `
+		SELECT ` + transactionColumns + ` FROM transactions
+		WHERE status = 'scheduled' AND scheduled_for <= $1
+		ORDER BY scheduled_for
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	transaction, err := scanTransaction(r.db.Conn(ctx).QueryRowContext(ctx, query, now))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar transferência agendada: %w", err)
+	}
+
+	return transaction, nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+const (
+	transferBatchColumns = "id, payer_id, mode, status, total_items, total_amount, succeeded_count, failed_count, created_at, updated_at, completed_at"
+	batchItemColumns     = "id, batch_id, position, payee_id, payee_key, amount, status, transaction_id, failure_reason, updated_at"
+)
+
+type transferBatchPostgresRepository struct {
+	db *database.Database
+}
+
+func NewTransferBatchPostgresRepository(db *database.Database) TransferBatchRepository {
+	return &transferBatchPostgresRepository{
+		db: db,
+	}
+}
+
+func scanTransferBatch(row rowScanner) (*entity.TransferBatch, error) {
+	batch := &entity.TransferBatch{}
+	err := row.Scan(
+		&batch.ID,
+		&batch.PayerID,
+		&batch.Mode,
+		&batch.Status,
+		&batch.TotalItems,
+		&batch.TotalAmount,
+		&batch.SucceededCount,
+		&batch.FailedCount,
+		&batch.CreatedAt,
+		&batch.UpdatedAt,
+		&batch.CompletedAt,
+	)
+	return batch, err
+}
+
+func (r *transferBatchPostgresRepository) Create(ctx context.Context, batch *entity.TransferBatch) error {
+	return r.db.WithinTx(ctx, func(ctx context.Context) error {
+		query := `
+			INSERT INTO transfer_batches (` + transferBatchColumns + `)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`
+
+		_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+			batch.ID,
+			batch.PayerID,
+			batch.Mode,
+			batch.Status,
+			batch.TotalItems,
+			batch.TotalAmount,
+			batch.SucceededCount,
+			batch.FailedCount,
+			batch.CreatedAt,
+			batch.UpdatedAt,
+			batch.CompletedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao criar lote de transferências: %w", err)
+		}
+
+		itemQuery := `
+			INSERT INTO transfer_batch_items (` + batchItemColumns + `)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`
+
+		for _, item := range batch.Items {
+			_, err := r.db.Conn(ctx).ExecContext(ctx, itemQuery,
+				item.ID,
+				item.BatchID,
+				item.Position,
+				item.PayeeID,
+				item.PayeeKey,
+				item.Amount,
+				item.Status,
+				item.TransactionID,
+				item.FailureReason,
+				item.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("erro ao criar item do lote: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *transferBatchPostgresRepository) GetByID(ctx context.Context, id string) (*entity.TransferBatch, error) {
+	query := "SELECT " + transferBatchColumns + " FROM transfer_batches WHERE id = $1"
+
+	batch, err := scanTransferBatch(r.db.Conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrBatchNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar lote de transferências: %w", err)
+	}
+
+	if batch.Items, err = r.listItems(ctx, batch.ID); err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+func (r *transferBatchPostgresRepository) ClaimNextPending(ctx context.Context, now time.Time, lease time.Duration) (*entity.TransferBatch, error) {
+	query := `
+		UPDATE transfer_batches
+		SET status = 'processing', lease_expires_at = $2, updated_at = $1
+		WHERE id = (
+			SELECT id FROM transfer_batches
+			WHERE status = 'pending' OR (status = 'processing' AND lease_expires_at <= $1)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + transferBatchColumns
+
+	batch, err := scanTransferBatch(r.db.Conn(ctx).QueryRowContext(ctx, query, now, now.Add(lease)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar lote pendente: %w", err)
+	}
+
+	if batch.Items, err = r.listItems(ctx, batch.ID); err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+func (r *transferBatchPostgresRepository) RenewLease(ctx context.Context, id string, until time.Time) error {
+	query := "UPDATE transfer_batches SET lease_expires_at = $2 WHERE id = $1 AND status = 'processing'"
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query, id, until)
+	if err != nil {
+		return fmt.Errorf("erro ao renovar prazo do lote de transferências: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrBatchNotFound)
+}
+
+func (r *transferBatchPostgresRepository) Update(ctx context.Context, batch *entity.TransferBatch) error {
+	query := `
+		UPDATE transfer_batches
+		SET status = $2, succeeded_count = $3, failed_count = $4, updated_at = $5, completed_at = $6
+		WHERE id = $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		batch.ID,
+		batch.Status,
+		batch.SucceededCount,
+		batch.FailedCount,
+		time.Now(),
+		batch.CompletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar lote de transferências: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrBatchNotFound)
+}
+
+func (r *transferBatchPostgresRepository) UpdateItem(ctx context.Context, item *entity.BatchItem) error {
+	query := `
+		UPDATE transfer_batch_items
+		SET status = $2, transaction_id = $3, failure_reason = $4, updated_at = $5
+		WHERE id = $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		item.ID,
+		item.Status,
+		item.TransactionID,
+		item.FailureReason,
+		item.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar item do lote: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrBatchNotFound)
+}
+
+func (r *transferBatchPostgresRepository) listItems(ctx context.Context, batchID string) ([]*entity.BatchItem, error) {
+	query := "SELECT " + batchItemColumns + " FROM transfer_batch_items WHERE batch_id = $1 ORDER BY position"
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar itens do lote: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*entity.BatchItem
+	for rows.Next() {
+		item := &entity.BatchItem{}
+		err := rows.Scan(
+			&item.ID,
+			&item.BatchID,
+			&item.Position,
+			&item.PayeeID,
+			&item.PayeeKey,
+			&item.Amount,
+			&item.Status,
+			&item.TransactionID,
+			&item.FailureReason,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do item do lote: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
@@ -11,6 +11,8 @@ import (
 	"payflow-api/pkg/database"
 )
 
+const userColumns = "id, full_name, document, email, password, user_type, balance, held_balance, status, status_reason, created_at, updated_at, deleted_at, anonymized_at"
+
 type userPostgresRepository struct {
 	db *database.Database
 }
@@ -21,13 +23,38 @@ func NewUserPostgresRepository(db *database.Database) UserRepository {
 	}
 }
 
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (*entity.User, error) {
+	user := &entity.User{}
+	err := row.Scan(
+		&user.ID,
+		&user.FullName,
+		&user.Document,
+		&user.Email,
+		&user.Password,
+		&user.UserType,
+		&user.Balance,
+		&user.HeldBalance,
+		&user.Status,
+		&user.StatusReason,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.DeletedAt,
+		&user.AnonymizedAt,
+	)
+	return user, err
+}
+
 func (r *userPostgresRepository) Create(ctx context.Context, user *entity.User) error {
 	query := `
-		INSERT INTO users (id, full_name, document, email, password, user_type, balance, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO users (id, full_name, document, email, password, user_type, balance, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
-	_, err := r.db.DB.ExecContext(ctx, query,
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
 		user.ID,
 		user.FullName,
 		user.Document,
@@ -35,6 +62,7 @@ func (r *userPostgresRepository) Create(ctx context.Context, user *entity.User)
 		user.Password,
 		user.UserType,
 		user.Balance,
+		user.Status,
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
@@ -55,28 +83,34 @@ func (r *userPostgresRepository) Create(ctx context.Context, user *entity.User)
 }
 
 func (r *userPostgresRepository) GetByID(ctx context.Context, id string) (*entity.User, error) {
-	query := `
-		SELECT id, full_name, document, email, password, user_type, balance, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
+	query := "SELECT " + userColumns + " FROM users WHERE id = $1"
 
-	user := &entity.User{}
-	err := r.db.DB.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.FullName,
-		&user.Document,
-		&user.Email,
-		&user.Password,
-		&user.UserType,
-		&user.Balance,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	return r.getOne(ctx, query, id)
+}
+
+func (r *userPostgresRepository) GetByIDForUpdate(ctx context.Context, id string) (*entity.User, error) {
+	query := "SELECT " + userColumns + " FROM users WHERE id = $1 FOR UPDATE"
+
+	return r.getOne(ctx, query, id)
+}
+
+func (r *userPostgresRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	query := "SELECT " + userColumns + " FROM users WHERE email = $1 AND deleted_at IS NULL"
+
+	return r.getOne(ctx, query, email)
+}
+
+func (r *userPostgresRepository) GetByDocument(ctx context.Context, document string) (*entity.User, error) {
+	query := "SELECT " + userColumns + " FROM users WHERE document = $1 AND deleted_at IS NULL"
+
+	return r.getOne(ctx, query, document)
+}
 
+func (r *userPostgresRepository) getOne(ctx context.Context, query string, arg interface{}) (*entity.User, error) {
+	user, err := scanUser(r.db.Conn(ctx).QueryRowContext(ctx, query, arg))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("usuário não encontrado")
+			return nil, entity.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
 	}
@@ -84,106 +118,131 @@ func (r *userPostgresRepository) GetByID(ctx context.Context, id string) (*entit
 	return user, nil
 }
 
-func (r *userPostgresRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+func (r *userPostgresRepository) Update(ctx context.Context, user *entity.User) error {
 	query := `
-		SELECT id, full_name, document, email, password, user_type, balance, created_at, updated_at
-		FROM users
-		WHERE email = $1
+		UPDATE users 
+		SET full_name = $2, email = $3, updated_at = $4
+		WHERE id = $1
 	`
 
-	user := &entity.User{}
-	err := r.db.DB.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.FullName,
-		&user.Document,
-		&user.Email,
-		&user.Password,
-		&user.UserType,
-		&user.Balance,
-		&user.CreatedAt,
-		&user.UpdatedAt,
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		user.ID,
+		user.FullName,
+		user.Email,
+		time.Now(),
 	)
 
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("usuário não encontrado")
-		}
-		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+		return fmt.Errorf("erro ao atualizar usuário: %w", err)
 	}
 
-	return user, nil
+	return checkRowsAffected(result, entity.ErrUserNotFound)
 }
 
-func (r *userPostgresRepository) GetByDocument(ctx context.Context, document string) (*entity.User, error) {
+func (r *userPostgresRepository) UpdateBalance(ctx context.Context, user *entity.User) error {
 	query := `
-		SELECT id, full_name, document, email, password, user_type, balance, created_at, updated_at
-		FROM users
-		WHERE document = $1
+		UPDATE users
+		SET balance = $2, held_balance = $3, updated_at = $4
+		WHERE id = $1
 	`
 
-	user := &entity.User{}
-	err := r.db.DB.QueryRowContext(ctx, query, document).Scan(
-		&user.ID,
-		&user.FullName,
-		&user.Document,
-		&user.Email,
-		&user.Password,
-		&user.UserType,
-		&user.Balance,
-		&user.CreatedAt,
-		&user.UpdatedAt,
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		user.ID,
+		user.Balance,
+		user.HeldBalance,
+		time.Now(),
 	)
 
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("usuário não encontrado")
-		}
-		return nil, fmt.Errorf("erro ao buscar usuário: %w", err)
+		return fmt.Errorf("erro ao atualizar saldo: %w", err)
 	}
 
-	return user, nil
+	return checkRowsAffected(result, entity.ErrUserNotFound)
 }
 
-func (r *userPostgresRepository) Update(ctx context.Context, user *entity.User) error {
+func (r *userPostgresRepository) UpdateStatus(ctx context.Context, user *entity.User) error {
 	query := `
-		UPDATE users 
-		SET full_name = $2, email = $3, updated_at = $4
+		UPDATE users
+		SET status = $2, status_reason = $3, updated_at = $4
 		WHERE id = $1
 	`
 
-	result, err := r.db.DB.ExecContext(ctx, query,
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
 		user.ID,
-		user.FullName,
-		user.Email,
-		time.Now(),
+		user.Status,
+		user.StatusReason,
+		user.UpdatedAt,
 	)
 
 	if err != nil {
-		return fmt.Errorf("erro ao atualizar usuário: %w", err)
+		return fmt.Errorf("erro ao atualizar status da conta: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	return checkRowsAffected(result, entity.ErrUserNotFound)
+}
+
+func (r *userPostgresRepository) AddStatusChange(ctx context.Context, change *entity.AccountStatusChange) error {
+	query := `
+		INSERT INTO account_status_changes (id, user_id, from_status, to_status, reason, changed_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		change.ID,
+		change.UserID,
+		change.FromStatus,
+		change.ToStatus,
+		change.Reason,
+		change.ChangedBy,
+		change.CreatedAt,
+	)
 	if err != nil {
-		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+		return fmt.Errorf("erro ao registrar mudança de status da conta: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("usuário não encontrado")
+	return nil
+}
+
+func (r *userPostgresRepository) ListStatusChanges(ctx context.Context, userID string) ([]*entity.AccountStatusChange, error) {
+	query := `
+		SELECT id, user_id, from_status, to_status, reason, changed_by, created_at
+		FROM account_status_changes
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar histórico de status da conta: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
+	changes := []*entity.AccountStatusChange{}
+	for rows.Next() {
+		change := &entity.AccountStatusChange{}
+		if err := rows.Scan(
+			&change.ID,
+			&change.UserID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.Reason,
+			&change.ChangedBy,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan da mudança de status: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
 }
 
 func (r *userPostgresRepository) List(ctx context.Context, filters *entity.UserFilters) ([]*entity.User, int, error) {
-	countQuery := "SELECT COUNT(*) FROM users WHERE 1=1"
+	countQuery := "SELECT COUNT(*) FROM users WHERE deleted_at IS NULL"
 	args := []interface{}{}
 	argCount := 0
 
-	query := `
-		SELECT id, full_name, document, email, password, user_type, balance, created_at, updated_at
-		FROM users
-		WHERE 1=1
-	`
+	query := "SELECT " + userColumns + " FROM users WHERE deleted_at IS NULL"
 
 	if filters.UserType != "" {
 		argCount++
@@ -199,8 +258,15 @@ func (r *userPostgresRepository) List(ctx context.Context, filters *entity.UserF
 		args = append(args, "%"+filters.Email+"%")
 	}
 
+	if filters.Status != "" {
+		argCount++
+		countQuery += fmt.Sprintf(" AND status = $%d", argCount)
+		query += fmt.Sprintf(" AND status = $%d", argCount)
+		args = append(args, filters.Status)
+	}
+
 	var total int
-	err := r.db.DB.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	err := r.db.Conn(ctx).QueryRowContext(ctx, countQuery, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("erro ao contar usuários: %w", err)
 	}
@@ -214,7 +280,7 @@ func (r *userPostgresRepository) List(ctx context.Context, filters *entity.UserF
 	query += fmt.Sprintf(" OFFSET $%d", argCount)
 	args = append(args, filters.Offset())
 
-	rows, err := r.db.DB.QueryContext(ctx, query, args...)
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("erro ao listar usuários: %w", err)
 	}
@@ -222,55 +288,116 @@ func (r *userPostgresRepository) List(ctx context.Context, filters *entity.UserF
 
 	var users []*entity.User
 	for rows.Next() {
-		user := &entity.User{}
-		err := rows.Scan(
-			&user.ID,
-			&user.FullName,
-			&user.Document,
-			&user.Email,
-			&user.Password,
-			&user.UserType,
-			&user.Balance,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, 0, fmt.Errorf("erro ao fazer scan do usuário: %w", err)
 		}
 		users = append(users, user)
 	}
 
-	return users, total, nil
+	return users, total, rows.Err()
 }
 
-func (r *userPostgresRepository) Delete(ctx context.Context, id string) error {
-	query := "DELETE FROM users WHERE id = $1"
+// Delete faz a exclusão lógica: a linha continua referenciada por transações e lançamentos
+func (r *userPostgresRepository) Delete(ctx context.Context, user *entity.User) error {
+	query := `
+		UPDATE users
+		SET deleted_at = $2, updated_at = $3
+		WHERE id = $1 AND deleted_at IS NULL
+	`
 
-	result, err := r.db.DB.ExecContext(ctx, query, id)
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query, user.ID, user.DeletedAt, user.UpdatedAt)
 	if err != nil {
-		return fmt.Errorf("erro ao deletar usuário: %w", err)
+		return fmt.Errorf("erro ao excluir usuário: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	return checkRowsAffected(result, entity.ErrUserNotFound)
+}
+
+// Anonymize grava os dados pessoais apagados e remove as cópias do email e do documento
+// guardadas como chave Pix do recebedor em lotes e pagamentos divididos
+func (r *userPostgresRepository) Anonymize(ctx context.Context, user *entity.User) error {
+	return r.db.WithinTx(ctx, func(ctx context.Context) error {
+		query := `
+			UPDATE users
+			SET full_name = $2, email = $3, document = $4, password = $5, anonymized_at = $6, updated_at = $7
+			WHERE id = $1 AND deleted_at IS NOT NULL AND anonymized_at IS NULL
+		`
+
+		result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+			user.ID,
+			user.FullName,
+			user.Email,
+			user.Document,
+			user.Password,
+			user.AnonymizedAt,
+			user.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("erro ao anonimizar usuário: %w", err)
+		}
+		if err := checkRowsAffected(result, entity.ErrUserNotFound); err != nil {
+			return err
+		}
+
+		for _, table := range []string{"transfer_batch_items", "split_payment_legs"} {
+			query := "UPDATE " + table + " SET payee_key = NULL WHERE payee_id = $1 AND payee_key IS NOT NULL"
+			if _, err := r.db.Conn(ctx).ExecContext(ctx, query, user.ID); err != nil {
+				return fmt.Errorf("erro ao anonimizar chaves Pix do usuário: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *userPostgresRepository) ListPendingAnonymization(ctx context.Context, deletedBefore time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT id FROM users
+		WHERE deleted_at IS NOT NULL AND anonymized_at IS NULL AND deleted_at <= $1
+		ORDER BY deleted_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, deletedBefore, limit)
 	if err != nil {
-		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+		return nil, fmt.Errorf("erro ao listar usuários pendentes de anonimização: %w", err)
 	}
+	defer rows.Close()
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("usuário não encontrado")
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do usuário: %w", err)
+		}
+		ids = append(ids, id)
 	}
 
-	return nil
+	return ids, rows.Err()
 }
 
 func (r *userPostgresRepository) ExistsByEmailOrDocument(ctx context.Context, email, document string) (bool, error) {
-	query := "SELECT COUNT(*) FROM users WHERE email = $1 OR document = $2"
+	query := "SELECT COUNT(*) FROM users WHERE (email = $1 OR document = $2) AND deleted_at IS NULL"
 
 	var count int
-	err := r.db.DB.QueryRowContext(ctx, query, email, document).Scan(&count)
+	err := r.db.Conn(ctx).QueryRowContext(ctx, query, email, document).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("erro ao verificar usuário existente: %w", err)
 	}
 
 	return count > 0, nil
 }
+
+func checkRowsAffected(result sql.Result, notFound error) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
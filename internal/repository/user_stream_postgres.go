@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+// UserStreamChannel é o canal do LISTEN/NOTIFY que avisa as instâncias de novos eventos do stream.
+// O payload é o ID do usuário.
+const UserStreamChannel = "user_stream_events"
+
+// userStreamLockKey é o namespace dos advisory locks por usuário do stream
+const userStreamLockKey = 7301
+
+const userStreamEventColumns = "id, user_id, event_type, source_event_id, data, created_at"
+
+type userStreamPostgresRepository struct {
+	db *database.Database
+}
+
+func NewUserStreamPostgresRepository(db *database.Database) UserStreamRepository {
+	return &userStreamPostgresRepository{
+		db: db,
+	}
+}
+
+func scanUserStreamEvent(row rowScanner) (*entity.UserStreamEvent, error) {
+	event := &entity.UserStreamEvent{}
+	var data []byte
+	err := row.Scan(
+		&event.ID,
+		&event.UserID,
+		&event.Type,
+		&event.SourceEventID,
+		&data,
+		&event.CreatedAt,
+	)
+	event.Data = data
+	return event, err
+}
+
+func (r *userStreamPostgresRepository) Append(ctx context.Context, events []*entity.UserStreamEvent) error {
+	query := `
+		INSERT INTO user_stream_events (user_id, event_type, source_event_id, data, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (source_event_id, user_id) DO NOTHING
+		RETURNING id
+	`
+
+	// Os IDs são a posição do Last-Event-ID, então os eventos de um usuário precisam ficar visíveis na
+	// ordem dos IDs: o lock segura outra gravação para o mesmo usuário até o commit desta
+	userIDs := make([]string, 0, len(events))
+	for _, event := range events {
+		userIDs = append(userIDs, event.UserID)
+	}
+	sort.Strings(userIDs)
+	for i, userID := range userIDs {
+		if i > 0 && userIDs[i-1] == userID {
+			continue
+		}
+		if _, err := r.db.Conn(ctx).ExecContext(ctx, "SELECT pg_advisory_xact_lock($1, hashtext($2))", userStreamLockKey, userID); err != nil {
+			return fmt.Errorf("erro ao bloquear stream do usuário: %w", err)
+		}
+	}
+
+	notified := make(map[string]bool)
+	for _, event := range events {
+		err := r.db.Conn(ctx).QueryRowContext(ctx, query,
+			event.UserID,
+			event.Type,
+			event.SourceEventID,
+			[]byte(event.Data),
+			event.CreatedAt,
+		).Scan(&event.ID)
+		// Já gravado em um despacho anterior do mesmo evento de domínio
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("erro ao gravar evento do stream: %w", err)
+		}
+
+		if notified[event.UserID] {
+			continue
+		}
+		if _, err := r.db.Conn(ctx).ExecContext(ctx, "SELECT pg_notify($1, $2)", UserStreamChannel, event.UserID); err != nil {
+			return fmt.Errorf("erro ao avisar novo evento do stream: %w", err)
+		}
+		notified[event.UserID] = true
+	}
+
+	return nil
+}
+
+func (r *userStreamPostgresRepository) ListAfter(ctx context.Context, userID string, afterID int64, limit int) ([]*entity.UserStreamEvent, error) {
+	query := `SELECT ` + userStreamEventColumns + ` FROM user_stream_events WHERE user_id = $1 AND id > $2 ORDER BY id LIMIT $3`
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, userID, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar eventos do stream: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*entity.UserStreamEvent
+	for rows.Next() {
+		event, err := scanUserStreamEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do evento do stream: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *userStreamPostgresRepository) LatestID(ctx context.Context, userID string) (int64, error) {
+	var id int64
+	err := r.db.Conn(ctx).QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM user_stream_events WHERE user_id = $1`, userID).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao buscar último evento do stream: %w", err)
+	}
+	return id, nil
+}
+
+func (r *userStreamPostgresRepository) OldestID(ctx context.Context) (int64, error) {
+	var id int64
+	err := r.db.Conn(ctx).QueryRowContext(ctx, `SELECT COALESCE(MIN(id), 0) FROM user_stream_events`).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao buscar primeiro evento do stream: %w", err)
+	}
+	return id, nil
+}
+
+func (r *userStreamPostgresRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, `DELETE FROM user_stream_events WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao apagar eventos antigos do stream: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao verificar linhas afetadas: %w", err)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,419 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/database"
+)
+
+const webhookEndpointColumns = "id, merchant_id, url, description, event_types, secret, active, created_at, updated_at"
+
+// webhookDeliveryColumns não inclui o corpo enviado, lido apenas na consulta de uma entrega e no envio
+const webhookDeliveryColumns = `id, endpoint_id, merchant_id, event_id, event_type, status, attempts, redelivery_of,
+	next_attempt_at, last_response_status, last_error, created_at, updated_at, delivered_at`
+
+type webhookPostgresRepository struct {
+	db *database.Database
+}
+
+func NewWebhookPostgresRepository(db *database.Database) WebhookRepository {
+	return &webhookPostgresRepository{
+		db: db,
+	}
+}
+
+func scanWebhookEndpoint(row rowScanner) (*entity.WebhookEndpoint, error) {
+	endpoint := &entity.WebhookEndpoint{}
+	var eventTypes []byte
+	err := row.Scan(
+		&endpoint.ID,
+		&endpoint.MerchantID,
+		&endpoint.URL,
+		&endpoint.Description,
+		&eventTypes,
+		&endpoint.Secret,
+		&endpoint.Active,
+		&endpoint.CreatedAt,
+		&endpoint.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(eventTypes, &endpoint.EventTypes); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar eventos do webhook: %w", err)
+	}
+	return endpoint, nil
+}
+
+func scanWebhookDelivery(row rowScanner, extra ...interface{}) (*entity.WebhookDelivery, error) {
+	delivery := &entity.WebhookDelivery{}
+	dest := []interface{}{
+		&delivery.ID,
+		&delivery.EndpointID,
+		&delivery.MerchantID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.RedeliveryOf,
+		&delivery.NextAttemptAt,
+		&delivery.LastResponseStatus,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+		&delivery.DeliveredAt,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return delivery, err
+}
+
+func (r *webhookPostgresRepository) CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	eventTypes, err := json.Marshal(endpoint.EventTypes)
+	if err != nil {
+		return fmt.Errorf("erro ao codificar eventos do webhook: %w", err)
+	}
+
+	query := `
+		INSERT INTO webhook_endpoints (id, merchant_id, url, description, event_types, secret, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err = r.db.Conn(ctx).ExecContext(ctx, query,
+		endpoint.ID,
+		endpoint.MerchantID,
+		endpoint.URL,
+		endpoint.Description,
+		eventTypes,
+		endpoint.Secret,
+		endpoint.Active,
+		endpoint.CreatedAt,
+		endpoint.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao criar endpoint de webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookPostgresRepository) GetEndpoint(ctx context.Context, id string) (*entity.WebhookEndpoint, error) {
+	query := "SELECT " + webhookEndpointColumns + " FROM webhook_endpoints WHERE id = $1"
+
+	endpoint, err := scanWebhookEndpoint(r.db.Conn(ctx).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrWebhookEndpointNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar endpoint de webhook: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+func (r *webhookPostgresRepository) ListEndpoints(ctx context.Context, merchantID string) ([]*entity.WebhookEndpoint, error) {
+	query := "SELECT " + webhookEndpointColumns + " FROM webhook_endpoints WHERE merchant_id = $1 ORDER BY created_at"
+	return r.listEndpoints(ctx, query, merchantID)
+}
+
+func (r *webhookPostgresRepository) ListSubscribedEndpoints(ctx context.Context, merchantID string, eventType entity.WebhookEventType) ([]*entity.WebhookEndpoint, error) {
+	eventTypes, err := json.Marshal([]entity.WebhookEventType{eventType})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao codificar evento do webhook: %w", err)
+	}
+
+	query := "SELECT " + webhookEndpointColumns + `
+		FROM webhook_endpoints
+		WHERE merchant_id = $1 AND active AND event_types @> $2::jsonb
+		ORDER BY created_at`
+	return r.listEndpoints(ctx, query, merchantID, eventTypes)
+}
+
+func (r *webhookPostgresRepository) listEndpoints(ctx context.Context, query string, args ...interface{}) ([]*entity.WebhookEndpoint, error) {
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar endpoints de webhook: %w", err)
+	}
+	defer rows.Close()
+
+	var endpoints []*entity.WebhookEndpoint
+	for rows.Next() {
+		endpoint, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan do endpoint de webhook: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, rows.Err()
+}
+
+func (r *webhookPostgresRepository) CountEndpoints(ctx context.Context, merchantID string) (int, error) {
+	var count int
+	err := r.db.Conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM webhook_endpoints WHERE merchant_id = $1", merchantID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao contar endpoints de webhook: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *webhookPostgresRepository) UpdateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	eventTypes, err := json.Marshal(endpoint.EventTypes)
+	if err != nil {
+		return fmt.Errorf("erro ao codificar eventos do webhook: %w", err)
+	}
+
+	query := `
+		UPDATE webhook_endpoints
+		SET url = $2, description = $3, event_types = $4, active = $5, updated_at = $6
+		WHERE id = $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		endpoint.ID,
+		endpoint.URL,
+		endpoint.Description,
+		eventTypes,
+		endpoint.Active,
+		endpoint.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar endpoint de webhook: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrWebhookEndpointNotFound)
+}
+
+func (r *webhookPostgresRepository) DeleteEndpoint(ctx context.Context, id string) error {
+	result, err := r.db.Conn(ctx).ExecContext(ctx, "DELETE FROM webhook_endpoints WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("erro ao remover endpoint de webhook: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrWebhookEndpointNotFound)
+}
+
+func (r *webhookPostgresRepository) CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, endpoint_id, merchant_id, event_id, event_type, payload, status, attempts,
+			redelivery_of, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		delivery.ID,
+		delivery.EndpointID,
+		delivery.MerchantID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Payload,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.RedeliveryOf,
+		delivery.NextAttemptAt,
+		delivery.CreatedAt,
+		delivery.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao criar entrega de webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookPostgresRepository) GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	query := "SELECT " + webhookDeliveryColumns + ", payload FROM webhook_deliveries WHERE id = $1"
+
+	var payload []byte
+	delivery, err := scanWebhookDelivery(r.db.Conn(ctx).QueryRowContext(ctx, query, id), &payload)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entity.ErrWebhookDeliveryNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar entrega de webhook: %w", err)
+	}
+	delivery.Payload = payload
+
+	delivery.AttemptLog, err = r.listAttempts(ctx, delivery.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (r *webhookPostgresRepository) ListDeliveries(ctx context.Context, filters *entity.WebhookDeliveryFilters) ([]*entity.WebhookDelivery, int, error) {
+	where := " WHERE merchant_id = $1"
+	args := []interface{}{filters.MerchantID}
+	argCount := 1
+
+	if filters.EndpointID != "" {
+		argCount++
+		where += fmt.Sprintf(" AND endpoint_id = $%d", argCount)
+		args = append(args, filters.EndpointID)
+	}
+
+	if filters.EventType != "" {
+		argCount++
+		where += fmt.Sprintf(" AND event_type = $%d", argCount)
+		args = append(args, filters.EventType)
+	}
+
+	if filters.Status != "" {
+		argCount++
+		where += fmt.Sprintf(" AND status = $%d", argCount)
+		args = append(args, filters.Status)
+	}
+
+	var total int
+	err := r.db.Conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM webhook_deliveries"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao contar entregas de webhook: %w", err)
+	}
+
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries" + where + " ORDER BY created_at DESC"
+	argCount++
+	query += fmt.Sprintf(" LIMIT $%d", argCount)
+	args = append(args, filters.Limit)
+
+	argCount++
+	query += fmt.Sprintf(" OFFSET $%d", argCount)
+	args = append(args, filters.Offset())
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao listar entregas de webhook: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*entity.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("erro ao fazer scan da entrega de webhook: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, total, rows.Err()
+}
+
+func (r *webhookPostgresRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns + ", payload"
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar entregas de webhook pendentes: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*entity.WebhookDelivery
+	for rows.Next() {
+		var payload []byte
+		delivery, err := scanWebhookDelivery(rows, &payload)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan da entrega de webhook: %w", err)
+		}
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *webhookPostgresRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_response_status = $5, last_error = $6,
+			updated_at = $7, delivered_at = $8
+		WHERE id = $1
+	`
+
+	result, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastResponseStatus,
+		delivery.LastError,
+		delivery.UpdatedAt,
+		delivery.DeliveredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar entrega de webhook: %w", err)
+	}
+
+	return checkRowsAffected(result, entity.ErrWebhookDeliveryNotFound)
+}
+
+func (r *webhookPostgresRepository) AddAttempt(ctx context.Context, attempt *entity.WebhookAttempt) error {
+	query := `
+		INSERT INTO webhook_delivery_attempts (id, delivery_id, number, response_status, error, duration_ms, attempted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.Conn(ctx).ExecContext(ctx, query,
+		attempt.ID,
+		attempt.DeliveryID,
+		attempt.Number,
+		attempt.ResponseStatus,
+		attempt.Error,
+		attempt.DurationMs,
+		attempt.AttemptedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar tentativa de webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookPostgresRepository) listAttempts(ctx context.Context, deliveryID string) ([]*entity.WebhookAttempt, error) {
+	query := `
+		SELECT id, delivery_id, number, response_status, error, duration_ms, attempted_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY number
+	`
+
+	rows, err := r.db.Conn(ctx).QueryContext(ctx, query, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar tentativas de webhook: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []*entity.WebhookAttempt
+	for rows.Next() {
+		attempt := &entity.WebhookAttempt{}
+		err := rows.Scan(
+			&attempt.ID,
+			&attempt.DeliveryID,
+			&attempt.Number,
+			&attempt.ResponseStatus,
+			&attempt.Error,
+			&attempt.DurationMs,
+			&attempt.AttemptedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao fazer scan da tentativa de webhook: %w", err)
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, rows.Err()
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+)
+
+// auditVerifyPageSize é o tamanho dos lotes lidos ao conferir a cadeia de hashes
+const auditVerifyPageSize = 1000
+
+type auditMetadataKey struct{}
+
+// WithAuditMetadata associa ao contexto quem fez a requisição, para ser gravado na trilha
+func WithAuditMetadata(ctx context.Context, meta entity.AuditMetadata) context.Context {
+	return context.WithValue(ctx, auditMetadataKey{}, meta)
+}
+
+// auditMetadataFrom retorna os dados da requisição; fora de uma requisição, o ator é o sistema
+func auditMetadataFrom(ctx context.Context) entity.AuditMetadata {
+	if meta, ok := ctx.Value(auditMetadataKey{}).(entity.AuditMetadata); ok {
+		return meta
+	}
+	return entity.AuditMetadata{ActorType: entity.AuditActorSystem}
+}
+
+// AuditLogger registra na trilha de auditoria as ações que alteram estado. Deve ser chamado dentro da
+// transação da própria ação, para que a ação e o registro sejam confirmados ou desfeitos juntos.
+type AuditLogger interface {
+	// Record grava a ação com a diferença entre os estados. before deve ser capturado com
+	// entity.AuditSnapshot antes da alteração e é nulo nas criações.
+	Record(ctx context.Context, action, resourceType, resourceID string, before, after interface{}) error
+}
+
+// AuditUseCase define as consultas administrativas à trilha de auditoria
+type AuditUseCase interface {
+	AuditLogger
+	ListEntries(ctx context.Context, filters *entity.AuditLogFilters) (*entity.ListAuditLogsResponse, error)
+	// VerifyChain confere sequência e hashes da trilha inteira e aponta o primeiro registro adulterado
+	VerifyChain(ctx context.Context) (*entity.AuditChainVerification, error)
+}
+
+type auditUseCase struct {
+	auditRepo repository.AuditRepository
+}
+
+// NewAuditUseCase cria uma nova instância do use case de auditoria
+func NewAuditUseCase(auditRepo repository.AuditRepository) AuditUseCase {
+	return &auditUseCase{
+		auditRepo: auditRepo,
+	}
+}
+
+func (uc *auditUseCase) Record(ctx context.Context, action, resourceType, resourceID string, before, after interface{}) error {
+	entry := entity.NewAuditEntry(auditMetadataFrom(ctx), action, resourceType, resourceID, before, after, time.Now())
+	return uc.auditRepo.Append(ctx, entry)
+}
+
+func (uc *auditUseCase) ListEntries(ctx context.Context, filters *entity.AuditLogFilters) (*entity.ListAuditLogsResponse, error) {
+	// Validar paginação
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 20
+	}
+	if filters.Limit > 100 {
+		filters.Limit = 100
+	}
+
+	entries, total, err := uc.auditRepo.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []*entity.AuditEntry{}
+	}
+
+	totalPages := (total + filters.Limit - 1) / filters.Limit
+
+	return &entity.ListAuditLogsResponse{
+		Entries:    entries,
+		Total:      total,
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (uc *auditUseCase) VerifyChain(ctx context.Context) (*entity.AuditChainVerification, error) {
+	verification := &entity.AuditChainVerification{Valid: true}
+
+	for {
+		entries, err := uc.auditRepo.ListAfter(ctx, verification.LastSequence, auditVerifyPageSize)
+		if err != nil {
+			return nil, err
+		}
+		if !verification.Verify(entries) || len(entries) < auditVerifyPageSize {
+			break
+		}
+	}
+
+	return verification, nil
+}
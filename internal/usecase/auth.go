@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyPasswordHash é comparado quando o e-mail não existe, para que a resposta leve o mesmo tempo
+// e não revele quais e-mails estão cadastrados
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("senha-inexistente"), bcrypt.DefaultCost)
+
+// AuthUseCase define a emissão dos tokens de acesso dos usuários
+type AuthUseCase interface {
+	// CreateToken confere e-mail e senha e emite o token que identifica o usuário nas rotas protegidas
+	CreateToken(ctx context.Context, req *entity.CreateAuthTokenRequest) (*entity.AuthTokenResponse, error)
+}
+
+type authUseCase struct {
+	userRepo repository.UserRepository
+	tokens   *entity.AuthTokenSigner
+}
+
+// NewAuthUseCase cria uma nova instância do use case de autenticação
+func NewAuthUseCase(userRepo repository.UserRepository, tokens *entity.AuthTokenSigner) AuthUseCase {
+	return &authUseCase{
+		userRepo: userRepo,
+		tokens:   tokens,
+	}
+}
+
+func (uc *authUseCase) CreateToken(ctx context.Context, req *entity.CreateAuthTokenRequest) (*entity.AuthTokenResponse, error) {
+	user, err := uc.userRepo.GetByEmail(ctx, req.Email)
+	if errors.Is(err, entity.ErrUserNotFound) {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(req.Password))
+		return nil, entity.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
+		return nil, entity.ErrInvalidCredentials
+	}
+	// Conta encerrada não recebe novos tokens
+	if user.Status == entity.AccountStatusClosed {
+		return nil, entity.ErrAccountClosed
+	}
+
+	return uc.tokens.Issue(user.ID, time.Now()), nil
+}
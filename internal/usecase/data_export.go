@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+)
+
+// dataExportPageSize é o tamanho das páginas lidas ao reunir transações e disputas do titular
+const dataExportPageSize = 500
+
+// DataExportPolicy define a validade do link e a URL base usada para montá-lo
+type DataExportPolicy struct {
+	LinkTTL time.Duration
+	BaseURL string
+	// Lease deve cobrir a geração de um arquivo; vencido, outra instância retoma a exportação
+	Lease time.Duration
+}
+
+// DataExportFile é o arquivo entregue no download
+type DataExportFile struct {
+	FileName string
+	Content  []byte
+}
+
+// DataExportUseCase define as operações de negócio para a exportação de dados do titular
+type DataExportUseCase interface {
+	// RequestExport enfileira a geração do arquivo; só uma exportação por vez pode estar em andamento
+	RequestExport(ctx context.Context, userID string) (*entity.DataExportResponse, error)
+	// GetExport retorna o andamento e, quando pronta, o link assinado de download
+	GetExport(ctx context.Context, userID, id string) (*entity.DataExportResponse, error)
+	// Download confere a assinatura do link e entrega o arquivo uma única vez
+	Download(ctx context.Context, id string, expires int64, signature string) (*DataExportFile, error)
+	// ProcessPendingExports gera os arquivos das exportações pendentes
+	ProcessPendingExports(ctx context.Context) (int, error)
+	// ExpireExports descarta os arquivos cujo link venceu sem download
+	ExpireExports(ctx context.Context) (int, error)
+}
+
+type dataExportUseCase struct {
+	txManager       repository.TxManager
+	exportRepo      repository.DataExportRepository
+	userRepo        repository.UserRepository
+	pixKeyRepo      repository.PixKeyRepository
+	transactionRepo repository.TransactionRepository
+	disputeRepo     repository.DisputeRepository
+	signer          *entity.DataExportSigner
+	policy          DataExportPolicy
+	audit           AuditLogger
+}
+
+// NewDataExportUseCase cria uma nova instância do use case de exportação de dados
+func NewDataExportUseCase(
+	txManager repository.TxManager,
+	exportRepo repository.DataExportRepository,
+	userRepo repository.UserRepository,
+	pixKeyRepo repository.PixKeyRepository,
+	transactionRepo repository.TransactionRepository,
+	disputeRepo repository.DisputeRepository,
+	signer *entity.DataExportSigner,
+	policy DataExportPolicy,
+	audit AuditLogger,
+) DataExportUseCase {
+	return &dataExportUseCase{
+		txManager:       txManager,
+		exportRepo:      exportRepo,
+		userRepo:        userRepo,
+		pixKeyRepo:      pixKeyRepo,
+		transactionRepo: transactionRepo,
+		disputeRepo:     disputeRepo,
+		signer:          signer,
+		policy:          policy,
+		audit:           audit,
+	}
+}
+
+func (uc *dataExportUseCase) RequestExport(ctx context.Context, userID string) (*entity.DataExportResponse, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDeleted() {
+		return nil, entity.ErrUserNotFound
+	}
+
+	inProgress, err := uc.exportRepo.HasInProgress(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if inProgress {
+		return nil, entity.ErrDataExportInProgress
+	}
+
+	export := entity.NewDataExport(userID, time.Now())
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.exportRepo.Create(ctx, export); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "data_export.request", "data_export", export.ID, nil, export)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return export.ToDataExportResponse(""), nil
+}
+
+func (uc *dataExportUseCase) GetExport(ctx context.Context, userID, id string) (*entity.DataExportResponse, error) {
+	export, err := uc.exportRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Não revelar exportações de outros usuários
+	if export.UserID != userID {
+		return nil, entity.ErrDataExportNotFound
+	}
+
+	downloadURL := ""
+	if export.Status == entity.DataExportStatusReady && time.Now().Before(*export.ExpiresAt) {
+		downloadURL = uc.downloadURL(export)
+	}
+
+	return export.ToDataExportResponse(downloadURL), nil
+}
+
+// Download marca a exportação como baixada na mesma transação que lê o arquivo,
+// então dois downloads simultâneos do mesmo link não entregam o arquivo duas vezes
+func (uc *dataExportUseCase) Download(ctx context.Context, id string, expires int64, signature string) (*DataExportFile, error) {
+	if err := uc.signer.Verify(id, expires, signature, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var file *DataExportFile
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		export, err := uc.exportRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(export)
+		content, err := export.Download(time.Now())
+		if err != nil {
+			return err
+		}
+
+		if err := uc.exportRepo.Update(ctx, export); err != nil {
+			return err
+		}
+		if err := uc.audit.Record(ctx, "data_export.download", "data_export", export.ID, before, export); err != nil {
+			return err
+		}
+
+		file = &DataExportFile{FileName: export.FileName(), Content: content}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (uc *dataExportUseCase) ProcessPendingExports(ctx context.Context) (int, error) {
+	processed := 0
+
+	for ctx.Err() == nil {
+		export, err := uc.exportRepo.ClaimNextPending(ctx, time.Now(), uc.policy.Lease)
+		if err != nil {
+			return processed, err
+		}
+		if export == nil {
+			break
+		}
+
+		if err := uc.process(ctx, export); err != nil {
+			slog.ErrorContext(ctx, "erro ao gerar exportação de dados", "export_id", export.ID, "error", err)
+			export.Fail("falha ao gerar o arquivo de exportação", time.Now())
+			if err := uc.exportRepo.Update(ctx, export); err != nil {
+				slog.ErrorContext(ctx, "erro ao registrar falha da exportação de dados", "export_id", export.ID, "error", err)
+			}
+			continue
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+func (uc *dataExportUseCase) ExpireExports(ctx context.Context) (int, error) {
+	return uc.exportRepo.ExpireReady(ctx, time.Now())
+}
+
+func (uc *dataExportUseCase) process(ctx context.Context, export *entity.DataExport) error {
+	bundle, err := uc.collect(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	archive, err := bundle.Archive()
+	if err != nil {
+		return fmt.Errorf("erro ao compactar exportação: %w", err)
+	}
+
+	if err := export.MarkReady(archive, uc.policy.LinkTTL, time.Now()); err != nil {
+		return err
+	}
+
+	return uc.exportRepo.Update(ctx, export)
+}
+
+// collect reúne perfil, chaves Pix, transações enviadas e recebidas, disputas e histórico de status
+func (uc *dataExportUseCase) collect(ctx context.Context, userID string) (*entity.DataExportBundle, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pixKeys, err := uc.pixKeyRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []*entity.Transaction
+	for page := 1; ; page++ {
+		filters := &entity.TransactionFilters{UserID: userID}
+		filters.Page = page
+		filters.Limit = dataExportPageSize
+
+		items, total, err := uc.transactionRepo.List(ctx, filters)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, items...)
+		if len(items) == 0 || len(transactions) >= total {
+			break
+		}
+	}
+
+	var disputes []*entity.Dispute
+	for page := 1; ; page++ {
+		filters := &entity.DisputeFilters{UserID: userID}
+		filters.Page = page
+		filters.Limit = dataExportPageSize
+
+		items, total, err := uc.disputeRepo.List(ctx, filters)
+		if err != nil {
+			return nil, err
+		}
+		// A listagem não traz evidências e histórico
+		for _, item := range items {
+			dispute, err := uc.disputeRepo.GetByID(ctx, item.ID)
+			if err != nil {
+				return nil, err
+			}
+			disputes = append(disputes, dispute)
+		}
+		if len(items) == 0 || len(disputes) >= total {
+			break
+		}
+	}
+
+	history, err := uc.userRepo.ListStatusChanges(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewDataExportBundle(user, pixKeys, transactions, disputes, history, time.Now()), nil
+}
+
+func (uc *dataExportUseCase) downloadURL(export *entity.DataExport) string {
+	return fmt.Sprintf("%s/api/v1/data-exports/%s/download?expires=%d&signature=%s",
+		uc.policy.BaseURL,
+		export.ID,
+		export.ExpiresAt.Unix(),
+		uc.signer.Sign(export.ID, *export.ExpiresAt),
+	)
+}
@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+)
+
+// DisputeUseCase define as operações de negócio para disputas de transações
+type DisputeUseCase interface {
+	// OpenDispute abre a contestação de uma transação concluída do pagador
+	OpenDispute(ctx context.Context, payerID string, req *entity.OpenDisputeRequest) (*entity.DisputeResponse, error)
+	GetDispute(ctx context.Context, userID, id string) (*entity.DisputeResponse, error)
+	ListDisputes(ctx context.Context, filters *entity.DisputeFilters) (*entity.ListDisputesResponse, error)
+	// RespondDispute registra a defesa do lojista dentro do prazo de resposta
+	RespondDispute(ctx context.Context, merchantID, id string, req *entity.RespondDisputeRequest) (*entity.DisputeResponse, error)
+	// ResolveDispute decide a disputa; a favor do pagador, a transação é revertida na mesma transação do banco
+	ResolveDispute(ctx context.Context, id string, req *entity.ResolveDisputeRequest) (*entity.DisputeResponse, error)
+	// EscalateOverdueDisputes envia para decisão as disputas sem resposta do lojista no prazo e escala
+	// as que não foram decididas no prazo de decisão
+	EscalateOverdueDisputes(ctx context.Context) (int, error)
+}
+
+type disputeUseCase struct {
+	txManager       repository.TxManager
+	disputeRepo     repository.DisputeRepository
+	transactionRepo repository.TransactionRepository
+	splitRepo       repository.SplitPaymentRepository
+	transactions    TransactionUseCase
+	policy          entity.DisputePolicy
+	webhooks        WebhookPublisher
+	audit           AuditLogger
+}
+
+// NewDisputeUseCase cria uma nova instância do use case de disputas
+func NewDisputeUseCase(
+	txManager repository.TxManager,
+	disputeRepo repository.DisputeRepository,
+	transactionRepo repository.TransactionRepository,
+	splitRepo repository.SplitPaymentRepository,
+	transactions TransactionUseCase,
+	policy entity.DisputePolicy,
+	webhooks WebhookPublisher,
+	audit AuditLogger,
+) DisputeUseCase {
+	return &disputeUseCase{
+		txManager:       txManager,
+		disputeRepo:     disputeRepo,
+		transactionRepo: transactionRepo,
+		splitRepo:       splitRepo,
+		transactions:    transactions,
+		policy:          policy,
+		webhooks:        webhooks,
+		audit:           audit,
+	}
+}
+
+// OpenDispute valida a transação e grava a disputa com a evidência do pagador
+func (uc *disputeUseCase) OpenDispute(ctx context.Context, payerID string, req *entity.OpenDisputeRequest) (*entity.DisputeResponse, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, req.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// O estorno de um pagamento dividido já devolveu parte da transação ao pagador
+	refunded, err := uc.splitRepo.HasRefundedLeg(ctx, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	if refunded {
+		return nil, entity.ErrDisputeSplitRefunded
+	}
+
+	now := time.Now()
+	dispute, err := entity.NewDispute(transaction, payerID, req.Reason, req.Description, req.Attachments, uc.policy, now)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao validar dados da disputa: %w", err)
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.disputeRepo.Create(ctx, dispute); err != nil {
+			return err
+		}
+		if err := uc.audit.Record(ctx, "dispute.open", "dispute", dispute.ID, nil, dispute); err != nil {
+			return err
+		}
+		return uc.webhooks.Publish(ctx, dispute.MerchantID, entity.WebhookEventDisputeOpened, dispute.ToDisputeResponse(now))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dispute.ToDisputeResponse(now), nil
+}
+
+// GetDispute retorna a disputa ao pagador ou ao lojista
+func (uc *disputeUseCase) GetDispute(ctx context.Context, userID, id string) (*entity.DisputeResponse, error) {
+	dispute, err := uc.disputeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Não revelar disputas de outros usuários
+	if !dispute.IsVisibleTo(userID) {
+		return nil, entity.ErrDisputeNotFound
+	}
+
+	return dispute.ToDisputeResponse(time.Now()), nil
+}
+
+// ListDisputes lista disputas de um usuário ou, para administradores, de todos
+func (uc *disputeUseCase) ListDisputes(ctx context.Context, filters *entity.DisputeFilters) (*entity.ListDisputesResponse, error) {
+	// Validar paginação
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 20
+	}
+	if filters.Limit > 100 {
+		filters.Limit = 100
+	}
+
+	disputes, total, err := uc.disputeRepo.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	responses := make([]entity.DisputeResponse, 0, len(disputes))
+	for _, dispute := range disputes {
+		responses = append(responses, *dispute.ToDisputeResponse(now))
+	}
+
+	totalPages := (total + filters.Limit - 1) / filters.Limit
+
+	return &entity.ListDisputesResponse{
+		Disputes:   responses,
+		Total:      total,
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// RespondDispute grava a evidência do lojista e envia a disputa para análise
+func (uc *disputeUseCase) RespondDispute(ctx context.Context, merchantID, id string, req *entity.RespondDisputeRequest) (*entity.DisputeResponse, error) {
+	var dispute *entity.Dispute
+	now := time.Now()
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		dispute, err = uc.disputeRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(dispute)
+		if err := dispute.Respond(merchantID, req.Description, req.Attachments, now); err != nil {
+			return fmt.Errorf("erro ao validar dados da disputa: %w", err)
+		}
+
+		if err := uc.disputeRepo.AddEvidence(ctx, dispute.LastEvidence()); err != nil {
+			return err
+		}
+
+		return uc.saveTransition(ctx, "dispute.respond", before, dispute)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dispute.ToDisputeResponse(now), nil
+}
+
+// ResolveDispute registra a decisão do administrador. Se o pagador vencer, a transação é
+// revertida junto com a decisão: se o lojista não tiver saldo, nada é gravado.
+func (uc *disputeUseCase) ResolveDispute(ctx context.Context, id string, req *entity.ResolveDisputeRequest) (*entity.DisputeResponse, error) {
+	var dispute *entity.Dispute
+	now := time.Now()
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		dispute, err = uc.disputeRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(dispute)
+		if err := dispute.Resolve(req.Winner, req.Note, now); err != nil {
+			return err
+		}
+
+		if dispute.Status == entity.DisputeStatusWonByPayer {
+			// Bloquear a transação antes de consultar os estornos: um estorno do pagamento dividido
+			// bloqueia a mesma linha, então um dos dois espera o outro terminar
+			if _, err := uc.transactionRepo.GetByIDForUpdate(ctx, dispute.TransactionID); err != nil {
+				return err
+			}
+			refunded, err := uc.splitRepo.HasRefundedLeg(ctx, dispute.TransactionID)
+			if err != nil {
+				return err
+			}
+			if refunded {
+				return entity.ErrDisputeSplitRefunded
+			}
+
+			reason := fmt.Sprintf("revertida pela disputa %s", dispute.ID)
+			if _, err := uc.transactions.ReverseTransaction(ctx, dispute.TransactionID, reason); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.saveTransition(ctx, "dispute.resolve", before, dispute); err != nil {
+			return err
+		}
+		return uc.webhooks.Publish(ctx, dispute.MerchantID, entity.WebhookEventDisputeResolved, dispute.ToDisputeResponse(now))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dispute.ToDisputeResponse(now), nil
+}
+
+// EscalateOverdueDisputes move para análise as disputas abertas cujo prazo de resposta terminou e
+// escala as que passaram do prazo de decisão sem ser resolvidas
+func (uc *disputeUseCase) EscalateOverdueDisputes(ctx context.Context) (int, error) {
+	now := time.Now()
+	ids, err := uc.disputeRepo.ListAwaitingResponseBefore(ctx, now, 100)
+	if err != nil {
+		return 0, err
+	}
+	escalated := uc.escalate(ctx, ids, "dispute.escalate", func(dispute *entity.Dispute) bool {
+		return dispute.EscalateOverdue(time.Now())
+	})
+
+	ids, err = uc.disputeRepo.ListPastResolutionDue(ctx, now, 100)
+	if err != nil {
+		return escalated, err
+	}
+	escalated += uc.escalate(ctx, ids, "dispute.escalate_resolution", func(dispute *entity.Dispute) bool {
+		if !dispute.EscalateResolutionOverdue(time.Now()) {
+			return false
+		}
+		slog.WarnContext(ctx, "disputa não decidida no prazo", "dispute_id", dispute.ID, "resolution_due_at", dispute.ResolutionDueAt)
+		return true
+	})
+
+	return escalated, nil
+}
+
+// escalate aplica a mudança de cada disputa com a linha bloqueada e retorna quantas mudaram
+func (uc *disputeUseCase) escalate(ctx context.Context, ids []string, action string, apply func(dispute *entity.Dispute) bool) int {
+	escalated := 0
+	for _, id := range ids {
+		changed := false
+		err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			// Recarregar com bloqueio: a disputa pode ter sido respondida ou decidida nesse meio tempo
+			dispute, err := uc.disputeRepo.GetByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			before := entity.AuditSnapshot(dispute)
+			if !apply(dispute) {
+				return nil
+			}
+
+			changed = true
+			return uc.saveTransition(ctx, action, before, dispute)
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "disputa não escalada", "dispute_id", id, "error", err)
+			continue
+		}
+		if changed {
+			escalated++
+		}
+	}
+
+	return escalated
+}
+
+// saveTransition grava o novo status, a mudança mais recente do histórico e o registro de auditoria
+func (uc *disputeUseCase) saveTransition(ctx context.Context, action string, before map[string]interface{}, dispute *entity.Dispute) error {
+	if err := uc.disputeRepo.Update(ctx, dispute); err != nil {
+		return err
+	}
+
+	if err := uc.disputeRepo.AddEvent(ctx, dispute.LastEvent()); err != nil {
+		return err
+	}
+	return uc.audit.Record(ctx, action, "dispute", dispute.ID, before, dispute)
+}
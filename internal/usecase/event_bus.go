@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+)
+
+// outboxClaimBatch é quantos eventos o relay reserva por vez
+const outboxClaimBatch = 50
+
+// EventHandler processa um evento de domínio. A entrega é pelo menos uma vez: depois de uma falha
+// ou de uma queda do processo o mesmo evento chega de novo, então o assinante deve ser idempotente.
+type EventHandler func(ctx context.Context, event *entity.DomainEvent) error
+
+// EventDispatchMode define quando o assinante roda depois do commit
+type EventDispatchMode int
+
+const (
+	// EventSync roda o assinante logo após o commit, antes de o caso de uso retornar
+	EventSync EventDispatchMode = iota
+	// EventAsync roda o assinante em segundo plano, sem atrasar a resposta
+	EventAsync
+)
+
+// EventPolicy configura o despacho dos eventos do outbox
+type EventPolicy struct {
+	Retry entity.OutboxRetryPolicy
+	// Grace é a espera antes de o relay assumir um evento que o próprio processo ainda deve despachar
+	Grace time.Duration
+	// Lease é por quanto tempo um evento reservado pelo relay fica fora do alcance das outras instâncias
+	Lease time.Duration
+}
+
+// DomainEventRecorder grava os eventos acumulados pelas entidades. Deve ser chamado dentro da
+// transação da mudança: os eventos só são despachados se ela for confirmada.
+type DomainEventRecorder interface {
+	Record(ctx context.Context, sources ...entity.EventSource) error
+}
+
+type eventSubscription struct {
+	name       string
+	mode       EventDispatchMode
+	handler    EventHandler
+	eventTypes map[entity.DomainEventType]bool
+}
+
+func (s *eventSubscription) accepts(eventType entity.DomainEventType) bool {
+	return len(s.eventTypes) == 0 || s.eventTypes[eventType]
+}
+
+// EventBus grava os eventos de domínio no outbox junto com a mudança e os entrega aos assinantes do
+// processo depois do commit. Eventos com assinantes que falharam ficam pendentes para o relay.
+type EventBus struct {
+	txManager repository.TxManager
+	outbox    repository.OutboxRepository
+	policy    EventPolicy
+
+	mu            sync.RWMutex
+	subscriptions []*eventSubscription
+	running       sync.WaitGroup
+}
+
+// NewEventBus cria o barramento de eventos de domínio
+func NewEventBus(txManager repository.TxManager, outbox repository.OutboxRepository, policy EventPolicy) *EventBus {
+	return &EventBus{
+		txManager: txManager,
+		outbox:    outbox,
+		policy:    policy,
+	}
+}
+
+// Subscribe registra um assinante para os tipos de evento informados; sem tipos, recebe todos.
+// O nome identifica o assinante no outbox e não deve mudar entre versões.
+func (b *EventBus) Subscribe(name string, mode EventDispatchMode, handler EventHandler, eventTypes ...entity.DomainEventType) {
+	subscription := &eventSubscription{
+		name:       name,
+		mode:       mode,
+		handler:    handler,
+		eventTypes: make(map[entity.DomainEventType]bool),
+	}
+	for _, eventType := range eventTypes {
+		subscription.eventTypes[eventType] = true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions = append(b.subscriptions, subscription)
+}
+
+// Record grava no outbox os eventos das entidades e agenda o despacho para depois do commit
+func (b *EventBus) Record(ctx context.Context, sources ...entity.EventSource) error {
+	now := time.Now()
+	var events []*entity.OutboxEvent
+	for _, source := range sources {
+		for _, event := range source.PullEvents() {
+			events = append(events, entity.NewOutboxEvent(event, b.policy.Grace, now))
+		}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := b.outbox.Append(ctx, events); err != nil {
+		return err
+	}
+
+	b.txManager.AfterCommit(ctx, func(ctx context.Context) {
+		for _, event := range events {
+			b.dispatch(ctx, event)
+		}
+	})
+	return nil
+}
+
+// dispatch entrega o evento recém-confirmado: os assinantes síncronos agora, os assíncronos em
+// segundo plano. O resultado só é gravado depois que todos rodaram.
+func (b *EventBus) dispatch(ctx context.Context, event *entity.OutboxEvent) {
+	syncSubs, asyncSubs := b.pending(event)
+	errs := b.run(ctx, event, syncSubs)
+
+	if len(asyncSubs) == 0 {
+		b.settle(ctx, event, errs)
+		return
+	}
+
+	b.running.Add(1)
+	go func() {
+		defer b.running.Done()
+		// A requisição que originou o evento pode terminar antes dos assinantes
+		ctx := context.WithoutCancel(ctx)
+		b.settle(ctx, event, append(errs, b.run(ctx, event, asyncSubs)...))
+	}()
+}
+
+// RelayPending despacha de novo os eventos que não foram processados por todos os assinantes,
+// seja por falha de um deles ou porque o processo caiu antes do despacho. Retorna quantos foram
+// concluídos.
+func (b *EventBus) RelayPending(ctx context.Context) (int, error) {
+	events, err := b.outbox.ClaimDue(ctx, time.Now(), b.policy.Lease, outboxClaimBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+	for _, event := range events {
+		syncSubs, asyncSubs := b.pending(event)
+		// No relay todos os assinantes rodam em sequência, inclusive os assíncronos
+		errs := append(b.run(ctx, event, syncSubs), b.run(ctx, event, asyncSubs)...)
+		b.settle(ctx, event, errs)
+		if event.IsDispatched() {
+			dispatched++
+		}
+	}
+
+	return dispatched, nil
+}
+
+// Wait aguarda os assinantes assíncronos em andamento ou o fim do contexto. O que não terminar
+// continua no outbox e é despachado pelo relay.
+func (b *EventBus) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pending separa os assinantes do evento que ainda não o processaram
+func (b *EventBus) pending(event *entity.OutboxEvent) ([]*eventSubscription, []*eventSubscription) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var syncSubs, asyncSubs []*eventSubscription
+	for _, subscription := range b.subscriptions {
+		if !subscription.accepts(event.Type) || event.IsHandledBy(subscription.name) {
+			continue
+		}
+		if subscription.mode == EventAsync {
+			asyncSubs = append(asyncSubs, subscription)
+		} else {
+			syncSubs = append(syncSubs, subscription)
+		}
+	}
+	return syncSubs, asyncSubs
+}
+
+// run chama os assinantes em sequência e marca no evento os que o processaram
+func (b *EventBus) run(ctx context.Context, event *entity.OutboxEvent, subscriptions []*eventSubscription) []error {
+	var errs []error
+	for _, subscription := range subscriptions {
+		if err := b.call(ctx, subscription, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", subscription.name, err))
+			continue
+		}
+		event.MarkHandled(subscription.name)
+	}
+	return errs
+}
+
+// call isola o pânico de um assinante para que ele não derrube quem confirmou a transação
+func (b *EventBus) call(ctx context.Context, subscription *eventSubscription, event *entity.OutboxEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("pânico no assinante: %v", r)
+		}
+	}()
+
+	return subscription.handler(ctx, &event.DomainEvent)
+}
+
+// settle grava o resultado do despacho; falhas deixam o evento pendente para o relay
+func (b *EventBus) settle(ctx context.Context, event *entity.OutboxEvent, errs []error) {
+	dispatchErr := errors.Join(errs...)
+	if dispatchErr != nil {
+		slog.ErrorContext(ctx, "erro ao despachar evento de domínio", "event_id", event.ID, "event_type", event.Type, "error", dispatchErr)
+	}
+
+	event.RecordDispatch(dispatchErr, b.policy.Retry, time.Now())
+	if err := b.outbox.Update(ctx, event); err != nil {
+		slog.ErrorContext(ctx, "erro ao registrar despacho do evento de domínio", "event_id", event.ID, "error", err)
+	}
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/gateway"
+	"payflow-api/internal/repository"
+)
+
+// eventPublishBatch é quantos eventos o relay de publicação envia ao broker por vez
+const eventPublishBatch = 100
+
+// EventPublishRelay publica no broker os eventos gravados no outbox, independente dos assinantes
+// do processo. Um único relay publica por vez entre as instâncias. Os eventos saem por created_at,
+// que é o momento da gravação e não o do commit: um evento de uma transação mais longa pode sair
+// depois de outro gravado mais tarde, então os consumidores não devem contar com a ordem.
+type EventPublishRelay struct {
+	txManager repository.TxManager
+	outbox    repository.OutboxRepository
+	publisher gateway.EventPublisher
+}
+
+// NewEventPublishRelay cria o relay de publicação dos eventos de domínio
+func NewEventPublishRelay(txManager repository.TxManager, outbox repository.OutboxRepository, publisher gateway.EventPublisher) *EventPublishRelay {
+	return &EventPublishRelay{
+		txManager: txManager,
+		outbox:    outbox,
+		publisher: publisher,
+	}
+}
+
+// PublishPending publica os eventos ainda não publicados e retorna quantos o broker confirmou.
+// Cada lote é lido, publicado e marcado na mesma transação: um evento confirmado não é enviado de
+// novo, e se o processo cair antes do commit o reenvio leva o mesmo ID para o broker descartar.
+func (r *EventPublishRelay) PublishPending(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		published, full, err := r.publishBatch(ctx)
+		total += published
+		if err != nil || !full {
+			return total, err
+		}
+	}
+}
+
+// publishBatch publica um lote e informa se ele veio cheio, indicando que pode haver mais eventos
+func (r *EventPublishRelay) publishBatch(ctx context.Context) (int, bool, error) {
+	var published int
+	var full bool
+	var publishErr error
+
+	err := r.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		locked, err := r.outbox.LockPublishing(ctx)
+		if err != nil || !locked {
+			// Outra instância está publicando
+			return err
+		}
+
+		events, err := r.outbox.ListUnpublished(ctx, eventPublishBatch)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		messages := make([]*entity.EventMessage, 0, len(events))
+		for _, event := range events {
+			message, err := entity.NewEventMessage(&event.DomainEvent)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, message)
+		}
+
+		// A falha do broker não desfaz a marcação do que ele já confirmou
+		published, publishErr = r.publisher.Publish(ctx, messages)
+		now := time.Now()
+		for _, event := range events[:published] {
+			if err := r.outbox.MarkPublished(ctx, event.ID, now); err != nil {
+				return err
+			}
+		}
+		full = len(events) == eventPublishBatch
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return published, full && publishErr == nil, publishErr
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// LimitUseCase define as operações de negócio para limites de transferência
+type LimitUseCase interface {
+	GetUserLimits(ctx context.Context, userID string) (*entity.UserLimitsResponse, error)
+	UpdateUserLimits(ctx context.Context, userID string, req *entity.UpdateLimitsRequest) (*entity.UserLimitsResponse, error)
+	ResetUserLimits(ctx context.Context, userID string) error
+	GetDefaultLimits(ctx context.Context, userType entity.UserType) (*entity.DefaultLimitsResponse, error)
+	UpdateDefaultLimits(ctx context.Context, userType entity.UserType, req *entity.UpdateLimitsRequest) (*entity.DefaultLimitsResponse, error)
+
+	// Consume verifica os limites e registra o uso; deve rodar na mesma transação da transferência
+	Consume(ctx context.Context, payer *entity.User, amount decimal.Decimal, at time.Time) error
+	// Restore devolve aos contadores o valor de uma transferência que não foi concluída
+	Restore(ctx context.Context, userID string, amount decimal.Decimal, at time.Time) error
+}
+
+type limitUseCase struct {
+	txManager repository.TxManager
+	limitRepo repository.LimitRepository
+	userRepo  repository.UserRepository
+	policy    entity.LimitPolicy
+	audit     AuditLogger
+}
+
+// NewLimitUseCase cria uma nova instância do use case de limites
+func NewLimitUseCase(txManager repository.TxManager, limitRepo repository.LimitRepository, userRepo repository.UserRepository, policy entity.LimitPolicy, audit AuditLogger) LimitUseCase {
+	return &limitUseCase{
+		txManager: txManager,
+		limitRepo: limitRepo,
+		userRepo:  userRepo,
+		policy:    policy,
+		audit:     audit,
+	}
+}
+
+// GetUserLimits retorna os limites efetivos, padrão, sobrescritos e o uso atual de um usuário
+func (uc *limitUseCase) GetUserLimits(ctx context.Context, userID string) (*entity.UserLimitsResponse, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, err := uc.limitRepo.GetDefaults(ctx, user.UserType)
+	if err != nil {
+		return nil, err
+	}
+
+	override, err := uc.limitRepo.GetOverride(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := uc.limitRepo.GetUsage(ctx, userID, uc.policy.PeriodKeys(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.UserLimitsResponse{
+		UserID:    user.ID,
+		UserType:  user.UserType,
+		Effective: defaults.Apply(override).ToLimitValuesResponse(),
+		Defaults:  defaults.ToLimitValuesResponse(),
+		Override:  override.ToLimitValuesResponse(),
+		Usage:     usage.ToLimitValuesResponse(),
+	}, nil
+}
+
+// UpdateUserLimits grava a sobrescrita de limites de um usuário
+func (uc *limitUseCase) UpdateUserLimits(ctx context.Context, userID string, req *entity.UpdateLimitsRequest) (*entity.UserLimitsResponse, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, err := uc.limitRepo.GetDefaults(ctx, user.UserType)
+	if err != nil {
+		return nil, err
+	}
+
+	override := entity.FromUpdateLimitsRequest(userID, req)
+	effective := defaults.Apply(override)
+	if err := effective.Validate(); err != nil {
+		return nil, fmt.Errorf("erro ao validar dados dos limites: %w", err)
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := uc.limitRepo.GetOverride(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := uc.limitRepo.UpsertOverride(ctx, override); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "limits.update", "user_limits", userID, before.ToLimitValuesResponse(), override.ToLimitValuesResponse())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetUserLimits(ctx, userID)
+}
+
+// ResetUserLimits remove a sobrescrita, voltando aos limites padrão do tipo de usuário
+func (uc *limitUseCase) ResetUserLimits(ctx context.Context, userID string) error {
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := uc.limitRepo.GetOverride(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := uc.limitRepo.DeleteOverride(ctx, userID); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "limits.reset", "user_limits", userID, before.ToLimitValuesResponse(), nil)
+	})
+}
+
+// GetDefaultLimits retorna os limites padrão de um tipo de usuário
+func (uc *limitUseCase) GetDefaultLimits(ctx context.Context, userType entity.UserType) (*entity.DefaultLimitsResponse, error) {
+	if userType != entity.UserTypeCommon && userType != entity.UserTypeMerchant {
+		return nil, entity.ErrInvalidUserType
+	}
+
+	defaults, err := uc.limitRepo.GetDefaults(ctx, userType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.DefaultLimitsResponse{
+		UserType: userType,
+		Limits:   defaults.ToLimitValuesResponse(),
+	}, nil
+}
+
+// UpdateDefaultLimits altera os limites padrão de um tipo de usuário
+func (uc *limitUseCase) UpdateDefaultLimits(ctx context.Context, userType entity.UserType, req *entity.UpdateLimitsRequest) (*entity.DefaultLimitsResponse, error) {
+	if userType != entity.UserTypeCommon && userType != entity.UserTypeMerchant {
+		return nil, entity.ErrInvalidUserType
+	}
+
+	defaults, err := uc.limitRepo.GetDefaults(ctx, userType)
+	if err != nil {
+		return nil, err
+	}
+
+	before := defaults.ToLimitValuesResponse()
+	if err := defaults.ApplyUpdateLimitsRequest(req); err != nil {
+		return nil, fmt.Errorf("erro ao validar dados dos limites: %w", err)
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.limitRepo.UpsertDefaults(ctx, userType, defaults); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "limits.update_defaults", "default_limits", string(userType), before, defaults.ToLimitValuesResponse())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.DefaultLimitsResponse{
+		UserType: userType,
+		Limits:   defaults.ToLimitValuesResponse(),
+	}, nil
+}
+
+// Consume verifica os limites do pagador e soma o valor aos contadores de cada período.
+// O pagador deve estar bloqueado (SELECT ... FOR UPDATE) pela transação em andamento.
+func (uc *limitUseCase) Consume(ctx context.Context, payer *entity.User, amount decimal.Decimal, at time.Time) error {
+	defaults, err := uc.limitRepo.GetDefaults(ctx, payer.UserType)
+	if err != nil {
+		return err
+	}
+
+	override, err := uc.limitRepo.GetOverride(ctx, payer.ID)
+	if err != nil {
+		return err
+	}
+
+	keys := uc.policy.PeriodKeys(at)
+	usage, err := uc.limitRepo.GetUsage(ctx, payer.ID, keys)
+	if err != nil {
+		return err
+	}
+
+	if err := defaults.Apply(override).Check(amount, usage, uc.policy.IsNight(at)); err != nil {
+		return err
+	}
+
+	for period, key := range keys {
+		if err := uc.limitRepo.AddUsage(ctx, payer.ID, period, key, amount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Restore subtrai dos contadores o valor de uma transferência que falhou
+func (uc *limitUseCase) Restore(ctx context.Context, userID string, amount decimal.Decimal, at time.Time) error {
+	for period, key := range uc.policy.PeriodKeys(at) {
+		if err := uc.limitRepo.AddUsage(ctx, userID, period, key, amount.Neg()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
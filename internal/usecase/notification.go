@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/gateway"
+	"payflow-api/internal/repository"
+)
+
+// NewTransferNotificationHandler cria o assinante de transfer.completed que notifica o recebedor.
+// Falhas do serviço de notificação não afetam a transferência: o evento fica no outbox e o relay
+// tenta de novo. Uma notificação já registrada não é repetida.
+func NewTransferNotificationHandler(
+	transactionRepo repository.TransactionRepository,
+	userRepo repository.UserRepository,
+	notifier gateway.Notifier,
+) EventHandler {
+	return func(ctx context.Context, event *entity.DomainEvent) error {
+		var payload entity.TransferCompleted
+		if err := event.Decode(&payload); err != nil {
+			return fmt.Errorf("erro ao ler evento de transferência concluída: %w", err)
+		}
+
+		transaction, err := transactionRepo.GetByID(ctx, payload.TransactionID)
+		if err != nil {
+			return err
+		}
+		if transaction.NotificationSent {
+			return nil
+		}
+
+		payee, err := userRepo.GetByID(ctx, transaction.PayeeID)
+		if err != nil {
+			return err
+		}
+
+		if err := notifier.Notify(ctx, payee, transaction); err != nil {
+			return err
+		}
+
+		return transactionRepo.MarkNotificationSent(ctx, transaction.ID)
+	}
+}
@@ -0,0 +1,448 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+	"payflow-api/pkg/brcode"
+)
+
+// PaymentRequestUseCase define as operações de negócio para cobranças
+type PaymentRequestUseCase interface {
+	CreatePaymentRequest(ctx context.Context, merchantID string, req *entity.CreatePaymentRequestRequest) (*entity.PaymentRequestResponse, error)
+	GetPaymentRequest(ctx context.Context, userID, id string) (*entity.PaymentRequestResponse, error)
+	ListPaymentRequests(ctx context.Context, filters *entity.PaymentRequestFilters) (*entity.ListPaymentRequestsResponse, error)
+	CancelPaymentRequest(ctx context.Context, merchantID, id string) (*entity.PaymentRequestResponse, error)
+	PayPaymentRequest(ctx context.Context, payerID, id string) (*entity.PayPaymentRequestResponse, error)
+	ExpirePaymentRequests(ctx context.Context) (int, error)
+
+	// GetBRCode monta o payload BR Code da cobrança, dinâmico (URL) ou estático (chave Pix do lojista)
+	GetBRCode(ctx context.Context, userID, id string, static bool) (*entity.BRCodeResponse, error)
+	// ParseBRCode interpreta um payload BR Code e identifica a cobrança vinculada, se houver
+	ParseBRCode(ctx context.Context, userID, payload string) (*entity.ParseBRCodeResponse, error)
+	// HandleTransferCompleted é o assinante de transfer.completed que quita a cobrança paga pela
+	// transferência, inclusive quando ela foi retida para análise e liberada depois
+	HandleTransferCompleted(ctx context.Context, event *entity.DomainEvent) error
+}
+
+type paymentRequestUseCase struct {
+	txManager       repository.TxManager
+	requestRepo     repository.PaymentRequestRepository
+	userRepo        repository.UserRepository
+	transactionRepo repository.TransactionRepository
+	pixKeyRepo      repository.PixKeyRepository
+	transactions    TransactionUseCase
+	brCode          entity.BRCodeSettings
+	audit           AuditLogger
+}
+
+// NewPaymentRequestUseCase cria uma nova instância do use case de cobranças
+func NewPaymentRequestUseCase(
+	txManager repository.TxManager,
+	requestRepo repository.PaymentRequestRepository,
+	userRepo repository.UserRepository,
+	transactionRepo repository.TransactionRepository,
+	pixKeyRepo repository.PixKeyRepository,
+	transactions TransactionUseCase,
+	brCode entity.BRCodeSettings,
+	audit AuditLogger,
+) PaymentRequestUseCase {
+	return &paymentRequestUseCase{
+		txManager:       txManager,
+		requestRepo:     requestRepo,
+		userRepo:        userRepo,
+		transactionRepo: transactionRepo,
+		pixKeyRepo:      pixKeyRepo,
+		transactions:    transactions,
+		brCode:          brCode,
+		audit:           audit,
+	}
+}
+
+// CreatePaymentRequest emite uma cobrança do lojista
+func (uc *paymentRequestUseCase) CreatePaymentRequest(ctx context.Context, merchantID string, req *entity.CreatePaymentRequestRequest) (*entity.PaymentRequestResponse, error) {
+	request, err := entity.FromCreatePaymentRequestRequest(req, merchantID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao validar dados da cobrança: %w", err)
+	}
+
+	merchant, err := uc.userRepo.GetByID(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := request.ValidateMerchant(merchant); err != nil {
+		return nil, err
+	}
+
+	if request.PayerID != nil {
+		payer, err := uc.userRepo.GetByID(ctx, *request.PayerID)
+		if err != nil {
+			return nil, err
+		}
+		if payer.IsMerchant() {
+			return nil, entity.ErrMerchantCannotSend
+		}
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.requestRepo.Create(ctx, request); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "payment_request.create", "payment_request", request.ID, nil, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return request.ToPaymentRequestResponse(), nil
+}
+
+// GetPaymentRequest retorna uma cobrança visível ao usuário
+func (uc *paymentRequestUseCase) GetPaymentRequest(ctx context.Context, userID, id string) (*entity.PaymentRequestResponse, error) {
+	request, err := uc.requestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Não revelar cobranças destinadas a outros usuários
+	if !request.IsVisibleTo(userID) {
+		return nil, entity.ErrPaymentRequestNotFound
+	}
+
+	return request.ToPaymentRequestResponse(), nil
+}
+
+// ListPaymentRequests lista as cobranças emitidas por um lojista
+func (uc *paymentRequestUseCase) ListPaymentRequests(ctx context.Context, filters *entity.PaymentRequestFilters) (*entity.ListPaymentRequestsResponse, error) {
+	// Validar paginação
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 20
+	}
+	if filters.Limit > 100 {
+		filters.Limit = 100
+	}
+
+	requests, total, err := uc.requestRepo.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]entity.PaymentRequestResponse, 0, len(requests))
+	for _, request := range requests {
+		responses = append(responses, *request.ToPaymentRequestResponse())
+	}
+
+	totalPages := (total + filters.Limit - 1) / filters.Limit
+
+	return &entity.ListPaymentRequestsResponse{
+		PaymentRequests: responses,
+		Total:           total,
+		Page:            filters.Page,
+		Limit:           filters.Limit,
+		TotalPages:      totalPages,
+	}, nil
+}
+
+// CancelPaymentRequest cancela uma cobrança aberta do lojista
+func (uc *paymentRequestUseCase) CancelPaymentRequest(ctx context.Context, merchantID, id string) (*entity.PaymentRequestResponse, error) {
+	var request *entity.PaymentRequest
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		request, err = uc.requestRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if request.MerchantID != merchantID {
+			return entity.ErrPaymentRequestNotFound
+		}
+
+		inProgress, err := uc.attemptInProgress(ctx, request)
+		if err != nil {
+			return err
+		}
+		if inProgress != nil {
+			return entity.ErrPaymentRequestInProgress
+		}
+
+		before := entity.AuditSnapshot(request)
+		if err := request.Cancel(); err != nil {
+			return err
+		}
+
+		if err := uc.requestRepo.Update(ctx, request); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "payment_request.cancel", "payment_request", request.ID, before, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return request.ToPaymentRequestResponse(), nil
+}
+
+// PayPaymentRequest paga a cobrança criando a transação correspondente.
+// A operação é idempotente: repetir a chamada do mesmo pagador devolve a tentativa
+// em andamento ou a transação que quitou a cobrança, sem cobrar novamente.
+func (uc *paymentRequestUseCase) PayPaymentRequest(ctx context.Context, payerID, id string) (*entity.PayPaymentRequestResponse, error) {
+	var request *entity.PaymentRequest
+	var transaction *entity.Transaction
+	var existing bool
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		request, err = uc.requestRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if !request.IsVisibleTo(payerID) {
+			return entity.ErrPaymentRequestNotFound
+		}
+
+		previous, err := uc.attemptInProgress(ctx, request)
+		if err != nil {
+			return err
+		}
+		if previous != nil {
+			if previous.PayerID != payerID {
+				if request.Status == entity.PaymentRequestStatusPaid {
+					return entity.ErrPaymentRequestAlreadyPaid
+				}
+				return entity.ErrPaymentRequestInProgress
+			}
+
+			transaction, existing = previous, true
+			return uc.settle(ctx, request, transaction)
+		}
+
+		if err := request.CanBePaidBy(payerID, time.Now()); err != nil {
+			return err
+		}
+
+		payer, err := uc.userRepo.GetByID(ctx, payerID)
+		if err != nil {
+			return err
+		}
+		if payer.IsMerchant() {
+			return entity.ErrMerchantCannotSend
+		}
+
+		transaction, err = entity.NewTransaction(payerID, request.MerchantID, request.Amount)
+		if err != nil {
+			return err
+		}
+
+		if err := uc.transactionRepo.Create(ctx, transaction); err != nil {
+			return err
+		}
+		if err := uc.audit.Record(ctx, "transaction.create", "transaction", transaction.ID, nil, transaction); err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(request)
+		request.AttachTransaction(transaction.ID)
+		if err := uc.requestRepo.Update(ctx, request); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "payment_request.pay", "payment_request", request.ID, before, request)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !existing {
+		// O autorizador é um serviço externo: executar fora da transação que bloqueia a cobrança
+		if err := uc.transactions.ExecutePending(ctx, transaction); err != nil {
+			return nil, err
+		}
+
+		err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			request, err = uc.requestRepo.GetByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			return uc.settle(ctx, request, transaction)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &entity.PayPaymentRequestResponse{
+		PaymentRequest: *request.ToPaymentRequestResponse(),
+		Transaction:    *transaction.ToGetTransactionResponse(),
+	}, nil
+}
+
+// ExpirePaymentRequests marca como expiradas as cobranças abertas vencidas
+func (uc *paymentRequestUseCase) ExpirePaymentRequests(ctx context.Context) (int, error) {
+	return uc.requestRepo.ExpireOverdue(ctx, time.Now())
+}
+
+// GetBRCode monta o payload BR Code da cobrança para pagamento via QR Code
+func (uc *paymentRequestUseCase) GetBRCode(ctx context.Context, userID, id string, static bool) (*entity.BRCodeResponse, error) {
+	request, err := uc.requestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !request.IsVisibleTo(userID) {
+		return nil, entity.ErrPaymentRequestNotFound
+	}
+
+	if request.Status != entity.PaymentRequestStatusOpen {
+		return nil, entity.ErrPaymentRequestNotOpen
+	}
+
+	merchant, err := uc.userRepo.GetByID(ctx, request.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := brcode.Payload{
+		Dynamic:      !static,
+		Amount:       request.Amount.StringFixed(2),
+		MerchantName: merchant.FullName,
+		MerchantCity: uc.brCode.MerchantCity,
+		TxID:         request.TxID,
+	}
+
+	codeType := "dynamic"
+	if static {
+		codeType = "static"
+		keys, err := uc.pixKeyRepo.ListByUser(ctx, merchant.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) == 0 {
+			return nil, entity.ErrMerchantWithoutPixKey
+		}
+		payload.Key = keys[0].KeyValue
+		payload.Description = request.Description
+	} else {
+		payload.URL = uc.brCode.PaymentRequestURL(request.TxID)
+	}
+
+	encoded, err := brcode.Build(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.BRCodeResponse{
+		PaymentRequestID: request.ID,
+		Type:             codeType,
+		TxID:             request.TxID,
+		Payload:          encoded,
+	}, nil
+}
+
+// ParseBRCode interpreta o payload e localiza a cobrança pelo txid ou pela URL dinâmica
+func (uc *paymentRequestUseCase) ParseBRCode(ctx context.Context, userID, payload string) (*entity.ParseBRCodeResponse, error) {
+	parsed, err := brcode.Parse(payload)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao validar dados do BR Code: %w", err)
+	}
+
+	response := &entity.ParseBRCodeResponse{
+		Type:         "static",
+		Key:          parsed.Key,
+		URL:          parsed.URL,
+		Amount:       parsed.Amount,
+		MerchantName: parsed.MerchantName,
+		MerchantCity: parsed.MerchantCity,
+		TxID:         parsed.TxID,
+	}
+
+	txID := parsed.TxID
+	if parsed.Dynamic {
+		response.Type = "dynamic"
+		txID, _ = uc.brCode.TxIDFromURL(parsed.URL)
+	}
+
+	if txID == "" || txID == brcode.DynamicTxID {
+		return response, nil
+	}
+
+	request, err := uc.requestRepo.GetByTxID(ctx, txID)
+	if err != nil && !errors.Is(err, entity.ErrPaymentRequestNotFound) {
+		return nil, err
+	}
+	if request != nil && request.IsVisibleTo(userID) {
+		response.TxID = request.TxID
+		response.PaymentRequest = request.ToPaymentRequestResponse()
+	}
+
+	return response, nil
+}
+
+func (uc *paymentRequestUseCase) HandleTransferCompleted(ctx context.Context, event *entity.DomainEvent) error {
+	var payload entity.TransferCompleted
+	if err := event.Decode(&payload); err != nil {
+		return fmt.Errorf("erro ao ler evento de transferência concluída: %w", err)
+	}
+
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		request, err := uc.requestRepo.GetByTransactionIDForUpdate(ctx, payload.TransactionID)
+		if errors.Is(err, entity.ErrPaymentRequestNotFound) {
+			// Transferência comum, sem cobrança vinculada
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		transaction, err := uc.transactionRepo.GetByID(ctx, payload.TransactionID)
+		if err != nil {
+			return err
+		}
+
+		// settle ignora cobranças já quitadas: o evento pode ser entregue mais de uma vez
+		return uc.settle(ctx, request, transaction)
+	})
+}
+
+// attemptInProgress retorna a tentativa de pagamento vinculada que ainda vale (pendente, autorizada ou concluída)
+func (uc *paymentRequestUseCase) attemptInProgress(ctx context.Context, request *entity.PaymentRequest) (*entity.Transaction, error) {
+	if request.TransactionID == nil {
+		return nil, nil
+	}
+
+	transaction, err := uc.transactionRepo.GetByID(ctx, *request.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.IsFailed() || transaction.IsCancelled() {
+		return nil, nil
+	}
+
+	return transaction, nil
+}
+
+// settle marca a cobrança como paga quando a transação vinculada já foi concluída
+func (uc *paymentRequestUseCase) settle(ctx context.Context, request *entity.PaymentRequest, transaction *entity.Transaction) error {
+	if !transaction.IsCompleted() || request.Status != entity.PaymentRequestStatusOpen {
+		return nil
+	}
+
+	before := entity.AuditSnapshot(request)
+	if err := request.MarkPaid(transaction); err != nil {
+		return err
+	}
+
+	if err := uc.requestRepo.Update(ctx, request); err != nil {
+		return err
+	}
+	return uc.audit.Record(ctx, "payment_request.settle", "payment_request", request.ID, before, request)
+}
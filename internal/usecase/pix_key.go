@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+)
+
+// PixKeyUseCase define as operações de negócio para o diretório de chaves Pix
+type PixKeyUseCase interface {
+	RegisterKey(ctx context.Context, userID string, req *entity.CreatePixKeyRequest) (*entity.PixKeyResponse, error)
+	ListKeys(ctx context.Context, userID string) (*entity.ListPixKeysResponse, error)
+	DeleteKey(ctx context.Context, userID, id string) error
+	LookupKey(ctx context.Context, key string) (*entity.PixKeyLookupResponse, error)
+}
+
+type pixKeyUseCase struct {
+	txManager  repository.TxManager
+	pixKeyRepo repository.PixKeyRepository
+	userRepo   repository.UserRepository
+	audit      AuditLogger
+}
+
+// NewPixKeyUseCase cria uma nova instância do use case de chaves Pix
+func NewPixKeyUseCase(txManager repository.TxManager, pixKeyRepo repository.PixKeyRepository, userRepo repository.UserRepository, audit AuditLogger) PixKeyUseCase {
+	return &pixKeyUseCase{
+		txManager:  txManager,
+		pixKeyRepo: pixKeyRepo,
+		userRepo:   userRepo,
+		audit:      audit,
+	}
+}
+
+// RegisterKey cadastra uma chave do usuário respeitando o limite do tipo de conta
+func (uc *pixKeyUseCase) RegisterKey(ctx context.Context, userID string, req *entity.CreatePixKeyRequest) (*entity.PixKeyResponse, error) {
+	var key *entity.PixKey
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Bloquear o usuário para que cadastros simultâneos não ultrapassem o limite
+		owner, err := uc.userRepo.GetByIDForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		key, err = entity.NewPixKey(owner, req.KeyType, req.Key)
+		if err != nil {
+			return err
+		}
+
+		count, err := uc.pixKeyRepo.CountByUser(ctx, owner.ID)
+		if err != nil {
+			return err
+		}
+		if count >= entity.MaxPixKeys(owner.UserType) {
+			return entity.ErrPixKeyLimitReached
+		}
+
+		if err := uc.pixKeyRepo.Create(ctx, key); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "pix_key.register", "pix_key", key.ID, nil, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return key.ToPixKeyResponse(), nil
+}
+
+// ListKeys lista as chaves do usuário
+func (uc *pixKeyUseCase) ListKeys(ctx context.Context, userID string) (*entity.ListPixKeysResponse, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := uc.pixKeyRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]entity.PixKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, *key.ToPixKeyResponse())
+	}
+
+	return &entity.ListPixKeysResponse{
+		Keys:    responses,
+		Total:   len(responses),
+		MaxKeys: entity.MaxPixKeys(user.UserType),
+	}, nil
+}
+
+// DeleteKey remove uma chave do usuário
+func (uc *pixKeyUseCase) DeleteKey(ctx context.Context, userID, id string) error {
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		keys, err := uc.pixKeyRepo.ListByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		var before *entity.PixKey
+		for _, key := range keys {
+			if key.ID == id {
+				before = key
+			}
+		}
+
+		if err := uc.pixKeyRepo.Delete(ctx, userID, id); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "pix_key.delete", "pix_key", id, before, nil)
+	})
+}
+
+// LookupKey consulta o titular de uma chave, com nome e documento mascarados
+func (uc *pixKeyUseCase) LookupKey(ctx context.Context, value string) (*entity.PixKeyLookupResponse, error) {
+	key, owner, err := resolvePixKey(ctx, uc.pixKeyRepo, uc.userRepo, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.ToPixKeyLookupResponse(owner), nil
+}
+
+// resolvePixKey localiza a chave e o usuário titular
+func resolvePixKey(ctx context.Context, pixKeyRepo repository.PixKeyRepository, userRepo repository.UserRepository, value string) (*entity.PixKey, *entity.User, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil, entity.ErrInvalidPixKey
+	}
+
+	key, err := pixKeyRepo.GetByValue(ctx, entity.NormalizePixKey(value))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owner, err := userRepo.GetByID(ctx, key.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, owner, nil
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// PricingUseCase define as operações de negócio para tarifas
+type PricingUseCase interface {
+	CreatePlan(ctx context.Context, req *entity.CreatePricingPlanRequest) (*entity.PricingPlan, error)
+	ListPlans(ctx context.Context) ([]*entity.PricingPlan, error)
+	AssignPlan(ctx context.Context, userID string, req *entity.AssignPricingPlanRequest) error
+	QuoteFee(ctx context.Context, payeeID string, amount decimal.Decimal) (*entity.FeeQuoteResponse, error)
+	GetRevenueAccount(ctx context.Context) (*entity.PlatformAccountResponse, error)
+
+	// ApplyFee calcula a tarifa do recebedor e registra na transação
+	ApplyFee(ctx context.Context, transaction *entity.Transaction, payee *entity.User) error
+	// PostFee lança a tarifa na conta de receita; deve rodar na mesma transação que credita o recebedor
+	PostFee(ctx context.Context, transaction *entity.Transaction) error
+	// ReverseFee estorna da conta de receita a tarifa de uma transação revertida
+	ReverseFee(ctx context.Context, transaction *entity.Transaction) error
+}
+
+type pricingUseCase struct {
+	txManager   repository.TxManager
+	pricingRepo repository.PricingRepository
+	userRepo    repository.UserRepository
+	audit       AuditLogger
+}
+
+// NewPricingUseCase cria uma nova instância do use case de tarifas
+func NewPricingUseCase(txManager repository.TxManager, pricingRepo repository.PricingRepository, userRepo repository.UserRepository, audit AuditLogger) PricingUseCase {
+	return &pricingUseCase{
+		txManager:   txManager,
+		pricingRepo: pricingRepo,
+		userRepo:    userRepo,
+		audit:       audit,
+	}
+}
+
+// CreatePlan cadastra um novo plano de tarifas
+func (uc *pricingUseCase) CreatePlan(ctx context.Context, req *entity.CreatePricingPlanRequest) (*entity.PricingPlan, error) {
+	plan, err := entity.FromCreatePricingPlanRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao validar dados do plano: %w", err)
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.pricingRepo.CreatePlan(ctx, plan); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "pricing_plan.create", "pricing_plan", plan.ID, nil, plan)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// ListPlans lista os planos de tarifas cadastrados
+func (uc *pricingUseCase) ListPlans(ctx context.Context) ([]*entity.PricingPlan, error) {
+	return uc.pricingRepo.ListPlans(ctx)
+}
+
+// AssignPlan vincula um plano específico a um usuário
+func (uc *pricingUseCase) AssignPlan(ctx context.Context, userID string, req *entity.AssignPricingPlanRequest) error {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := uc.pricingRepo.GetPlan(ctx, req.PricingPlanID); err != nil {
+		return err
+	}
+
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// O plano anterior pode ser o padrão do tipo de usuário
+		previous, err := uc.pricingRepo.GetPlanForUser(ctx, user)
+		if err != nil {
+			return err
+		}
+		before := map[string]interface{}{"pricing_plan_id": nil}
+		if previous != nil {
+			before["pricing_plan_id"] = previous.ID
+		}
+
+		if err := uc.pricingRepo.AssignPlan(ctx, userID, req.PricingPlanID); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "pricing_plan.assign", "user", userID, before, map[string]interface{}{"pricing_plan_id": req.PricingPlanID})
+	})
+}
+
+// QuoteFee simula a tarifa que seria cobrada do recebedor para um valor
+func (uc *pricingUseCase) QuoteFee(ctx context.Context, payeeID string, amount decimal.Decimal) (*entity.FeeQuoteResponse, error) {
+	if amount.LessThanOrEqual(decimal.Zero) {
+		return nil, entity.ErrInvalidAmount
+	}
+
+	payee, err := uc.userRepo.GetByID(ctx, payeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, planID, err := uc.calculate(ctx, payee, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.FeeQuoteResponse{
+		PayeeID:       payee.ID,
+		GrossAmount:   amount.StringFixed(2),
+		FeeAmount:     fee.StringFixed(2),
+		NetAmount:     amount.Sub(fee).StringFixed(2),
+		PricingPlanID: planID,
+	}, nil
+}
+
+// GetRevenueAccount retorna o saldo acumulado de tarifas da plataforma
+func (uc *pricingUseCase) GetRevenueAccount(ctx context.Context) (*entity.PlatformAccountResponse, error) {
+	account, err := uc.pricingRepo.GetAccount(ctx, entity.RevenueAccountCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return account.ToPlatformAccountResponse(), nil
+}
+
+// ApplyFee calcula a tarifa do plano do recebedor e registra na transação
+func (uc *pricingUseCase) ApplyFee(ctx context.Context, transaction *entity.Transaction, payee *entity.User) error {
+	fee, planID, err := uc.calculate(ctx, payee, transaction.Amount)
+	if err != nil {
+		return err
+	}
+
+	return transaction.ApplyFee(fee, planID)
+}
+
+// PostFee lança a tarifa da transação na conta de receita da plataforma
+func (uc *pricingUseCase) PostFee(ctx context.Context, transaction *entity.Transaction) error {
+	if transaction.FeeAmount.IsZero() {
+		return nil
+	}
+
+	return uc.pricingRepo.PostFee(ctx, entity.NewFeeEntry(transaction))
+}
+
+// ReverseFee lança o estorno da tarifa da transação na conta de receita da plataforma
+func (uc *pricingUseCase) ReverseFee(ctx context.Context, transaction *entity.Transaction) error {
+	if transaction.FeeAmount.IsZero() {
+		return nil
+	}
+
+	return uc.pricingRepo.PostFee(ctx, entity.NewFeeReversalEntry(transaction))
+}
+
+func (uc *pricingUseCase) calculate(ctx context.Context, payee *entity.User, amount decimal.Decimal) (decimal.Decimal, *string, error) {
+	plan, err := uc.pricingRepo.GetPlanForUser(ctx, payee)
+	if err != nil {
+		return decimal.Zero, nil, err
+	}
+
+	if plan == nil {
+		return decimal.Zero, nil, nil
+	}
+
+	planID := plan.ID
+	return plan.CalculateFee(amount), &planID, nil
+}
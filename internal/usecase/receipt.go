@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+)
+
+// ReceiptUseCase define a emissão e a conferência dos comprovantes de transferências
+type ReceiptUseCase interface {
+	// GetReceipt retorna o comprovante ao pagador ou ao recebedor, emitindo-o no primeiro pedido
+	GetReceipt(ctx context.Context, userID, transactionID string) (*entity.Receipt, error)
+	// VerifyByCode confere o comprovante registrado com o código de autenticação
+	VerifyByCode(ctx context.Context, code string) (*entity.ReceiptVerificationResponse, error)
+	// Verify confere a assinatura de um comprovante em JSON e se ele consta nos registros
+	Verify(ctx context.Context, req *entity.VerifyReceiptRequest) (*entity.ReceiptVerificationResponse, error)
+	// PublicKey retorna a chave pública atual e as anteriores para conferência fora da API
+	PublicKey() *entity.ReceiptPublicKeyResponse
+}
+
+type receiptUseCase struct {
+	txManager       repository.TxManager
+	receiptRepo     repository.ReceiptRepository
+	transactionRepo repository.TransactionRepository
+	userRepo        repository.UserRepository
+	signer          *entity.ReceiptSigner
+	audit           AuditLogger
+}
+
+// NewReceiptUseCase cria uma nova instância do use case de comprovantes
+func NewReceiptUseCase(
+	txManager repository.TxManager,
+	receiptRepo repository.ReceiptRepository,
+	transactionRepo repository.TransactionRepository,
+	userRepo repository.UserRepository,
+	signer *entity.ReceiptSigner,
+	audit AuditLogger,
+) ReceiptUseCase {
+	return &receiptUseCase{
+		txManager:       txManager,
+		receiptRepo:     receiptRepo,
+		transactionRepo: transactionRepo,
+		userRepo:        userRepo,
+		signer:          signer,
+		audit:           audit,
+	}
+}
+
+func (uc *receiptUseCase) GetReceipt(ctx context.Context, userID, transactionID string) (*entity.Receipt, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Não revelar transações de outros usuários
+	if !transaction.IsVisibleTo(userID) {
+		return nil, entity.ErrTransactionNotFound
+	}
+
+	receipt, err := uc.receiptRepo.GetByTransactionID(ctx, transaction.ID)
+	if err == nil {
+		return receipt, nil
+	}
+	if !errors.Is(err, entity.ErrReceiptNotFound) {
+		return nil, err
+	}
+
+	return uc.issue(ctx, transaction)
+}
+
+// issue assina e grava o comprovante com os dados cadastrais atuais de pagador e recebedor
+func (uc *receiptUseCase) issue(ctx context.Context, transaction *entity.Transaction) (*entity.Receipt, error) {
+	payer, err := uc.userRepo.GetByID(ctx, transaction.PayerID)
+	if err != nil {
+		return nil, err
+	}
+	payee, err := uc.userRepo.GetByID(ctx, transaction.PayeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := entity.NewReceiptPayload(transaction, payer, payee, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	issued, err := uc.signer.Issue(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var receipt *entity.Receipt
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		receipt, err = uc.receiptRepo.Create(ctx, issued)
+		if err != nil {
+			return err
+		}
+
+		// Outro pedido emitiu o comprovante antes
+		if receipt.AuthenticationCode != issued.AuthenticationCode {
+			return nil
+		}
+		return uc.audit.Record(ctx, "receipt.issue", "transaction_receipt", receipt.TransactionID, nil, receipt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+func (uc *receiptUseCase) VerifyByCode(ctx context.Context, code string) (*entity.ReceiptVerificationResponse, error) {
+	receipt, err := uc.receiptRepo.GetByAuthenticationCode(ctx, entity.NormalizeAuthenticationCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	if !uc.signer.Verify(receipt.KeyID, receipt.Payload, receipt.Signature) {
+		return &entity.ReceiptVerificationResponse{Valid: false, Reason: "assinatura do comprovante registrado não confere com a chave que o emitiu"}, nil
+	}
+
+	return uc.verified(ctx, receipt)
+}
+
+func (uc *receiptUseCase) Verify(ctx context.Context, req *entity.VerifyReceiptRequest) (*entity.ReceiptVerificationResponse, error) {
+	payload, err := base64.StdEncoding.DecodeString(req.Payload)
+	if err != nil {
+		return &entity.ReceiptVerificationResponse{Valid: false, Reason: "conteúdo do comprovante não está em base64"}, nil
+	}
+	signature, err := base64.StdEncoding.DecodeString(req.Signature)
+	if err != nil {
+		return &entity.ReceiptVerificationResponse{Valid: false, Reason: "assinatura não está em base64"}, nil
+	}
+
+	if !uc.signer.KnowsKey(req.KeyID) {
+		return &entity.ReceiptVerificationResponse{Valid: false, Reason: "chave de assinatura desconhecida"}, nil
+	}
+	if !uc.signer.Verify(req.KeyID, payload, signature) {
+		return &entity.ReceiptVerificationResponse{Valid: false, Reason: "assinatura inválida"}, nil
+	}
+
+	content := &entity.Receipt{Payload: payload}
+	decoded, err := content.Content()
+	if err != nil {
+		return &entity.ReceiptVerificationResponse{Valid: false, Reason: "conteúdo do comprovante ilegível"}, nil
+	}
+
+	receipt, err := uc.receiptRepo.GetByAuthenticationCode(ctx, decoded.AuthenticationCode)
+	if errors.Is(err, entity.ErrReceiptNotFound) {
+		return &entity.ReceiptVerificationResponse{Valid: false, Reason: "comprovante não consta nos registros"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(receipt.Payload, payload) {
+		return &entity.ReceiptVerificationResponse{Valid: false, Reason: "comprovante não confere com o registrado"}, nil
+	}
+
+	return uc.verified(ctx, receipt)
+}
+
+// verified monta a resposta de um comprovante autêntico com o status atual da transação,
+// já que uma transferência pode ter sido estornada depois da emissão. As rotas são públicas, então
+// a resposta não repete o conteúdo: quem confere já tem o comprovante em mãos.
+func (uc *receiptUseCase) verified(ctx context.Context, receipt *entity.Receipt) (*entity.ReceiptVerificationResponse, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, receipt.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.ReceiptVerificationResponse{
+		Valid:             true,
+		TransactionStatus: transaction.Status,
+		StatusDescription: transaction.GetStatusDescription(),
+	}, nil
+}
+
+func (uc *receiptUseCase) PublicKey() *entity.ReceiptPublicKeyResponse {
+	keys := uc.signer.PublicKeys()
+	current := keys[0]
+	current.PreviousKeys = keys[1:]
+	return &current
+}
@@ -0,0 +1,307 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+)
+
+// maxRunsPerCycle limita quantas ocorrências são geradas a cada execução do runner
+const maxRunsPerCycle = 100
+
+// recurringRetryDelay é quanto um mandato cuja ocorrência falhou espera pela próxima tentativa
+const recurringRetryDelay = time.Hour
+
+// RecurringUseCase define as operações de negócio para pagamentos recorrentes
+type RecurringUseCase interface {
+	CreateRecurringPayment(ctx context.Context, payerID string, req *entity.CreateRecurringPaymentRequest) (*entity.RecurringPaymentResponse, error)
+	GetRecurringPayment(ctx context.Context, payerID, id string) (*entity.RecurringPaymentResponse, error)
+	ListRecurringPayments(ctx context.Context, payerID string) (*entity.ListRecurringPaymentsResponse, error)
+	PauseRecurringPayment(ctx context.Context, payerID, id string) (*entity.RecurringPaymentResponse, error)
+	ResumeRecurringPayment(ctx context.Context, payerID, id string) (*entity.RecurringPaymentResponse, error)
+	CancelRecurringPayment(ctx context.Context, payerID, id string) (*entity.RecurringPaymentResponse, error)
+	ListRuns(ctx context.Context, payerID, id string) (*entity.ListMandateRunsResponse, error)
+
+	// GenerateDueRuns gera as transferências agendadas das ocorrências vencidas
+	GenerateDueRuns(ctx context.Context) (int, error)
+}
+
+type recurringUseCase struct {
+	txManager       repository.TxManager
+	recurringRepo   repository.RecurringRepository
+	userRepo        repository.UserRepository
+	transactionRepo repository.TransactionRepository
+	audit           AuditLogger
+}
+
+// NewRecurringUseCase cria uma nova instância do use case de pagamentos recorrentes
+func NewRecurringUseCase(
+	txManager repository.TxManager,
+	recurringRepo repository.RecurringRepository,
+	userRepo repository.UserRepository,
+	transactionRepo repository.TransactionRepository,
+	audit AuditLogger,
+) RecurringUseCase {
+	return &recurringUseCase{
+		txManager:       txManager,
+		recurringRepo:   recurringRepo,
+		userRepo:        userRepo,
+		transactionRepo: transactionRepo,
+		audit:           audit,
+	}
+}
+
+// CreateRecurringPayment cadastra um mandato de pagamento recorrente para um lojista
+func (uc *recurringUseCase) CreateRecurringPayment(ctx context.Context, payerID string, req *entity.CreateRecurringPaymentRequest) (*entity.RecurringPaymentResponse, error) {
+	mandate, err := entity.FromCreateRecurringPaymentRequest(req, payerID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao validar dados do pagamento recorrente: %w", err)
+	}
+
+	payer, err := uc.userRepo.GetByID(ctx, payerID)
+	if err != nil {
+		return nil, err
+	}
+
+	payee, err := uc.userRepo.GetByID(ctx, mandate.PayeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mandate.ValidateParties(payer, payee); err != nil {
+		return nil, err
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.recurringRepo.Create(ctx, mandate); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "recurring_payment.create", "recurring_payment", mandate.ID, nil, mandate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mandate.ToRecurringPaymentResponse(), nil
+}
+
+// GetRecurringPayment retorna um mandato do pagador
+func (uc *recurringUseCase) GetRecurringPayment(ctx context.Context, payerID, id string) (*entity.RecurringPaymentResponse, error) {
+	mandate, err := uc.getOwned(ctx, payerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return mandate.ToRecurringPaymentResponse(), nil
+}
+
+// ListRecurringPayments lista os mandatos do pagador
+func (uc *recurringUseCase) ListRecurringPayments(ctx context.Context, payerID string) (*entity.ListRecurringPaymentsResponse, error) {
+	mandates, err := uc.recurringRepo.ListByPayer(ctx, payerID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]entity.RecurringPaymentResponse, len(mandates))
+	for i, mandate := range mandates {
+		responses[i] = *mandate.ToRecurringPaymentResponse()
+	}
+
+	return &entity.ListRecurringPaymentsResponse{
+		RecurringPayments: responses,
+		Total:             len(responses),
+	}, nil
+}
+
+// PauseRecurringPayment suspende as próximas cobranças do mandato
+func (uc *recurringUseCase) PauseRecurringPayment(ctx context.Context, payerID, id string) (*entity.RecurringPaymentResponse, error) {
+	return uc.transition(ctx, payerID, id, "recurring_payment.pause", (*entity.RecurringMandate).Pause)
+}
+
+// ResumeRecurringPayment reativa um mandato pausado a partir da próxima ocorrência
+func (uc *recurringUseCase) ResumeRecurringPayment(ctx context.Context, payerID, id string) (*entity.RecurringPaymentResponse, error) {
+	return uc.transition(ctx, payerID, id, "recurring_payment.resume", (*entity.RecurringMandate).Resume)
+}
+
+// CancelRecurringPayment encerra definitivamente o mandato
+func (uc *recurringUseCase) CancelRecurringPayment(ctx context.Context, payerID, id string) (*entity.RecurringPaymentResponse, error) {
+	return uc.transition(ctx, payerID, id, "recurring_payment.cancel", (*entity.RecurringMandate).Cancel)
+}
+
+// ListRuns retorna o histórico de execuções do mandato com o status das transferências geradas
+func (uc *recurringUseCase) ListRuns(ctx context.Context, payerID, id string) (*entity.ListMandateRunsResponse, error) {
+	if _, err := uc.getOwned(ctx, payerID, id); err != nil {
+		return nil, err
+	}
+
+	runs, err := uc.recurringRepo.ListRuns(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.ListMandateRunsResponse{
+		Runs:  runs,
+		Total: len(runs),
+	}, nil
+}
+
+// GenerateDueRuns cria uma transferência agendada para cada ocorrência vencida.
+// Cada ocorrência é gerada em uma transação própria; o período registrado em
+// recurring_mandate_runs impede que um reinício cobre o mesmo período duas vezes.
+// As transferências geradas seguem o fluxo normal do agendador. Um mandato cuja ocorrência falha
+// é adiado por recurringRetryDelay e os demais continuam sendo gerados.
+func (uc *recurringUseCase) GenerateDueRuns(ctx context.Context) (int, error) {
+	generated := 0
+
+	for i := 0; i < maxRunsPerCycle; i++ {
+		var found bool
+		var mandateID string
+
+		err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			mandate, err := uc.recurringRepo.LockNextDue(ctx, time.Now())
+			if err != nil || mandate == nil {
+				return err
+			}
+			found = true
+			mandateID = mandate.ID
+
+			created, err := uc.generateRun(ctx, mandate)
+			if err != nil {
+				return err
+			}
+			if created {
+				generated++
+			}
+			return nil
+		})
+		if err != nil && mandateID == "" {
+			return generated, err
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "ocorrência do pagamento recorrente não gerada", "mandate_id", mandateID, "error", err)
+			if err := uc.deferRun(ctx, mandateID); err != nil {
+				return generated, err
+			}
+			continue
+		}
+
+		if !found {
+			break
+		}
+	}
+
+	return generated, nil
+}
+
+// deferRun adia a próxima tentativa do mandato, mantendo a ocorrência que falhou
+func (uc *recurringUseCase) deferRun(ctx context.Context, id string) error {
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		mandate, err := uc.recurringRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(mandate)
+		mandate.DeferRun(time.Now().Add(recurringRetryDelay))
+		if err := uc.recurringRepo.Update(ctx, mandate); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "recurring_payment.defer", "recurring_payment", mandate.ID, before, mandate)
+	})
+}
+
+// generateRun registra a ocorrência atual do mandato e avança a agenda; retorna false se o período já foi pago
+func (uc *recurringUseCase) generateRun(ctx context.Context, mandate *entity.RecurringMandate) (bool, error) {
+	occurrence := *mandate.NextRunAt
+
+	exists, err := uc.recurringRepo.RunExists(ctx, mandate.ID, mandate.PeriodKey(occurrence))
+	if err != nil {
+		return false, err
+	}
+
+	if exists {
+		// Período já executado (ex.: reinício após a gravação da execução): apenas avançar a agenda
+		if err := mandate.SkipOccurrence(occurrence); err != nil {
+			return false, err
+		}
+		return false, uc.recurringRepo.Update(ctx, mandate)
+	}
+
+	transaction, err := entity.NewTransaction(mandate.PayerID, mandate.PayeeID, mandate.Amount)
+	if err != nil {
+		return false, err
+	}
+	if err := transaction.ScheduleOccurrence(occurrence); err != nil {
+		return false, err
+	}
+
+	if err := uc.transactionRepo.Create(ctx, transaction); err != nil {
+		return false, err
+	}
+	if err := uc.audit.Record(ctx, "transaction.schedule", "transaction", transaction.ID, nil, transaction); err != nil {
+		return false, err
+	}
+
+	if err := uc.recurringRepo.CreateRun(ctx, entity.NewMandateRun(mandate, occurrence, transaction.ID)); err != nil {
+		return false, err
+	}
+
+	if err := mandate.RecordRun(occurrence); err != nil {
+		return false, err
+	}
+
+	if err := uc.recurringRepo.Update(ctx, mandate); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (uc *recurringUseCase) transition(ctx context.Context, payerID, id, action string, change func(*entity.RecurringMandate) error) (*entity.RecurringPaymentResponse, error) {
+	var mandate *entity.RecurringMandate
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		mandate, err = uc.recurringRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Não revelar mandatos de outros usuários
+		if mandate.PayerID != payerID {
+			return entity.ErrMandateNotFound
+		}
+
+		before := entity.AuditSnapshot(mandate)
+		if err := change(mandate); err != nil {
+			return err
+		}
+
+		if err := uc.recurringRepo.Update(ctx, mandate); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, action, "recurring_payment", mandate.ID, before, mandate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mandate.ToRecurringPaymentResponse(), nil
+}
+
+func (uc *recurringUseCase) getOwned(ctx context.Context, payerID, id string) (*entity.RecurringMandate, error) {
+	mandate, err := uc.recurringRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if mandate.PayerID != payerID {
+		return nil, entity.ErrMandateNotFound
+	}
+
+	return mandate, nil
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+)
+
+// RiskEngine avalia transferências antes da consulta ao autorizador externo
+type RiskEngine interface {
+	// Assess executa as regras de risco sobre a transferência já reservada
+	Assess(ctx context.Context, transaction *entity.Transaction) (*entity.RiskAssessment, error)
+	// Enqueue registra a transferência retida ou negada na fila de análise
+	Enqueue(ctx context.Context, transaction *entity.Transaction, assessment *entity.RiskAssessment) (*entity.RiskReview, error)
+}
+
+type riskEngine struct {
+	riskRepo repository.RiskRepository
+	policy   entity.RiskPolicy
+}
+
+// NewRiskEngine cria o motor de risco com as regras configuradas
+func NewRiskEngine(riskRepo repository.RiskRepository, policy entity.RiskPolicy) RiskEngine {
+	return &riskEngine{
+		riskRepo: riskRepo,
+		policy:   policy,
+	}
+}
+
+// Assess consulta o histórico do pagador e aplica a política de risco
+func (e *riskEngine) Assess(ctx context.Context, transaction *entity.Transaction) (*entity.RiskAssessment, error) {
+	now := time.Now()
+
+	profile, err := e.riskRepo.GetProfile(ctx, transaction, now.Add(-time.Minute))
+	if err != nil {
+		return nil, err
+	}
+
+	return e.policy.Assess(transaction, profile, now), nil
+}
+
+// Enqueue grava o item da fila; retidas recebem o prazo de análise da política
+func (e *riskEngine) Enqueue(ctx context.Context, transaction *entity.Transaction, assessment *entity.RiskAssessment) (*entity.RiskReview, error) {
+	review := entity.NewRiskReview(transaction, assessment, e.policy.ReviewTTL, time.Now())
+
+	if err := e.riskRepo.CreateReview(ctx, review); err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
@@ -0,0 +1,293 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+)
+
+// RiskReviewUseCase define as operações da fila de análise manual de transferências retidas
+type RiskReviewUseCase interface {
+	ListReviews(ctx context.Context, filters *entity.RiskReviewFilters) (*entity.ListRiskReviewsResponse, error)
+	GetReview(ctx context.Context, id string) (*entity.RiskReviewResponse, error)
+	// ClaimReview atribui o item ao analista, que passa a ser o único que pode decidi-lo
+	ClaimReview(ctx context.Context, id, reviewer string) (*entity.RiskReviewResponse, error)
+	// ApproveReview libera a transferência e continua a autorização
+	ApproveReview(ctx context.Context, id, reviewer string, req *entity.DecideRiskReviewRequest) (*entity.RiskReviewResponse, error)
+	// RejectReview falha a transferência e libera o saldo reservado
+	RejectReview(ctx context.Context, id, reviewer string, req *entity.DecideRiskReviewRequest) (*entity.RiskReviewResponse, error)
+	// ExpireOverdueReviews falha as transferências que não foram analisadas no prazo
+	ExpireOverdueReviews(ctx context.Context) (int, error)
+}
+
+type riskReviewUseCase struct {
+	txManager       repository.TxManager
+	riskRepo        repository.RiskRepository
+	transactionRepo repository.TransactionRepository
+	transactions    TransactionUseCase
+	audit           AuditLogger
+}
+
+// NewRiskReviewUseCase cria uma nova instância do use case da fila de análise
+func NewRiskReviewUseCase(
+	txManager repository.TxManager,
+	riskRepo repository.RiskRepository,
+	transactionRepo repository.TransactionRepository,
+	transactions TransactionUseCase,
+	audit AuditLogger,
+) RiskReviewUseCase {
+	return &riskReviewUseCase{
+		txManager:       txManager,
+		riskRepo:        riskRepo,
+		transactionRepo: transactionRepo,
+		transactions:    transactions,
+		audit:           audit,
+	}
+}
+
+// ListReviews lista a fila de análise; sem filtro de status, traz os itens abertos
+func (uc *riskReviewUseCase) ListReviews(ctx context.Context, filters *entity.RiskReviewFilters) (*entity.ListRiskReviewsResponse, error) {
+	// Validar paginação
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 20
+	}
+	if filters.Limit > 100 {
+		filters.Limit = 100
+	}
+
+	reviews, total, err := uc.riskRepo.ListReviews(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]entity.RiskReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		responses = append(responses, *review.ToRiskReviewResponse())
+	}
+
+	totalPages := (total + filters.Limit - 1) / filters.Limit
+
+	return &entity.ListRiskReviewsResponse{
+		Reviews:    responses,
+		Total:      total,
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetReview retorna o item com a trilha de auditoria e a situação atual da transação
+func (uc *riskReviewUseCase) GetReview(ctx context.Context, id string) (*entity.RiskReviewResponse, error) {
+	review, err := uc.riskRepo.GetReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := uc.transactionRepo.GetByID(ctx, review.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return reviewResponse(review, transaction), nil
+}
+
+// ClaimReview assume o item para o analista
+func (uc *riskReviewUseCase) ClaimReview(ctx context.Context, id, reviewer string) (*entity.RiskReviewResponse, error) {
+	var review *entity.RiskReview
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		review, err = uc.riskRepo.GetReviewForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(review)
+		events := len(review.Events)
+		if err := review.Claim(reviewer, time.Now()); err != nil {
+			return err
+		}
+		// Assumir de novo o próprio item não gera ação
+		if len(review.Events) == events {
+			return nil
+		}
+
+		return uc.saveAction(ctx, "risk_review.claim", before, review)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return review.ToRiskReviewResponse(), nil
+}
+
+// ApproveReview grava a aprovação junto com a volta da transação ao fluxo e, fora da transação
+// do banco, consulta o autorizador. Uma recusa do autorizador fica registrada no histórico do item.
+func (uc *riskReviewUseCase) ApproveReview(ctx context.Context, id, reviewer string, req *entity.DecideRiskReviewRequest) (*entity.RiskReviewResponse, error) {
+	var review *entity.RiskReview
+	var transaction *entity.Transaction
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		review, err = uc.riskRepo.GetReviewForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(review)
+		if err := review.Approve(reviewer, req.Note, time.Now()); err != nil {
+			return err
+		}
+
+		transaction, err = uc.transactionRepo.GetByIDForUpdate(ctx, review.TransactionID)
+		if err != nil {
+			return err
+		}
+		transactionBefore := entity.AuditSnapshot(transaction)
+		if err := transaction.ResumeFromReview(); err != nil {
+			return err
+		}
+		if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+			return err
+		}
+		if err := uc.audit.Record(ctx, "transaction.resume_from_review", "transaction", transaction.ID, transactionBefore, transaction); err != nil {
+			return err
+		}
+
+		return uc.saveAction(ctx, "risk_review.approve", before, review)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// O autorizador é um serviço externo: executar fora da transação que bloqueia o item
+	if err := uc.transactions.ResumeReviewed(ctx, transaction); err != nil {
+		review.Record(entity.RiskReviewActionAuthorizationFailed, entity.RiskReviewSystemActor, err.Error(), time.Now())
+		if eventErr := uc.riskRepo.AddReviewEvent(ctx, review.LastEvent()); eventErr != nil {
+			slog.ErrorContext(ctx, "erro ao registrar falha de autorização do item da fila de análise", "risk_review_id", review.ID, "error", eventErr)
+		}
+	}
+
+	return reviewResponse(review, transaction), nil
+}
+
+// RejectReview grava a rejeição e falha a transação na mesma transação do banco
+func (uc *riskReviewUseCase) RejectReview(ctx context.Context, id, reviewer string, req *entity.DecideRiskReviewRequest) (*entity.RiskReviewResponse, error) {
+	var review *entity.RiskReview
+	var transaction *entity.Transaction
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		review, err = uc.riskRepo.GetReviewForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(review)
+		if err := review.Reject(reviewer, req.Note, time.Now()); err != nil {
+			return err
+		}
+
+		reason := "transferência rejeitada na análise de risco"
+		if review.DecisionNote != nil {
+			reason += ": " + *review.DecisionNote
+		}
+
+		transaction, err = uc.failTransaction(ctx, review, reason)
+		if err != nil {
+			return err
+		}
+
+		return uc.saveAction(ctx, "risk_review.reject", before, review)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reviewResponse(review, transaction), nil
+}
+
+// ExpireOverdueReviews encerra os itens abertos vencidos e falha as transferências retidas
+func (uc *riskReviewUseCase) ExpireOverdueReviews(ctx context.Context) (int, error) {
+	ids, err := uc.riskRepo.ListOverdueReviews(ctx, time.Now(), 100)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		changed := false
+		err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			// Recarregar com bloqueio: o item pode ter sido decidido nesse meio tempo
+			review, err := uc.riskRepo.GetReviewForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			before := entity.AuditSnapshot(review)
+			if !review.Expire(time.Now()) {
+				return nil
+			}
+
+			if _, err := uc.failTransaction(ctx, review, "prazo de análise de risco expirado"); err != nil {
+				return err
+			}
+
+			changed = true
+			return uc.saveAction(ctx, "risk_review.expire", before, review)
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "item da fila de análise não expirado", "risk_review_id", id, "error", err)
+			continue
+		}
+		if changed {
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// failTransaction falha a transação retida do item; se ela já saiu da análise, só o item é encerrado
+func (uc *riskReviewUseCase) failTransaction(ctx context.Context, review *entity.RiskReview, reason string) (*entity.Transaction, error) {
+	transaction, err := uc.transactionRepo.GetByIDForUpdate(ctx, review.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	if !transaction.IsUnderReview() {
+		return transaction, nil
+	}
+
+	if err := uc.transactions.FailReviewed(ctx, transaction, reason); err != nil {
+		return nil, fmt.Errorf("erro ao falhar transação retida: %w", err)
+	}
+
+	return transaction, nil
+}
+
+// saveAction grava o novo estado do item, a ação mais recente do histórico do item e o registro
+// na trilha de auditoria geral
+func (uc *riskReviewUseCase) saveAction(ctx context.Context, action string, before map[string]interface{}, review *entity.RiskReview) error {
+	if err := uc.riskRepo.UpdateReview(ctx, review); err != nil {
+		return err
+	}
+
+	if err := uc.riskRepo.AddReviewEvent(ctx, review.LastEvent()); err != nil {
+		return err
+	}
+	return uc.audit.Record(ctx, action, "risk_review", review.ID, before, review)
+}
+
+func reviewResponse(review *entity.RiskReview, transaction *entity.Transaction) *entity.RiskReviewResponse {
+	response := review.ToRiskReviewResponse()
+	if transaction != nil {
+		response.Transaction = transaction.ToGetTransactionResponse()
+	}
+	return response
+}
@@ -0,0 +1,443 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// SplitPaymentUseCase define as operações de negócio para pagamentos divididos
+type SplitPaymentUseCase interface {
+	// CreateSplitPayment debita o pagador uma única vez e liquida todas as partes ou nenhuma
+	CreateSplitPayment(ctx context.Context, payerID string, req *entity.CreateSplitPaymentRequest) (*entity.SplitPaymentResponse, error)
+	GetSplitPayment(ctx context.Context, userID, id string) (*entity.SplitPaymentResponse, error)
+	// RefundSplitPayment estorna o valor informado (ou todo o restante) proporcionalmente entre as partes
+	RefundSplitPayment(ctx context.Context, id string, req *entity.RefundSplitPaymentRequest) (*entity.SplitPaymentResponse, error)
+}
+
+type splitPaymentUseCase struct {
+	txManager       repository.TxManager
+	splitRepo       repository.SplitPaymentRepository
+	userRepo        repository.UserRepository
+	transactionRepo repository.TransactionRepository
+	pixKeyRepo      repository.PixKeyRepository
+	pricingRepo     repository.PricingRepository
+	limits          LimitUseCase
+	transactions    TransactionUseCase
+	events          DomainEventRecorder
+	webhooks        WebhookPublisher
+	audit           AuditLogger
+}
+
+// NewSplitPaymentUseCase cria uma nova instância do use case de pagamentos divididos
+func NewSplitPaymentUseCase(
+	txManager repository.TxManager,
+	splitRepo repository.SplitPaymentRepository,
+	userRepo repository.UserRepository,
+	transactionRepo repository.TransactionRepository,
+	pixKeyRepo repository.PixKeyRepository,
+	pricingRepo repository.PricingRepository,
+	limits LimitUseCase,
+	transactions TransactionUseCase,
+	events DomainEventRecorder,
+	webhooks WebhookPublisher,
+	audit AuditLogger,
+) SplitPaymentUseCase {
+	return &splitPaymentUseCase{
+		txManager:       txManager,
+		splitRepo:       splitRepo,
+		userRepo:        userRepo,
+		transactionRepo: transactionRepo,
+		pixKeyRepo:      pixKeyRepo,
+		pricingRepo:     pricingRepo,
+		limits:          limits,
+		transactions:    transactions,
+		events:          events,
+		webhooks:        webhooks,
+		audit:           audit,
+	}
+}
+
+// CreateSplitPayment liquida as partes dos recebedores como um lote atômico de transações.
+// O pagamento dividido é gravado junto com as reservas e a parte da plataforma é debitada
+// do pagador na mesma transação do banco que credita os recebedores.
+func (uc *splitPaymentUseCase) CreateSplitPayment(ctx context.Context, payerID string, req *entity.CreateSplitPaymentRequest) (*entity.SplitPaymentResponse, error) {
+	specs := make([]entity.SplitLegSpec, 0, len(req.Legs))
+	for _, legReq := range req.Legs {
+		spec, err := uc.resolveLeg(ctx, legReq)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+
+	split, err := entity.NewSplitPayment(payerID, req.Amount, req.Description, specs)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao validar dados do pagamento dividido: %w", err)
+	}
+
+	payer, err := uc.userRepo.GetByID(ctx, payerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := payer.ValidateCanSend(); err != nil {
+		return nil, err
+	}
+	if !payer.HasSufficientBalance(split.Amount) {
+		return nil, entity.ErrInsufficientBalance
+	}
+
+	legs := split.UserLegs()
+	transactions := make([]*entity.Transaction, len(legs))
+	for i, leg := range legs {
+		transaction, err := entity.NewTransaction(payerID, *leg.PayeeID, leg.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao validar dados do pagamento dividido: %w", err)
+		}
+		transactions[i] = transaction
+		leg.TransactionID = &transaction.ID
+	}
+
+	created := false
+	errs := uc.transactions.ExecuteAtomic(ctx, transactions, AtomicHooks{
+		OnReserve: func(ctx context.Context) error {
+			if err := uc.reservePlatformShare(ctx, split); err != nil {
+				return err
+			}
+			if err := uc.splitRepo.Create(ctx, split); err != nil {
+				return err
+			}
+			created = true
+			return uc.audit.Record(ctx, "split_payment.create", "split_payment", split.ID, nil, split)
+		},
+		OnComplete: func(ctx context.Context) error {
+			if err := uc.settlePlatformShare(ctx, split); err != nil {
+				return err
+			}
+			before := entity.AuditSnapshot(split)
+			split.Complete()
+			if err := uc.splitRepo.Update(ctx, split); err != nil {
+				return err
+			}
+			return uc.audit.Record(ctx, "split_payment.complete", "split_payment", split.ID, before, split)
+		},
+	})
+
+	if cause := firstSplitError(errs); cause != nil {
+		if created {
+			split.Fail(cause.Error())
+			if err := uc.splitRepo.Update(ctx, split); err != nil {
+				slog.ErrorContext(ctx, "erro ao registrar falha do pagamento dividido", "split_payment_id", split.ID, "error", err)
+			}
+		}
+		return nil, cause
+	}
+
+	return split.ToSplitPaymentResponse(), nil
+}
+
+// firstSplitError retorna o erro que cancelou o lote, ignorando os das partes abortadas por ele
+func firstSplitError(errs []error) error {
+	var aborted error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, entity.ErrBatchAborted) {
+			return err
+		}
+		aborted = err
+	}
+	return aborted
+}
+
+// reservePlatformShare confere se, com as reservas das partes já gravadas, ainda sobra saldo para a plataforma
+func (uc *splitPaymentUseCase) reservePlatformShare(ctx context.Context, split *entity.SplitPayment) error {
+	amount := split.PlatformAmount()
+	if amount.IsZero() {
+		return nil
+	}
+
+	payer, err := uc.userRepo.GetByIDForUpdate(ctx, split.PayerID)
+	if err != nil {
+		return err
+	}
+
+	if !payer.HasSufficientBalance(amount) {
+		return entity.ErrInsufficientBalance
+	}
+
+	return nil
+}
+
+// settlePlatformShare debita a parte da plataforma do pagador e lança na conta de receita
+func (uc *splitPaymentUseCase) settlePlatformShare(ctx context.Context, split *entity.SplitPayment) error {
+	amount := split.PlatformAmount()
+	if amount.IsZero() {
+		return nil
+	}
+
+	payer, err := uc.userRepo.GetByIDForUpdate(ctx, split.PayerID)
+	if err != nil {
+		return err
+	}
+
+	if !payer.HasSufficientBalance(amount) {
+		return entity.ErrInsufficientBalance
+	}
+
+	if err := uc.limits.Consume(ctx, payer, amount, time.Now()); err != nil {
+		return err
+	}
+
+	if err := payer.DebitBalance(amount); err != nil {
+		return err
+	}
+
+	if err := uc.userRepo.UpdateBalance(ctx, payer); err != nil {
+		return err
+	}
+	if err := uc.events.Record(ctx, payer); err != nil {
+		return err
+	}
+
+	return uc.pricingRepo.PostFee(ctx, entity.NewSplitPlatformEntry(split, amount))
+}
+
+// GetSplitPayment retorna o pagamento dividido ao pagador ou a um dos recebedores
+func (uc *splitPaymentUseCase) GetSplitPayment(ctx context.Context, userID, id string) (*entity.SplitPaymentResponse, error) {
+	split, err := uc.splitRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Não revelar pagamentos de outros usuários
+	if !split.IsVisibleTo(userID) {
+		return nil, entity.ErrSplitPaymentNotFound
+	}
+
+	return split.ToSplitPaymentResponse(), nil
+}
+
+// RefundSplitPayment devolve ao pagador a parte estornada de cada recebedor e da plataforma.
+// Como em ReverseTransaction, o recebedor devolve o valor líquido e a plataforma devolve a tarifa
+// proporcional à parte estornada; a transação de uma parte é revertida quando a parte é estornada
+// por completo, já com a tarifa inteira devolvida.
+func (uc *splitPaymentUseCase) RefundSplitPayment(ctx context.Context, id string, req *entity.RefundSplitPaymentRequest) (*entity.SplitPaymentResponse, error) {
+	var split *entity.SplitPayment
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		split, err = uc.splitRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		amount := split.RefundableAmount()
+		if req.Amount != nil {
+			amount = *req.Amount
+		}
+
+		reason := req.Reason
+		if reason == "" {
+			reason = "estorno do pagamento dividido"
+		}
+
+		legTransactions, err := uc.lockLegTransactions(ctx, split)
+		if err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(split)
+		refund, err := split.Refund(amount, reason)
+		if err != nil {
+			return err
+		}
+
+		users, err := uc.lockRefundParties(ctx, split, refund)
+		if err != nil {
+			return err
+		}
+		payer := users[split.PayerID]
+
+		for i := range refund.Legs {
+			refundLeg := &refund.Legs[i]
+			leg := split.Leg(refundLeg.LegID)
+
+			if leg.IsPlatform() {
+				if err := uc.pricingRepo.PostFee(ctx, entity.NewSplitPlatformEntry(split, refundLeg.Amount.Neg())); err != nil {
+					return err
+				}
+			} else if err := uc.refundLeg(ctx, leg, refundLeg, legTransactions[leg.ID], users[*leg.PayeeID], reason); err != nil {
+				return err
+			}
+
+			payer.CreditBalance(refundLeg.Amount)
+		}
+
+		for _, user := range users {
+			if err := uc.userRepo.UpdateBalance(ctx, user); err != nil {
+				return err
+			}
+			if err := uc.events.Record(ctx, user); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.splitRepo.CreateRefund(ctx, refund); err != nil {
+			return err
+		}
+
+		if err := uc.splitRepo.Update(ctx, split); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "split_payment.refund", "split_payment", split.ID, before, split)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return split.ToSplitPaymentResponse(), nil
+}
+
+// refundLeg debita do recebedor o líquido da parte estornada, estorna a tarifa proporcional da
+// conta de receita e reverte a transação da parte quando ela é estornada por completo
+func (uc *splitPaymentUseCase) refundLeg(ctx context.Context, leg *entity.SplitLeg, refundLeg *entity.SplitRefundLeg, transaction *entity.Transaction, payee *entity.User, reason string) error {
+	if transaction == nil {
+		return fmt.Errorf("erro ao estornar parte %d do pagamento dividido: parte sem transação", leg.Position)
+	}
+
+	refundLeg.Fee = leg.RefundFee(transaction.FeeAmount, refundLeg.Amount)
+	net := refundLeg.Amount.Sub(refundLeg.Fee)
+	if !payee.HasSufficientBalance(net) {
+		return fmt.Errorf("%w (parte %d)", entity.ErrPayeeCannotCoverRefund, leg.Position)
+	}
+	if err := payee.DebitBalance(net); err != nil {
+		return err
+	}
+
+	if !refundLeg.Fee.IsZero() {
+		if err := uc.pricingRepo.PostFee(ctx, entity.NewFeeRefundEntry(transaction, refundLeg.Fee)); err != nil {
+			return err
+		}
+	}
+
+	if !leg.IsFullyRefunded() {
+		return nil
+	}
+	return uc.reverseLegTransaction(ctx, leg, transaction, reason)
+}
+
+// lockRefundParties bloqueia pagador e recebedores envolvidos no estorno sempre na ordem dos IDs para evitar deadlocks
+func (uc *splitPaymentUseCase) lockRefundParties(ctx context.Context, split *entity.SplitPayment, refund *entity.SplitRefund) (map[string]*entity.User, error) {
+	ids := []string{split.PayerID}
+	for _, refundLeg := range refund.Legs {
+		if leg := split.Leg(refundLeg.LegID); !leg.IsPlatform() {
+			ids = append(ids, *leg.PayeeID)
+		}
+	}
+	sort.Strings(ids)
+
+	users := make(map[string]*entity.User, len(ids))
+	for _, id := range ids {
+		user, err := uc.userRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		users[id] = user
+	}
+
+	return users, nil
+}
+
+// lockLegTransactions bloqueia as transações das partes que ainda podem ser estornadas e recusa o
+// estorno se alguma já foi revertida por uma disputa: o pagador já recebeu aquela parte de volta.
+// Uma disputa bloqueia a mesma linha antes de reverter a transação. Retorna as transações pelo ID da parte.
+func (uc *splitPaymentUseCase) lockLegTransactions(ctx context.Context, split *entity.SplitPayment) (map[string]*entity.Transaction, error) {
+	transactions := make(map[string]*entity.Transaction)
+	for _, leg := range split.UserLegs() {
+		if leg.TransactionID == nil || leg.IsFullyRefunded() {
+			continue
+		}
+
+		transaction, err := uc.transactionRepo.GetByIDForUpdate(ctx, *leg.TransactionID)
+		if err != nil {
+			return nil, err
+		}
+		if transaction.IsReversed() {
+			return nil, fmt.Errorf("%w (parte %d)", entity.ErrSplitLegReversed, leg.Position)
+		}
+		transactions[leg.ID] = transaction
+	}
+
+	return transactions, nil
+}
+
+// reverseLegTransaction marca como revertida a transação de uma parte estornada por completo; os
+// saldos e a tarifa já foram devolvidos pelos estornos da parte
+func (uc *splitPaymentUseCase) reverseLegTransaction(ctx context.Context, leg *entity.SplitLeg, transaction *entity.Transaction, reason string) error {
+	if !transaction.CanBeReversed() {
+		return fmt.Errorf("%w (parte %d)", entity.ErrSplitLegReversed, leg.Position)
+	}
+
+	before := entity.AuditSnapshot(transaction)
+	transaction.Reverse(reason)
+	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+		return err
+	}
+	if err := uc.audit.Record(ctx, "transaction.reverse", "transaction", transaction.ID, before, transaction); err != nil {
+		return err
+	}
+	if err := uc.events.Record(ctx, transaction); err != nil {
+		return err
+	}
+	return uc.webhooks.Publish(ctx, transaction.PayeeID, entity.WebhookEventTransactionReversed, transaction.ToGetTransactionResponse())
+}
+
+// resolveLeg identifica o recebedor de uma parte por ID ou chave Pix
+func (uc *splitPaymentUseCase) resolveLeg(ctx context.Context, req entity.SplitLegRequest) (entity.SplitLegSpec, error) {
+	spec := entity.SplitLegSpec{SplitType: req.SplitType, Value: req.Value}
+
+	if req.Platform {
+		if req.PayeeID != "" || req.PayeeKey != "" {
+			return spec, errors.New("erro ao validar dados do pagamento dividido: a parte da plataforma não tem recebedor")
+		}
+		return spec, nil
+	}
+
+	if req.PayeeKey != "" {
+		key, owner, err := resolvePixKey(ctx, uc.pixKeyRepo, uc.userRepo, req.PayeeKey)
+		if err != nil {
+			return spec, err
+		}
+		if req.PayeeID != "" && req.PayeeID != owner.ID {
+			return spec, errors.New("erro ao validar dados do pagamento dividido: payee_id e payee_key indicam recebedores diferentes")
+		}
+		spec.PayeeID = &owner.ID
+		spec.PayeeKey = &key.KeyValue
+		return spec, nil
+	}
+
+	if req.PayeeID == "" {
+		return spec, errors.New("erro ao validar dados do pagamento dividido: payee_id, payee_key ou platform é obrigatório em cada parte")
+	}
+	if uuid.Validate(req.PayeeID) != nil {
+		return spec, errors.New("erro ao validar dados do pagamento dividido: payee_id deve ser um UUID válido")
+	}
+
+	payee, err := uc.userRepo.GetByID(ctx, req.PayeeID)
+	if err != nil {
+		return spec, err
+	}
+
+	spec.PayeeID = &payee.ID
+	return spec, nil
+}
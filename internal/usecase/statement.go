@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+)
+
+// StatementUseCase define a geração de extratos de conta
+type StatementUseCase interface {
+	// GetStatement monta o extrato de [from, to) do próprio usuário
+	GetStatement(ctx context.Context, requesterID, userID string, from, to time.Time) (*entity.Statement, error)
+}
+
+type statementUseCase struct {
+	statementRepo repository.StatementRepository
+	userRepo      repository.UserRepository
+}
+
+// NewStatementUseCase cria uma nova instância do use case de extratos
+func NewStatementUseCase(statementRepo repository.StatementRepository, userRepo repository.UserRepository) StatementUseCase {
+	return &statementUseCase{
+		statementRepo: statementRepo,
+		userRepo:      userRepo,
+	}
+}
+
+func (uc *statementUseCase) GetStatement(ctx context.Context, requesterID, userID string, from, to time.Time) (*entity.Statement, error) {
+	// Não revelar a existência de contas de outros usuários
+	if requesterID != userID {
+		return nil, entity.ErrUserNotFound
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDeleted() {
+		return nil, entity.ErrUserNotFound
+	}
+
+	closing, err := uc.statementRepo.BalanceAt(ctx, userID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := uc.statementRepo.ListMovements(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewStatement(user, from, to, closing, movements, time.Now()), nil
+}
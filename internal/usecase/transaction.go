@@ -0,0 +1,903 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/gateway"
+	"payflow-api/internal/repository"
+)
+
+// scheduledRunLimit limita as transferências agendadas executadas a cada rodada do agendador
+const scheduledRunLimit = 50
+
+// TransactionUseCase define as operações de negócio para transferências
+type TransactionUseCase interface {
+	CreateTransaction(ctx context.Context, payerID string, req *entity.CreateTransactionRequest) (*entity.CreateTransactionResponse, error)
+	// GetTransaction busca uma transação em que userID é pagador ou recebedor
+	GetTransaction(ctx context.Context, userID, id string) (*entity.GetTransactionResponse, error)
+	// ListTransactions lista as transações em que userID é pagador ou recebedor
+	ListTransactions(ctx context.Context, userID string, filters *entity.TransactionFilters) (*entity.ListTransactionsResponse, error)
+	CancelTransaction(ctx context.Context, payerID, id string, req *entity.CancelTransactionRequest) (*entity.GetTransactionResponse, error)
+	// ExecutePending executa uma transação pendente já gravada, como o pagamento de uma cobrança
+	ExecutePending(ctx context.Context, transaction *entity.Transaction) error
+	// ResumeReviewed continua a autorização de uma transferência liberada pela análise manual
+	ResumeReviewed(ctx context.Context, transaction *entity.Transaction) error
+	// FailReviewed falha uma transferência retida para análise, liberando a reserva
+	FailReviewed(ctx context.Context, transaction *entity.Transaction, reason string) error
+	// ExecuteAtomic executa transações novas como uma unidade, rodando os ganchos nas mesmas
+	// transações do banco que gravam as reservas e que efetivam os saldos
+	ExecuteAtomic(ctx context.Context, transactions []*entity.Transaction, hooks AtomicHooks) []error
+	// ReverseTransaction devolve ao pagador uma transferência concluída, debitando o recebedor e estornando a tarifa
+	ReverseTransaction(ctx context.Context, id, reason string) (*entity.Transaction, error)
+	RunScheduledTransactions(ctx context.Context) (int, error)
+	ExpireHolds(ctx context.Context) (int, error)
+}
+
+// AtomicHooks permite que outros fluxos gravem seus dados junto com um lote atômico.
+// Se um gancho falhar, o lote inteiro é desfeito.
+type AtomicHooks struct {
+	// OnReserve roda depois de gravadas as transações e as reservas
+	OnReserve func(ctx context.Context) error
+	// OnComplete roda depois de efetivados os saldos de todas as transações
+	OnComplete func(ctx context.Context) error
+}
+
+type transactionUseCase struct {
+	txManager       repository.TxManager
+	userRepo        repository.UserRepository
+	transactionRepo repository.TransactionRepository
+	holdRepo        repository.BalanceHoldRepository
+	pixKeyRepo      repository.PixKeyRepository
+	limits          LimitUseCase
+	pricing         PricingUseCase
+	risk            RiskEngine
+	authorizer      gateway.Authorizer
+	holdTTL         time.Duration
+	events          DomainEventRecorder
+	webhooks        WebhookPublisher
+	audit           AuditLogger
+}
+
+// NewTransactionUseCase cria uma nova instância do use case de transferências
+func NewTransactionUseCase(
+	txManager repository.TxManager,
+	userRepo repository.UserRepository,
+	transactionRepo repository.TransactionRepository,
+	holdRepo repository.BalanceHoldRepository,
+	pixKeyRepo repository.PixKeyRepository,
+	limits LimitUseCase,
+	pricing PricingUseCase,
+	risk RiskEngine,
+	authorizer gateway.Authorizer,
+	holdTTL time.Duration,
+	events DomainEventRecorder,
+	webhooks WebhookPublisher,
+	audit AuditLogger,
+) TransactionUseCase {
+	return &transactionUseCase{
+		txManager:       txManager,
+		userRepo:        userRepo,
+		transactionRepo: transactionRepo,
+		holdRepo:        holdRepo,
+		pixKeyRepo:      pixKeyRepo,
+		limits:          limits,
+		pricing:         pricing,
+		risk:            risk,
+		authorizer:      authorizer,
+		holdTTL:         holdTTL,
+		events:          events,
+		webhooks:        webhooks,
+		audit:           audit,
+	}
+}
+
+// CreateTransaction executa uma transferência imediata ou agenda uma transferência futura
+func (uc *transactionUseCase) CreateTransaction(ctx context.Context, payerID string, req *entity.CreateTransactionRequest) (*entity.CreateTransactionResponse, error) {
+	if req.PayeeKey != "" {
+		if err := uc.resolvePayeeKey(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	transaction, err := entity.FromCreateTransactionRequest(req, payerID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao validar dados da transação: %w", err)
+	}
+
+	if transaction.IsScheduled() {
+		if err := uc.schedule(ctx, transaction); err != nil {
+			return nil, err
+		}
+		return transaction.ToCreateTransactionResponse(), nil
+	}
+
+	if err := uc.execute(ctx, transaction, true, true); err != nil {
+		if transaction.IsFailed() {
+			return transaction.ToCreateTransactionResponse(), err
+		}
+		return nil, err
+	}
+
+	return transaction.ToCreateTransactionResponse(), nil
+}
+
+// resolvePayeeKey troca a chave Pix do recebedor pelo ID do titular
+func (uc *transactionUseCase) resolvePayeeKey(ctx context.Context, req *entity.CreateTransactionRequest) error {
+	_, owner, err := resolvePixKey(ctx, uc.pixKeyRepo, uc.userRepo, req.PayeeKey)
+	if err != nil {
+		return err
+	}
+
+	if req.PayeeID != "" && req.PayeeID != owner.ID {
+		return errors.New("erro ao validar dados da transação: payee_id e payee_key indicam recebedores diferentes")
+	}
+
+	req.PayeeID = owner.ID
+	return nil
+}
+
+// execute reserva o saldo, passa pela análise de risco, consulta o autorizador e efetiva a reserva.
+// isNew indica se a transação ainda precisa ser gravada (agendadas e cobranças já existem); canHold indica se a transferência pode ficar retida para análise manual.
+func (uc *transactionUseCase) execute(ctx context.Context, transaction *entity.Transaction, isNew, canHold bool) error {
+	// Reservar o saldo antes de consultar o autorizador para que transferências
+	// concorrentes não usem o mesmo saldo
+	if err := uc.reserve(ctx, transaction, isNew); err != nil {
+		if !isNew {
+			before := entity.AuditSnapshot(transaction)
+			transaction.Fail(err.Error())
+			updateErr := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+				if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+					return err
+				}
+				if err := uc.audit.Record(ctx, "transaction.fail", "transaction", transaction.ID, before, transaction); err != nil {
+					return err
+				}
+				return uc.events.Record(ctx, transaction)
+			})
+			if updateErr != nil {
+				slog.ErrorContext(ctx, "erro ao registrar falha da transação", "transaction_id", transaction.ID, "error", updateErr)
+			}
+		}
+		return err
+	}
+
+	return uc.screenAndAuthorize(ctx, transaction, canHold)
+}
+
+// screenAndAuthorize passa a transação reservada pela análise de risco e, se não ficar retida,
+// consulta o autorizador e efetiva a reserva
+func (uc *transactionUseCase) screenAndAuthorize(ctx context.Context, transaction *entity.Transaction, canHold bool) error {
+	held, err := uc.screen(ctx, transaction, canHold)
+	if err != nil || held {
+		return err
+	}
+
+	return uc.authorizeAndComplete(ctx, transaction)
+}
+
+// authorizeAndComplete consulta o autorizador para a transação reservada e efetiva a reserva.
+// O recebedor é notificado pelo assinante do evento de conclusão.
+func (uc *transactionUseCase) authorizeAndComplete(ctx context.Context, transaction *entity.Transaction) error {
+	authorizationID, err := uc.authorizer.Authorize(ctx, transaction)
+	if err != nil {
+		if failErr := uc.fail(ctx, transaction, err.Error()); failErr != nil {
+			slog.ErrorContext(ctx, "erro ao liberar reserva da transação", "transaction_id", transaction.ID, "error", failErr)
+		}
+		return err
+	}
+
+	return uc.complete(ctx, transaction, authorizationID)
+}
+
+// ExecutePending executa com o fluxo completo uma transação pendente gravada por outro fluxo
+func (uc *transactionUseCase) ExecutePending(ctx context.Context, transaction *entity.Transaction) error {
+	if !transaction.IsPending() {
+		return entity.ErrTransactionNotPending
+	}
+
+	return uc.execute(ctx, transaction, false, true)
+}
+
+// ResumeReviewed continua a autorização de uma transferência aprovada na análise manual
+func (uc *transactionUseCase) ResumeReviewed(ctx context.Context, transaction *entity.Transaction) error {
+	if !transaction.IsPending() {
+		return entity.ErrTransactionNotPending
+	}
+
+	return uc.authorizeAndComplete(ctx, transaction)
+}
+
+// FailReviewed libera a reserva de uma transferência retida que foi rejeitada ou não analisada no prazo
+func (uc *transactionUseCase) FailReviewed(ctx context.Context, transaction *entity.Transaction, reason string) error {
+	if !transaction.IsUnderReview() {
+		return entity.ErrTransactionNotUnderReview
+	}
+
+	return uc.fail(ctx, transaction, reason)
+}
+
+// ExecuteAtomic reserva, autoriza e efetiva todas as transações ou nenhuma
+func (uc *transactionUseCase) ExecuteAtomic(ctx context.Context, transactions []*entity.Transaction, hooks AtomicHooks) []error {
+	errs := make([]error, len(transactions))
+
+	// Reservar todas as transferências na mesma transação do banco: se uma falhar, nenhuma é gravada
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		for i, transaction := range transactions {
+			if err := uc.reserve(ctx, transaction, true); err != nil {
+				errs[i] = err
+				return err
+			}
+		}
+		if hooks.OnReserve != nil {
+			return hooks.OnReserve(ctx)
+		}
+		return nil
+	})
+	if err != nil {
+		return abortBatch(errs, err)
+	}
+
+	for i, transaction := range transactions {
+		if _, err := uc.screen(ctx, transaction, false); err != nil {
+			errs[i] = err
+			uc.failAll(ctx, transactions, "lote cancelado: "+err.Error())
+			return abortBatch(errs, err)
+		}
+	}
+
+	// Consultar o autorizador para todas antes de efetivar qualquer uma
+	authorizationIDs := make([]string, len(transactions))
+	for i, transaction := range transactions {
+		authorizationID, err := uc.authorizer.Authorize(ctx, transaction)
+		if err != nil {
+			errs[i] = err
+			uc.failAll(ctx, transactions, "lote cancelado: "+err.Error())
+			return abortBatch(errs, err)
+		}
+		authorizationIDs[i] = authorizationID
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		for i, transaction := range transactions {
+			if err := uc.complete(ctx, transaction, authorizationIDs[i]); err != nil {
+				errs[i] = err
+				return err
+			}
+		}
+		if hooks.OnComplete != nil {
+			return hooks.OnComplete(ctx)
+		}
+		return nil
+	})
+	if err != nil {
+		uc.failAll(ctx, transactions, "lote cancelado: "+err.Error())
+		return abortBatch(errs, err)
+	}
+
+	return errs
+}
+
+// failAll libera as reservas de todas as transações de um lote atômico
+func (uc *transactionUseCase) failAll(ctx context.Context, transactions []*entity.Transaction, reason string) {
+	for _, transaction := range transactions {
+		// Negadas pela análise de risco já tiveram a reserva liberada
+		if transaction.IsFailed() {
+			continue
+		}
+		if err := uc.fail(ctx, transaction, reason); err != nil {
+			slog.ErrorContext(ctx, "erro ao liberar reserva da transação", "transaction_id", transaction.ID, "error", err)
+		}
+	}
+}
+
+// abortBatch marca com ErrBatchAborted as transações que não tiveram erro próprio.
+// Quando a falha veio de um gancho, nenhuma transação tem erro próprio e cause é usada na primeira.
+func abortBatch(errs []error, cause error) []error {
+	owned := false
+	for _, err := range errs {
+		if err != nil {
+			owned = true
+			break
+		}
+	}
+	if !owned && len(errs) > 0 {
+		errs[0] = cause
+	}
+
+	for i, err := range errs {
+		if err == nil {
+			errs[i] = entity.ErrBatchAborted
+		}
+	}
+	return errs
+}
+
+// screen submete a transferência reservada ao motor de risco e informa se ela ficou retida.
+// Negadas têm a reserva liberada; retidas mantêm a reserva até o prazo de análise. As duas vão
+// para a fila de análise. Sem a avaliação, a transferência não segue para o autorizador.
+func (uc *transactionUseCase) screen(ctx context.Context, transaction *entity.Transaction, canHold bool) (bool, error) {
+	assessment, err := uc.risk.Assess(ctx, transaction)
+	if err != nil {
+		if failErr := uc.fail(ctx, transaction, "análise de risco indisponível"); failErr != nil {
+			slog.ErrorContext(ctx, "erro ao liberar reserva da transação", "transaction_id", transaction.ID, "error", failErr)
+		}
+		return false, fmt.Errorf("erro na análise de risco: %w", err)
+	}
+
+	if assessment.IsAllowed() {
+		return false, nil
+	}
+	if !canHold {
+		assessment.EscalateToDeny()
+	}
+
+	if assessment.Decision == entity.RiskDecisionReview {
+		return true, uc.holdForReview(ctx, transaction, assessment)
+	}
+
+	denied := fmt.Errorf("%w: %s", entity.ErrRiskDenied, assessment.Summary())
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.fail(ctx, transaction, denied.Error()); err != nil {
+			return err
+		}
+		_, err := uc.risk.Enqueue(ctx, transaction, assessment)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return false, denied
+}
+
+// holdForReview retém a transação e prorroga a reserva de saldo até o prazo de análise
+func (uc *transactionUseCase) holdForReview(ctx context.Context, transaction *entity.Transaction, assessment *entity.RiskAssessment) error {
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		review, err := uc.risk.Enqueue(ctx, transaction, assessment)
+		if err != nil {
+			return err
+		}
+
+		hold, err := uc.holdRepo.GetByTransactionID(ctx, transaction.ID)
+		if err != nil {
+			return err
+		}
+		// A reserva dura além do prazo de análise para que a expiração da fila decida antes
+		if err := hold.ExtendUntil(review.DueAt.Add(uc.holdTTL)); err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(transaction)
+		if err := transaction.HoldForReview("transferência retida para análise de risco: " + assessment.Summary()); err != nil {
+			return err
+		}
+
+		if err := uc.holdRepo.Update(ctx, hold); err != nil {
+			return err
+		}
+		if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "transaction.hold_for_review", "transaction", transaction.ID, before, transaction)
+	})
+}
+
+// schedule valida pagador e recebedor e grava a transferência agendada, sem reservar saldo
+func (uc *transactionUseCase) schedule(ctx context.Context, transaction *entity.Transaction) error {
+	payer, err := uc.userRepo.GetByID(ctx, transaction.PayerID)
+	if err != nil {
+		return err
+	}
+
+	payee, err := uc.userRepo.GetByID(ctx, transaction.PayeeID)
+	if err != nil {
+		return err
+	}
+
+	if err := transaction.ValidateParties(payer, payee); err != nil {
+		return err
+	}
+
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.transactionRepo.Create(ctx, transaction); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "transaction.schedule", "transaction", transaction.ID, nil, transaction)
+	})
+}
+
+// scheduledFailureReason traduz o erro da execução agendada em um motivo legível para o usuário
+func scheduledFailureReason(err error) string {
+	switch {
+	case errors.Is(err, entity.ErrInsufficientBalance):
+		return "saldo insuficiente na data agendada"
+	case errors.Is(err, entity.ErrAmountExceedsLimit):
+		return "limite de transferência excedido na data agendada: " + err.Error()
+	case errors.Is(err, entity.ErrUserNotFound):
+		return "pagador ou recebedor não encontrado na data agendada"
+	default:
+		return "falha ao executar transferência agendada: " + err.Error()
+	}
+}
+
+// reserve valida as regras de negócio, grava a transação pendente e cria a reserva de saldo
+func (uc *transactionUseCase) reserve(ctx context.Context, transaction *entity.Transaction, isNew bool) error {
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		action := "transaction.create"
+		var before map[string]interface{}
+		if !isNew {
+			action = "transaction.reserve"
+			before = entity.AuditSnapshot(transaction)
+		}
+
+		payer, err := uc.userRepo.GetByIDForUpdate(ctx, transaction.PayerID)
+		if err != nil {
+			return err
+		}
+
+		payee, err := uc.userRepo.GetByID(ctx, transaction.PayeeID)
+		if err != nil {
+			return err
+		}
+
+		if err := transaction.ValidateBusinessRules(payer, payee); err != nil {
+			return err
+		}
+
+		if err := uc.limits.Consume(ctx, payer, transaction.Amount, transaction.EffectiveDate()); err != nil {
+			return err
+		}
+
+		if err := uc.pricing.ApplyFee(ctx, transaction, payee); err != nil {
+			return err
+		}
+
+		hold, err := entity.NewBalanceHold(payer.ID, transaction.ID, transaction.Amount, uc.holdTTL)
+		if err != nil {
+			return err
+		}
+
+		if err := payer.PlaceHold(transaction.Amount); err != nil {
+			return err
+		}
+
+		if isNew {
+			err = uc.transactionRepo.Create(ctx, transaction)
+		} else {
+			err = uc.transactionRepo.Update(ctx, transaction)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := uc.holdRepo.Create(ctx, hold); err != nil {
+			return err
+		}
+
+		if err := uc.userRepo.UpdateBalance(ctx, payer); err != nil {
+			return err
+		}
+		if err := uc.audit.Record(ctx, action, "transaction", transaction.ID, before, transaction); err != nil {
+			return err
+		}
+		return uc.events.Record(ctx, payer)
+	})
+}
+
+// complete efetiva a reserva: debita o pagador, credita o recebedor, lança a tarifa e conclui a transação
+func (uc *transactionUseCase) complete(ctx context.Context, transaction *entity.Transaction, authorizationID string) error {
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		hold, err := uc.holdRepo.GetByTransactionID(ctx, transaction.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := hold.Capture(); err != nil {
+			return err
+		}
+
+		payer, receiver, err := uc.lockParties(ctx, transaction.PayerID, transaction.PayeeID)
+		if err != nil {
+			return err
+		}
+
+		if err := payer.CaptureHold(hold.Amount); err != nil {
+			return err
+		}
+		// O recebedor recebe o valor líquido; a tarifa vai para a conta de receita
+		receiver.CreditBalance(transaction.NetAmount())
+
+		before := entity.AuditSnapshot(transaction)
+		transaction.Authorize(authorizationID)
+		transaction.Complete()
+
+		if err := uc.pricing.PostFee(ctx, transaction); err != nil {
+			return err
+		}
+
+		if err := uc.holdRepo.Update(ctx, hold); err != nil {
+			return err
+		}
+		if err := uc.userRepo.UpdateBalance(ctx, payer); err != nil {
+			return err
+		}
+		if err := uc.userRepo.UpdateBalance(ctx, receiver); err != nil {
+			return err
+		}
+
+		if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+			return err
+		}
+		if err := uc.audit.Record(ctx, "transaction.complete", "transaction", transaction.ID, before, transaction); err != nil {
+			return err
+		}
+		if err := uc.events.Record(ctx, transaction, payer, receiver); err != nil {
+			return err
+		}
+		return uc.webhooks.Publish(ctx, transaction.PayeeID, entity.WebhookEventTransactionCompleted, transaction.ToGetTransactionResponse())
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao concluir transação: %w", err)
+	}
+
+	return nil
+}
+
+// fail libera a reserva e marca a transação como falha
+func (uc *transactionUseCase) fail(ctx context.Context, transaction *entity.Transaction, reason string) error {
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		hold, err := uc.holdRepo.GetByTransactionID(ctx, transaction.ID)
+		if err != nil {
+			return err
+		}
+
+		return uc.releaseHold(ctx, hold, transaction, reason, hold.Release)
+	})
+}
+
+// releaseHold devolve o valor reservado ao pagador e falha a transação vinculada
+func (uc *transactionUseCase) releaseHold(ctx context.Context, hold *entity.BalanceHold, transaction *entity.Transaction, reason string, settle func() error) error {
+	if err := settle(); err != nil {
+		return err
+	}
+
+	payer, err := uc.userRepo.GetByIDForUpdate(ctx, hold.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := payer.ReleaseHold(hold.Amount); err != nil {
+		return err
+	}
+
+	before := entity.AuditSnapshot(transaction)
+	transaction.Fail(reason)
+
+	if err := uc.limits.Restore(ctx, payer.ID, hold.Amount, transaction.EffectiveDate()); err != nil {
+		return err
+	}
+
+	if err := uc.holdRepo.Update(ctx, hold); err != nil {
+		return err
+	}
+	if err := uc.userRepo.UpdateBalance(ctx, payer); err != nil {
+		return err
+	}
+
+	if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+		return err
+	}
+	if err := uc.audit.Record(ctx, "transaction.fail", "transaction", transaction.ID, before, transaction); err != nil {
+		return err
+	}
+	return uc.events.Record(ctx, transaction, payer)
+}
+
+// lockParties bloqueia pagador e recebedor sempre na mesma ordem para evitar deadlocks
+func (uc *transactionUseCase) lockParties(ctx context.Context, payerID, payeeID string) (*entity.User, *entity.User, error) {
+	firstID, secondID := payerID, payeeID
+	if payeeID < payerID {
+		firstID, secondID = payeeID, payerID
+	}
+
+	first, err := uc.userRepo.GetByIDForUpdate(ctx, firstID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	second, err := uc.userRepo.GetByIDForUpdate(ctx, secondID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if first.ID == payerID {
+		return first, second, nil
+	}
+	return second, first, nil
+}
+
+// GetTransaction busca uma transação por ID com pagador e recebedor
+func (uc *transactionUseCase) GetTransaction(ctx context.Context, userID, id string) (*entity.GetTransactionResponse, error) {
+	transaction, err := uc.transactionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Não revelar transações de outros usuários
+	if !transaction.IsVisibleTo(userID) {
+		return nil, entity.ErrTransactionNotFound
+	}
+
+	if payer, err := uc.userRepo.GetByID(ctx, transaction.PayerID); err == nil {
+		transaction.Payer = payer
+	}
+	if payee, err := uc.userRepo.GetByID(ctx, transaction.PayeeID); err == nil {
+		transaction.Payee = payee
+	}
+
+	return transaction.ToGetTransactionResponse(), nil
+}
+
+// ListTransactions lista transações com paginação e filtros
+func (uc *transactionUseCase) ListTransactions(ctx context.Context, userID string, filters *entity.TransactionFilters) (*entity.ListTransactionsResponse, error) {
+	// Só as transações do próprio usuário, qualquer que seja o filtro recebido
+	filters.UserID = userID
+
+	// Validar paginação
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 20
+	}
+	if filters.Limit > 100 {
+		filters.Limit = 100
+	}
+
+	transactions, total, err := uc.transactionRepo.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	// Converter para responses
+	var transactionResponses []entity.GetTransactionResponse
+	for _, transaction := range transactions {
+		transactionResponses = append(transactionResponses, *transaction.ToGetTransactionResponse())
+	}
+
+	// Calcular total de páginas
+	totalPages := (total + filters.Limit - 1) / filters.Limit
+
+	return &entity.ListTransactionsResponse{
+		Transactions: transactionResponses,
+		Total:        total,
+		Page:         filters.Page,
+		Limit:        filters.Limit,
+		TotalPages:   totalPages,
+	}, nil
+}
+
+// CancelTransaction cancela uma transferência agendada do pagador antes da execução
+func (uc *transactionUseCase) CancelTransaction(ctx context.Context, payerID, id string, req *entity.CancelTransactionRequest) (*entity.GetTransactionResponse, error) {
+	var transaction *entity.Transaction
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = uc.transactionRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Não revelar transações de outros usuários
+		if transaction.PayerID != payerID {
+			return entity.ErrTransactionNotFound
+		}
+
+		reason := req.Reason
+		if reason == "" {
+			reason = "cancelada pelo pagador"
+		}
+
+		before := entity.AuditSnapshot(transaction)
+		if err := transaction.Cancel(reason); err != nil {
+			return err
+		}
+
+		if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "transaction.cancel", "transaction", transaction.ID, before, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction.ToGetTransactionResponse(), nil
+}
+
+// ReverseTransaction reverte a transferência: o recebedor devolve o valor líquido, a plataforma
+// devolve a tarifa e o pagador recebe o valor integral. Roda na transação do banco do chamador, se houver.
+func (uc *transactionUseCase) ReverseTransaction(ctx context.Context, id, reason string) (*entity.Transaction, error) {
+	var transaction *entity.Transaction
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = uc.transactionRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Só transferências concluídas já movimentaram os saldos
+		if !transaction.IsCompleted() {
+			return entity.ErrTransactionNotCompleted
+		}
+
+		payer, payee, err := uc.lockParties(ctx, transaction.PayerID, transaction.PayeeID)
+		if err != nil {
+			return err
+		}
+
+		net := transaction.NetAmount()
+		if !payee.HasSufficientBalance(net) {
+			return entity.ErrPayeeCannotCoverRefund
+		}
+		if err := payee.DebitBalance(net); err != nil {
+			return err
+		}
+		payer.CreditBalance(transaction.Amount)
+
+		if err := uc.userRepo.UpdateBalance(ctx, payee); err != nil {
+			return err
+		}
+		if err := uc.userRepo.UpdateBalance(ctx, payer); err != nil {
+			return err
+		}
+
+		if err := uc.pricing.ReverseFee(ctx, transaction); err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(transaction)
+		transaction.Reverse(reason)
+		if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+			return err
+		}
+		if err := uc.audit.Record(ctx, "transaction.reverse", "transaction", transaction.ID, before, transaction); err != nil {
+			return err
+		}
+		if err := uc.events.Record(ctx, transaction, payer, payee); err != nil {
+			return err
+		}
+		return uc.webhooks.Publish(ctx, transaction.PayeeID, entity.WebhookEventTransactionReversed, transaction.ToGetTransactionResponse())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// RunScheduledTransactions executa as transferências agendadas que venceram
+func (uc *transactionUseCase) RunScheduledTransactions(ctx context.Context) (int, error) {
+	executed := 0
+	for i := 0; i < scheduledRunLimit; i++ {
+		transaction, err := uc.reserveNextScheduled(ctx)
+		if transaction == nil {
+			if errors.Is(err, entity.ErrTransactionNotFound) {
+				break
+			}
+			return executed, err
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "transferência agendada não executada", "transaction_id", transaction.ID, "error", err)
+			continue
+		}
+
+		if err := uc.screenAndAuthorize(ctx, transaction, true); err != nil {
+			slog.WarnContext(ctx, "transferência agendada não executada", "transaction_id", transaction.ID, "error", err)
+			continue
+		}
+		executed++
+	}
+
+	return executed, nil
+}
+
+// reserveNextScheduled tira de agendada a próxima transferência vencida e reserva o saldo na mesma
+// transação do banco, para que ela nunca fique pendente sem reserva. Se a reserva falhar, a
+// transferência é marcada como falha; retorna nil e ErrTransactionNotFound quando não há mais nenhuma.
+func (uc *transactionUseCase) reserveNextScheduled(ctx context.Context) (*entity.Transaction, error) {
+	var transaction *entity.Transaction
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		transaction, err = uc.transactionRepo.LockNextDueScheduled(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := transaction.Activate(); err != nil {
+			return err
+		}
+		return uc.reserve(ctx, transaction, false)
+	})
+	if err != nil && transaction != nil {
+		uc.failScheduled(ctx, transaction.ID, err)
+	}
+
+	return transaction, err
+}
+
+// failScheduled registra a falha de uma transferência agendada cuja reserva foi recusada
+func (uc *transactionUseCase) failScheduled(ctx context.Context, id string, cause error) {
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		transaction, err := uc.transactionRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		// Outra instância pode ter executado a transferência depois que a reserva foi desfeita
+		if !transaction.IsScheduled() {
+			return nil
+		}
+
+		before := entity.AuditSnapshot(transaction)
+		transaction.Fail(scheduledFailureReason(cause))
+		if err := uc.transactionRepo.Update(ctx, transaction); err != nil {
+			return err
+		}
+		if err := uc.audit.Record(ctx, "transaction.fail", "transaction", transaction.ID, before, transaction); err != nil {
+			return err
+		}
+		return uc.events.Record(ctx, transaction)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao registrar falha da transação", "transaction_id", id, "error", err)
+	}
+}
+
+// ExpireHolds libera as reservas vencidas e falha as transações que ficaram presas
+func (uc *transactionUseCase) ExpireHolds(ctx context.Context) (int, error) {
+	holds, err := uc.holdRepo.ListExpired(ctx, time.Now(), 100)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, candidate := range holds {
+		err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			// Recarregar com bloqueio: a reserva pode ter sido efetivada nesse meio tempo
+			hold, err := uc.holdRepo.GetByTransactionID(ctx, candidate.TransactionID)
+			if err != nil {
+				return err
+			}
+			if !hold.IsExpired(time.Now()) {
+				return nil
+			}
+
+			transaction, err := uc.transactionRepo.GetByID(ctx, hold.TransactionID)
+			if err != nil {
+				return err
+			}
+			// Retidas para análise são falhadas pela expiração da fila de análise
+			if transaction.IsUnderReview() {
+				return nil
+			}
+
+			if err := uc.releaseHold(ctx, hold, transaction, "reserva de saldo expirada", hold.Expire); err != nil {
+				return err
+			}
+
+			expired++
+			return nil
+		})
+		if err != nil && !errors.Is(err, entity.ErrHoldNotActive) {
+			return expired, fmt.Errorf("erro ao expirar reserva %s: %w", candidate.ID, err)
+		}
+	}
+
+	return expired, nil
+}
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// TransferBatchUseCase define as operações de negócio para lotes de transferências
+type TransferBatchUseCase interface {
+	// CreateBatch valida todos os itens e enfileira o lote para processamento assíncrono
+	CreateBatch(ctx context.Context, payerID string, req *entity.CreateBatchRequest) (*entity.TransferBatchResponse, error)
+	GetBatch(ctx context.Context, payerID, id string) (*entity.TransferBatchResponse, error)
+	// ProcessPendingBatches processa os lotes pendentes, um por vez
+	ProcessPendingBatches(ctx context.Context) (int, error)
+}
+
+type transferBatchUseCase struct {
+	txManager    repository.TxManager
+	batchRepo    repository.TransferBatchRepository
+	userRepo     repository.UserRepository
+	pixKeyRepo   repository.PixKeyRepository
+	transactions TransactionUseCase
+	maxItems     int
+	lease        time.Duration
+	audit        AuditLogger
+}
+
+// NewTransferBatchUseCase cria uma nova instância do use case de lotes de transferências
+func NewTransferBatchUseCase(
+	txManager repository.TxManager,
+	batchRepo repository.TransferBatchRepository,
+	userRepo repository.UserRepository,
+	pixKeyRepo repository.PixKeyRepository,
+	transactions TransactionUseCase,
+	maxItems int,
+	lease time.Duration,
+	audit AuditLogger,
+) TransferBatchUseCase {
+	return &transferBatchUseCase{
+		txManager:    txManager,
+		batchRepo:    batchRepo,
+		userRepo:     userRepo,
+		pixKeyRepo:   pixKeyRepo,
+		transactions: transactions,
+		maxItems:     maxItems,
+		lease:        lease,
+		audit:        audit,
+	}
+}
+
+// CreateBatch valida pagador e itens antes de gravar o lote; nenhum item é gravado se houver erro
+func (uc *transferBatchUseCase) CreateBatch(ctx context.Context, payerID string, req *entity.CreateBatchRequest) (*entity.TransferBatchResponse, error) {
+	if len(req.Items) == 0 {
+		return nil, entity.ErrBatchEmpty
+	}
+	if len(req.Items) > uc.maxItems {
+		return nil, fmt.Errorf("%w (máximo: %d)", entity.ErrBatchTooLarge, uc.maxItems)
+	}
+
+	payer, err := uc.userRepo.GetByID(ctx, payerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := payer.ValidateCanSend(); err != nil {
+		return nil, err
+	}
+
+	batch, err := entity.NewTransferBatch(payer.ID, req.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	validation := &entity.BatchValidationError{}
+	for i, item := range req.Items {
+		position := i + 1
+
+		payeeID, payeeKey, err := uc.resolvePayee(ctx, item)
+		if err == nil {
+			err = batch.AddItem(position, payeeID, payeeKey, item.Amount)
+		}
+		if err != nil {
+			validation.Items = append(validation.Items, entity.BatchItemError{Position: position, Message: err.Error()})
+		}
+	}
+
+	if len(validation.Items) > 0 {
+		return nil, validation
+	}
+
+	// No modo tudo ou nada o saldo disponível precisa cobrir o lote inteiro
+	if batch.Mode == entity.BatchModeAllOrNothing && !payer.HasSufficientBalance(batch.TotalAmount) {
+		return nil, entity.ErrInsufficientBalance
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.batchRepo.Create(ctx, batch); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "transfer_batch.create", "transfer_batch", batch.ID, nil, batch)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return batch.ToTransferBatchResponse(), nil
+}
+
+// GetBatch retorna o lote do pagador com o resultado de cada item
+func (uc *transferBatchUseCase) GetBatch(ctx context.Context, payerID, id string) (*entity.TransferBatchResponse, error) {
+	batch, err := uc.batchRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Não revelar lotes de outros usuários
+	if batch.PayerID != payerID {
+		return nil, entity.ErrBatchNotFound
+	}
+
+	return batch.ToTransferBatchResponse(), nil
+}
+
+// ProcessPendingBatches processa os lotes pendentes até não restar nenhum
+func (uc *transferBatchUseCase) ProcessPendingBatches(ctx context.Context) (int, error) {
+	processed := 0
+
+	for ctx.Err() == nil {
+		batch, err := uc.batchRepo.ClaimNextPending(ctx, time.Now(), uc.lease)
+		if err != nil {
+			return processed, err
+		}
+		if batch == nil {
+			break
+		}
+
+		if err := uc.process(ctx, batch); err != nil {
+			slog.ErrorContext(ctx, "erro ao processar lote", "batch_id", batch.ID, "error", err)
+			uc.abort(ctx, batch, err)
+			continue
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// process executa os itens ainda sem resultado. O resultado de um item concluído é gravado na
+// mesma transação do banco que efetiva a transferência, então um lote retomado após a queda de
+// uma instância nunca repete uma transferência já concluída.
+func (uc *transferBatchUseCase) process(ctx context.Context, batch *entity.TransferBatch) error {
+	items := batch.PendingItems()
+	transactions := make([]*entity.Transaction, len(items))
+	for i, item := range items {
+		transaction, err := entity.NewTransaction(batch.PayerID, item.PayeeID, item.Amount)
+		if err != nil {
+			return err
+		}
+		transactions[i] = transaction
+	}
+
+	if batch.Mode == entity.BatchModeAllOrNothing {
+		return uc.processAtomic(ctx, batch, items, transactions)
+	}
+
+	for i, item := range items {
+		if err := uc.batchRepo.RenewLease(ctx, batch.ID, time.Now().Add(uc.lease)); err != nil {
+			return err
+		}
+
+		// O resultado de um lote é definitivo, então transferências que seriam retidas para análise são negadas
+		errs := uc.transactions.ExecuteAtomic(ctx, transactions[i:i+1], AtomicHooks{
+			OnComplete: func(ctx context.Context) error {
+				item.Complete(transactions[i].ID)
+				return uc.batchRepo.UpdateItem(ctx, item)
+			},
+		})
+		if errs[0] != nil {
+			if err := uc.failItem(ctx, item, transactions[i], errs[0]); err != nil {
+				return err
+			}
+		}
+	}
+
+	batch.Finish()
+	return uc.batchRepo.Update(ctx, batch)
+}
+
+// processAtomic executa o lote como uma unidade; itens e lote concluídos são gravados junto com os saldos
+func (uc *transferBatchUseCase) processAtomic(ctx context.Context, batch *entity.TransferBatch, items []*entity.BatchItem, transactions []*entity.Transaction) error {
+	if err := uc.batchRepo.RenewLease(ctx, batch.ID, time.Now().Add(uc.lease*time.Duration(len(items)))); err != nil {
+		return err
+	}
+
+	errs := uc.transactions.ExecuteAtomic(ctx, transactions, AtomicHooks{
+		OnComplete: func(ctx context.Context) error {
+			for i, item := range items {
+				item.Complete(transactions[i].ID)
+				if err := uc.batchRepo.UpdateItem(ctx, item); err != nil {
+					return err
+				}
+			}
+			batch.Finish()
+			return uc.batchRepo.Update(ctx, batch)
+		},
+	})
+	if errs[0] == nil {
+		return nil
+	}
+
+	for i, item := range items {
+		if err := uc.failItem(ctx, item, transactions[i], errs[i]); err != nil {
+			return err
+		}
+	}
+
+	batch.Finish()
+	return uc.batchRepo.Update(ctx, batch)
+}
+
+// failItem grava a falha do item com a transação, se ela chegou a ser reservada
+func (uc *transferBatchUseCase) failItem(ctx context.Context, item *entity.BatchItem, transaction *entity.Transaction, cause error) error {
+	// A transação só existe no banco se chegou a ser reservada
+	var transactionID *string
+	if transaction.IsFailed() || transaction.IsCompleted() {
+		transactionID = &transaction.ID
+	}
+	item.Fail(transactionID, cause.Error())
+	return uc.batchRepo.UpdateItem(ctx, item)
+}
+
+// abort encerra como falho o lote cujo processamento foi interrompido por um erro. Se nem isso for
+// possível, o lote continua em processamento e é retomado quando o prazo vencer.
+func (uc *transferBatchUseCase) abort(ctx context.Context, batch *entity.TransferBatch, cause error) {
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		batch.Abort("processamento do lote interrompido: " + cause.Error())
+		// Gravar todos os itens: o erro pode ter interrompido a gravação do resultado de algum
+		for _, item := range batch.Items {
+			if err := uc.batchRepo.UpdateItem(ctx, item); err != nil {
+				return err
+			}
+		}
+		return uc.batchRepo.Update(ctx, batch)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao encerrar lote com falha", "batch_id", batch.ID, "error", err)
+	}
+}
+
+func (uc *transferBatchUseCase) resolvePayee(ctx context.Context, item entity.BatchItemRequest) (string, *string, error) {
+	if item.PayeeKey != "" {
+		key, owner, err := resolvePixKey(ctx, uc.pixKeyRepo, uc.userRepo, item.PayeeKey)
+		if err != nil {
+			return "", nil, err
+		}
+		if item.PayeeID != "" && item.PayeeID != owner.ID {
+			return "", nil, errors.New("payee_id e payee_key indicam recebedores diferentes")
+		}
+		return owner.ID, &key.KeyValue, nil
+	}
+
+	if item.PayeeID == "" {
+		return "", nil, errors.New("payee_id ou payee_key é obrigatório")
+	}
+
+	if uuid.Validate(item.PayeeID) != nil {
+		return "", nil, errors.New("payee_id deve ser um UUID válido")
+	}
+
+	payee, err := uc.userRepo.GetByID(ctx, item.PayeeID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return payee.ID, nil, nil
+}
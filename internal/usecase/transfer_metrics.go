@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/metrics"
+	"payflow-api/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// transferEventStatus é o status a que cada evento de transferência leva
+var transferEventStatus = map[entity.DomainEventType]entity.TransactionStatus{
+	entity.EventTransferAuthorized: entity.TransactionStatusAuthorized,
+	entity.EventTransferCompleted:  entity.TransactionStatusCompleted,
+	entity.EventTransferFailed:     entity.TransactionStatusFailed,
+	entity.EventTransferReversed:   entity.TransactionStatusReversed,
+}
+
+// transferMetricsRecorder conta as transferências e soma os valores por status no momento em que a
+// mudança de status é confirmada. Um assinante do barramento contaria de novo cada evento entregue
+// mais de uma vez; o AfterCommit roda uma única vez por transação confirmada.
+type transferMetricsRecorder struct {
+	txManager repository.TxManager
+	next      DomainEventRecorder
+	metrics   *metrics.Metrics
+}
+
+// NewTransferMetricsRecorder envolve o gravador de eventos dos casos de uso que mudam o status das
+// transferências
+func NewTransferMetricsRecorder(txManager repository.TxManager, next DomainEventRecorder, m *metrics.Metrics) DomainEventRecorder {
+	return &transferMetricsRecorder{
+		txManager: txManager,
+		next:      next,
+		metrics:   m,
+	}
+}
+
+func (r *transferMetricsRecorder) Record(ctx context.Context, sources ...entity.EventSource) error {
+	var events pulledEvents
+	for _, source := range sources {
+		events = append(events, source.PullEvents()...)
+	}
+
+	observed := events
+	if err := r.next.Record(ctx, &events); err != nil {
+		return err
+	}
+
+	r.txManager.AfterCommit(ctx, func(ctx context.Context) {
+		for _, event := range observed {
+			if err := r.observe(event); err != nil {
+				slog.ErrorContext(ctx, "erro ao registrar métrica de transferência", "event_id", event.ID, "error", err)
+			}
+		}
+	})
+	return nil
+}
+
+func (r *transferMetricsRecorder) observe(event *entity.DomainEvent) error {
+	status, ok := transferEventStatus[event.Type]
+	if !ok {
+		return nil
+	}
+
+	var payload struct {
+		Amount decimal.Decimal `json:"amount"`
+	}
+	if err := event.Decode(&payload); err != nil {
+		return fmt.Errorf("erro ao ler valor da transferência do evento %s: %w", event.ID, err)
+	}
+
+	amount, _ := payload.Amount.Float64()
+	r.metrics.ObserveTransfer(string(status), amount)
+	return nil
+}
+
+// pulledEvents repassa ao próximo gravador os eventos já retirados das entidades
+type pulledEvents []*entity.DomainEvent
+
+func (e *pulledEvents) PullEvents() []*entity.DomainEvent {
+	events := *e
+	*e = nil
+	return events
+}
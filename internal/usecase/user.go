@@ -3,6 +3,9 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"time"
+
 	"payflow-api/internal/entity"
 	"payflow-api/internal/repository"
 
@@ -16,18 +19,41 @@ type UserUseCase interface {
 	GetUserByEmail(ctx context.Context, email string) (*entity.GetUserResponse, error)
 	UpdateUser(ctx context.Context, id string, req *entity.UpdateUserRequest) (*entity.GetUserResponse, error)
 	ListUsers(ctx context.Context, filters *entity.UserFilters) (*entity.ListUsersResponse, error)
-	DeleteUser(ctx context.Context, id string) error
+	// DeleteUser encerra e exclui logicamente a conta; os dados pessoais são anonimizados ao fim da retenção
+	DeleteUser(ctx context.Context, requesterID, id string) error
 	GetBalance(ctx context.Context, id string) (*entity.BalanceResponse, error)
+	// ChangeAccountStatus altera o status da conta em nome do administrador e registra a mudança na trilha de auditoria
+	ChangeAccountStatus(ctx context.Context, id, adminID string, req *entity.ChangeAccountStatusRequest) (*entity.GetUserResponse, error)
+	GetAccountStatusHistory(ctx context.Context, id string) (*entity.AccountStatusHistoryResponse, error)
+	// AnonymizeDeletedUsers anonimiza os usuários excluídos cujo prazo de retenção terminou
+	AnonymizeDeletedUsers(ctx context.Context) (int, error)
 }
 
 type userUseCase struct {
-	userRepo repository.UserRepository
+	txManager  repository.TxManager
+	userRepo   repository.UserRepository
+	pixKeyRepo repository.PixKeyRepository
+	retention  entity.RetentionPolicy
+	events     DomainEventRecorder
+	audit      AuditLogger
 }
 
 // NewUserUseCase cria uma nova instância do use case
-func NewUserUseCase(userRepo repository.UserRepository) UserUseCase {
+func NewUserUseCase(
+	txManager repository.TxManager,
+	userRepo repository.UserRepository,
+	pixKeyRepo repository.PixKeyRepository,
+	retention entity.RetentionPolicy,
+	events DomainEventRecorder,
+	audit AuditLogger,
+) UserUseCase {
 	return &userUseCase{
-		userRepo: userRepo,
+		txManager:  txManager,
+		userRepo:   userRepo,
+		pixKeyRepo: pixKeyRepo,
+		retention:  retention,
+		events:     events,
+		audit:      audit,
 	}
 }
 
@@ -59,9 +85,17 @@ func (uc *userUseCase) CreateUser(ctx context.Context, req *entity.CreateUserReq
 	user.Password = string(hashedPassword)
 
 	// Salvar no banco
-	err = uc.userRepo.Create(ctx, user)
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Create(ctx, user); err != nil {
+			return fmt.Errorf("erro ao salvar usuário: %w", err)
+		}
+		if err := uc.audit.Record(ctx, "user.create", "user", user.ID, nil, user); err != nil {
+			return err
+		}
+		return uc.events.Record(ctx, user)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("erro ao salvar usuário: %w", err)
+		return nil, err
 	}
 
 	// Retornar resposta
@@ -70,7 +104,7 @@ func (uc *userUseCase) CreateUser(ctx context.Context, req *entity.CreateUserReq
 
 // GetUser busca um usuário por ID
 func (uc *userUseCase) GetUser(ctx context.Context, id string) (*entity.GetUserResponse, error) {
-	user, err := uc.userRepo.GetByID(ctx, id)
+	user, err := uc.getActive(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -91,11 +125,13 @@ func (uc *userUseCase) GetUserByEmail(ctx context.Context, email string) (*entit
 // UpdateUser atualiza os dados de um usuário
 func (uc *userUseCase) UpdateUser(ctx context.Context, id string, req *entity.UpdateUserRequest) (*entity.GetUserResponse, error) {
 	// Buscar usuário existente
-	user, err := uc.userRepo.GetByID(ctx, id)
+	user, err := uc.getActive(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	before := entity.AuditSnapshot(user)
+
 	// Aplicar alterações
 	err = user.ApplyUpdateUserRequest(req)
 	if err != nil {
@@ -103,7 +139,12 @@ func (uc *userUseCase) UpdateUser(ctx context.Context, id string, req *entity.Up
 	}
 
 	// Salvar alterações
-	err = uc.userRepo.Update(ctx, user)
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "user.update", "user", user.ID, before, user)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -147,17 +188,179 @@ func (uc *userUseCase) ListUsers(ctx context.Context, filters *entity.UserFilter
 	}, nil
 }
 
-// DeleteUser remove um usuário
-func (uc *userUseCase) DeleteUser(ctx context.Context, id string) error {
-	return uc.userRepo.Delete(ctx, id)
+// DeleteUser atende o pedido de exclusão do titular: encerra a conta (exige saldo zerado),
+// remove as chaves Pix e marca o usuário como excluído. Transações e lançamentos continuam
+// referenciando a linha; nome, email e documento são apagados ao fim da retenção.
+func (uc *userUseCase) DeleteUser(ctx context.Context, requesterID, id string) error {
+	// Não revelar a existência de contas de outros usuários
+	if requesterID != id {
+		return entity.ErrUserNotFound
+	}
+
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		user, err := uc.userRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if user.IsDeleted() {
+			return entity.ErrUserNotFound
+		}
+		before := entity.AuditSnapshot(user)
+
+		now := time.Now()
+		if !user.IsClosed() {
+			if err := uc.applyStatus(ctx, user, entity.AccountStatusClosed, "exclusão solicitada pelo titular", id, now); err != nil {
+				return err
+			}
+		}
+
+		if err := user.SoftDelete(now); err != nil {
+			return err
+		}
+
+		if err := uc.userRepo.Delete(ctx, user); err != nil {
+			return err
+		}
+
+		if err := uc.pixKeyRepo.DeleteByUser(ctx, id); err != nil {
+			return err
+		}
+
+		if uc.retention.AnonymizeImmediately() {
+			if err := user.Anonymize(uc.retention, now); err != nil {
+				return err
+			}
+			if err := uc.userRepo.Anonymize(ctx, user); err != nil {
+				return err
+			}
+		}
+
+		return uc.audit.Record(ctx, "user.delete", "user", user.ID, before, user)
+	})
+}
+
+// AnonymizeDeletedUsers apaga os dados pessoais dos usuários excluídos há mais tempo que a retenção
+func (uc *userUseCase) AnonymizeDeletedUsers(ctx context.Context) (int, error) {
+	ids, err := uc.userRepo.ListPendingAnonymization(ctx, time.Now().Add(-uc.retention.Period), 100)
+	if err != nil {
+		return 0, err
+	}
+
+	anonymized := 0
+	for _, id := range ids {
+		err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			user, err := uc.userRepo.GetByIDForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			before := entity.AuditSnapshot(user)
+
+			if err := user.Anonymize(uc.retention, time.Now()); err != nil {
+				return err
+			}
+
+			if err := uc.userRepo.Anonymize(ctx, user); err != nil {
+				return err
+			}
+			return uc.audit.Record(ctx, "user.anonymize", "user", user.ID, before, user)
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "usuário não anonimizado", "user_id", id, "error", err)
+			continue
+		}
+		anonymized++
+	}
+
+	return anonymized, nil
 }
 
 // GetBalance retorna o saldo de um usuário
 func (uc *userUseCase) GetBalance(ctx context.Context, id string) (*entity.BalanceResponse, error) {
-	user, err := uc.userRepo.GetByID(ctx, id)
+	user, err := uc.getActive(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	return user.ToBalanceResponse(), nil
 }
+
+// ChangeAccountStatus aplica a mudança de status pedida pelo administrador identificado pela chave
+func (uc *userUseCase) ChangeAccountStatus(ctx context.Context, id, adminID string, req *entity.ChangeAccountStatusRequest) (*entity.GetUserResponse, error) {
+	user, err := uc.changeStatus(ctx, id, req.Status, req.Reason, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	return user.ToGetUserResponse(), nil
+}
+
+// GetAccountStatusHistory retorna o status atual e todas as mudanças registradas
+func (uc *userUseCase) GetAccountStatusHistory(ctx context.Context, id string) (*entity.AccountStatusHistoryResponse, error) {
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := uc.userRepo.ListStatusChanges(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.AccountStatusHistoryResponse{
+		UserID:  user.ID,
+		Status:  user.Status,
+		Changes: changes,
+	}, nil
+}
+
+// changeStatus bloqueia a conta, para que o saldo conferido no encerramento não mude em paralelo,
+// e grava o novo status junto com o registro de auditoria
+func (uc *userUseCase) changeStatus(ctx context.Context, id string, to entity.AccountStatus, reason, changedBy string) (*entity.User, error) {
+	var user *entity.User
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = uc.userRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := entity.AuditSnapshot(user)
+
+		if err := uc.applyStatus(ctx, user, to, reason, changedBy, time.Now()); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "user.status_change", "user", user.ID, before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// applyStatus muda o status de um usuário já bloqueado na transação atual
+func (uc *userUseCase) applyStatus(ctx context.Context, user *entity.User, to entity.AccountStatus, reason, changedBy string, now time.Time) error {
+	change, err := user.ChangeStatus(to, reason, changedBy, now)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.userRepo.UpdateStatus(ctx, user); err != nil {
+		return err
+	}
+
+	return uc.userRepo.AddStatusChange(ctx, change)
+}
+
+// getActive busca o usuário tratando os excluídos como inexistentes
+func (uc *userUseCase) getActive(ctx context.Context, id string) (*entity.User, error) {
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDeleted() {
+		return nil, entity.ErrUserNotFound
+	}
+
+	return user, nil
+}
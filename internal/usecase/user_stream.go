@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/repository"
+)
+
+// userStreamBatch é quantos eventos retidos são lidos por vez no replay
+const userStreamBatch = 100
+
+// UserStreamPolicy define a retenção dos eventos do stream e o limite de conexões simultâneas de
+// um usuário em cada instância
+type UserStreamPolicy struct {
+	Retention             time.Duration
+	MaxConnectionsPerUser int
+}
+
+// UserStreamUseCase define o stream em tempo real de saldo e transações do usuário
+type UserStreamUseCase interface {
+	// Open abre o stream do próprio usuário. lastEventID maior que zero retoma a conexão a partir
+	// do evento seguinte ao último recebido.
+	Open(ctx context.Context, requesterID, userID string, lastEventID int64) (*UserStream, error)
+	// HandleEvent é o assinante do barramento que converte eventos de domínio em mensagens do stream
+	HandleEvent(ctx context.Context, event *entity.DomainEvent) error
+	// PruneEvents apaga os eventos mais antigos que a retenção
+	PruneEvents(ctx context.Context) (int, error)
+}
+
+type userStreamUseCase struct {
+	txManager  repository.TxManager
+	streamRepo repository.UserStreamRepository
+	userRepo   repository.UserRepository
+	hub        *UserStreamHub
+	policy     UserStreamPolicy
+}
+
+// NewUserStreamUseCase cria uma nova instância do use case do stream de eventos do usuário
+func NewUserStreamUseCase(
+	txManager repository.TxManager,
+	streamRepo repository.UserStreamRepository,
+	userRepo repository.UserRepository,
+	hub *UserStreamHub,
+	policy UserStreamPolicy,
+) UserStreamUseCase {
+	return &userStreamUseCase{
+		txManager:  txManager,
+		streamRepo: streamRepo,
+		userRepo:   userRepo,
+		hub:        hub,
+		policy:     policy,
+	}
+}
+
+func (uc *userStreamUseCase) Open(ctx context.Context, requesterID, userID string, lastEventID int64) (*UserStream, error) {
+	// Não revelar a existência de contas de outros usuários
+	if requesterID != userID {
+		return nil, entity.ErrUserNotFound
+	}
+	if lastEventID < 0 {
+		return nil, entity.ErrInvalidLastEventID
+	}
+
+	// Inscrever antes de ler a posição inicial para não perder avisos entre as duas coisas
+	wake, release, err := uc.hub.subscribe(userID, uc.policy.MaxConnectionsPerUser)
+	if err != nil {
+		return nil, err
+	}
+	stream := &UserStream{
+		userID:  userID,
+		repo:    uc.streamRepo,
+		wake:    wake,
+		closed:  uc.hub.closed,
+		release: release,
+	}
+
+	if err := uc.position(ctx, stream, lastEventID); err != nil {
+		stream.Close()
+		return nil, err
+	}
+
+	if stream.Reset || lastEventID == 0 {
+		user, err := uc.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			stream.Close()
+			return nil, err
+		}
+		if user.IsDeleted() {
+			stream.Close()
+			return nil, entity.ErrUserNotFound
+		}
+		stream.Snapshot = user.ToBalanceResponse()
+	}
+
+	return stream, nil
+}
+
+// position define de onde o stream começa: depois do Last-Event-ID, se ainda estiver retido, ou
+// do evento mais recente, sem replay
+func (uc *userStreamUseCase) position(ctx context.Context, stream *UserStream, lastEventID int64) error {
+	if lastEventID > 0 {
+		oldest, err := uc.streamRepo.OldestID(ctx)
+		if err != nil {
+			return err
+		}
+		if oldest > 0 && lastEventID >= oldest {
+			stream.lastID = lastEventID
+			stream.pending = true
+			return nil
+		}
+		stream.Reset = true
+	}
+
+	latest, err := uc.streamRepo.LatestID(ctx, stream.userID)
+	if err != nil {
+		return err
+	}
+	stream.lastID = latest
+	return nil
+}
+
+func (uc *userStreamUseCase) HandleEvent(ctx context.Context, event *entity.DomainEvent) error {
+	events, err := entity.UserStreamEventsFrom(event)
+	if err != nil {
+		return fmt.Errorf("erro ao converter evento %s para o stream: %w", event.ID, err)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	// O aviso às instâncias sai no commit, quando os eventos já podem ser lidos
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		return uc.streamRepo.Append(ctx, events)
+	})
+}
+
+func (uc *userStreamUseCase) PruneEvents(ctx context.Context) (int, error) {
+	return uc.streamRepo.DeleteBefore(ctx, time.Now().Add(-uc.policy.Retention))
+}
+
+// UserStream é uma conexão aberta ao stream de um usuário
+type UserStream struct {
+	// Snapshot é o saldo atual, enviado ao abrir o stream sem Last-Event-ID ou depois de um reset
+	Snapshot *entity.BalanceResponse
+	// Reset indica que o Last-Event-ID informado não está mais retido
+	Reset bool
+
+	userID  string
+	repo    repository.UserStreamRepository
+	lastID  int64
+	pending bool
+	wake    <-chan struct{}
+	closed  <-chan struct{}
+	release func()
+	once    sync.Once
+}
+
+// Next retorna os próximos eventos, aguardando até wait por um aviso de evento novo. Sem eventos
+// no período retorna lista vazia, para que a conexão envie um heartbeat.
+func (s *UserStream) Next(ctx context.Context, wait time.Duration) ([]*entity.UserStreamEvent, error) {
+	if !s.pending {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-s.wake:
+		case <-timer.C:
+			return nil, nil
+		case <-s.closed:
+			return nil, entity.ErrUserStreamClosed
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	events, err := s.repo.ListAfter(ctx, s.userID, s.lastID, userStreamBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lote cheio: pode haver mais eventos, então a próxima chamada lê sem esperar aviso
+	s.pending = len(events) == userStreamBatch
+	if len(events) > 0 {
+		s.lastID = events[len(events)-1].ID
+	}
+	return events, nil
+}
+
+// Close libera a conexão no hub
+func (s *UserStream) Close() {
+	s.once.Do(s.release)
+}
+
+// UserStreamHub acorda as conexões abertas nesta instância quando chega o aviso de evento novo
+// de um usuário pelo LISTEN/NOTIFY do Postgres
+type UserStreamHub struct {
+	mu          sync.Mutex
+	connections map[string]map[chan struct{}]bool
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+// NewUserStreamHub cria o hub de conexões do stream
+func NewUserStreamHub() *UserStreamHub {
+	return &UserStreamHub{
+		connections: make(map[string]map[chan struct{}]bool),
+		closed:      make(chan struct{}),
+	}
+}
+
+// Close encerra as conexões abertas no desligamento do servidor; os clientes reconectam em outra
+// instância com o Last-Event-ID
+func (h *UserStreamHub) Close() {
+	h.closeOnce.Do(func() {
+		close(h.closed)
+	})
+}
+
+// HandleNotification recebe o payload do canal UserStreamChannel. Payload vazio indica que a escuta
+// reconectou e pode ter perdido avisos, então todas as conexões são acordadas.
+func (h *UserStreamHub) HandleNotification(payload string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if payload != "" {
+		wakeConnections(h.connections[payload])
+		return
+	}
+	for _, connections := range h.connections {
+		wakeConnections(connections)
+	}
+}
+
+func wakeConnections(connections map[chan struct{}]bool) {
+	for wake := range connections {
+		// Um aviso pendente já basta: a conexão lê tudo que chegou desde a última leitura
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (h *UserStreamHub) subscribe(userID string, limit int) (<-chan struct{}, func(), error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	connections := h.connections[userID]
+	if limit > 0 && len(connections) >= limit {
+		return nil, nil, entity.ErrTooManyUserStreams
+	}
+	if connections == nil {
+		connections = make(map[chan struct{}]bool)
+		h.connections[userID] = connections
+	}
+
+	wake := make(chan struct{}, 1)
+	connections[wake] = true
+
+	release := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(connections, wake)
+		if len(h.connections[userID]) == 0 {
+			delete(h.connections, userID)
+		}
+	}
+	return wake, release, nil
+}
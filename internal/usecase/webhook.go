@@ -0,0 +1,376 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"payflow-api/internal/entity"
+	"payflow-api/internal/gateway"
+	"payflow-api/internal/repository"
+)
+
+// webhookClaimBatch é quantas entregas são reservadas e enviadas em paralelo a cada rodada do worker
+const webhookClaimBatch = 20
+
+// WebhookPolicy define as novas tentativas e quanto tempo uma entrega fica reservada durante o envio
+type WebhookPolicy struct {
+	Retry entity.WebhookRetryPolicy
+	// Lease deve ser maior que o timeout do envio, para que outra instância não reenvie a mesma entrega
+	Lease time.Duration
+	URLs  entity.WebhookURLPolicy
+}
+
+// WebhookPublisher enfileira eventos para os endpoints dos lojistas. Deve ser chamado dentro da
+// transação da própria ação, para que o evento só seja entregue se a ação for confirmada.
+type WebhookPublisher interface {
+	// Publish cria uma entrega pendente para cada endpoint ativo do lojista que assina o evento.
+	// data é o objeto enviado no campo "data" do corpo.
+	Publish(ctx context.Context, merchantID string, eventType entity.WebhookEventType, data interface{}) error
+}
+
+// WebhookUseCase define as operações de negócio para os webhooks dos lojistas
+type WebhookUseCase interface {
+	WebhookPublisher
+	// CreateEndpoint cadastra um endpoint do lojista; o segredo de assinatura só é retornado aqui
+	CreateEndpoint(ctx context.Context, merchantID string, req *entity.CreateWebhookEndpointRequest) (*entity.WebhookEndpointResponse, error)
+	ListEndpoints(ctx context.Context, merchantID string) (*entity.ListWebhookEndpointsResponse, error)
+	GetEndpoint(ctx context.Context, merchantID, id string) (*entity.WebhookEndpointResponse, error)
+	UpdateEndpoint(ctx context.Context, merchantID, id string, req *entity.UpdateWebhookEndpointRequest) (*entity.WebhookEndpointResponse, error)
+	// DeleteEndpoint remove o endpoint e o histórico de entregas dele
+	DeleteEndpoint(ctx context.Context, merchantID, id string) error
+	// ListDeliveries lista o log de entregas do lojista
+	ListDeliveries(ctx context.Context, filters *entity.WebhookDeliveryFilters) (*entity.ListWebhookDeliveriesResponse, error)
+	// GetDelivery retorna a entrega com o corpo enviado e todas as tentativas
+	GetDelivery(ctx context.Context, merchantID, id string) (*entity.WebhookDeliveryResponse, error)
+	// Redeliver reenvia o evento de uma entrega concluída ou falha, como uma nova entrega
+	Redeliver(ctx context.Context, merchantID, id string) (*entity.WebhookDeliveryResponse, error)
+	// DeliverPending envia as entregas pendentes cuja próxima tentativa venceu
+	DeliverPending(ctx context.Context) (int, error)
+}
+
+type webhookUseCase struct {
+	txManager   repository.TxManager
+	webhookRepo repository.WebhookRepository
+	userRepo    repository.UserRepository
+	sender      gateway.WebhookSender
+	policy      WebhookPolicy
+	audit       AuditLogger
+}
+
+// NewWebhookUseCase cria uma nova instância do use case de webhooks
+func NewWebhookUseCase(
+	txManager repository.TxManager,
+	webhookRepo repository.WebhookRepository,
+	userRepo repository.UserRepository,
+	sender gateway.WebhookSender,
+	policy WebhookPolicy,
+	audit AuditLogger,
+) WebhookUseCase {
+	return &webhookUseCase{
+		txManager:   txManager,
+		webhookRepo: webhookRepo,
+		userRepo:    userRepo,
+		sender:      sender,
+		policy:      policy,
+		audit:       audit,
+	}
+}
+
+func (uc *webhookUseCase) Publish(ctx context.Context, merchantID string, eventType entity.WebhookEventType, data interface{}) error {
+	endpoints, err := uc.webhookRepo.ListSubscribedEndpoints(ctx, merchantID, eventType)
+	if err != nil || len(endpoints) == 0 {
+		return err
+	}
+
+	now := time.Now()
+	event := entity.NewWebhookEvent(eventType, merchantID, data, now)
+	for _, endpoint := range endpoints {
+		delivery, err := entity.NewWebhookDelivery(endpoint, event, now)
+		if err != nil {
+			return err
+		}
+		if err := uc.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateEndpoint cadastra o endpoint respeitando o limite por lojista
+func (uc *webhookUseCase) CreateEndpoint(ctx context.Context, merchantID string, req *entity.CreateWebhookEndpointRequest) (*entity.WebhookEndpointResponse, error) {
+	var endpoint *entity.WebhookEndpoint
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Bloquear o lojista para que cadastros simultâneos não ultrapassem o limite
+		merchant, err := uc.userRepo.GetByIDForUpdate(ctx, merchantID)
+		if err != nil {
+			return err
+		}
+		if merchant.IsDeleted() {
+			return entity.ErrUserNotFound
+		}
+
+		endpoint, err = entity.NewWebhookEndpoint(merchant, req.URL, req.Description, req.EventTypes, uc.policy.URLs, time.Now())
+		if err != nil {
+			return err
+		}
+
+		count, err := uc.webhookRepo.CountEndpoints(ctx, merchant.ID)
+		if err != nil {
+			return err
+		}
+		if count >= entity.WebhookMaxEndpoints {
+			return entity.ErrWebhookEndpointLimitReached
+		}
+
+		if err := uc.webhookRepo.CreateEndpoint(ctx, endpoint); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "webhook_endpoint.create", "webhook_endpoint", endpoint.ID, nil, endpoint)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := endpoint.ToWebhookEndpointResponse()
+	response.Secret = endpoint.Secret
+	return response, nil
+}
+
+func (uc *webhookUseCase) ListEndpoints(ctx context.Context, merchantID string) (*entity.ListWebhookEndpointsResponse, error) {
+	endpoints, err := uc.webhookRepo.ListEndpoints(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]entity.WebhookEndpointResponse, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		responses = append(responses, *endpoint.ToWebhookEndpointResponse())
+	}
+
+	return &entity.ListWebhookEndpointsResponse{
+		Endpoints:    responses,
+		Total:        len(responses),
+		MaxEndpoints: entity.WebhookMaxEndpoints,
+	}, nil
+}
+
+func (uc *webhookUseCase) GetEndpoint(ctx context.Context, merchantID, id string) (*entity.WebhookEndpointResponse, error) {
+	endpoint, err := uc.ownedEndpoint(ctx, merchantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoint.ToWebhookEndpointResponse(), nil
+}
+
+// UpdateEndpoint altera o endpoint; entregas pendentes de um endpoint desativado não são mais enviadas
+func (uc *webhookUseCase) UpdateEndpoint(ctx context.Context, merchantID, id string, req *entity.UpdateWebhookEndpointRequest) (*entity.WebhookEndpointResponse, error) {
+	var endpoint *entity.WebhookEndpoint
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		endpoint, err = uc.ownedEndpoint(ctx, merchantID, id)
+		if err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(endpoint)
+		if err := endpoint.ApplyUpdateWebhookEndpointRequest(req, uc.policy.URLs, time.Now()); err != nil {
+			return err
+		}
+
+		if err := uc.webhookRepo.UpdateEndpoint(ctx, endpoint); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "webhook_endpoint.update", "webhook_endpoint", endpoint.ID, before, endpoint)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoint.ToWebhookEndpointResponse(), nil
+}
+
+func (uc *webhookUseCase) DeleteEndpoint(ctx context.Context, merchantID, id string) error {
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		endpoint, err := uc.ownedEndpoint(ctx, merchantID, id)
+		if err != nil {
+			return err
+		}
+
+		before := entity.AuditSnapshot(endpoint)
+		if err := uc.webhookRepo.DeleteEndpoint(ctx, endpoint.ID); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "webhook_endpoint.delete", "webhook_endpoint", endpoint.ID, before, nil)
+	})
+}
+
+func (uc *webhookUseCase) ListDeliveries(ctx context.Context, filters *entity.WebhookDeliveryFilters) (*entity.ListWebhookDeliveriesResponse, error) {
+	// Validar paginação
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.Limit == 0 {
+		filters.Limit = 20
+	}
+	if filters.Limit > 100 {
+		filters.Limit = 100
+	}
+
+	deliveries, total, err := uc.webhookRepo.ListDeliveries(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]entity.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		responses = append(responses, *delivery.ToWebhookDeliveryResponse())
+	}
+
+	totalPages := (total + filters.Limit - 1) / filters.Limit
+
+	return &entity.ListWebhookDeliveriesResponse{
+		Deliveries: responses,
+		Total:      total,
+		Page:       filters.Page,
+		Limit:      filters.Limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (uc *webhookUseCase) GetDelivery(ctx context.Context, merchantID, id string) (*entity.WebhookDeliveryResponse, error) {
+	delivery, err := uc.ownedDelivery(ctx, merchantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := delivery.ToWebhookDeliveryResponse()
+	response.Payload = delivery.Payload
+	return response, nil
+}
+
+// Redeliver agenda o reenvio imediato; o evento mantém o ID para que o lojista possa descartar duplicados
+func (uc *webhookUseCase) Redeliver(ctx context.Context, merchantID, id string) (*entity.WebhookDeliveryResponse, error) {
+	var redelivery *entity.WebhookDelivery
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		original, err := uc.ownedDelivery(ctx, merchantID, id)
+		if err != nil {
+			return err
+		}
+
+		redelivery, err = original.Redeliver(time.Now())
+		if err != nil {
+			return err
+		}
+
+		if err := uc.webhookRepo.CreateDelivery(ctx, redelivery); err != nil {
+			return err
+		}
+		return uc.audit.Record(ctx, "webhook_delivery.redeliver", "webhook_delivery", redelivery.ID, nil, redelivery)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return redelivery.ToWebhookDeliveryResponse(), nil
+}
+
+// DeliverPending reserva as entregas vencidas em lotes e as envia em paralelo dentro de cada lote
+func (uc *webhookUseCase) DeliverPending(ctx context.Context) (int, error) {
+	delivered := 0
+
+	for ctx.Err() == nil {
+		deliveries, err := uc.webhookRepo.ClaimDueDeliveries(ctx, time.Now(), uc.policy.Lease, webhookClaimBatch)
+		if err != nil {
+			return delivered, err
+		}
+		if len(deliveries) == 0 {
+			break
+		}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			go func(delivery *entity.WebhookDelivery) {
+				defer wg.Done()
+				if uc.deliver(ctx, delivery) {
+					mu.Lock()
+					delivered++
+					mu.Unlock()
+				}
+			}(delivery)
+		}
+		wg.Wait()
+
+		if len(deliveries) < webhookClaimBatch {
+			break
+		}
+	}
+
+	return delivered, nil
+}
+
+// deliver faz uma tentativa e grava o resultado; retorna true se o endpoint confirmou o recebimento
+func (uc *webhookUseCase) deliver(ctx context.Context, delivery *entity.WebhookDelivery) bool {
+	endpoint, err := uc.webhookRepo.GetEndpoint(ctx, delivery.EndpointID)
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao carregar endpoint da entrega de webhook", "delivery_id", delivery.ID, "error", err)
+		return false
+	}
+
+	if !endpoint.Active {
+		delivery.Abandon("endpoint desativado", time.Now())
+		if err := uc.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			slog.ErrorContext(ctx, "erro ao encerrar entrega de webhook", "delivery_id", delivery.ID, "error", err)
+		}
+		return false
+	}
+
+	started := time.Now()
+	status, sendErr := uc.sender.Send(ctx, endpoint.URL, entity.WebhookHeaders(delivery, endpoint.Secret, started), delivery.Payload)
+	attempt := delivery.RecordAttempt(status, sendErr, time.Since(started), uc.policy.Retry, time.Now())
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.webhookRepo.AddAttempt(ctx, attempt); err != nil {
+			return err
+		}
+		return uc.webhookRepo.UpdateDelivery(ctx, delivery)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "erro ao registrar tentativa da entrega de webhook", "delivery_id", delivery.ID, "error", err)
+		return false
+	}
+
+	return delivery.Status == entity.WebhookDeliveryStatusSucceeded
+}
+
+// ownedEndpoint busca o endpoint do lojista; endpoints de outros lojistas não são revelados
+func (uc *webhookUseCase) ownedEndpoint(ctx context.Context, merchantID, id string) (*entity.WebhookEndpoint, error) {
+	endpoint, err := uc.webhookRepo.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.MerchantID != merchantID {
+		return nil, entity.ErrWebhookEndpointNotFound
+	}
+	return endpoint, nil
+}
+
+// ownedDelivery busca a entrega do lojista; entregas de outros lojistas não são reveladas
+func (uc *webhookUseCase) ownedDelivery(ctx context.Context, merchantID, id string) (*entity.WebhookDelivery, error) {
+	delivery, err := uc.webhookRepo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.MerchantID != merchantID {
+		return nil, entity.ErrWebhookDeliveryNotFound
+	}
+	return delivery, nil
+}
@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// DataExportJob gera as exportações de dados pendentes e descarta os arquivos com link vencido.
+func DataExportJob(dataExportUseCase usecase.DataExportUseCase) Job {
+	return func(ctx context.Context) error {
+		processed, err := dataExportUseCase.ProcessPendingExports(ctx)
+		if processed > 0 {
+			slog.InfoContext(ctx, "exportações de dados geradas", "count", processed)
+		}
+		if err != nil {
+			return err
+		}
+
+		expired, err := dataExportUseCase.ExpireExports(ctx)
+		if expired > 0 {
+			slog.InfoContext(ctx, "exportações de dados expiradas", "count", expired)
+		}
+		return err
+	}
+}
@@ -0,0 +1,20 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// DisputeSLAJob envia para decisão as disputas que o lojista não respondeu no prazo e escala as que
+// não foram decididas no prazo de decisão.
+func DisputeSLAJob(disputeUseCase usecase.DisputeUseCase) Job {
+	return func(ctx context.Context) error {
+		escalated, err := disputeUseCase.EscalateOverdueDisputes(ctx)
+		if escalated > 0 {
+			slog.InfoContext(ctx, "disputas vencidas escaladas", "count", escalated)
+		}
+		return err
+	}
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// EventPublishJob publica no broker os eventos de domínio ainda não publicados.
+func EventPublishJob(relay *usecase.EventPublishRelay) Job {
+	return func(ctx context.Context) error {
+		published, err := relay.PublishPending(ctx)
+		if published > 0 {
+			slog.InfoContext(ctx, "eventos de domínio publicados no broker", "count", published)
+		}
+		return err
+	}
+}
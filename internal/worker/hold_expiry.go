@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// HoldExpiryJob libera reservas de saldo vencidas e falha as transações correspondentes.
+func HoldExpiryJob(transactionUseCase usecase.TransactionUseCase) Job {
+	return func(ctx context.Context) error {
+		expired, err := transactionUseCase.ExpireHolds(ctx)
+		if expired > 0 {
+			slog.InfoContext(ctx, "reservas de saldo expiradas", "count", expired)
+		}
+		return err
+	}
+}
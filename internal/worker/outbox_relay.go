@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// OutboxRelayJob despacha os eventos de domínio que ficaram pendentes no outbox.
+func OutboxRelayJob(eventBus *usecase.EventBus) Job {
+	return func(ctx context.Context) error {
+		dispatched, err := eventBus.RelayPending(ctx)
+		if dispatched > 0 {
+			slog.InfoContext(ctx, "eventos de domínio despachados pelo relay", "count", dispatched)
+		}
+		return err
+	}
+}
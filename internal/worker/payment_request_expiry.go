@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// PaymentRequestExpiryJob expira as cobranças abertas que passaram da validade.
+func PaymentRequestExpiryJob(paymentRequestUseCase usecase.PaymentRequestUseCase) Job {
+	return func(ctx context.Context) error {
+		expired, err := paymentRequestUseCase.ExpirePaymentRequests(ctx)
+		if expired > 0 {
+			slog.InfoContext(ctx, "cobranças expiradas", "count", expired)
+		}
+		return err
+	}
+}
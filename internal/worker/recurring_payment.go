@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// RecurringPaymentJob gera as transferências agendadas das ocorrências vencidas dos pagamentos recorrentes.
+func RecurringPaymentJob(recurringUseCase usecase.RecurringUseCase) Job {
+	return func(ctx context.Context) error {
+		generated, err := recurringUseCase.GenerateDueRuns(ctx)
+		if generated > 0 {
+			slog.InfoContext(ctx, "cobranças recorrentes geradas", "count", generated)
+		}
+		return err
+	}
+}
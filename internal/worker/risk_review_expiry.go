@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// RiskReviewExpiryJob falha as transferências retidas que não foram analisadas no prazo.
+func RiskReviewExpiryJob(riskReviewUseCase usecase.RiskReviewUseCase) Job {
+	return func(ctx context.Context) error {
+		expired, err := riskReviewUseCase.ExpireOverdueReviews(ctx)
+		if expired > 0 {
+			slog.InfoContext(ctx, "itens da fila de análise expirados", "count", expired)
+		}
+		return err
+	}
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// ScheduledTransferJob executa as transferências agendadas cuja data chegou.
+func ScheduledTransferJob(transactionUseCase usecase.TransactionUseCase) Job {
+	return func(ctx context.Context) error {
+		executed, err := transactionUseCase.RunScheduledTransactions(ctx)
+		if executed > 0 {
+			slog.InfoContext(ctx, "transferências agendadas executadas", "count", executed)
+		}
+		return err
+	}
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// TransferBatchJob processa os lotes de transferências pendentes.
+func TransferBatchJob(batchUseCase usecase.TransferBatchUseCase) Job {
+	return func(ctx context.Context) error {
+		processed, err := batchUseCase.ProcessPendingBatches(ctx)
+		if processed > 0 {
+			slog.InfoContext(ctx, "lotes de transferências processados", "count", processed)
+		}
+		return err
+	}
+}
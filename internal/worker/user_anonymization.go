@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// UserAnonymizationJob apaga os dados pessoais dos usuários excluídos cujo prazo de retenção terminou.
+func UserAnonymizationJob(userUseCase usecase.UserUseCase) Job {
+	return func(ctx context.Context) error {
+		anonymized, err := userUseCase.AnonymizeDeletedUsers(ctx)
+		if anonymized > 0 {
+			slog.InfoContext(ctx, "usuários excluídos anonimizados", "count", anonymized)
+		}
+		return err
+	}
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// UserStreamPruneJob apaga os eventos do stream SSE que passaram da retenção.
+func UserStreamPruneJob(userStreamUseCase usecase.UserStreamUseCase) Job {
+	return func(ctx context.Context) error {
+		deleted, err := userStreamUseCase.PruneEvents(ctx)
+		if deleted > 0 {
+			slog.InfoContext(ctx, "eventos do stream apagados", "count", deleted)
+		}
+		return err
+	}
+}
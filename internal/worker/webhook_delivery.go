@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"payflow-api/internal/usecase"
+)
+
+// WebhookDeliveryJob envia aos lojistas as entregas de webhook pendentes e as novas tentativas vencidas.
+func WebhookDeliveryJob(webhookUseCase usecase.WebhookUseCase) Job {
+	return func(ctx context.Context) error {
+		delivered, err := webhookUseCase.DeliverPending(ctx)
+		if delivered > 0 {
+			slog.InfoContext(ctx, "webhooks entregues", "count", delivered)
+		}
+		return err
+	}
+}
@@ -0,0 +1,37 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"payflow-api/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Job é uma rotina executada periodicamente em segundo plano.
+type Job func(ctx context.Context) error
+
+// RunEvery executa job a cada intervalo até que o contexto seja cancelado.
+// Erros são registrados e não interrompem as próximas execuções. Cada execução recebe um
+// request_id próprio, que correlaciona os logs dos casos de uso chamados por ela.
+func RunEvery(ctx context.Context, name string, interval time.Duration, job Job) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	slog.InfoContext(ctx, "worker iniciado", "worker", name, "interval", interval.String())
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "worker finalizado", "worker", name)
+			return
+		case <-ticker.C:
+			runCtx := logger.WithRequestID(ctx, uuid.New().String())
+			if err := job(runCtx); err != nil {
+				slog.ErrorContext(runCtx, "erro no worker", "worker", name, "error", err)
+			}
+		}
+	}
+}
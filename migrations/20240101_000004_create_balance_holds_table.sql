@@ -0,0 +1,28 @@
+-- Migration: 20240101_000004_create_balance_holds_table.sql
+-- Reservas de saldo entre a autorização e a conclusão das transferências
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS held_balance DECIMAL(15,2) NOT NULL DEFAULT 0.00,
+    ADD CONSTRAINT check_held_balance CHECK (held_balance >= 0 AND held_balance <= balance);
+
+CREATE TABLE IF NOT EXISTS balance_holds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    transaction_id UUID NOT NULL UNIQUE REFERENCES transactions(id) ON DELETE RESTRICT,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('active', 'captured', 'released', 'expired')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    settled_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Índices para melhor performance
+CREATE INDEX idx_balance_holds_user_id ON balance_holds(user_id);
+CREATE INDEX idx_balance_holds_active_expiry ON balance_holds(expires_at) WHERE status = 'active';
+
+-- Trigger para atualizar updated_at automaticamente
+CREATE TRIGGER update_balance_holds_updated_at
+    BEFORE UPDATE ON balance_holds
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,47 @@
+-- Migration: 20240101_000005_create_transaction_limits_tables.sql
+-- Limites de transferência por tipo de usuário, sobrescritas por usuário e contadores de uso por período
+
+CREATE TABLE IF NOT EXISTS default_transaction_limits (
+    user_type VARCHAR(20) PRIMARY KEY CHECK (user_type IN ('common', 'merchant')),
+    per_transaction DECIMAL(15,2) NOT NULL CHECK (per_transaction >= 0),
+    daily DECIMAL(15,2) NOT NULL CHECK (daily >= 0),
+    monthly DECIMAL(15,2) NOT NULL CHECK (monthly >= 0),
+    nightly DECIMAL(15,2) NOT NULL CHECK (nightly >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_transaction_limits (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    per_transaction DECIMAL(15,2) CHECK (per_transaction >= 0),
+    daily DECIMAL(15,2) CHECK (daily >= 0),
+    monthly DECIMAL(15,2) CHECK (monthly >= 0),
+    nightly DECIMAL(15,2) CHECK (nightly >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Contadores de uso: um registro por usuário, período e chave do período (ex.: 2024-01-15, 2024-01)
+CREATE TABLE IF NOT EXISTS transaction_limit_usage (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period VARCHAR(10) NOT NULL CHECK (period IN ('daily', 'monthly', 'nightly')),
+    period_key VARCHAR(10) NOT NULL,
+    amount DECIMAL(15,2) NOT NULL DEFAULT 0.00 CHECK (amount >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, period, period_key)
+);
+
+-- Limites padrão (limite noturno segue a regulamentação do Pix para pessoas físicas)
+INSERT INTO default_transaction_limits (user_type, per_transaction, daily, monthly, nightly) VALUES
+('common', 10000.00, 20000.00, 100000.00, 1000.00),
+('merchant', 50000.00, 200000.00, 1000000.00, 50000.00)
+ON CONFLICT (user_type) DO NOTHING;
+
+-- Trigger para atualizar updated_at automaticamente
+CREATE TRIGGER update_default_transaction_limits_updated_at
+    BEFORE UPDATE ON default_transaction_limits
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_user_transaction_limits_updated_at
+    BEFORE UPDATE ON user_transaction_limits
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,75 @@
+-- Migration: 20240101_000006_create_pricing_tables.sql
+-- Planos de tarifas (MDR), atribuição por lojista e conta de receita da plataforma
+
+CREATE TABLE IF NOT EXISTS pricing_plans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    fee_type VARCHAR(20) NOT NULL CHECK (fee_type IN ('fixed', 'percentage', 'tiered')),
+    fixed_amount DECIMAL(15,2) NOT NULL DEFAULT 0.00 CHECK (fixed_amount >= 0),
+    percentage DECIMAL(7,4) NOT NULL DEFAULT 0.0000 CHECK (percentage >= 0 AND percentage <= 100),
+    tiers JSONB,
+    min_fee DECIMAL(15,2) CHECK (min_fee >= 0),
+    max_fee DECIMAL(15,2) CHECK (max_fee >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Plano padrão por tipo de usuário e plano específico por lojista
+CREATE TABLE IF NOT EXISTS default_pricing_plans (
+    user_type VARCHAR(20) PRIMARY KEY CHECK (user_type IN ('common', 'merchant')),
+    pricing_plan_id UUID NOT NULL REFERENCES pricing_plans(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS user_pricing_plans (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    pricing_plan_id UUID NOT NULL REFERENCES pricing_plans(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Contas internas da plataforma
+CREATE TABLE IF NOT EXISTS platform_accounts (
+    code VARCHAR(30) PRIMARY KEY,
+    balance DECIMAL(15,2) NOT NULL DEFAULT 0.00,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Lançamentos de tarifas na conta de receita
+CREATE TABLE IF NOT EXISTS fee_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE RESTRICT,
+    pricing_plan_id UUID REFERENCES pricing_plans(id) ON DELETE RESTRICT,
+    account_code VARCHAR(30) NOT NULL REFERENCES platform_accounts(code),
+    amount DECIMAL(15,2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS fee_amount DECIMAL(15,2) NOT NULL DEFAULT 0.00 CHECK (fee_amount >= 0),
+    ADD COLUMN IF NOT EXISTS pricing_plan_id UUID REFERENCES pricing_plans(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT check_fee_below_amount CHECK (fee_amount < amount);
+
+-- Índices para melhor performance
+CREATE INDEX idx_fee_entries_transaction_id ON fee_entries(transaction_id);
+CREATE INDEX idx_fee_entries_created_at ON fee_entries(created_at);
+
+-- Conta de receita e plano MDR padrão para lojistas
+INSERT INTO platform_accounts (code) VALUES ('revenue') ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO pricing_plans (id, name, fee_type, percentage) VALUES
+('770e8400-e29b-41d4-a716-446655440001', 'MDR padrão lojista', 'percentage', 1.9900)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO default_pricing_plans (user_type, pricing_plan_id) VALUES
+('merchant', '770e8400-e29b-41d4-a716-446655440001')
+ON CONFLICT (user_type) DO NOTHING;
+
+-- Trigger para atualizar updated_at automaticamente
+CREATE TRIGGER update_pricing_plans_updated_at
+    BEFORE UPDATE ON pricing_plans
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_platform_accounts_updated_at
+    BEFORE UPDATE ON platform_accounts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
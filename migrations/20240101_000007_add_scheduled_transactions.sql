@@ -0,0 +1,13 @@
+-- Migration: 20240101_000007_add_scheduled_transactions.sql
+-- Transferências agendadas para data futura e cancelamento antes da execução
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('pending', 'authorized', 'completed', 'failed', 'reversed', 'scheduled', 'cancelled'));
+
+-- Índice parcial usado pelo agendador para encontrar transferências vencidas
+CREATE INDEX idx_transactions_scheduled_due ON transactions(scheduled_for) WHERE status = 'scheduled';
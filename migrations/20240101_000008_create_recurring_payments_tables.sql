@@ -0,0 +1,46 @@
+-- Migration: 20240101_000008_create_recurring_payments_tables.sql
+-- Pagamentos recorrentes (mandatos) de usuários comuns para lojistas e histórico de execuções
+
+CREATE TABLE IF NOT EXISTS recurring_mandates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    payer_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    payee_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    description VARCHAR(140) NOT NULL DEFAULT '',
+    frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('weekly', 'monthly', 'cron')),
+    cron_expression VARCHAR(100),
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE,
+    max_occurrences INTEGER CHECK (max_occurrences > 0),
+    occurrences INTEGER NOT NULL DEFAULT 0,
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('active', 'paused', 'cancelled', 'completed')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT check_mandate_different_users CHECK (payer_id != payee_id),
+    CONSTRAINT check_mandate_cron CHECK (frequency != 'cron' OR cron_expression IS NOT NULL)
+);
+
+-- Cada período de um mandato gera no máximo uma execução (idempotência do runner)
+CREATE TABLE IF NOT EXISTS recurring_mandate_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    mandate_id UUID NOT NULL REFERENCES recurring_mandates(id) ON DELETE RESTRICT,
+    period_key VARCHAR(20) NOT NULL,
+    occurrence_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    transaction_id UUID NOT NULL UNIQUE REFERENCES transactions(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_mandate_period UNIQUE (mandate_id, period_key)
+);
+
+-- Índices para melhor performance
+CREATE INDEX idx_recurring_mandates_payer_id ON recurring_mandates(payer_id);
+CREATE INDEX idx_recurring_mandates_due ON recurring_mandates(next_run_at) WHERE status = 'active';
+
+-- Trigger para atualizar updated_at automaticamente
+CREATE TRIGGER update_recurring_mandates_updated_at
+    BEFORE UPDATE ON recurring_mandates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,32 @@
+-- Migration: 20240101_000009_create_payment_requests_table.sql
+-- Cobranças emitidas por lojistas e pagas por usuários comuns
+
+CREATE TABLE IF NOT EXISTS payment_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    merchant_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    payer_id UUID REFERENCES users(id) ON DELETE RESTRICT,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    description VARCHAR(140) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('open', 'paid', 'expired', 'cancelled')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- Última tentativa de pagamento; quando paga, a transação que quitou a cobrança
+    transaction_id UUID REFERENCES transactions(id) ON DELETE RESTRICT,
+    paid_by UUID REFERENCES users(id) ON DELETE RESTRICT,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT check_payment_request_different_users CHECK (payer_id IS NULL OR payer_id != merchant_id),
+    CONSTRAINT check_payment_request_paid CHECK (status != 'paid' OR (transaction_id IS NOT NULL AND paid_at IS NOT NULL))
+);
+
+-- Índices para melhor performance
+CREATE INDEX idx_payment_requests_merchant_id ON payment_requests(merchant_id);
+CREATE INDEX idx_payment_requests_payer_id ON payment_requests(payer_id);
+CREATE INDEX idx_payment_requests_open_expires_at ON payment_requests(expires_at) WHERE status = 'open';
+
+-- Trigger para atualizar updated_at automaticamente
+CREATE TRIGGER update_payment_requests_updated_at
+    BEFORE UPDATE ON payment_requests
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
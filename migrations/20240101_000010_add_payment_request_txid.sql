@@ -0,0 +1,12 @@
+-- Migration: 20240101_000010_add_payment_request_txid.sql
+-- Identificador da cobrança usado nos QR Codes BR Code (até 25 caracteres alfanuméricos)
+
+ALTER TABLE payment_requests ADD COLUMN txid VARCHAR(25);
+
+UPDATE payment_requests
+SET txid = UPPER(SUBSTRING(REPLACE(id::text, '-', '') FROM 1 FOR 25))
+WHERE txid IS NULL;
+
+ALTER TABLE payment_requests ALTER COLUMN txid SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_payment_requests_txid ON payment_requests(txid);
@@ -0,0 +1,16 @@
+-- Migration: 20240101_000011_create_pix_keys_table.sql
+-- Diretório de chaves Pix (CPF, CNPJ, email, telefone E.164 ou aleatória) dos usuários
+
+CREATE TABLE IF NOT EXISTS pix_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key_type VARCHAR(10) NOT NULL CHECK (key_type IN ('cpf', 'cnpj', 'email', 'phone', 'evp')),
+    key_value VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- Uma chave identifica um único usuário em todo o sistema
+    CONSTRAINT unique_pix_key_value UNIQUE (key_value)
+);
+
+-- Índices para melhor performance
+CREATE INDEX idx_pix_keys_user_id ON pix_keys(user_id);
@@ -0,0 +1,41 @@
+-- Migration: 20240101_000012_create_transfer_batches_tables.sql
+-- Lotes de transferências (pagamentos em massa) processados em segundo plano
+
+CREATE TABLE IF NOT EXISTS transfer_batches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    payer_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    mode VARCHAR(20) NOT NULL CHECK (mode IN ('all_or_nothing', 'best_effort')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'processing', 'completed', 'partially_completed', 'failed')),
+    total_items INTEGER NOT NULL CHECK (total_items > 0),
+    total_amount DECIMAL(15,2) NOT NULL CHECK (total_amount > 0),
+    succeeded_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS transfer_batch_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    batch_id UUID NOT NULL REFERENCES transfer_batches(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    payee_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    payee_key VARCHAR(100),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'completed', 'failed')),
+    transaction_id UUID REFERENCES transactions(id) ON DELETE RESTRICT,
+    failure_reason TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_batch_item_position UNIQUE (batch_id, position)
+);
+
+-- Índices para melhor performance
+CREATE INDEX idx_transfer_batches_payer_id ON transfer_batches(payer_id);
+CREATE INDEX idx_transfer_batches_pending ON transfer_batches(created_at) WHERE status = 'pending';
+
+-- Trigger para atualizar updated_at automaticamente
+CREATE TRIGGER update_transfer_batches_updated_at
+    BEFORE UPDATE ON transfer_batches
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
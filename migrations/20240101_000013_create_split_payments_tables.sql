@@ -0,0 +1,61 @@
+-- Migration: 20240101_000013_create_split_payments_tables.sql
+-- Pagamentos divididos entre vários recebedores e a plataforma, com estornos proporcionais
+
+CREATE TABLE IF NOT EXISTS split_payments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    payer_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    description VARCHAR(140) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'completed', 'failed', 'partially_refunded', 'refunded')),
+    refunded_amount DECIMAL(15,2) NOT NULL DEFAULT 0.00,
+    failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT check_split_refund_within_amount CHECK (refunded_amount >= 0 AND refunded_amount <= amount)
+);
+
+-- payee_id nulo indica a parte da plataforma
+CREATE TABLE IF NOT EXISTS split_payment_legs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    split_payment_id UUID NOT NULL REFERENCES split_payments(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    payee_id UUID REFERENCES users(id) ON DELETE RESTRICT,
+    payee_key VARCHAR(100),
+    split_type VARCHAR(20) NOT NULL CHECK (split_type IN ('percentage', 'fixed')),
+    value DECIMAL(15,4) NOT NULL CHECK (value > 0),
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    refunded_amount DECIMAL(15,2) NOT NULL DEFAULT 0.00,
+    transaction_id UUID UNIQUE REFERENCES transactions(id) ON DELETE RESTRICT,
+
+    CONSTRAINT unique_split_leg_position UNIQUE (split_payment_id, position),
+    CONSTRAINT check_split_leg_refund_within_amount CHECK (refunded_amount >= 0 AND refunded_amount <= amount)
+);
+
+CREATE TABLE IF NOT EXISTS split_payment_refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    split_payment_id UUID NOT NULL REFERENCES split_payments(id) ON DELETE CASCADE,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL DEFAULT '',
+    legs JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Lançamentos da parte da plataforma não têm transação própria
+ALTER TABLE fee_entries
+    ALTER COLUMN transaction_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS split_payment_id UUID REFERENCES split_payments(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT check_fee_entry_source CHECK (transaction_id IS NOT NULL OR split_payment_id IS NOT NULL);
+
+-- Índices para melhor performance
+CREATE INDEX idx_split_payments_payer_id ON split_payments(payer_id);
+CREATE INDEX idx_split_payment_legs_split_id ON split_payment_legs(split_payment_id);
+CREATE INDEX idx_split_payment_refunds_split_id ON split_payment_refunds(split_payment_id);
+CREATE INDEX idx_fee_entries_split_payment_id ON fee_entries(split_payment_id);
+
+-- Trigger para atualizar updated_at automaticamente
+CREATE TRIGGER update_split_payments_updated_at
+    BEFORE UPDATE ON split_payments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
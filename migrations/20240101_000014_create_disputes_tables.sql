@@ -0,0 +1,53 @@
+-- Migration: 20240101_000014_create_disputes_tables.sql
+-- Disputas (chargebacks) de transações concluídas, com evidências, prazos e histórico de status
+
+CREATE TABLE IF NOT EXISTS disputes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id UUID NOT NULL UNIQUE REFERENCES transactions(id) ON DELETE RESTRICT,
+    payer_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    merchant_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('unauthorized', 'not_received', 'duplicate', 'incorrect_amount', 'other')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('open', 'under_review', 'won_by_payer', 'won_by_merchant')),
+    response_due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    resolution_due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    resolution_note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS dispute_evidence (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    dispute_id UUID NOT NULL REFERENCES disputes(id) ON DELETE CASCADE,
+    party VARCHAR(20) NOT NULL CHECK (party IN ('payer', 'merchant')),
+    submitted_by UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    description TEXT NOT NULL,
+    attachments JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Histórico de status: apenas inserções
+CREATE TABLE IF NOT EXISTS dispute_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    dispute_id UUID NOT NULL REFERENCES disputes(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(20) NOT NULL CHECK (actor IN ('payer', 'merchant', 'admin', 'system')),
+    actor_id UUID,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Índices para melhor performance
+CREATE INDEX idx_disputes_payer_id ON disputes(payer_id);
+CREATE INDEX idx_disputes_merchant_id ON disputes(merchant_id);
+CREATE INDEX idx_disputes_status ON disputes(status);
+CREATE INDEX idx_disputes_response_due ON disputes(response_due_at) WHERE status = 'open';
+CREATE INDEX idx_dispute_evidence_dispute_id ON dispute_evidence(dispute_id);
+CREATE INDEX idx_dispute_events_dispute_id ON dispute_events(dispute_id);
+
+-- Trigger para atualizar updated_at automaticamente
+CREATE TRIGGER update_disputes_updated_at
+    BEFORE UPDATE ON disputes
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,35 @@
+-- Migration: 20240101_000015_create_risk_reviews_table.sql
+-- Análise de risco antes da autorização: transferências retidas e fila de análise manual
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('pending', 'authorized', 'completed', 'failed', 'reversed', 'scheduled', 'cancelled', 'under_review'));
+
+CREATE TABLE IF NOT EXISTS risk_reviews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id UUID NOT NULL UNIQUE REFERENCES transactions(id) ON DELETE RESTRICT,
+    payer_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    payee_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount > 0),
+    decision VARCHAR(10) NOT NULL CHECK (decision IN ('review', 'deny')),
+    reasons JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'denied')),
+    due_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT risk_reviews_due_at_check CHECK (status <> 'pending' OR due_at IS NOT NULL)
+);
+
+-- Fila de análise: itens pendentes pelo prazo
+CREATE INDEX idx_risk_reviews_pending ON risk_reviews(due_at) WHERE status = 'pending';
+CREATE INDEX idx_risk_reviews_payer ON risk_reviews(payer_id);
+
+-- Velocidade e histórico do pagador consultados a cada transferência
+CREATE INDEX IF NOT EXISTS idx_transactions_payer_created ON transactions(payer_id, created_at);
+
+CREATE TRIGGER update_risk_reviews_updated_at
+    BEFORE UPDATE ON risk_reviews
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,29 @@
+-- Migration: 20240101_000016_create_risk_review_queue.sql
+-- Fila de análise manual: atribuição a analistas, decisão, expiração por prazo e histórico de ações
+
+ALTER TABLE risk_reviews DROP CONSTRAINT IF EXISTS risk_reviews_status_check;
+ALTER TABLE risk_reviews
+    ADD CONSTRAINT risk_reviews_status_check
+    CHECK (status IN ('pending', 'claimed', 'approved', 'rejected', 'expired', 'denied'));
+
+ALTER TABLE risk_reviews
+    ADD COLUMN IF NOT EXISTS claimed_by VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS decided_by VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS decided_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS decision_note TEXT;
+
+DROP INDEX IF EXISTS idx_risk_reviews_pending;
+CREATE INDEX idx_risk_reviews_open ON risk_reviews(due_at) WHERE status IN ('pending', 'claimed');
+
+-- Trilha de auditoria das decisões: apenas inserções
+CREATE TABLE IF NOT EXISTS risk_review_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    review_id UUID NOT NULL REFERENCES risk_reviews(id) ON DELETE RESTRICT,
+    action VARCHAR(30) NOT NULL CHECK (action IN ('created', 'claimed', 'approved', 'rejected', 'expired', 'authorization_failed')),
+    actor VARCHAR(100) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_risk_review_events_review ON risk_review_events(review_id, created_at);
@@ -0,0 +1,22 @@
+-- Migration: 20240101_000017_add_account_status.sql
+-- Ciclo de vida da conta (ativa, bloqueada, congelada, encerrada) com histórico auditável das mudanças
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'blocked', 'frozen', 'closed')),
+    ADD COLUMN IF NOT EXISTS status_reason TEXT;
+
+CREATE INDEX idx_users_status ON users(status) WHERE status <> 'active';
+
+-- Trilha de auditoria das mudanças de status: apenas inserções
+CREATE TABLE IF NOT EXISTS account_status_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    from_status VARCHAR(20) NOT NULL CHECK (from_status IN ('active', 'blocked', 'frozen', 'closed')),
+    to_status VARCHAR(20) NOT NULL CHECK (to_status IN ('active', 'blocked', 'frozen', 'closed')),
+    reason TEXT NOT NULL,
+    changed_by VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_account_status_changes_user ON account_status_changes(user_id, created_at);
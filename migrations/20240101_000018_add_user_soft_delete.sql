@@ -0,0 +1,16 @@
+-- Migration: 20240101_000018_add_user_soft_delete.sql
+-- Exclusão lógica de usuários e anonimização dos dados pessoais após o prazo de retenção (LGPD)
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMP WITH TIME ZONE;
+
+-- Email e documento só precisam ser únicos entre as contas não excluídas,
+-- para que o titular possa abrir uma nova conta depois da exclusão
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_document_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX idx_users_document_active ON users(document) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_users_email_active ON users(email) WHERE deleted_at IS NULL;
+
+-- Contas excluídas aguardando o fim da retenção
+CREATE INDEX idx_users_pending_anonymization ON users(deleted_at) WHERE deleted_at IS NOT NULL AND anonymized_at IS NULL;
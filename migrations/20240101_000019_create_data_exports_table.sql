@@ -0,0 +1,22 @@
+-- Migration: 20240101_000019_create_data_exports_table.sql
+-- Exportações dos dados do titular (LGPD): geradas por worker e baixadas uma única vez por link assinado
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'processing', 'ready', 'downloaded', 'expired', 'failed')),
+    -- O arquivo é descartado após o download ou a expiração
+    archive BYTEA,
+    size_bytes INTEGER NOT NULL DEFAULT 0,
+    failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    ready_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    downloaded_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Índices para melhor performance
+CREATE INDEX idx_data_exports_user_id ON data_exports(user_id, created_at DESC);
+CREATE INDEX idx_data_exports_pending ON data_exports(created_at) WHERE status = 'pending';
+CREATE INDEX idx_data_exports_ready ON data_exports(expires_at) WHERE status = 'ready';
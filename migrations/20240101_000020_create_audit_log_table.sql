@@ -0,0 +1,44 @@
+-- Migration: 20240101_000020_create_audit_log_table.sql
+-- Trilha de auditoria somente de inserção: cada registro guarda o hash do anterior
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY,
+    sequence BIGINT NOT NULL UNIQUE CHECK (sequence > 0),
+    actor_type VARCHAR(20) NOT NULL CHECK (actor_type IN ('user', 'admin', 'system', 'anonymous')),
+    actor_id VARCHAR(100),
+    action VARCHAR(100) NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(100) NOT NULL,
+    -- Campos alterados com o valor anterior e o novo, já sem segredos e documentos
+    changes JSONB NOT NULL DEFAULT '{}',
+    request_id VARCHAR(100),
+    ip VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL
+);
+
+-- Índices para melhor performance
+CREATE INDEX idx_audit_log_actor ON audit_log(actor_type, actor_id, created_at DESC);
+CREATE INDEX idx_audit_log_resource ON audit_log(resource_type, resource_id, created_at DESC);
+CREATE INDEX idx_audit_log_action ON audit_log(action, created_at DESC);
+CREATE INDEX idx_audit_log_request_id ON audit_log(request_id) WHERE request_id IS NOT NULL;
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at DESC);
+
+-- A trilha não pode ser alterada nem apagada pela aplicação
+CREATE OR REPLACE FUNCTION reject_audit_log_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log é somente de inserção';
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW
+    EXECUTE FUNCTION reject_audit_log_changes();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION reject_audit_log_changes();
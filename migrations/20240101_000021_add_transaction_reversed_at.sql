@@ -0,0 +1,13 @@
+-- Migration: 20240101_000021_add_transaction_reversed_at.sql
+-- Data do estorno das transferências, usada para posicionar o estorno no extrato
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS reversed_at TIMESTAMP WITH TIME ZONE;
+
+-- Transferências revertidas antes desta migration usam a última atualização como data do estorno
+UPDATE transactions SET reversed_at = updated_at WHERE status = 'reversed' AND reversed_at IS NULL;
+
+-- Índices para os lançamentos do extrato
+CREATE INDEX IF NOT EXISTS idx_transactions_payer_completed_at ON transactions(payer_id, completed_at) WHERE completed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_payee_completed_at ON transactions(payee_id, completed_at) WHERE completed_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_split_payment_legs_payee_id ON split_payment_legs(payee_id) WHERE payee_id IS NOT NULL;
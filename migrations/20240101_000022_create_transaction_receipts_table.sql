@@ -0,0 +1,12 @@
+-- Migration: 20240101_000022_create_transaction_receipts_table.sql
+-- Comprovantes de transferências: emitidos uma vez, com o conteúdo exato que foi assinado
+
+CREATE TABLE IF NOT EXISTS transaction_receipts (
+    transaction_id UUID PRIMARY KEY REFERENCES transactions(id) ON DELETE RESTRICT,
+    authentication_code VARCHAR(24) NOT NULL UNIQUE,
+    -- Bytes assinados; guardados como estão para que a assinatura continue conferindo
+    payload BYTEA NOT NULL,
+    signature BYTEA NOT NULL,
+    key_id VARCHAR(32) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
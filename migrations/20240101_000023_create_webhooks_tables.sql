@@ -0,0 +1,52 @@
+-- Migration: 20240101_000023_create_webhooks_tables.sql
+-- Webhooks dos lojistas: endpoints com eventos assinados, entregas com novas tentativas e log de tentativas
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    merchant_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    url TEXT NOT NULL,
+    description VARCHAR(140) NOT NULL DEFAULT '',
+    event_types JSONB NOT NULL,
+    -- Segredo do HMAC-SHA256; precisa ser lido em claro para assinar as entregas
+    secret VARCHAR(100) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    endpoint_id UUID NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    merchant_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    event_id VARCHAR(50) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    -- Corpo enviado; o reenvio manual usa exatamente os mesmos bytes
+    payload BYTEA NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    redelivery_of UUID REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    last_response_status INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Log de tentativas: apenas inserções
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    response_status INTEGER,
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    attempted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (delivery_id, number)
+);
+
+-- Índices para melhor performance
+CREATE INDEX idx_webhook_endpoints_merchant_id ON webhook_endpoints(merchant_id);
+CREATE INDEX idx_webhook_deliveries_merchant_id ON webhook_deliveries(merchant_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_endpoint_id ON webhook_deliveries(endpoint_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
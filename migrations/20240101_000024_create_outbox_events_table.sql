@@ -0,0 +1,22 @@
+-- Migration: 20240101_000024_create_outbox_events_table.sql
+-- Outbox dos eventos de domínio: gravados na mesma transação da mudança e despachados após o commit
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id UUID PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    aggregate_type VARCHAR(30) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- Assinantes que já processaram o evento; uma nova tentativa chama apenas os demais
+    handled_by JSONB NOT NULL DEFAULT '[]',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Índices para o relay: eventos ainda não despachados cuja vez chegou
+CREATE INDEX idx_outbox_events_pending ON outbox_events(next_attempt_at) WHERE dispatched_at IS NULL;
+CREATE INDEX idx_outbox_events_aggregate ON outbox_events(aggregate_type, aggregate_id, occurred_at);
@@ -0,0 +1,17 @@
+-- Migration: 20240101_000025_create_user_stream_events_table.sql
+-- Eventos do stream SSE dos usuários, retidos por tempo limitado para o replay com Last-Event-ID
+
+CREATE TABLE IF NOT EXISTS user_stream_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    -- Evento de domínio que originou a mensagem; o reprocessamento pelo outbox não a duplica
+    source_event_id UUID NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (source_event_id, user_id)
+);
+
+-- Índices para melhor performance
+CREATE INDEX idx_user_stream_events_user_id ON user_stream_events(user_id, id);
+CREATE INDEX idx_user_stream_events_created_at ON user_stream_events(created_at);
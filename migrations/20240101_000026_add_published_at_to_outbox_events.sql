@@ -0,0 +1,10 @@
+-- Migration: 20240101_000026_add_published_at_to_outbox_events.sql
+-- Publicação dos eventos de domínio no broker, acompanhada à parte do despacho aos assinantes do processo
+
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITH TIME ZONE;
+
+-- Eventos anteriores à publicação não são enviados ao broker
+UPDATE outbox_events SET published_at = created_at WHERE published_at IS NULL;
+
+-- Índice para o relay de publicação: eventos ainda não publicados na ordem de gravação
+CREATE INDEX idx_outbox_events_unpublished ON outbox_events(created_at, occurred_at) WHERE published_at IS NULL;
@@ -0,0 +1,10 @@
+-- Migration: 20240101_000027_add_lease_to_transfer_batches.sql
+-- Prazo da instância que processa o lote: vencido, o lote volta a ser elegível para outra instância
+
+ALTER TABLE transfer_batches ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMP WITH TIME ZONE;
+
+-- Lotes que ficaram em processamento antes do prazo existir podem ser retomados
+UPDATE transfer_batches SET lease_expires_at = updated_at WHERE status = 'processing' AND lease_expires_at IS NULL;
+
+-- Índice para retomar lotes abandonados por uma instância que parou
+CREATE INDEX idx_transfer_batches_processing ON transfer_batches(lease_expires_at) WHERE status = 'processing';
@@ -0,0 +1,10 @@
+-- Migration: 20240101_000028_add_lease_to_data_exports.sql
+-- Prazo da instância que gera a exportação: vencido, a exportação volta a ser elegível para outra instância
+
+ALTER TABLE data_exports ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMP WITH TIME ZONE;
+
+-- Exportações que ficaram em processamento antes do prazo existir podem ser retomadas
+UPDATE data_exports SET lease_expires_at = updated_at WHERE status = 'processing' AND lease_expires_at IS NULL;
+
+-- Índice para retomar exportações abandonadas por uma instância que parou
+CREATE INDEX idx_data_exports_processing ON data_exports(lease_expires_at) WHERE status = 'processing';
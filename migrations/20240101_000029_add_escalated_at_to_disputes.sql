@@ -0,0 +1,8 @@
+-- Migration: 20240101_000029_add_escalated_at_to_disputes.sql
+-- Momento em que a disputa passou do prazo de decisão sem ser resolvida e foi escalada
+
+ALTER TABLE disputes ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMP WITH TIME ZONE;
+
+-- Índice para encontrar as disputas não decididas que venceram o prazo e ainda não foram escaladas
+CREATE INDEX idx_disputes_resolution_due ON disputes(resolution_due_at)
+    WHERE status IN ('open', 'under_review') AND escalated_at IS NULL;
@@ -0,0 +1,4 @@
+-- Migration: 20240101_000030_add_retry_at_to_recurring_mandates.sql
+-- Próxima tentativa de um mandato cuja ocorrência falhou ao ser gerada; a ocorrência continua a mesma
+
+ALTER TABLE recurring_mandates ADD COLUMN IF NOT EXISTS retry_at TIMESTAMP WITH TIME ZONE;
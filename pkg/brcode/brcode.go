@@ -0,0 +1,299 @@
+// Package brcode monta e interpreta payloads BR Code (padrão EMV-MPM usado pelo Pix).
+//
+// O payload é uma sequência de campos TLV: ID de 2 dígitos, tamanho de 2 dígitos e valor.
+// O último campo (63) é o CRC16-CCITT de todo o conteúdo anterior, incluindo "6304".
+package brcode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// IDs dos campos de primeiro nível
+const (
+	idPayloadFormat     = "00"
+	idPointOfInitiation = "01"
+	idMerchantAccount   = "26"
+	idMerchantCategory  = "52"
+	idCurrency          = "53"
+	idAmount            = "54"
+	idCountryCode       = "58"
+	idMerchantName      = "59"
+	idMerchantCity      = "60"
+	idAdditionalData    = "62"
+	idCRC               = "63"
+)
+
+// IDs dos subcampos da conta do recebedor (26) e dos dados adicionais (62)
+const (
+	idAccountGUI         = "00"
+	idAccountKey         = "01"
+	idAccountDescription = "02"
+	idAccountURL         = "25"
+	idAdditionalTxID     = "05"
+)
+
+const (
+	// PixGUI identifica o arranjo Pix na conta do recebedor
+	PixGUI = "br.gov.bcb.pix"
+	// CurrencyBRL é o código ISO 4217 do real
+	CurrencyBRL = "986"
+	// DynamicTxID é o txid usado em payloads dinâmicos, em que a cobrança é obtida pela URL
+	DynamicTxID = "***"
+
+	maxMerchantName = 25
+	maxMerchantCity = 15
+	maxStaticTxID   = 25
+	maxAmount       = 13
+	// maxFieldValue é o maior valor que cabe no tamanho de 2 dígitos de um campo ou template
+	maxFieldValue = 99
+)
+
+var (
+	ErrInvalidPayload = errors.New("payload BR Code inválido")
+	ErrInvalidCRC     = errors.New("CRC do payload BR Code não confere")
+)
+
+// Payload representa os campos de um BR Code estático (chave) ou dinâmico (URL da cobrança)
+type Payload struct {
+	Dynamic       bool
+	Key           string
+	Description   string
+	URL           string
+	MerchantCode  string
+	Amount        string
+	MerchantName  string
+	MerchantCity  string
+	TxID          string
+	CountryCode   string
+	Currency      string
+	PayloadFormat string
+}
+
+// Build monta o payload com o CRC calculado
+func Build(p Payload) (string, error) {
+	if err := p.validate(); err != nil {
+		return "", err
+	}
+
+	account := field(idAccountGUI, PixGUI)
+	if p.Dynamic {
+		account += field(idAccountURL, p.URL)
+	} else {
+		account += field(idAccountKey, p.Key)
+		// A descrição é opcional e fica com o espaço que sobra no campo 26, no mesmo conjunto de
+		// caracteres de nome e cidade
+		if space := maxFieldValue - len(account) - 4; space > 0 {
+			if description := NormalizeText(p.Description, space); description != "" {
+				account += field(idAccountDescription, description)
+			}
+		}
+	}
+
+	txID := p.TxID
+	if p.Dynamic {
+		txID = DynamicTxID
+	}
+
+	merchantCode := p.MerchantCode
+	if merchantCode == "" {
+		merchantCode = "0000"
+	}
+
+	var b strings.Builder
+	b.WriteString(field(idPayloadFormat, "01"))
+	if p.Dynamic {
+		// 12: o QR Code deve ser usado uma única vez
+		b.WriteString(field(idPointOfInitiation, "12"))
+	}
+	b.WriteString(field(idMerchantAccount, account))
+	b.WriteString(field(idMerchantCategory, merchantCode))
+	b.WriteString(field(idCurrency, CurrencyBRL))
+	if p.Amount != "" {
+		b.WriteString(field(idAmount, p.Amount))
+	}
+	b.WriteString(field(idCountryCode, "BR"))
+	b.WriteString(field(idMerchantName, NormalizeText(p.MerchantName, maxMerchantName)))
+	b.WriteString(field(idMerchantCity, NormalizeText(p.MerchantCity, maxMerchantCity)))
+	b.WriteString(field(idAdditionalData, field(idAdditionalTxID, txID)))
+	b.WriteString(idCRC + "04")
+
+	payload := b.String()
+	return payload + fmt.Sprintf("%04X", CRC16(payload)), nil
+}
+
+// Parse interpreta um payload BR Code, validando a estrutura TLV e o CRC
+func Parse(payload string) (*Payload, error) {
+	if len(payload) < 8 {
+		return nil, ErrInvalidPayload
+	}
+
+	body, checksum := payload[:len(payload)-4], payload[len(payload)-4:]
+	if !strings.HasSuffix(body, idCRC+"04") {
+		return nil, fmt.Errorf("%w: campo CRC ausente", ErrInvalidPayload)
+	}
+	if !strings.EqualFold(checksum, fmt.Sprintf("%04X", CRC16(body))) {
+		return nil, ErrInvalidCRC
+	}
+
+	fields, err := parseTLV(body[:len(body)-4])
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Payload{
+		PayloadFormat: fields[idPayloadFormat],
+		MerchantCode:  fields[idMerchantCategory],
+		Currency:      fields[idCurrency],
+		Amount:        fields[idAmount],
+		CountryCode:   fields[idCountryCode],
+		MerchantName:  fields[idMerchantName],
+		MerchantCity:  fields[idMerchantCity],
+	}
+
+	if p.PayloadFormat != "01" {
+		return nil, fmt.Errorf("%w: indicador de formato deve ser 01", ErrInvalidPayload)
+	}
+
+	account, err := parseTLV(fields[idMerchantAccount])
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(account[idAccountGUI], PixGUI) {
+		return nil, fmt.Errorf("%w: conta do recebedor não é Pix", ErrInvalidPayload)
+	}
+	p.Key = account[idAccountKey]
+	p.Description = account[idAccountDescription]
+	p.URL = account[idAccountURL]
+	// O ponto de iniciação 12 só indica uso único; o QR Code é dinâmico quando aponta para uma URL
+	p.Dynamic = p.URL != ""
+
+	if additional, ok := fields[idAdditionalData]; ok {
+		data, err := parseTLV(additional)
+		if err != nil {
+			return nil, err
+		}
+		p.TxID = data[idAdditionalTxID]
+	}
+
+	return p, nil
+}
+
+// CRC16 calcula o CRC16-CCITT (polinômio 0x1021, valor inicial 0xFFFF)
+func CRC16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// NormalizeText remove acentos e caracteres fora do ASCII e limita o tamanho do texto
+func NormalizeText(text string, max int) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(text) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if r < 32 || r > 126 {
+			continue
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	result := strings.TrimSpace(b.String())
+	if len(result) > max {
+		result = strings.TrimSpace(result[:max])
+	}
+	return result
+}
+
+func (p Payload) validate() error {
+	// GUI do arranjo sempre presente no campo 26
+	accountSpace := maxFieldValue - len(field(idAccountGUI, PixGUI))
+	if p.Dynamic {
+		if p.URL == "" {
+			return fmt.Errorf("%w: URL da cobrança é obrigatória no payload dinâmico", ErrInvalidPayload)
+		}
+		if len(field(idAccountURL, p.URL)) > accountSpace {
+			return fmt.Errorf("%w: URL da cobrança excede o campo da conta do recebedor", ErrInvalidPayload)
+		}
+	} else {
+		if p.Key == "" {
+			return fmt.Errorf("%w: chave é obrigatória no payload estático", ErrInvalidPayload)
+		}
+		if len(field(idAccountKey, p.Key)) > accountSpace {
+			return fmt.Errorf("%w: chave excede o campo da conta do recebedor", ErrInvalidPayload)
+		}
+		if p.TxID == "" || len(p.TxID) > maxStaticTxID || !isAlphanumeric(p.TxID) {
+			return fmt.Errorf("%w: txid deve ter de 1 a %d caracteres alfanuméricos", ErrInvalidPayload, maxStaticTxID)
+		}
+	}
+
+	if NormalizeText(p.MerchantName, maxMerchantName) == "" || NormalizeText(p.MerchantCity, maxMerchantCity) == "" {
+		return fmt.Errorf("%w: nome e cidade do recebedor são obrigatórios", ErrInvalidPayload)
+	}
+
+	if p.Amount != "" && len(p.Amount) > maxAmount {
+		return fmt.Errorf("%w: valor excede %d caracteres", ErrInvalidPayload, maxAmount)
+	}
+
+	if len(p.MerchantCode) > maxFieldValue {
+		return fmt.Errorf("%w: código de categoria excede %d caracteres", ErrInvalidPayload, maxFieldValue)
+	}
+
+	return nil
+}
+
+// field monta um campo TLV; validate garante que nenhum valor passa de maxFieldValue
+func field(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// parseTLV lê uma sequência de campos TLV; o tamanho de cada valor vai de 00 a 99
+func parseTLV(data string) (map[string]string, error) {
+	fields := make(map[string]string)
+
+	for i := 0; i < len(data); {
+		if i+4 > len(data) {
+			return nil, fmt.Errorf("%w: campo truncado na posição %d", ErrInvalidPayload, i)
+		}
+
+		id := data[i : i+2]
+		size, err := strconv.Atoi(data[i+2 : i+4])
+		if err != nil {
+			return nil, fmt.Errorf("%w: tamanho inválido no campo %s", ErrInvalidPayload, id)
+		}
+
+		start := i + 4
+		if start+size > len(data) {
+			return nil, fmt.Errorf("%w: valor do campo %s excede o payload", ErrInvalidPayload, id)
+		}
+
+		fields[id] = data[start : start+size]
+		i = start + size
+	}
+
+	return fields, nil
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
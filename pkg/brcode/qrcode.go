@@ -0,0 +1,10 @@
+package brcode
+
+import (
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// QRCodePNG gera localmente a imagem PNG do QR Code com o payload informado
+func QRCodePNG(payload string, size int) ([]byte, error) {
+	return qrcode.Encode(payload, qrcode.Medium, size)
+}
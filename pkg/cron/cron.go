@@ -0,0 +1,158 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule é uma expressão cron de cinco campos: minuto, hora, dia do mês, mês e dia da semana.
+// Cada campo aceita "*", valores, listas (1,15), intervalos (1-5) e passos (*/2, 1-10/3).
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minuto", 0, 59},
+	{"hora", 0, 23},
+	{"dia do mês", 1, 31},
+	{"mês", 1, 12},
+	{"dia da semana", 0, 6},
+}
+
+// maxSearch limita a busca da próxima ocorrência para expressões impossíveis (ex.: 31 de fevereiro)
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Parse interpreta uma expressão cron de cinco campos
+func Parse(expr string) (*Schedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("expressão cron deve ter %d campos, recebeu %d", len(fields), len(parts))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, part := range parts {
+		value, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = value
+	}
+
+	return &Schedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+	}, nil
+}
+
+// Next retorna a primeira ocorrência estritamente posterior a after, no fuso de after.
+// Retorna o instante zero se não houver ocorrência no horizonte de busca.
+func (s *Schedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(maxSearch)
+
+	for t.Before(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchesDay segue a regra do cron: se dia do mês e dia da semana forem restritos, basta um coincidir
+func (s *Schedule) matchesDay(t time.Time) bool {
+	domMatch := has(s.dom, t.Day())
+	dowMatch := has(s.dow, int(t.Weekday()))
+
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, step := item, 1
+		if idx := strings.Index(item, "/"); idx >= 0 {
+			rangeExpr = item[:idx]
+			n, err := strconv.Atoi(item[idx+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("passo inválido no campo %s: %q", f.name, item)
+			}
+			step = n
+		}
+
+		start, end := f.min, f.max
+		switch {
+		case rangeExpr == "*":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if start, err = parseValue(bounds[0], f); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(bounds[1], f); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("intervalo inválido no campo %s: %q", f.name, item)
+			}
+		default:
+			value, err := parseValue(rangeExpr, f)
+			if err != nil {
+				return 0, err
+			}
+			start = value
+			if step == 1 {
+				end = value
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseValue(value string, f field) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("valor inválido no campo %s: %q (esperado entre %d e %d)", f.name, value, f.min, f.max)
+	}
+	return n, nil
+}
+
+func has(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// listenPingInterval é a frequência com que a conexão do LISTEN é testada, para que uma queda
+// silenciosa seja detectada e a conexão refeita
+const listenPingInterval = time.Minute
+
+// Listen escuta o canal com LISTEN em uma conexão dedicada e chama onNotify com o payload de cada
+// NOTIFY até o contexto terminar. A conexão é refeita automaticamente; depois de uma reconexão
+// onNotify recebe payload vazio, porque avisos enviados enquanto ela estava caída se perderam.
+func (d *Database) Listen(ctx context.Context, channel string, onNotify func(payload string)) error {
+	listener := pq.NewListener(d.dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Error("erro na conexão de escuta do canal", "channel", channel, "error", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(channel); err != nil {
+		return fmt.Errorf("erro ao escutar canal %s: %w", channel, err)
+	}
+
+	ping := time.NewTicker(listenPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			// O pq envia nil depois de reconectar
+			if notification == nil {
+				onNotify("")
+				continue
+			}
+			onNotify(notification.Extra)
+		case <-ping.C:
+			go listener.Ping()
+		}
+	}
+}
@@ -12,6 +12,8 @@ import (
 
 type Database struct {
 	DB *sql.DB
+	// dsn abre as conexões dedicadas do LISTEN, que não podem vir do pool
+	dsn string
 }
 
 func NewPostgresConnection(cfg *config.Config) (*Database, error) {
@@ -38,7 +40,7 @@ func NewPostgresConnection(cfg *config.Config) (*Database, error) {
 		return nil, fmt.Errorf("erro ao verificar conexão com PostgreSQL: %w", err)
 	}
 
-	return &Database{DB: db}, nil
+	return &Database{DB: db, dsn: dsn}, nil
 }
 
 func (d *Database) Close() error {
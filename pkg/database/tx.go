@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Querier abstrai *sql.DB e *sql.Tx para que os repositórios funcionem dentro ou fora de uma transação.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// txState é a transação em andamento e as funções a executar antes e depois do commit
+type txState struct {
+	tx           *sql.Tx
+	beforeCommit []func(ctx context.Context) error
+	afterCommit  []func(ctx context.Context)
+}
+
+// Conn retorna a transação em andamento no contexto ou, se não houver, a conexão padrão.
+func (d *Database) Conn(ctx context.Context) Querier {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	return d.DB
+}
+
+// WithinTx executa fn dentro de uma transação, fazendo commit em caso de sucesso e rollback em caso de erro.
+// Chamadas aninhadas reutilizam a transação já aberta no contexto.
+func (d *Database) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+
+	tx, err := d.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+
+	state := &txState{tx: tx}
+	txCtx := context.WithValue(ctx, txKey{}, state)
+
+	err = fn(txCtx)
+	// Funções registradas durante a execução de outra também rodam
+	for i := 0; err == nil && i < len(state.beforeCommit); i++ {
+		err = state.beforeCommit[i](txCtx)
+	}
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (erro no rollback: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	// Depois do commit as funções recebem o contexto original, sem a transação encerrada
+	for _, fn := range state.afterCommit {
+		fn(ctx)
+	}
+
+	return nil
+}
+
+// BeforeCommit agenda fn para rodar na transação do contexto logo antes do commit, depois de todo o
+// trabalho da transação. Serve para gravações que precisam de um lock global sem segurá-lo enquanto
+// outras linhas são bloqueadas. Fora de uma transação, fn roda em uma transação própria.
+func (d *Database) BeforeCommit(ctx context.Context, fn func(ctx context.Context) error) error {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.beforeCommit = append(state.beforeCommit, fn)
+		return nil
+	}
+
+	return d.WithinTx(ctx, fn)
+}
+
+// AfterCommit agenda fn para rodar depois do commit da transação do contexto; se houver rollback,
+// fn é descartada. Fora de uma transação, fn roda imediatamente.
+func (d *Database) AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+
+	fn(ctx)
+}
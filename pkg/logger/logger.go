@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Redacted substitui nos logs os valores que não podem ser gravados
+const Redacted = "[REDACTED]"
+
+type requestIDKey struct{}
+
+// sensitiveKeys são os atributos cujo valor nunca aparece nos logs, qualquer que seja o conteúdo
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"senha":         true,
+	"token":         true,
+	"secret":        true,
+	"signature":     true,
+	"api_key":       true,
+	"authorization": true,
+	"document":      true,
+	"cpf":           true,
+	"cnpj":          true,
+}
+
+var (
+	// documentPattern encontra CPF e CNPJ completos, com ou sem pontuação, no meio de um texto
+	documentPattern = regexp.MustCompile(`\b(\d{3}\.\d{3}\.\d{3}-\d{2}|\d{2}\.\d{3}\.\d{3}/\d{4}-\d{2}|\d{14}|\d{11})\b`)
+	// credentialPattern encontra credenciais em query strings e textos do tipo chave=valor
+	credentialPattern = regexp.MustCompile(`(?i)\b(password|senha|access_token|token|secret|signature|api_key)=[^&\s"]+`)
+)
+
+// New cria o logger JSON no nível informado (debug, info, warn ou error; padrão info). Toda linha
+// registrada com um contexto traz o request_id dele, e valores sensíveis são ocultados.
+func New(level string, w io.Writer) *slog.Logger {
+	options := &slog.HandlerOptions{
+		Level:       ParseLevel(level),
+		ReplaceAttr: redactAttr,
+	}
+	return slog.New(&contextHandler{Handler: slog.NewJSONHandler(w, options)})
+}
+
+// ParseLevel converte o nível configurado; valores desconhecidos viram info
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithRequestID guarda no contexto o ID que correlaciona os logs de uma requisição ou execução
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID retorna o ID de correlação do contexto, ou vazio
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// Redact oculta documentos e credenciais em um texto livre, como mensagens de erro
+func Redact(text string) string {
+	text = documentPattern.ReplaceAllString(text, Redacted)
+	return credentialPattern.ReplaceAllString(text, "$1="+Redacted)
+}
+
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		attr.Value = slog.StringValue(Redact(attr.Value.String()))
+	case slog.KindAny:
+		// Erros costumam carregar a entrada que falhou, como o documento de um cadastro duplicado
+		if err, ok := attr.Value.Any().(error); ok {
+			attr.Value = slog.StringValue(Redact(err.Error()))
+		}
+	}
+	return attr
+}
+
+// contextHandler acrescenta o request_id do contexto a cada registro
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,168 @@
+// Package pdf gera documentos PDF 1.4 simples, apenas com texto e linhas, sem dependências externas.
+//
+// As fontes são as 14 padrão do PDF (não embutidas) com codificação WinAnsi, o que cobre os
+// acentos do português. Coordenadas seguem o PDF: origem no canto inferior esquerdo, em pontos.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Tamanho de uma página A4 em pontos
+const (
+	A4Width  = 595.0
+	A4Height = 842.0
+)
+
+// Font é uma das fontes padrão registradas em todo documento
+type Font string
+
+const (
+	Helvetica     Font = "F1"
+	HelveticaBold Font = "F2"
+	Courier       Font = "F3"
+)
+
+var fontNames = []struct {
+	font Font
+	name string
+}{
+	{Helvetica, "Helvetica"},
+	{HelveticaBold, "Helvetica-Bold"},
+	{Courier, "Courier"},
+}
+
+// courierAdvance é a largura de cada caractere da Courier, em milésimos do tamanho da fonte
+const courierAdvance = 600
+
+// Document acumula as páginas; cada página é o fluxo de operadores de conteúdo já montado
+type Document struct {
+	pages []*bytes.Buffer
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage inicia uma nova página A4, que passa a receber os textos e linhas
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// PageCount retorna o número de páginas já criadas
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+func (d *Document) current() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text escreve s com a linha de base em (x, y)
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(d.current(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escape(s))
+}
+
+// TextRight escreve s em Courier terminando em x, para alinhar valores à direita
+func (d *Document) TextRight(x, y, size float64, s string) {
+	d.Text(x-MonospaceWidth(s, size), y, Courier, size, s)
+}
+
+// Line traça uma linha de (x1, y1) a (x2, y2)
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.current(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// MonospaceWidth retorna a largura de s escrito em Courier no tamanho informado
+func MonospaceWidth(s string, size float64) float64 {
+	return float64(len([]rune(s))) * size * courierAdvance / 1000
+}
+
+// Bytes serializa o documento: catálogo, árvore de páginas, fontes, páginas e tabela xref
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+
+	addObject := func(body string) int {
+		offsets = append(offsets, out.Len())
+		id := len(offsets)
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", id, body)
+		return id
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objetos 1 e 2 (catálogo e árvore de páginas) referenciam objetos criados depois
+	const catalogID, pagesID = 1, 2
+	firstFontID := 3
+	firstPageID := firstFontID + len(fontNames)
+
+	addObject(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID))
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageID+2*i)
+	}
+	addObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+
+	fonts := make([]string, len(fontNames))
+	for i, f := range fontNames {
+		addObject(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.name))
+		fonts[i] = fmt.Sprintf("/%s %d 0 R", f.font, firstFontID+i)
+	}
+	resources := "<< /Font << " + strings.Join(fonts, " ") + " >> >>"
+
+	for i, page := range d.pages {
+		contentID := firstPageID + 2*i + 1
+		addObject(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Resources %s /Contents %d 0 R >>",
+			pagesID, A4Width, A4Height, resources, contentID))
+		addObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, catalogID, xref)
+
+	return out.Bytes()
+}
+
+// escape converte s para WinAnsi e protege os caracteres especiais das strings literais do PDF.
+// Caracteres fora da WinAnsi viram "?".
+func escape(s string) string {
+	encoder := charmap.Windows1252.NewEncoder()
+
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+			continue
+		case '\n', '\r', '\t':
+			b.WriteByte(' ')
+			continue
+		}
+
+		encoded, err := encoder.String(string(r))
+		if err != nil || len(encoded) != 1 {
+			b.WriteByte('?')
+			continue
+		}
+		b.WriteByte(encoded[0])
+	}
+	return b.String()
+}
@@ -0,0 +1,147 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func transferParties(t *testing.T) (*entity.User, *entity.User, *entity.Transaction) {
+	payer := NewUser(t)
+	payer.Balance = decimal.NewFromInt(1000)
+
+	payee, err := entity.NewUser("Loja Exemplo", "11222333000181", "loja@example.com", "SenhaForte123", entity.UserTypeMerchant)
+	require.NoError(t, err)
+
+	transaction, err := entity.NewTransaction(payer.ID, payee.ID, decimal.NewFromInt(100))
+	require.NoError(t, err)
+
+	return payer, payee, transaction
+}
+
+func TestNewUserIsActive(t *testing.T) {
+	user := NewUser(t)
+
+	assert.Equal(t, entity.AccountStatusActive, user.Status)
+	assert.True(t, user.CanSendMoney())
+	assert.True(t, user.CanReceiveMoney())
+}
+
+func TestChangeStatusRecordsAuditEntry(t *testing.T) {
+	now := time.Now()
+	user := NewUser(t)
+
+	change, err := user.ChangeStatus(entity.AccountStatusBlocked, "  suspeita de fraude  ", "ana", now)
+	require.NoError(t, err)
+
+	assert.Equal(t, entity.AccountStatusBlocked, user.Status)
+	assert.Equal(t, "suspeita de fraude", *user.StatusReason)
+	assert.Equal(t, user.ID, change.UserID)
+	assert.Equal(t, entity.AccountStatusActive, change.FromStatus)
+	assert.Equal(t, entity.AccountStatusBlocked, change.ToStatus)
+	assert.Equal(t, "suspeita de fraude", change.Reason)
+	assert.Equal(t, "ana", change.ChangedBy)
+	assert.Equal(t, now, change.CreatedAt)
+}
+
+func TestChangeStatusDefaultsActorToSystem(t *testing.T) {
+	user := NewUser(t)
+
+	change, err := user.ChangeStatus(entity.AccountStatusFrozen, "ordem judicial", "", time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, entity.AccountStatusSystemActor, change.ChangedBy)
+}
+
+func TestChangeStatusValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		status entity.AccountStatus
+		reason string
+		err    error
+	}{
+		{"status inválido", entity.AccountStatus("suspended"), "motivo", entity.ErrInvalidAccountStatus},
+		{"mesmo status", entity.AccountStatusActive, "motivo", entity.ErrAccountStatusUnchanged},
+		{"sem motivo", entity.AccountStatusBlocked, "  ", entity.ErrAccountStatusReasonRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := NewUser(t)
+
+			_, err := user.ChangeStatus(tt.status, tt.reason, "ana", time.Now())
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, entity.AccountStatusActive, user.Status)
+		})
+	}
+}
+
+func TestCloseRequiresZeroBalance(t *testing.T) {
+	user := NewUser(t)
+	user.Balance = decimal.RequireFromString("0.01")
+
+	_, err := user.ChangeStatus(entity.AccountStatusClosed, "pedido do titular", "ana", time.Now())
+	assert.ErrorIs(t, err, entity.ErrAccountHasBalance)
+
+	user.Balance = decimal.Zero
+	user.HeldBalance = decimal.NewFromInt(50)
+	_, err = user.ChangeStatus(entity.AccountStatusClosed, "pedido do titular", "ana", time.Now())
+	assert.ErrorIs(t, err, entity.ErrAccountHasBalance)
+	assert.Equal(t, entity.AccountStatusActive, user.Status)
+}
+
+func TestClosedAccountIsFinal(t *testing.T) {
+	user := NewUser(t)
+
+	_, err := user.ChangeStatus(entity.AccountStatusClosed, "pedido do titular", "ana", time.Now())
+	require.NoError(t, err)
+
+	_, err = user.ChangeStatus(entity.AccountStatusActive, "reabertura", "ana", time.Now())
+	assert.ErrorIs(t, err, entity.ErrAccountClosed)
+	assert.Equal(t, entity.AccountStatusClosed, user.Status)
+}
+
+func TestAccountStatusRestrictsTransfers(t *testing.T) {
+	tests := []struct {
+		name        string
+		payerStatus entity.AccountStatus
+		payeeStatus entity.AccountStatus
+		err         error
+	}{
+		{"contas ativas", entity.AccountStatusActive, entity.AccountStatusActive, nil},
+		{"pagador bloqueado", entity.AccountStatusBlocked, entity.AccountStatusActive, entity.ErrAccountBlocked},
+		{"pagador congelado", entity.AccountStatusFrozen, entity.AccountStatusActive, entity.ErrAccountFrozen},
+		{"pagador encerrado", entity.AccountStatusClosed, entity.AccountStatusActive, entity.ErrAccountClosed},
+		{"recebedor bloqueado continua recebendo", entity.AccountStatusActive, entity.AccountStatusBlocked, nil},
+		{"recebedor congelado", entity.AccountStatusActive, entity.AccountStatusFrozen, entity.ErrPayeeCannotReceive},
+		{"recebedor encerrado", entity.AccountStatusActive, entity.AccountStatusClosed, entity.ErrPayeeCannotReceive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payer, payee, transaction := transferParties(t)
+			payer.Status = tt.payerStatus
+			payee.Status = tt.payeeStatus
+
+			err := transaction.ValidateBusinessRules(payer, payee)
+
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestMerchantCannotSendRegardlessOfStatus(t *testing.T) {
+	_, merchant, _ := transferParties(t)
+
+	assert.ErrorIs(t, merchant.ValidateCanSend(), entity.ErrMerchantCannotSend)
+	assert.False(t, merchant.CanSendMoney())
+}
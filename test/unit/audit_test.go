@@ -0,0 +1,213 @@
+package entity_test
+
+import (
+	"encoding/json"
+	"payflow-api/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func auditUser() *entity.User {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &entity.User{
+		ID:        "user-1",
+		FullName:  "Maria Silva",
+		Document:  "12345678901",
+		Email:     "maria@example.com",
+		Password:  "hash",
+		UserType:  entity.UserTypeCommon,
+		Balance:   decimal.NewFromInt(100),
+		Status:    entity.AccountStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// chain sela os registros em sequência, como o repositório faz
+func chain(entries ...*entity.AuditEntry) []*entity.AuditEntry {
+	prevHash := ""
+	for i, entry := range entries {
+		entry.Seal(int64(i+1), prevHash)
+		prevHash = entry.Hash
+	}
+	return entries
+}
+
+func auditEntry(action string, before, after interface{}) *entity.AuditEntry {
+	meta := entity.AuditMetadata{ActorType: entity.AuditActorUser, ActorID: "user-1", RequestID: "req-1", IP: "10.0.0.1"}
+	return entity.NewAuditEntry(meta, action, "user", "user-1", before, after, time.Now())
+}
+
+func TestAuditSnapshot_RedactsSecretsAndDocuments(t *testing.T) {
+	snapshot := entity.AuditSnapshot(map[string]interface{}{
+		"token":  "abc",
+		"nested": map[string]interface{}{"secret": "s3cr3t", "cnpj": "12.345.678/0001-90"},
+		"keys":   []interface{}{"123.456.789-01", "maria@example.com"},
+	})
+	assert.Equal(t, entity.AuditRedacted, snapshot["token"])
+
+	user := auditUser()
+	userSnapshot := entity.AuditSnapshot(user)
+	assert.Equal(t, entity.AuditRedacted, userSnapshot["document"])
+	assert.NotContains(t, userSnapshot, "password")
+	assert.Equal(t, "maria@example.com", userSnapshot["email"])
+
+	content, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "s3cr3t")
+	assert.NotContains(t, string(content), "0001-90")
+	assert.NotContains(t, string(content), "789-01")
+	assert.Contains(t, string(content), "maria@example.com")
+}
+
+func TestAuditSnapshot_PixKeyWithDocumentValue(t *testing.T) {
+	key := &entity.PixKey{ID: "key-1", UserID: "user-1", KeyType: entity.PixKeyTypeCPF, KeyValue: "12345678901"}
+
+	snapshot := entity.AuditSnapshot(key)
+	for _, value := range snapshot {
+		assert.NotEqual(t, "12345678901", value)
+	}
+}
+
+func TestAuditSnapshot_NilResource(t *testing.T) {
+	var user *entity.User
+	assert.Nil(t, entity.AuditSnapshot(user))
+	assert.Nil(t, entity.AuditSnapshot(nil))
+}
+
+func TestNewAuditEntry_RecordsOnlyChangedFields(t *testing.T) {
+	user := auditUser()
+	before := entity.AuditSnapshot(user)
+
+	user.FullName = "Maria Souza"
+	user.UpdatedAt = user.UpdatedAt.Add(time.Minute)
+
+	entry := auditEntry("user.update", before, user)
+
+	// A mudança do nome é registrada sem o valor
+	assert.Len(t, entry.Changes, 1)
+	assert.Equal(t, entity.AuditRedacted, entry.Changes["full_name"].Before)
+	assert.Equal(t, entity.AuditRedacted, entry.Changes["full_name"].After)
+	assert.Equal(t, entity.AuditActorUser, entry.ActorType)
+	assert.Equal(t, "req-1", *entry.RequestID)
+	assert.Equal(t, "10.0.0.1", *entry.IP)
+}
+
+func TestNewAuditEntry_CreationAndDeletion(t *testing.T) {
+	created := auditEntry("user.create", nil, auditUser())
+	assert.Nil(t, created.Changes["email"].Before)
+	assert.Equal(t, entity.AuditRedacted, created.Changes["email"].After)
+	assert.Equal(t, entity.AuditRedacted, created.Changes["full_name"].After)
+	assert.Equal(t, entity.AuditRedacted, created.Changes["document"].After)
+
+	deleted := auditEntry("pix_key.delete", &entity.PixKey{ID: "key-1", KeyValue: "maria@example.com"}, nil)
+	assert.Equal(t, entity.AuditRedacted, deleted.Changes["key"].Before)
+	assert.Nil(t, deleted.Changes["key"].After)
+
+	content, err := json.Marshal([]*entity.AuditEntry{created, deleted})
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "maria@example.com")
+	assert.NotContains(t, string(content), "Maria Silva")
+}
+
+func TestNewAuditEntry_DefaultsToSystemActor(t *testing.T) {
+	entry := entity.NewAuditEntry(entity.AuditMetadata{}, "user.anonymize", "user", "user-1", nil, auditUser(), time.Now())
+
+	assert.Equal(t, entity.AuditActorSystem, entry.ActorType)
+	assert.Nil(t, entry.ActorID)
+	assert.Nil(t, entry.RequestID)
+}
+
+func TestAuditChain_Valid(t *testing.T) {
+	entries := chain(
+		auditEntry("user.create", nil, auditUser()),
+		auditEntry("user.update", nil, map[string]interface{}{"full_name": "Maria Souza"}),
+		auditEntry("user.delete", nil, map[string]interface{}{"status": "closed"}),
+	)
+
+	assert.Empty(t, entries[0].PrevHash)
+	assert.Equal(t, entries[0].Hash, entries[1].PrevHash)
+
+	verification := &entity.AuditChainVerification{Valid: true}
+	assert.True(t, verification.Verify(entries))
+	assert.True(t, verification.Valid)
+	assert.Equal(t, int64(3), verification.Checked)
+	assert.Equal(t, entries[2].Hash, verification.LastHash)
+}
+
+func TestAuditChain_VerifiesInBatches(t *testing.T) {
+	entries := chain(
+		auditEntry("user.create", nil, auditUser()),
+		auditEntry("user.update", nil, map[string]interface{}{"full_name": "Maria Souza"}),
+		auditEntry("user.delete", nil, map[string]interface{}{"status": "closed"}),
+	)
+
+	verification := &entity.AuditChainVerification{Valid: true}
+	assert.True(t, verification.Verify(entries[:2]))
+	assert.True(t, verification.Verify(entries[2:]))
+	assert.Equal(t, int64(3), verification.LastSequence)
+}
+
+func TestAuditChain_DetectsTampering(t *testing.T) {
+	entries := chain(
+		auditEntry("user.create", nil, auditUser()),
+		auditEntry("user.update", nil, map[string]interface{}{"full_name": "Maria Souza"}),
+		auditEntry("user.delete", nil, map[string]interface{}{"status": "closed"}),
+	)
+
+	// Alterar o conteúdo de um registro
+	entries[1].Action = "user.read"
+	verification := &entity.AuditChainVerification{Valid: true}
+	assert.False(t, verification.Verify(entries))
+	assert.False(t, verification.Valid)
+	assert.Equal(t, int64(2), *verification.BrokenAt)
+	assert.Equal(t, int64(1), verification.Checked)
+}
+
+func TestAuditChain_DetectsRecomputedHash(t *testing.T) {
+	entries := chain(
+		auditEntry("user.create", nil, auditUser()),
+		auditEntry("user.update", nil, map[string]interface{}{"full_name": "Maria Souza"}),
+		auditEntry("user.delete", nil, map[string]interface{}{"status": "closed"}),
+	)
+
+	// Recalcular o hash do registro alterado quebra o encadeamento do seguinte
+	entries[1].ResourceID = "user-2"
+	entries[1].Hash = entries[1].ComputeHash()
+
+	verification := &entity.AuditChainVerification{Valid: true}
+	assert.False(t, verification.Verify(entries))
+	assert.Equal(t, int64(3), *verification.BrokenAt)
+}
+
+func TestAuditChain_DetectsRemovedEntry(t *testing.T) {
+	entries := chain(
+		auditEntry("user.create", nil, auditUser()),
+		auditEntry("user.update", nil, map[string]interface{}{"full_name": "Maria Souza"}),
+		auditEntry("user.delete", nil, map[string]interface{}{"status": "closed"}),
+	)
+
+	verification := &entity.AuditChainVerification{Valid: true}
+	assert.False(t, verification.Verify([]*entity.AuditEntry{entries[0], entries[2]}))
+	assert.Equal(t, int64(3), *verification.BrokenAt)
+}
+
+func TestAuditEntry_HashSurvivesStorageRoundTrip(t *testing.T) {
+	entries := chain(auditEntry("user.create", nil, auditUser()))
+
+	// O repositório grava as mudanças em JSONB e as lê de volta
+	content, err := json.Marshal(entries[0].Changes)
+	require.NoError(t, err)
+	var changes map[string]entity.AuditChange
+	require.NoError(t, json.Unmarshal(content, &changes))
+
+	stored := *entries[0]
+	stored.Changes = changes
+	stored.CreatedAt = stored.CreatedAt.In(time.FixedZone("BRT", -3*3600))
+
+	assert.Equal(t, entries[0].Hash, stored.ComputeHash())
+}
@@ -0,0 +1,128 @@
+package entity_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"payflow-api/internal/entity"
+	"payflow-api/internal/handler"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const authTestUserID = "550e8400-e29b-41d4-a716-446655440001"
+
+func TestAuthTokenSigner(t *testing.T) {
+	now := time.Now()
+	signer := entity.NewAuthTokenSigner([]byte("segredo"), time.Hour)
+	token := signer.Issue(authTestUserID, now)
+	assert.Equal(t, entity.AuthTokenType, token.TokenType)
+
+	userID, err := signer.Verify(token.AccessToken, now)
+	require.NoError(t, err)
+	assert.Equal(t, authTestUserID, userID)
+
+	// Trocar o usuário do token invalida a assinatura
+	forged := strings.Replace(token.AccessToken, authTestUserID, "550e8400-e29b-41d4-a716-446655440002", 1)
+	_, err = signer.Verify(forged, now)
+	assert.ErrorIs(t, err, entity.ErrInvalidAuthToken)
+
+	_, err = entity.NewAuthTokenSigner([]byte("outra"), time.Hour).Verify(token.AccessToken, now)
+	assert.ErrorIs(t, err, entity.ErrInvalidAuthToken)
+	_, err = signer.Verify(authTestUserID, now)
+	assert.ErrorIs(t, err, entity.ErrInvalidAuthToken)
+	_, err = signer.Verify(token.AccessToken, now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, entity.ErrAuthTokenExpired)
+}
+
+func TestRequireUser_IdentifiesOnlyBySignedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	signer := entity.NewAuthTokenSigner([]byte("segredo"), time.Hour)
+	router := gin.New()
+	router.Use(handler.AuditContext())
+	router.GET("/me", handler.RequireUser(signer), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("user_id"))
+	})
+	router.GET("/events", handler.RequireStreamUser(signer), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("user_id"))
+	})
+
+	request := func(path string, header http.Header) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header = header
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// O cabeçalho com o ID do usuário não identifica mais ninguém
+	response := request("/me", http.Header{"X-User-Id": {authTestUserID}})
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+	response = request("/me", http.Header{"Authorization": {"Bearer " + authTestUserID}})
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+	token := signer.Issue(authTestUserID, time.Now()).AccessToken
+	response = request("/me", http.Header{"Authorization": {"Bearer " + token}})
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, authTestUserID, response.Body.String())
+
+	// Só as rotas de stream aceitam o token na query
+	assert.Equal(t, http.StatusUnauthorized, request("/me?access_token="+token, http.Header{}).Code)
+	response = request("/events?access_token="+token, http.Header{})
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, authTestUserID, response.Body.String())
+}
+
+func TestParseAdminKeys(t *testing.T) {
+	keys, err := entity.ParseAdminKeys(" ana:chave-da-ana, bruno:chave:com:dois-pontos,")
+	require.NoError(t, err)
+
+	adminID, ok := keys.Identify("chave-da-ana")
+	assert.True(t, ok)
+	assert.Equal(t, "ana", adminID)
+	adminID, ok = keys.Identify("chave:com:dois-pontos")
+	assert.True(t, ok)
+	assert.Equal(t, "bruno", adminID)
+	_, ok = keys.Identify("")
+	assert.False(t, ok)
+
+	for _, invalid := range []string{"sem-admin", ":chave", "ana:", "ana:a,ana:b", "ana:a,bruno:a"} {
+		_, err := entity.ParseAdminKeys(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	empty, err := entity.ParseAdminKeys("")
+	require.NoError(t, err)
+	assert.True(t, empty.Empty())
+}
+
+func TestRequireAdmin_IdentifiesAdminByKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keys, err := entity.ParseAdminKeys("ana:chave-da-ana,bruno:chave-do-bruno")
+	require.NoError(t, err)
+	router := gin.New()
+	router.Use(handler.AuditContext())
+	router.GET("/admin", handler.RequireAdmin(keys), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("admin_id"))
+	})
+
+	request := func(key string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.Header.Set(handler.AdminKeyHeader, key)
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	response := request("chave-do-bruno")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "bruno", response.Body.String())
+
+	assert.Equal(t, http.StatusForbidden, request("bruno").Code)
+	assert.Equal(t, http.StatusForbidden, request("").Code)
+}
@@ -0,0 +1,75 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceHoldReducesAvailableBalance(t *testing.T) {
+	user := NewUser(t)
+	user.Balance = decimal.NewFromInt(100)
+
+	err := user.PlaceHold(decimal.NewFromInt(70))
+
+	assert.NoError(t, err)
+	assert.True(t, user.Balance.Equal(decimal.NewFromInt(100)))
+	assert.True(t, user.HeldBalance.Equal(decimal.NewFromInt(70)))
+	assert.True(t, user.AvailableBalance().Equal(decimal.NewFromInt(30)))
+	assert.False(t, user.HasSufficientBalance(decimal.NewFromInt(31)))
+}
+
+func TestConcurrentHoldsCannotExceedBalance(t *testing.T) {
+	user := NewUser(t)
+	user.Balance = decimal.NewFromInt(100)
+
+	assert.NoError(t, user.PlaceHold(decimal.NewFromInt(60)))
+	assert.ErrorIs(t, user.PlaceHold(decimal.NewFromInt(60)), entity.ErrInsufficientBalance)
+}
+
+func TestCaptureAndReleaseHold(t *testing.T) {
+	user := NewUser(t)
+	user.Balance = decimal.NewFromInt(100)
+	assert.NoError(t, user.PlaceHold(decimal.NewFromInt(40)))
+	assert.NoError(t, user.PlaceHold(decimal.NewFromInt(10)))
+
+	assert.NoError(t, user.CaptureHold(decimal.NewFromInt(40)))
+	assert.True(t, user.Balance.Equal(decimal.NewFromInt(60)))
+	assert.True(t, user.HeldBalance.Equal(decimal.NewFromInt(10)))
+
+	assert.NoError(t, user.ReleaseHold(decimal.NewFromInt(10)))
+	assert.True(t, user.Balance.Equal(decimal.NewFromInt(60)))
+	assert.True(t, user.HeldBalance.IsZero())
+
+	assert.ErrorIs(t, user.ReleaseHold(decimal.NewFromInt(1)), entity.ErrHoldExceedsReserved)
+}
+
+func TestBalanceHoldLifecycle(t *testing.T) {
+	hold, err := entity.NewBalanceHold("payer", "transaction", decimal.NewFromInt(10), time.Minute)
+
+	assert.NoError(t, err)
+	assert.True(t, hold.IsActive())
+	assert.False(t, hold.IsExpired(time.Now()))
+	assert.True(t, hold.IsExpired(time.Now().Add(2*time.Minute)))
+
+	assert.NoError(t, hold.Capture())
+	assert.Equal(t, entity.HoldStatusCaptured, hold.Status)
+	assert.NotNil(t, hold.SettledAt)
+	assert.ErrorIs(t, hold.Release(), entity.ErrHoldNotActive)
+	assert.False(t, hold.IsExpired(time.Now().Add(2*time.Minute)))
+}
+
+func TestBalanceResponseShowsAvailableAndHeld(t *testing.T) {
+	user := NewUser(t)
+	user.Balance = decimal.NewFromInt(100)
+	assert.NoError(t, user.PlaceHold(decimal.NewFromFloat(25.5)))
+
+	response := user.ToBalanceResponse()
+
+	assert.Equal(t, "100.00", response.Balance)
+	assert.Equal(t, "74.50", response.Available)
+	assert.Equal(t, "25.50", response.Held)
+}
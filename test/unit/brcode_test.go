@@ -0,0 +1,129 @@
+package entity_test
+
+import (
+	"bytes"
+	"fmt"
+	"payflow-api/pkg/brcode"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Exemplo do manual do BR Code publicado pelo Banco Central
+const bcbExamplePayload = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func TestParseBRCodeExample(t *testing.T) {
+	payload, err := brcode.Parse(bcbExamplePayload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "123e4567-e12b-12d1-a456-426655440000", payload.Key)
+	assert.Equal(t, "Fulano de Tal", payload.MerchantName)
+	assert.Equal(t, "BRASILIA", payload.MerchantCity)
+	assert.Equal(t, "***", payload.TxID)
+	assert.Equal(t, "986", payload.Currency)
+	assert.False(t, payload.Dynamic)
+}
+
+func TestParseBRCodeRejectsInvalidCRC(t *testing.T) {
+	tampered := bcbExamplePayload[:len(bcbExamplePayload)-4] + "0000"
+
+	_, err := brcode.Parse(tampered)
+	assert.ErrorIs(t, err, brcode.ErrInvalidCRC)
+}
+
+func TestBuildStaticBRCodeRoundTrip(t *testing.T) {
+	encoded, err := brcode.Build(brcode.Payload{
+		Key:          "loja@exemplo.com",
+		Description:  "Pedido 123",
+		Amount:       "89.90",
+		MerchantName: "Padaria São João Ltda",
+		MerchantCity: "São Paulo",
+		TxID:         "ABC123",
+	})
+	assert.NoError(t, err)
+
+	parsed, err := brcode.Parse(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, "loja@exemplo.com", parsed.Key)
+	assert.Equal(t, "PEDIDO 123", parsed.Description)
+	assert.Equal(t, "89.90", parsed.Amount)
+	assert.Equal(t, "PADARIA SAO JOAO LTDA", parsed.MerchantName)
+	assert.Equal(t, "SAO PAULO", parsed.MerchantCity)
+	assert.Equal(t, "ABC123", parsed.TxID)
+}
+
+func TestBuildDynamicBRCode(t *testing.T) {
+	encoded, err := brcode.Build(brcode.Payload{
+		Dynamic:      true,
+		URL:          "pix.payflow.local/v2/cobv/ABC123",
+		Amount:       "10.00",
+		MerchantName: "Loja",
+		MerchantCity: "Recife",
+	})
+	assert.NoError(t, err)
+
+	parsed, err := brcode.Parse(encoded)
+	assert.NoError(t, err)
+	assert.True(t, parsed.Dynamic)
+	assert.Equal(t, "pix.payflow.local/v2/cobv/ABC123", parsed.URL)
+	assert.Equal(t, brcode.DynamicTxID, parsed.TxID)
+
+	image, err := brcode.QRCodePNG(encoded, 256)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(image, []byte("\x89PNG")))
+}
+
+func TestParseBRCodeDynamicOnlyWithURL(t *testing.T) {
+	encoded, err := brcode.Build(brcode.Payload{
+		Key:          "loja@exemplo.com",
+		MerchantName: "Loja",
+		MerchantCity: "Recife",
+		TxID:         "ABC123",
+	})
+	assert.NoError(t, err)
+
+	// Estático de uso único: ponto de iniciação 12 com chave e sem URL
+	body := strings.Replace(encoded[:len(encoded)-4], "000201", "000201"+"010212", 1)
+	parsed, err := brcode.Parse(body + fmt.Sprintf("%04X", brcode.CRC16(body)))
+	assert.NoError(t, err)
+	assert.False(t, parsed.Dynamic)
+	assert.Equal(t, "loja@exemplo.com", parsed.Key)
+}
+
+func TestBuildStaticBRCodeRequiresKeyAndTxID(t *testing.T) {
+	_, err := brcode.Build(brcode.Payload{MerchantName: "Loja", MerchantCity: "Recife", TxID: "A1"})
+	assert.ErrorIs(t, err, brcode.ErrInvalidPayload)
+
+	_, err = brcode.Build(brcode.Payload{Key: "loja@exemplo.com", MerchantName: "Loja", MerchantCity: "Recife", TxID: "inválido-!"})
+	assert.ErrorIs(t, err, brcode.ErrInvalidPayload)
+}
+
+func TestBuildStaticBRCodeFitsLongDescription(t *testing.T) {
+	description := strings.TrimSpace(strings.Repeat("Pedido de pão ", 10))
+	encoded, err := brcode.Build(brcode.Payload{
+		Key:          "loja@exemplo.com",
+		Description:  description,
+		Amount:       "89.90",
+		MerchantName: "Padaria São João Ltda",
+		MerchantCity: "São Paulo",
+		TxID:         "ABC123",
+	})
+	assert.NoError(t, err)
+
+	// A descrição é cortada para caber no campo 26, que não passa de 99 caracteres
+	parsed, err := brcode.Parse(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, "loja@exemplo.com", parsed.Key)
+	assert.NotEmpty(t, parsed.Description)
+	assert.True(t, strings.HasPrefix(strings.Repeat("PEDIDO DE PAO ", 10), parsed.Description))
+	assert.Equal(t, "ABC123", parsed.TxID)
+}
+
+func TestBuildBRCodeRejectsOversizedKeyAndURL(t *testing.T) {
+	_, err := brcode.Build(brcode.Payload{Key: strings.Repeat("a", 80), MerchantName: "Loja", MerchantCity: "Recife", TxID: "A1"})
+	assert.ErrorIs(t, err, brcode.ErrInvalidPayload)
+
+	_, err = brcode.Build(brcode.Payload{Dynamic: true, URL: "pix.payflow.local/v2/cobv/" + strings.Repeat("A", 80), MerchantName: "Loja", MerchantCity: "Recife"})
+	assert.ErrorIs(t, err, brcode.ErrInvalidPayload)
+}
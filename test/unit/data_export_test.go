@@ -0,0 +1,134 @@
+package entity_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"payflow-api/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readyExport(t *testing.T, now time.Time) *entity.DataExport {
+	export := entity.NewDataExport("user-1", now)
+	export.Status = entity.DataExportStatusProcessing
+	require.NoError(t, export.MarkReady([]byte("zip"), time.Hour, now))
+	return export
+}
+
+func readZip(t *testing.T, archive []byte) map[string][]byte {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[file.Name] = content
+	}
+	return files
+}
+
+func TestDataExportDownloadOnlyOnce(t *testing.T) {
+	now := time.Now()
+	export := readyExport(t, now)
+
+	assert.Equal(t, 3, export.SizeBytes)
+	assert.Equal(t, now.Add(time.Hour), *export.ExpiresAt)
+
+	content, err := export.Download(now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("zip"), content)
+	assert.Equal(t, entity.DataExportStatusDownloaded, export.Status)
+	assert.Nil(t, export.Archive)
+
+	_, err = export.Download(now.Add(2 * time.Minute))
+	assert.ErrorIs(t, err, entity.ErrDataExportAlreadyDownloaded)
+}
+
+func TestDataExportDownloadAfterExpiry(t *testing.T) {
+	now := time.Now()
+	export := readyExport(t, now)
+
+	_, err := export.Download(now.Add(2 * time.Hour))
+
+	assert.ErrorIs(t, err, entity.ErrDataExportExpired)
+	assert.Equal(t, entity.DataExportStatusExpired, export.Status)
+	assert.Nil(t, export.Archive)
+}
+
+func TestDataExportNotReady(t *testing.T) {
+	export := entity.NewDataExport("user-1", time.Now())
+
+	assert.True(t, export.IsInProgress())
+	_, err := export.Download(time.Now())
+	assert.ErrorIs(t, err, entity.ErrDataExportNotReady)
+	assert.ErrorIs(t, export.MarkReady([]byte("zip"), time.Hour, time.Now()), entity.ErrDataExportNotProcessing)
+}
+
+func TestDataExportSigner(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+	signer := entity.NewDataExportSigner([]byte("segredo"))
+	signature := signer.Sign("export-1", expiresAt)
+
+	assert.NoError(t, signer.Verify("export-1", expiresAt.Unix(), signature, now))
+	assert.ErrorIs(t, signer.Verify("export-2", expiresAt.Unix(), signature, now), entity.ErrInvalidDownloadSignature)
+	assert.ErrorIs(t, signer.Verify("export-1", expiresAt.Unix()+3600, signature, now), entity.ErrInvalidDownloadSignature)
+	assert.ErrorIs(t, entity.NewDataExportSigner([]byte("outra")).Verify("export-1", expiresAt.Unix(), signature, now), entity.ErrInvalidDownloadSignature)
+	assert.ErrorIs(t, signer.Verify("export-1", expiresAt.Unix(), signature, expiresAt.Add(time.Second)), entity.ErrDataExportExpired)
+}
+
+func TestDataExportBundleArchive(t *testing.T) {
+	now := time.Now()
+	user := NewUser(t)
+	counterparty, err := entity.NewUser("Loja Exemplo", "11222333000181", "loja@example.com", "SenhaForte123", entity.UserTypeMerchant)
+	require.NoError(t, err)
+
+	sent, err := entity.NewTransaction(user.ID, counterparty.ID, decimal.RequireFromString("10.50"))
+	require.NoError(t, err)
+	sent.Payee = counterparty
+	received, err := entity.NewTransaction(counterparty.ID, user.ID, decimal.NewFromInt(3))
+	require.NoError(t, err)
+
+	key, err := entity.NewPixKey(user, entity.PixKeyTypeEmail, user.Email)
+	require.NoError(t, err)
+
+	bundle := entity.NewDataExportBundle(user, []*entity.PixKey{key}, []*entity.Transaction{sent, received}, nil, nil, now)
+	archive, err := bundle.Archive()
+	require.NoError(t, err)
+
+	files := readZip(t, archive)
+	for _, name := range []string{"export.json", "profile.csv", "pix_keys.csv", "sessions.csv", "transactions.csv", "disputes.csv", "consents.csv", "account_status_history.csv"} {
+		assert.Contains(t, files, name)
+	}
+
+	var exported map[string]interface{}
+	require.NoError(t, json.Unmarshal(files["export.json"], &exported))
+	assert.Equal(t, entity.DataExportFormatVersion, exported["format_version"])
+	assert.Equal(t, []interface{}{}, exported["sessions"])
+	assert.Equal(t, []interface{}{}, exported["consents"])
+	assert.Equal(t, []interface{}{}, exported["disputes"])
+	assert.NotContains(t, string(files["export.json"]), "SenhaForte123")
+	// Dados cadastrais da contraparte não fazem parte da exportação
+	assert.NotContains(t, string(files["export.json"]), counterparty.Document)
+
+	rows, err := csv.NewReader(bytes.NewReader(files["transactions.csv"])).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{sent.ID, entity.DataExportDirectionSent, user.ID, counterparty.ID, "10.50"}, rows[1][:5])
+	assert.Equal(t, entity.DataExportDirectionReceived, rows[2][1])
+
+	profile, err := csv.NewReader(bytes.NewReader(files["profile.csv"])).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, user.Document, profile[1][2])
+}
@@ -0,0 +1,174 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var disputePolicy = entity.DisputePolicy{
+	Window:         90 * 24 * time.Hour,
+	ResponseTime:   7 * 24 * time.Hour,
+	ResolutionTime: 30 * 24 * time.Hour,
+}
+
+func completedTransaction(t *testing.T, completedAt time.Time) *entity.Transaction {
+	transaction, err := entity.NewTransaction("payer", "merchant", decimal.NewFromInt(100))
+	require.NoError(t, err)
+	transaction.Complete()
+	transaction.CompletedAt = &completedAt
+	return transaction
+}
+
+func openDispute(t *testing.T, now time.Time) *entity.Dispute {
+	dispute, err := entity.NewDispute(completedTransaction(t, now.Add(-time.Hour)), "payer", entity.DisputeReasonNotReceived, "produto não foi entregue", nil, disputePolicy, now)
+	require.NoError(t, err)
+	return dispute
+}
+
+func TestNewDisputeSetsDeadlines(t *testing.T) {
+	now := time.Now()
+
+	dispute := openDispute(t, now)
+
+	assert.Equal(t, entity.DisputeStatusOpen, dispute.Status)
+	assert.Equal(t, "merchant", dispute.MerchantID)
+	assert.Equal(t, now.Add(7*24*time.Hour), dispute.ResponseDueAt)
+	assert.Equal(t, now.Add(30*24*time.Hour), dispute.ResolutionDueAt)
+	assert.Len(t, dispute.Evidence, 1)
+	assert.Equal(t, entity.DisputePartyPayer, dispute.LastEvidence().Party)
+	assert.Len(t, dispute.History, 1)
+	assert.Nil(t, dispute.LastEvent().FromStatus)
+}
+
+func TestNewDisputeRejectsOtherUsersTransaction(t *testing.T) {
+	now := time.Now()
+
+	_, err := entity.NewDispute(completedTransaction(t, now), "merchant", entity.DisputeReasonOther, "não reconheço a compra", nil, disputePolicy, now)
+
+	assert.ErrorIs(t, err, entity.ErrTransactionNotFound)
+}
+
+func TestNewDisputeRequiresCompletedTransaction(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer", "merchant", decimal.NewFromInt(100))
+	require.NoError(t, err)
+
+	_, err = entity.NewDispute(transaction, "payer", entity.DisputeReasonOther, "não reconheço a compra", nil, disputePolicy, time.Now())
+
+	assert.ErrorIs(t, err, entity.ErrTransactionNotCompleted)
+}
+
+func TestNewDisputeOutsideWindow(t *testing.T) {
+	now := time.Now()
+
+	_, err := entity.NewDispute(completedTransaction(t, now.Add(-91*24*time.Hour)), "payer", entity.DisputeReasonOther, "não reconheço a compra", nil, disputePolicy, now)
+
+	assert.ErrorIs(t, err, entity.ErrDisputeWindowClosed)
+}
+
+func TestNewDisputeValidatesEvidence(t *testing.T) {
+	now := time.Now()
+	transaction := completedTransaction(t, now)
+
+	_, err := entity.NewDispute(transaction, "payer", entity.DisputeReasonOther, "curta", nil, disputePolicy, now)
+	assert.Error(t, err)
+
+	_, err = entity.NewDispute(transaction, "payer", entity.DisputeReasonOther, "não reconheço a compra", []string{"http://exemplo.com/a.png"}, disputePolicy, now)
+	assert.Error(t, err)
+
+	_, err = entity.NewDispute(transaction, "payer", entity.DisputeReason("fraude"), "não reconheço a compra", nil, disputePolicy, now)
+	assert.ErrorIs(t, err, entity.ErrInvalidDisputeReason)
+}
+
+func TestDisputeRespondMovesToReview(t *testing.T) {
+	now := time.Now()
+	dispute := openDispute(t, now)
+
+	err := dispute.Respond("merchant", "produto entregue, segue comprovante", []string{"https://exemplo.com/rastreio.pdf"}, now.Add(time.Hour))
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.DisputeStatusUnderReview, dispute.Status)
+	assert.Equal(t, entity.DisputePartyMerchant, dispute.LastEvidence().Party)
+	assert.Equal(t, entity.DisputeStatusOpen, *dispute.LastEvent().FromStatus)
+}
+
+func TestDisputeRespondRules(t *testing.T) {
+	now := time.Now()
+
+	dispute := openDispute(t, now)
+	assert.ErrorIs(t, dispute.Respond("payer", "não sou o lojista mas tento", nil, now), entity.ErrDisputeNotFound)
+	assert.ErrorIs(t, dispute.Respond("merchant", "resposta fora do prazo", nil, now.Add(8*24*time.Hour)), entity.ErrDisputeResponseOverdue)
+
+	require.NoError(t, dispute.Respond("merchant", "produto entregue no prazo", nil, now))
+	assert.ErrorIs(t, dispute.Respond("merchant", "segunda resposta do lojista", nil, now), entity.ErrDisputeNotOpen)
+}
+
+func TestDisputeEscalateOverdue(t *testing.T) {
+	now := time.Now()
+	dispute := openDispute(t, now)
+
+	assert.False(t, dispute.EscalateOverdue(now.Add(time.Hour)))
+	assert.True(t, dispute.EscalateOverdue(now.Add(8*24*time.Hour)))
+	assert.Equal(t, entity.DisputeStatusUnderReview, dispute.Status)
+	assert.Equal(t, entity.DisputePartySystem, dispute.LastEvent().Actor)
+	assert.False(t, dispute.EscalateOverdue(now.Add(9*24*time.Hour)))
+}
+
+func TestDisputeEscalateResolutionOverdue(t *testing.T) {
+	now := time.Now()
+	dispute := openDispute(t, now)
+	require.NoError(t, dispute.Respond("merchant", "produto entregue no prazo", nil, now))
+
+	assert.False(t, dispute.EscalateResolutionOverdue(now.Add(29*24*time.Hour)))
+	assert.True(t, dispute.EscalateResolutionOverdue(now.Add(31*24*time.Hour)))
+	assert.NotNil(t, dispute.EscalatedAt)
+	assert.Equal(t, entity.DisputeStatusUnderReview, dispute.Status)
+	assert.Equal(t, "prazo de decisão expirado", dispute.LastEvent().Note)
+	assert.False(t, dispute.EscalateResolutionOverdue(now.Add(32*24*time.Hour)))
+
+	// Disputa já decidida não é escalada
+	resolved := openDispute(t, now)
+	require.NoError(t, resolved.Resolve(entity.DisputePartyMerchant, "", now))
+	assert.False(t, resolved.EscalateResolutionOverdue(now.Add(31*24*time.Hour)))
+}
+
+func TestDisputeResolve(t *testing.T) {
+	now := time.Now()
+	dispute := openDispute(t, now)
+
+	assert.ErrorIs(t, dispute.Resolve(entity.DisputePartyAdmin, "", now), entity.ErrInvalidDisputeWinner)
+
+	require.NoError(t, dispute.Resolve(entity.DisputePartyPayer, "", now))
+	assert.Equal(t, entity.DisputeStatusWonByPayer, dispute.Status)
+	assert.NotNil(t, dispute.ResolvedAt)
+	assert.Equal(t, "disputa decidida a favor do pagador", *dispute.ResolutionNote)
+	assert.Equal(t, entity.DisputePartyAdmin, dispute.LastEvent().Actor)
+
+	assert.ErrorIs(t, dispute.Resolve(entity.DisputePartyMerchant, "", now), entity.ErrDisputeAlreadyResolved)
+}
+
+func TestDisputeIsOverdue(t *testing.T) {
+	now := time.Now()
+	dispute := openDispute(t, now)
+
+	assert.False(t, dispute.IsOverdue(now.Add(29*24*time.Hour)))
+	assert.True(t, dispute.IsOverdue(now.Add(31*24*time.Hour)))
+
+	require.NoError(t, dispute.Resolve(entity.DisputePartyMerchant, "entrega comprovada", now))
+	assert.False(t, dispute.IsOverdue(now.Add(31*24*time.Hour)))
+}
+
+func TestNewFeeReversalEntry(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer", "merchant", decimal.NewFromInt(100))
+	require.NoError(t, err)
+	require.NoError(t, transaction.ApplyFee(decimal.RequireFromString("1.50"), nil))
+
+	entry := entity.NewFeeReversalEntry(transaction)
+
+	assert.Equal(t, "-1.50", entry.Amount.StringFixed(2))
+	assert.Equal(t, transaction.ID, *entry.TransactionID)
+}
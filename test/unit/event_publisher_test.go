@@ -0,0 +1,197 @@
+package entity_test
+
+import (
+	"context"
+	"encoding/json"
+	"payflow-api/internal/entity"
+	"payflow-api/internal/gateway"
+	"payflow-api/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTxManager roda as funções direto, sem transação
+type memoryTxManager struct{}
+
+func (memoryTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (memoryTxManager) AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	fn(ctx)
+}
+
+// memoryOutbox guarda os eventos do outbox em memória, na ordem de gravação
+type memoryOutbox struct {
+	events []*entity.OutboxEvent
+	locked bool
+}
+
+func (o *memoryOutbox) Append(ctx context.Context, events []*entity.OutboxEvent) error {
+	o.events = append(o.events, events...)
+	return nil
+}
+
+func (o *memoryOutbox) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entity.OutboxEvent, error) {
+	return nil, nil
+}
+
+func (o *memoryOutbox) Update(ctx context.Context, event *entity.OutboxEvent) error {
+	return nil
+}
+
+func (o *memoryOutbox) LockPublishing(ctx context.Context) (bool, error) {
+	return !o.locked, nil
+}
+
+func (o *memoryOutbox) ListUnpublished(ctx context.Context, limit int) ([]*entity.OutboxEvent, error) {
+	var events []*entity.OutboxEvent
+	for _, event := range o.events {
+		if event.PublishedAt == nil && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (o *memoryOutbox) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	for _, event := range o.events {
+		if event.ID == id {
+			event.PublishedAt = &publishedAt
+			return nil
+		}
+	}
+	return entity.ErrOutboxEventNotFound
+}
+
+func (o *memoryOutbox) CountBacklog(ctx context.Context) (entity.OutboxBacklog, error) {
+	var backlog entity.OutboxBacklog
+	for _, event := range o.events {
+		if !event.IsDispatched() {
+			backlog.Undispatched++
+		}
+		if event.PublishedAt == nil {
+			backlog.Unpublished++
+		}
+	}
+	return backlog, nil
+}
+
+func newPublishTestOutbox(t *testing.T) *memoryOutbox {
+	transaction, err := entity.NewTransaction("payer-1", "payee-1", decimal.NewFromInt(100))
+	require.NoError(t, err)
+	transaction.Authorize("auth-1")
+	transaction.Complete()
+	transaction.Reverse("pedido do cliente")
+
+	outbox := &memoryOutbox{}
+	now := time.Now()
+	for _, event := range transaction.PullEvents() {
+		outbox.events = append(outbox.events, entity.NewOutboxEvent(event, 0, now))
+	}
+	return outbox
+}
+
+func TestNewEventMessage_Envelope(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer-1", "payee-1", decimal.NewFromInt(100))
+	require.NoError(t, err)
+	transaction.Complete()
+	event := transaction.PullEvents()[0]
+
+	message, err := entity.NewEventMessage(event)
+	require.NoError(t, err)
+	assert.Equal(t, entity.EventSchemaVersion, message.SchemaVersion)
+	assert.Equal(t, event.ID, message.ID)
+	assert.Equal(t, "payer-1", message.PartitionKey)
+
+	content, err := message.Encode()
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &decoded))
+	assert.Equal(t, float64(1), decoded["schema_version"])
+	assert.Equal(t, "transfer.completed", decoded["type"])
+	assert.Equal(t, "transaction", decoded["aggregate_type"])
+	assert.Equal(t, "payee-1", decoded["data"].(map[string]interface{})["payee_id"])
+}
+
+func TestNewEventMessage_PartitionsByUser(t *testing.T) {
+	user, err := entity.NewUser("João Silva", "11144477735", "joao@example.com", "senha123", entity.UserTypeCommon)
+	require.NoError(t, err)
+	user.CreditBalance(decimal.NewFromInt(10))
+
+	events := user.PullEvents()
+	require.Len(t, events, 2)
+	for _, event := range events {
+		message, err := entity.NewEventMessage(event)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, message.PartitionKey)
+	}
+
+	first := &entity.EventMessage{PartitionKey: user.ID}
+	second := &entity.EventMessage{PartitionKey: user.ID}
+	assert.Equal(t, first.Partition(16), second.Partition(16))
+	assert.GreaterOrEqual(t, first.Partition(16), 0)
+	assert.Less(t, first.Partition(16), 16)
+	assert.Equal(t, 0, first.Partition(1))
+}
+
+func TestEventPublishRelay_PublishesOnce(t *testing.T) {
+	outbox := newPublishTestOutbox(t)
+	publisher := gateway.NewMemoryEventPublisher()
+	relay := usecase.NewEventPublishRelay(memoryTxManager{}, outbox, publisher)
+
+	published, err := relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, published)
+
+	// Eventos já confirmados pelo broker não são publicados de novo
+	published, err = relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+
+	messages := publisher.Messages()
+	require.Len(t, messages, 3)
+	assert.Equal(t, entity.EventTransferAuthorized, messages[0].Type)
+	assert.Equal(t, entity.EventTransferCompleted, messages[1].Type)
+	assert.Equal(t, entity.EventTransferReversed, messages[2].Type)
+}
+
+func TestEventPublishRelay_ResumesAfterBrokerFailure(t *testing.T) {
+	outbox := newPublishTestOutbox(t)
+	publisher := gateway.NewMemoryEventPublisher()
+	publisher.FailAfter = 1
+	relay := usecase.NewEventPublishRelay(memoryTxManager{}, outbox, publisher)
+
+	published, err := relay.PublishPending(context.Background())
+	assert.ErrorIs(t, err, entity.ErrEventPublisherUnavailable)
+	assert.Equal(t, 1, published)
+
+	publisher.FailAfter = -1
+	published, err = relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+
+	// Cada evento chega uma única vez; o lote em memória sai na ordem de gravação
+	messages := publisher.Messages()
+	require.Len(t, messages, 3)
+	for i, event := range outbox.events {
+		assert.Equal(t, event.ID, messages[i].ID)
+		assert.NotNil(t, event.PublishedAt)
+	}
+}
+
+func TestEventPublishRelay_SkipsWhileAnotherInstancePublishes(t *testing.T) {
+	outbox := newPublishTestOutbox(t)
+	outbox.locked = true
+	publisher := gateway.NewMemoryEventPublisher()
+	relay := usecase.NewEventPublishRelay(memoryTxManager{}, outbox, publisher)
+
+	published, err := relay.PublishPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+	assert.Empty(t, publisher.Messages())
+}
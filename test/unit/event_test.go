@@ -0,0 +1,131 @@
+package entity_test
+
+import (
+	"encoding/json"
+	"errors"
+	"payflow-api/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUser_RecordsUserCreated(t *testing.T) {
+	user, err := entity.NewUser("João Silva", "11144477735", "joao@example.com", "senha123", entity.UserTypeCommon)
+	require.NoError(t, err)
+
+	events := user.PullEvents()
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.EventUserCreated, events[0].Type)
+	assert.Equal(t, "user", events[0].AggregateType)
+	assert.Equal(t, user.ID, events[0].AggregateID)
+
+	var payload entity.UserCreated
+	require.NoError(t, events[0].Decode(&payload))
+	assert.Equal(t, user.ID, payload.UserID)
+	assert.Equal(t, entity.UserTypeCommon, payload.UserType)
+
+	// O evento não carrega dados pessoais
+	assert.NotContains(t, string(events[0].Data), "11144477735")
+	assert.NotContains(t, string(events[0].Data), "joao@example.com")
+
+	// Os eventos são entregues uma única vez
+	assert.Empty(t, user.PullEvents())
+}
+
+func TestTransaction_RecordsLifecycleEvents(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer-1", "payee-1", decimal.NewFromInt(100))
+	require.NoError(t, err)
+	require.NoError(t, transaction.ApplyFee(decimal.NewFromInt(2), nil))
+	assert.Empty(t, transaction.PullEvents())
+
+	transaction.Authorize("auth-1")
+	transaction.Complete()
+	transaction.Reverse("pedido do cliente")
+
+	events := transaction.PullEvents()
+	require.Len(t, events, 3)
+	assert.Equal(t, entity.EventTransferAuthorized, events[0].Type)
+	assert.Equal(t, entity.EventTransferCompleted, events[1].Type)
+	assert.Equal(t, entity.EventTransferReversed, events[2].Type)
+	for _, event := range events {
+		assert.Equal(t, transaction.ID, event.AggregateID)
+		assert.NotEmpty(t, event.ID)
+	}
+
+	var completed entity.TransferCompleted
+	require.NoError(t, events[1].Decode(&completed))
+	assert.True(t, decimal.NewFromInt(98).Equal(completed.NetAmount))
+	assert.Equal(t, "payee-1", completed.PayeeID)
+
+	var reversed entity.TransferReversed
+	require.NoError(t, events[2].Decode(&reversed))
+	assert.Equal(t, "pedido do cliente", reversed.Reason)
+}
+
+func TestTransaction_FailRecordsEvent(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer-1", "payee-1", decimal.NewFromInt(50))
+	require.NoError(t, err)
+
+	transaction.Fail("saldo insuficiente")
+
+	events := transaction.PullEvents()
+	require.Len(t, events, 1)
+	var failed entity.TransferFailed
+	require.NoError(t, events[0].Decode(&failed))
+	assert.Equal(t, "saldo insuficiente", failed.Reason)
+}
+
+func TestTransaction_EventsNotSerialized(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer-1", "payee-1", decimal.NewFromInt(50))
+	require.NoError(t, err)
+	transaction.Fail("negada")
+
+	content, err := json.Marshal(transaction)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "transfer.failed")
+
+	snapshot := entity.AuditSnapshot(transaction)
+	assert.NotContains(t, snapshot, "EventRecorder")
+	assert.Len(t, transaction.PullEvents(), 1)
+}
+
+func TestOutboxEvent_Dispatch(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer-1", "payee-1", decimal.NewFromInt(50))
+	require.NoError(t, err)
+	transaction.Complete()
+
+	now := time.Now()
+	policy := entity.OutboxRetryPolicy{BaseDelay: 10 * time.Second, MaxDelay: time.Minute}
+	event := entity.NewOutboxEvent(transaction.PullEvents()[0], time.Minute, now)
+	assert.Equal(t, now.Add(time.Minute), event.NextAttemptAt)
+	assert.False(t, event.IsDispatched())
+
+	event.MarkHandled("transfer-notification")
+	event.MarkHandled("transfer-notification")
+	assert.Equal(t, []string{"transfer-notification"}, event.HandledBy)
+
+	event.RecordDispatch(errors.New("metrics: serviço indisponível"), policy, now)
+	assert.False(t, event.IsDispatched())
+	assert.Equal(t, 1, event.Attempts)
+	assert.Equal(t, now.Add(10*time.Second), event.NextAttemptAt)
+	assert.Equal(t, "metrics: serviço indisponível", *event.LastError)
+
+	event.RecordDispatch(errors.New("metrics: serviço indisponível"), policy, now)
+	assert.Equal(t, now.Add(20*time.Second), event.NextAttemptAt)
+
+	event.RecordDispatch(nil, policy, now)
+	assert.True(t, event.IsDispatched())
+	assert.Nil(t, event.LastError)
+	assert.True(t, event.IsHandledBy("transfer-notification"))
+}
+
+func TestOutboxRetryPolicy_NextDelay(t *testing.T) {
+	policy := entity.OutboxRetryPolicy{BaseDelay: 10 * time.Second, MaxDelay: time.Minute}
+
+	assert.Equal(t, 10*time.Second, policy.NextDelay(0))
+	assert.Equal(t, 40*time.Second, policy.NextDelay(3))
+	assert.Equal(t, time.Minute, policy.NextDelay(10))
+}
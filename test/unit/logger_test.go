@@ -0,0 +1,86 @@
+package entity_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"payflow-api/pkg/logger"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLogLine(t *testing.T, output *bytes.Buffer) map[string]interface{} {
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(output.Bytes(), &line))
+	return line
+}
+
+func TestLogger_IncludesRequestID(t *testing.T) {
+	var output bytes.Buffer
+	log := logger.New("info", &output)
+
+	ctx := logger.WithRequestID(context.Background(), "req-123")
+	log.InfoContext(ctx, "transferência concluída", "transaction_id", "tx-1")
+
+	line := decodeLogLine(t, &output)
+	assert.Equal(t, "INFO", line["level"])
+	assert.Equal(t, "transferência concluída", line["msg"])
+	assert.Equal(t, "req-123", line["request_id"])
+	assert.Equal(t, "tx-1", line["transaction_id"])
+}
+
+func TestLogger_WithoutRequestID(t *testing.T) {
+	var output bytes.Buffer
+	log := logger.New("info", &output)
+
+	log.Info("servidor iniciando")
+
+	line := decodeLogLine(t, &output)
+	assert.NotContains(t, line, "request_id")
+}
+
+func TestLogger_Level(t *testing.T) {
+	var output bytes.Buffer
+	log := logger.New("warn", &output)
+
+	log.Info("ignorado")
+	assert.Empty(t, output.String())
+
+	log.Warn("registrado")
+	assert.NotEmpty(t, output.String())
+
+	assert.Equal(t, slog.LevelDebug, logger.ParseLevel("DEBUG"))
+	assert.Equal(t, slog.LevelInfo, logger.ParseLevel("desconhecido"))
+}
+
+func TestLogger_RedactsSensitiveValues(t *testing.T) {
+	var output bytes.Buffer
+	log := logger.New("debug", &output)
+
+	log.Error("cadastro recusado para 111.444.777-35",
+		"password", "senha123",
+		"token", "abc",
+		"document", "11144477735",
+		"merchant", "CNPJ 11.222.333/0001-81",
+		"url", "https://api/exports/1/download?expires=1700000000&signature=abcdef",
+		"error", errors.New("documento 11144477735 já cadastrado"),
+		"user_id", "550e8400-e29b-41d4-a716-446655440001",
+	)
+
+	content := output.String()
+	for _, secret := range []string{"senha123", "\"abc\"", "11144477735", "111.444.777-35", "11.222.333/0001-81", "abcdef"} {
+		assert.NotContains(t, content, secret)
+	}
+
+	line := decodeLogLine(t, &output)
+	assert.Equal(t, logger.Redacted, line["password"])
+	assert.Equal(t, "cadastro recusado para [REDACTED]", line["msg"])
+	assert.Equal(t, "documento [REDACTED] já cadastrado", line["error"])
+	assert.Equal(t, "https://api/exports/1/download?expires=1700000000&signature=[REDACTED]", line["url"])
+	// IDs e timestamps não são confundidos com documentos
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440001", line["user_id"])
+}
@@ -0,0 +1,144 @@
+package entity_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http/httptest"
+	"payflow-api/internal/entity"
+	"payflow-api/internal/gateway"
+	"payflow-api/internal/metrics"
+	"payflow-api/internal/usecase"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAuthorizer responde sempre com o erro configurado
+type stubAuthorizer struct {
+	err error
+}
+
+func (a stubAuthorizer) Authorize(ctx context.Context, transaction *entity.Transaction) (string, error) {
+	if a.err != nil {
+		return "", a.err
+	}
+	return "auth-1", nil
+}
+
+func scrapeMetrics(t *testing.T, m *metrics.Metrics) string {
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, 200, recorder.Code)
+	return recorder.Body.String()
+}
+
+func TestMetrics_HTTPRequestsByRouteAndStatus(t *testing.T) {
+	m := metrics.New()
+	m.ObserveHTTPRequest("GET", "/api/v1/users/:id", 200, 30*time.Millisecond)
+	m.ObserveHTTPRequest("GET", "", 404, time.Millisecond)
+
+	content := scrapeMetrics(t, m)
+	assert.Contains(t, content, `payflow_http_request_duration_seconds_count{method="GET",route="/api/v1/users/:id",status="200"} 1`)
+	assert.Contains(t, content, `payflow_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+}
+
+// commitTxManager guarda as funções de AfterCommit até o teste confirmar a transação
+type commitTxManager struct {
+	afterCommit []func(ctx context.Context)
+}
+
+func (m *commitTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *commitTxManager) AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	m.afterCommit = append(m.afterCommit, fn)
+}
+
+func (m *commitTxManager) commit() {
+	for _, fn := range m.afterCommit {
+		fn(context.Background())
+	}
+	m.afterCommit = nil
+}
+
+func TestTransferMetricsRecorder_CountsCommittedStatusChanges(t *testing.T) {
+	m := metrics.New()
+	txManager := &commitTxManager{}
+	outbox := &memoryOutbox{}
+	recorder := usecase.NewTransferMetricsRecorder(txManager, usecase.NewEventBus(txManager, outbox, usecase.EventPolicy{}), m)
+
+	completed, err := entity.NewTransaction("payer-1", "payee-1", decimal.RequireFromString("100.50"))
+	require.NoError(t, err)
+	completed.Authorize("auth-1")
+	completed.Complete()
+	failed, err := entity.NewTransaction("payer-1", "payee-1", decimal.NewFromInt(20))
+	require.NoError(t, err)
+	failed.Fail("saldo insuficiente")
+
+	require.NoError(t, recorder.Record(context.Background(), completed, failed))
+	// Os eventos seguem para o outbox, mas nada é contado antes do commit
+	assert.Len(t, outbox.events, 3)
+	assert.NotContains(t, scrapeMetrics(t, m), `payflow_transfers_total{status="completed"}`)
+
+	txManager.commit()
+	content := scrapeMetrics(t, m)
+	assert.Contains(t, content, `payflow_transfers_total{status="authorized"} 1`)
+	assert.Contains(t, content, `payflow_transfers_total{status="completed"} 1`)
+	assert.Contains(t, content, `payflow_transfers_total{status="failed"} 1`)
+	assert.Contains(t, content, `payflow_transfer_amount_reais_total{status="completed"} 100.5`)
+	assert.Contains(t, content, `payflow_transfer_amount_reais_total{status="failed"} 20`)
+}
+
+func TestInstrumentedAuthorizer_Outcomes(t *testing.T) {
+	m := metrics.New()
+	transaction, err := entity.NewTransaction("payer-1", "payee-1", decimal.NewFromInt(10))
+	require.NoError(t, err)
+
+	for _, authorizerErr := range []error{nil, entity.ErrAuthorizationFailed, entity.ErrAuthorizationTimeout, entity.ErrAuthorizationService} {
+		authorizer := gateway.NewInstrumentedAuthorizer(stubAuthorizer{err: authorizerErr}, m)
+		_, err := authorizer.Authorize(context.Background(), transaction)
+		assert.ErrorIs(t, err, authorizerErr)
+	}
+
+	content := scrapeMetrics(t, m)
+	for _, outcome := range []string{"success", "denied", "timeout", "error"} {
+		assert.Contains(t, content, `payflow_external_requests_total{outcome="`+outcome+`",service="authorizer"} 1`)
+	}
+	assert.Contains(t, content, `payflow_external_request_duration_seconds_count{service="authorizer"} 4`)
+}
+
+func TestMetrics_OutboxBacklog(t *testing.T) {
+	m := metrics.New()
+	outbox := newPublishTestOutbox(t)
+	m.RegisterOutboxBacklog(func(ctx context.Context) (map[string]int, error) {
+		backlog, err := outbox.CountBacklog(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]int{"dispatch": backlog.Undispatched, "publish": backlog.Unpublished}, nil
+	})
+
+	content := scrapeMetrics(t, m)
+	assert.Contains(t, content, `payflow_outbox_backlog{queue="dispatch"} 3`)
+	assert.Contains(t, content, `payflow_outbox_backlog{queue="publish"} 3`)
+}
+
+func TestMetrics_DBPoolStats(t *testing.T) {
+	// sql.Open não conecta; as estatísticas do pool já podem ser lidas
+	db, err := sql.Open("postgres", "host=localhost dbname=payflow")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(25)
+
+	m := metrics.New()
+	m.RegisterDBStats(db, "payflow")
+
+	content := scrapeMetrics(t, m)
+	assert.Contains(t, content, `go_sql_max_open_connections{db_name="payflow"} 25`)
+	assert.Contains(t, content, `go_sql_in_use_connections{db_name="payflow"} 0`)
+}
@@ -0,0 +1,64 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePaymentRequest(t *testing.T) {
+	req := &entity.CreatePaymentRequestRequest{
+		Amount:      decimal.NewFromFloat(89.9),
+		Description: "Pedido #123",
+		ExpiresAt:   time.Now().Add(24 * time.Hour),
+	}
+
+	request, err := entity.FromCreatePaymentRequestRequest(req, "merchant")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PaymentRequestStatusOpen, request.Status)
+	assert.Equal(t, "Aberta", request.GetStatusDescription())
+	assert.True(t, request.IsVisibleTo("qualquer-usuario"))
+}
+
+func TestPaymentRequestRejectsInvalidExpiry(t *testing.T) {
+	_, err := entity.NewPaymentRequest("merchant", nil, decimal.NewFromInt(10), "", time.Now().Add(-time.Minute))
+	assert.Error(t, err)
+
+	_, err = entity.NewPaymentRequest("merchant", nil, decimal.NewFromInt(10), "", time.Now().Add(entity.MaxPaymentRequestExpiry+time.Hour))
+	assert.Error(t, err)
+}
+
+func TestPaymentRequestRestrictedToPayer(t *testing.T) {
+	payerID := "payer"
+	request, err := entity.NewPaymentRequest("merchant", &payerID, decimal.NewFromInt(10), "", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	assert.NoError(t, request.CanBePaidBy("payer", time.Now()))
+	assert.ErrorIs(t, request.CanBePaidBy("outro", time.Now()), entity.ErrPaymentRequestNotFound)
+	assert.False(t, request.IsVisibleTo("outro"))
+	assert.ErrorIs(t, request.CanBePaidBy("payer", time.Now().Add(2*time.Hour)), entity.ErrPaymentRequestExpired)
+}
+
+func TestPaymentRequestMarkPaid(t *testing.T) {
+	request, err := entity.NewPaymentRequest("merchant", nil, decimal.NewFromInt(10), "", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	transaction, err := entity.NewTransaction("payer", "merchant", decimal.NewFromInt(10))
+	assert.NoError(t, err)
+
+	request.AttachTransaction(transaction.ID)
+	assert.ErrorIs(t, request.MarkPaid(transaction), entity.ErrTransactionNotCompleted)
+
+	transaction.Complete()
+	assert.NoError(t, request.MarkPaid(transaction))
+	assert.Equal(t, entity.PaymentRequestStatusPaid, request.Status)
+	assert.Equal(t, "payer", *request.PaidBy)
+	assert.True(t, request.IsVisibleTo("payer"))
+
+	assert.ErrorIs(t, request.CanBePaidBy("payer", time.Now()), entity.ErrPaymentRequestAlreadyPaid)
+	assert.ErrorIs(t, request.Cancel(), entity.ErrPaymentRequestNotOpen)
+}
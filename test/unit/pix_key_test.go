@@ -0,0 +1,83 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPixKeyMustBelongToOwner(t *testing.T) {
+	user := NewUser(t)
+
+	key, err := entity.NewPixKey(user, entity.PixKeyTypeCPF, "111.444.777-35")
+	assert.NoError(t, err)
+	assert.Equal(t, "11144477735", key.KeyValue)
+
+	_, err = entity.NewPixKey(user, entity.PixKeyTypeCPF, "529.982.247-25")
+	assert.ErrorIs(t, err, entity.ErrPixKeyNotOwned)
+
+	_, err = entity.NewPixKey(user, entity.PixKeyTypeCNPJ, "11.222.333/0001-81")
+	assert.ErrorIs(t, err, entity.ErrPixKeyNotOwned)
+
+	key, err = entity.NewPixKey(user, entity.PixKeyTypeEmail, " Joao.Silva@Example.com ")
+	assert.NoError(t, err)
+	assert.Equal(t, "joao.silva@example.com", key.KeyValue)
+
+	_, err = entity.NewPixKey(user, entity.PixKeyTypeEmail, "outro@example.com")
+	assert.ErrorIs(t, err, entity.ErrPixKeyNotOwned)
+}
+
+func TestPixKeyPhoneAndRandom(t *testing.T) {
+	user := NewUser(t)
+
+	key, err := entity.NewPixKey(user, entity.PixKeyTypePhone, "+55 (11) 98765-4321")
+	assert.NoError(t, err)
+	assert.Equal(t, "+5511987654321", key.KeyValue)
+
+	_, err = entity.NewPixKey(user, entity.PixKeyTypePhone, "11987654321")
+	assert.ErrorIs(t, err, entity.ErrInvalidPixKey)
+
+	key, err = entity.NewPixKey(user, entity.PixKeyTypeEVP, "ignorado")
+	assert.NoError(t, err)
+	assert.Len(t, key.KeyValue, 36)
+
+	_, err = entity.NewPixKey(user, "iban", "x")
+	assert.ErrorIs(t, err, entity.ErrInvalidPixKeyType)
+}
+
+func TestNormalizePixKey(t *testing.T) {
+	assert.Equal(t, "11144477735", entity.NormalizePixKey("111.444.777-35"))
+	assert.Equal(t, "11222333000181", entity.NormalizePixKey("11.222.333/0001-81"))
+	assert.Equal(t, "loja@exemplo.com", entity.NormalizePixKey("Loja@Exemplo.com"))
+	assert.Equal(t, "+5511987654321", entity.NormalizePixKey("+55 11 98765-4321"))
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", entity.NormalizePixKey("123E4567-E89B-12D3-A456-426614174000"))
+}
+
+func TestPixKeyLookupMasksHolder(t *testing.T) {
+	user := NewUser(t)
+	key, err := entity.NewPixKey(user, entity.PixKeyTypeEmail, user.Email)
+	assert.NoError(t, err)
+
+	lookup := key.ToPixKeyLookupResponse(user)
+	assert.Equal(t, "João S***", lookup.HolderName)
+	assert.Equal(t, "111.***.***-35", lookup.Document)
+	assert.Equal(t, entity.MaxPixKeysCommon, entity.MaxPixKeys(entity.UserTypeCommon))
+	assert.Equal(t, entity.MaxPixKeysMerchant, entity.MaxPixKeys(entity.UserTypeMerchant))
+}
+
+func TestPixKeyLookupMasksMultiByteInitials(t *testing.T) {
+	user := NewUser(t)
+	key, err := entity.NewPixKey(user, entity.PixKeyTypeEmail, user.Email)
+	assert.NoError(t, err)
+
+	// Iniciais acentuadas ocupam mais de um byte e não podem ser cortadas ao meio
+	user.FullName = "  Ágata   Érica Ñuñez Ødegaard "
+	assert.Equal(t, "Ágata É*** Ñ*** Ø***", key.ToPixKeyLookupResponse(user).HolderName)
+
+	user.FullName = "Ângela"
+	assert.Equal(t, "Ângela", key.ToPixKeyLookupResponse(user).HolderName)
+
+	user.FullName = " "
+	assert.Empty(t, key.ToPixKeyLookupResponse(user).HolderName)
+}
@@ -0,0 +1,87 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func decimalPtr(value string) *decimal.Decimal {
+	d := decimal.RequireFromString(value)
+	return &d
+}
+
+func TestPricingPlanCalculateFee(t *testing.T) {
+	tiered := []entity.FeeTier{
+		{UpTo: decimalPtr("100"), FixedAmount: decimal.RequireFromString("0.50"), Percentage: decimal.Zero},
+		{UpTo: decimalPtr("1000"), Percentage: decimal.RequireFromString("2")},
+		{Percentage: decimal.RequireFromString("1")},
+	}
+
+	tests := []struct {
+		name     string
+		feeType  entity.FeeType
+		fixed    string
+		percent  string
+		tiers    []entity.FeeTier
+		minFee   *decimal.Decimal
+		maxFee   *decimal.Decimal
+		amount   string
+		expected string
+	}{
+		{"Fixa", entity.FeeTypeFixed, "1.50", "0", nil, nil, nil, "100", "1.50"},
+		{"Percentual com arredondamento", entity.FeeTypePercentage, "0", "1.99", nil, nil, nil, "33.33", "0.66"},
+		{"Percentual com mínimo", entity.FeeTypePercentage, "0", "1", nil, decimalPtr("0.80"), nil, "10", "0.80"},
+		{"Percentual com máximo", entity.FeeTypePercentage, "0", "3", nil, nil, decimalPtr("20"), "5000", "20.00"},
+		{"Escalonada primeira faixa", entity.FeeTypeTiered, "0", "0", tiered, nil, nil, "80", "0.50"},
+		{"Escalonada segunda faixa", entity.FeeTypeTiered, "0", "0", tiered, nil, nil, "500", "10.00"},
+		{"Escalonada faixa sem teto", entity.FeeTypeTiered, "0", "0", tiered, nil, nil, "2000", "20.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := entity.NewPricingPlan(
+				"Plano de teste",
+				tt.feeType,
+				decimal.RequireFromString(tt.fixed),
+				decimal.RequireFromString(tt.percent),
+				tt.tiers,
+				tt.minFee,
+				tt.maxFee,
+			)
+			assert.NoError(t, err)
+
+			fee := plan.CalculateFee(decimal.RequireFromString(tt.amount))
+			assert.Equal(t, tt.expected, fee.StringFixed(2))
+		})
+	}
+}
+
+func TestPricingPlanInvalidTiers(t *testing.T) {
+	tiers := []entity.FeeTier{
+		{Percentage: decimal.RequireFromString("1")},
+		{UpTo: decimalPtr("100"), Percentage: decimal.RequireFromString("2")},
+	}
+
+	_, err := entity.NewPricingPlan("Plano inválido", entity.FeeTypeTiered, decimal.Zero, decimal.Zero, tiers, nil, nil)
+
+	assert.Error(t, err)
+}
+
+func TestTransactionApplyFee(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer", "payee", decimal.NewFromInt(100))
+	assert.NoError(t, err)
+
+	planID := "plan"
+	assert.NoError(t, transaction.ApplyFee(decimal.RequireFromString("1.99"), &planID))
+	assert.Equal(t, "98.01", transaction.NetAmount().StringFixed(2))
+
+	response := transaction.ToCreateTransactionResponse()
+	assert.Equal(t, "100.00", response.GrossAmount)
+	assert.Equal(t, "1.99", response.FeeAmount)
+	assert.Equal(t, "98.01", response.NetAmount)
+
+	assert.ErrorIs(t, transaction.ApplyFee(decimal.NewFromInt(100), &planID), entity.ErrFeeExceedsAmount)
+}
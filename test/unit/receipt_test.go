@@ -0,0 +1,189 @@
+package entity_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"payflow-api/internal/entity"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receiptSigner(t *testing.T) *entity.ReceiptSigner {
+	key, err := entity.ParseReceiptSigningKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, ed25519.SeedSize)))
+	require.NoError(t, err)
+	return entity.NewReceiptSigner(key)
+}
+
+func receiptTransaction() *entity.Transaction {
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	completedAt := createdAt.Add(2 * time.Second)
+	authorizationID := "auth-123"
+	return &entity.Transaction{
+		ID:              "tx-1",
+		PayerID:         "user-1",
+		PayeeID:         "user-2",
+		Amount:          decimal.RequireFromString("150.5"),
+		Status:          entity.TransactionStatusCompleted,
+		AuthorizationID: &authorizationID,
+		CreatedAt:       createdAt,
+		CompletedAt:     &completedAt,
+	}
+}
+
+func receiptParties() (*entity.User, *entity.User) {
+	payer := &entity.User{ID: "user-1", FullName: "Maria Silva", Document: "12345678901"}
+	payee := &entity.User{ID: "user-2", FullName: "Loja do João", Document: "12345678000190"}
+	return payer, payee
+}
+
+func TestNewReceiptPayload_MasksPartyData(t *testing.T) {
+	payer, payee := receiptParties()
+
+	payload, err := entity.NewReceiptPayload(receiptTransaction(), payer, payee, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, "123.***.***-01", payload.Payer.Document)
+	assert.Equal(t, "12.***.***/0001-90", payload.Payee.Document)
+	assert.Equal(t, "Maria S***", payload.Payer.Name)
+	assert.Equal(t, "Loja d*** J***", payload.Payee.Name)
+	assert.Equal(t, "150.50", payload.Amount)
+	assert.Equal(t, "auth-123", payload.AuthorizationID)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9A-F]{4}(-[0-9A-F]{4}){4}$`), payload.AuthenticationCode)
+}
+
+func TestNewReceiptPayload_UniqueCodes(t *testing.T) {
+	payer, payee := receiptParties()
+
+	first, err := entity.NewReceiptPayload(receiptTransaction(), payer, payee, time.Now())
+	require.NoError(t, err)
+	second, err := entity.NewReceiptPayload(receiptTransaction(), payer, payee, time.Now())
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first.AuthenticationCode, second.AuthenticationCode)
+}
+
+func TestNewReceiptPayload_RequiresCompletedTransaction(t *testing.T) {
+	payer, payee := receiptParties()
+	transaction := receiptTransaction()
+	transaction.Status = entity.TransactionStatusPending
+	transaction.CompletedAt = nil
+
+	_, err := entity.NewReceiptPayload(transaction, payer, payee, time.Now())
+	assert.ErrorIs(t, err, entity.ErrTransactionNotCompleted)
+}
+
+func TestReceiptSigner_IssueAndVerify(t *testing.T) {
+	signer := receiptSigner(t)
+	payer, payee := receiptParties()
+	payload, err := entity.NewReceiptPayload(receiptTransaction(), payer, payee, time.Now())
+	require.NoError(t, err)
+
+	receipt, err := signer.Issue(payload)
+	require.NoError(t, err)
+	assert.Equal(t, signer.KeyID(), receipt.KeyID)
+	assert.True(t, signer.Verify(receipt.KeyID, receipt.Payload, receipt.Signature))
+
+	// Qualquer terceiro confere com a chave pública
+	assert.True(t, ed25519.Verify(signer.PublicKey(), receipt.Payload, receipt.Signature))
+
+	tampered := bytes.Replace(receipt.Payload, []byte("150.50"), []byte("950.50"), 1)
+	assert.False(t, signer.Verify(receipt.KeyID, tampered, receipt.Signature))
+	assert.False(t, signer.Verify("outra-chave", receipt.Payload, receipt.Signature))
+}
+
+func TestReceiptSigner_VerifiesAfterRotation(t *testing.T) {
+	previous := receiptSigner(t)
+	payer, payee := receiptParties()
+	payload, err := entity.NewReceiptPayload(receiptTransaction(), payer, payee, time.Now())
+	require.NoError(t, err)
+	receipt, err := previous.Issue(payload)
+	require.NoError(t, err)
+
+	encoded := base64.StdEncoding.EncodeToString(previous.PublicKey())
+	keys, err := entity.ParseReceiptPublicKeys(" " + encoded + ",")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+
+	current := entity.NewReceiptSigner(ed25519.NewKeyFromSeed(bytes.Repeat([]byte{9}, ed25519.SeedSize)), keys...)
+	assert.NotEqual(t, previous.KeyID(), current.KeyID())
+	assert.True(t, current.KnowsKey(previous.KeyID()))
+	assert.True(t, current.Verify(receipt.KeyID, receipt.Payload, receipt.Signature))
+
+	// Sem a chave anterior no chaveiro o comprovante antigo não confere
+	alone := entity.NewReceiptSigner(ed25519.NewKeyFromSeed(bytes.Repeat([]byte{9}, ed25519.SeedSize)))
+	assert.False(t, alone.Verify(receipt.KeyID, receipt.Payload, receipt.Signature))
+
+	published := current.PublicKeys()
+	require.Len(t, published, 2)
+	assert.Equal(t, current.KeyID(), published[0].KeyID)
+	assert.Equal(t, previous.KeyID(), published[1].KeyID)
+	assert.Equal(t, encoded, published[1].PublicKey)
+
+	_, err = entity.ParseReceiptPublicKeys(base64.StdEncoding.EncodeToString([]byte("curta")))
+	assert.Error(t, err)
+}
+
+func TestReceiptDocument_RoundTrip(t *testing.T) {
+	signer := receiptSigner(t)
+	payer, payee := receiptParties()
+	payload, err := entity.NewReceiptPayload(receiptTransaction(), payer, payee, time.Now())
+	require.NoError(t, err)
+	receipt, err := signer.Issue(payload)
+	require.NoError(t, err)
+
+	document, err := receipt.ToReceiptDocument()
+	require.NoError(t, err)
+	assert.Equal(t, entity.ReceiptAlgorithm, document.Algorithm)
+	assert.Equal(t, payload.AuthenticationCode, document.Receipt.AuthenticationCode)
+
+	signed, err := base64.StdEncoding.DecodeString(document.Payload)
+	require.NoError(t, err)
+	signature, err := base64.StdEncoding.DecodeString(document.Signature)
+	require.NoError(t, err)
+	assert.True(t, signer.Verify(document.KeyID, signed, signature))
+}
+
+func TestParseReceiptSigningKey(t *testing.T) {
+	seed := bytes.Repeat([]byte{1}, ed25519.SeedSize)
+
+	fromSeed, err := entity.ParseReceiptSigningKey(base64.StdEncoding.EncodeToString(seed))
+	require.NoError(t, err)
+	fromKey, err := entity.ParseReceiptSigningKey(base64.StdEncoding.EncodeToString(ed25519.NewKeyFromSeed(seed)))
+	require.NoError(t, err)
+	assert.Equal(t, fromSeed, fromKey)
+
+	_, err = entity.ParseReceiptSigningKey(base64.StdEncoding.EncodeToString([]byte("curta")))
+	assert.Error(t, err)
+	_, err = entity.ParseReceiptSigningKey("não é base64")
+	assert.Error(t, err)
+}
+
+func TestNormalizeAuthenticationCode(t *testing.T) {
+	assert.Equal(t, "ABCD-EF01-2345-6789-ABCD", entity.NormalizeAuthenticationCode("abcdef0123456789abcd"))
+	assert.Equal(t, "ABCD-EF01-2345-6789-ABCD", entity.NormalizeAuthenticationCode("abcd-ef01-2345-6789-abcd"))
+}
+
+func TestReceiptPDF(t *testing.T) {
+	signer := receiptSigner(t)
+	payer, payee := receiptParties()
+	payload, err := entity.NewReceiptPayload(receiptTransaction(), payer, payee, time.Now())
+	require.NoError(t, err)
+	receipt, err := signer.Issue(payload)
+	require.NoError(t, err)
+
+	content, err := receipt.PDF()
+	require.NoError(t, err)
+
+	assert.True(t, bytes.HasPrefix(content, []byte("%PDF-1.4")))
+	assert.Contains(t, string(content), "(R$ 150.50)")
+	assert.Contains(t, string(content), payload.AuthenticationCode)
+	assert.Contains(t, string(content), "123.***.***-01")
+	assert.NotContains(t, string(content), "12345678901")
+	assert.NotContains(t, string(content), "Maria Silva")
+}
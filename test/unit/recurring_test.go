@@ -0,0 +1,106 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"payflow-api/pkg/cron"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCronNext(t *testing.T) {
+	schedule, err := cron.Parse("30 9 * * 1-5")
+	assert.NoError(t, err)
+
+	// Sábado 06/01/2024 -> segunda 08/01/2024 às 09:30
+	after := time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 1, 8, 9, 30, 0, 0, time.UTC), schedule.Next(after))
+
+	_, err = cron.Parse("61 * * * *")
+	assert.Error(t, err)
+	_, err = cron.Parse("* * *")
+	assert.Error(t, err)
+}
+
+func TestMonthlyMandateClampsToLastDayOfMonth(t *testing.T) {
+	mandate := &entity.RecurringMandate{
+		Frequency: entity.RecurrenceMonthly,
+		StartDate: time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC),
+	}
+
+	next := mandate.OccurrenceAfter(mandate.StartDate)
+	assert.Equal(t, time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, time.Date(2024, 3, 31, 10, 0, 0, 0, time.UTC), mandate.OccurrenceAfter(next))
+	assert.Equal(t, "2024-02", mandate.PeriodKey(next))
+}
+
+func TestWeeklyMandatePeriodKey(t *testing.T) {
+	mandate := &entity.RecurringMandate{
+		Frequency: entity.RecurrenceWeekly,
+		StartDate: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC),
+	}
+
+	next := mandate.OccurrenceAfter(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, "2024-W02", mandate.PeriodKey(next))
+}
+
+func TestMandateCompletesAfterMaxOccurrences(t *testing.T) {
+	maxOccurrences := 2
+	mandate, err := entity.NewRecurringMandate("payer", "payee", decimal.NewFromInt(30), "assinatura",
+		entity.RecurrenceWeekly, nil, time.Now().Add(time.Hour), nil, &maxOccurrences)
+	assert.NoError(t, err)
+	assert.True(t, mandate.IsActive())
+
+	first := *mandate.NextRunAt
+	assert.NoError(t, mandate.RecordRun(first))
+	assert.Equal(t, first.Add(7*24*time.Hour), *mandate.NextRunAt)
+
+	assert.NoError(t, mandate.RecordRun(*mandate.NextRunAt))
+	assert.Equal(t, entity.MandateStatusCompleted, mandate.Status)
+	assert.Nil(t, mandate.NextRunAt)
+	assert.ErrorIs(t, mandate.RecordRun(first), entity.ErrMandateNotActive)
+}
+
+func TestMandateDeferRunKeepsOccurrence(t *testing.T) {
+	mandate, err := entity.NewRecurringMandate("payer", "payee", decimal.NewFromInt(30), "assinatura",
+		entity.RecurrenceWeekly, nil, time.Now().Add(time.Hour), nil, nil)
+	assert.NoError(t, err)
+
+	occurrence := *mandate.NextRunAt
+	retryAt := time.Now().Add(time.Hour)
+	mandate.DeferRun(retryAt)
+	assert.Equal(t, occurrence, *mandate.NextRunAt)
+	assert.Equal(t, retryAt, *mandate.RetryAt)
+
+	// A ocorrência gerada na nova tentativa libera o mandato
+	assert.NoError(t, mandate.RecordRun(occurrence))
+	assert.Nil(t, mandate.RetryAt)
+	assert.Equal(t, occurrence.Add(7*24*time.Hour), *mandate.NextRunAt)
+}
+
+func TestMandatePauseAndResume(t *testing.T) {
+	mandate, err := entity.NewRecurringMandate("payer", "payee", decimal.NewFromInt(30), "",
+		entity.RecurrenceMonthly, nil, time.Now().Add(-40*24*time.Hour), nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, mandate.NextRunAt.After(time.Now()), "ocorrências anteriores à criação não são cobradas")
+
+	assert.NoError(t, mandate.Pause())
+	assert.ErrorIs(t, mandate.Pause(), entity.ErrMandateNotActive)
+
+	assert.NoError(t, mandate.Resume())
+	assert.True(t, mandate.IsActive())
+	assert.ErrorIs(t, mandate.Resume(), entity.ErrMandateNotPaused)
+
+	assert.NoError(t, mandate.Cancel())
+	assert.Nil(t, mandate.NextRunAt)
+}
+
+func TestMandateRequiresValidCronExpression(t *testing.T) {
+	expr := "0 25 * * *"
+	_, err := entity.NewRecurringMandate("payer", "payee", decimal.NewFromInt(30), "",
+		entity.RecurrenceCron, &expr, time.Now(), nil, nil)
+	assert.ErrorIs(t, err, entity.ErrInvalidRecurrence)
+}
@@ -0,0 +1,111 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pendingReview(t *testing.T, now time.Time) *entity.RiskReview {
+	transaction := riskTransaction(t, "2000.00")
+	assessment := &entity.RiskAssessment{
+		Decision: entity.RiskDecisionReview,
+		Reasons: []entity.RiskReason{{
+			Rule:     entity.RiskRuleNewPayee,
+			Decision: entity.RiskDecisionReview,
+			Message:  "primeira transferência para o recebedor acima de R$ 1000.00",
+		}},
+	}
+	return entity.NewRiskReview(transaction, assessment, 24*time.Hour, now)
+}
+
+func reviewActions(review *entity.RiskReview) []entity.RiskReviewAction {
+	var actions []entity.RiskReviewAction
+	for _, event := range review.Events {
+		actions = append(actions, event.Action)
+	}
+	return actions
+}
+
+func TestRiskReviewClaimAndApprove(t *testing.T) {
+	now := time.Now()
+	review := pendingReview(t, now)
+
+	require.NoError(t, review.Claim("ana", now))
+	assert.Equal(t, entity.RiskReviewStatusClaimed, review.Status)
+	assert.Equal(t, "ana", *review.ClaimedBy)
+
+	// Assumir de novo não gera ação
+	require.NoError(t, review.Claim("ana", now))
+
+	require.NoError(t, review.Approve("ana", "cliente confirmou por telefone", now))
+	assert.Equal(t, entity.RiskReviewStatusApproved, review.Status)
+	assert.Equal(t, "ana", *review.DecidedBy)
+	assert.Equal(t, "cliente confirmou por telefone", *review.DecisionNote)
+	assert.Equal(t, []entity.RiskReviewAction{
+		entity.RiskReviewActionCreated,
+		entity.RiskReviewActionClaimed,
+		entity.RiskReviewActionApproved,
+	}, reviewActions(review))
+
+	assert.ErrorIs(t, review.Reject("ana", "", now), entity.ErrRiskReviewNotOpen)
+}
+
+func TestRiskReviewDecisionRequiresClaim(t *testing.T) {
+	now := time.Now()
+	review := pendingReview(t, now)
+
+	assert.ErrorIs(t, review.Reject("ana", "", now), entity.ErrRiskReviewNotClaimed)
+
+	require.NoError(t, review.Claim("ana", now))
+	assert.ErrorIs(t, review.Claim("bruno", now), entity.ErrRiskReviewClaimedByOther)
+	assert.ErrorIs(t, review.Approve("bruno", "", now), entity.ErrRiskReviewClaimedByOther)
+	assert.ErrorIs(t, review.Claim(" ", now), entity.ErrRiskReviewerRequired)
+
+	require.NoError(t, review.Reject("ana", "", now))
+	assert.Equal(t, entity.RiskReviewStatusRejected, review.Status)
+	assert.Nil(t, review.DecisionNote)
+}
+
+func TestRiskReviewExpire(t *testing.T) {
+	now := time.Now()
+	review := pendingReview(t, now)
+
+	assert.False(t, review.Expire(now.Add(time.Hour)))
+
+	require.NoError(t, review.Claim("ana", now))
+	assert.ErrorIs(t, review.Approve("ana", "", now.Add(25*time.Hour)), entity.ErrRiskReviewExpired)
+
+	assert.True(t, review.Expire(now.Add(25*time.Hour)))
+	assert.Equal(t, entity.RiskReviewStatusExpired, review.Status)
+	assert.Equal(t, entity.RiskReviewSystemActor, review.LastEvent().Actor)
+	assert.False(t, review.Expire(now.Add(26*time.Hour)))
+}
+
+func TestDeniedRiskReviewIsClosed(t *testing.T) {
+	now := time.Now()
+	transaction := riskTransaction(t, "10.00")
+	assessment := &entity.RiskAssessment{Decision: entity.RiskDecisionDeny, Reasons: []entity.RiskReason{}}
+
+	review := entity.NewRiskReview(transaction, assessment, 24*time.Hour, now)
+
+	assert.False(t, review.IsOpen())
+	assert.ErrorIs(t, review.Claim("ana", now), entity.ErrRiskReviewNotOpen)
+	assert.False(t, review.Expire(now.Add(48*time.Hour)))
+}
+
+func TestTransactionResumeFromReview(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer", "payee", decimal.NewFromInt(100))
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, transaction.ResumeFromReview(), entity.ErrTransactionNotUnderReview)
+
+	require.NoError(t, transaction.HoldForReview("retida"))
+	require.NoError(t, transaction.ResumeFromReview())
+	assert.True(t, transaction.IsPending())
+	assert.Nil(t, transaction.FailureReason)
+}
@@ -0,0 +1,173 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var riskPolicy = entity.RiskPolicy{
+	VelocityMaxPerMinute: 5,
+	VelocityAction:       entity.RiskDecisionDeny,
+	NewPayeeThreshold:    decimal.NewFromInt(1000),
+	NewPayeeAction:       entity.RiskDecisionReview,
+	AnomalyMultiplier:    decimal.NewFromInt(5),
+	AnomalyMinHistory:    3,
+	AnomalyAction:        entity.RiskDecisionReview,
+	NewAccountAge:        7 * 24 * time.Hour,
+	NewAccountThreshold:  decimal.NewFromInt(500),
+	NewAccountAction:     entity.RiskDecisionReview,
+	ReviewTTL:            24 * time.Hour,
+}
+
+func riskTransaction(t *testing.T, amount string) *entity.Transaction {
+	transaction, err := entity.NewTransaction("payer", "payee", decimal.RequireFromString(amount))
+	require.NoError(t, err)
+	return transaction
+}
+
+func trustedProfile(now time.Time) *entity.RiskProfile {
+	return &entity.RiskProfile{
+		AccountCreatedAt: now.Add(-365 * 24 * time.Hour),
+		KnownPayee:       true,
+		CompletedCount:   10,
+		AverageAmount:    decimal.NewFromInt(200),
+	}
+}
+
+func riskRules(assessment *entity.RiskAssessment) []entity.RiskRule {
+	var rules []entity.RiskRule
+	for _, reason := range assessment.Reasons {
+		rules = append(rules, reason.Rule)
+	}
+	return rules
+}
+
+func TestRiskAssessAllowsUsualTransfer(t *testing.T) {
+	now := time.Now()
+
+	assessment := riskPolicy.Assess(riskTransaction(t, "150.00"), trustedProfile(now), now)
+
+	assert.True(t, assessment.IsAllowed())
+	assert.Empty(t, assessment.Reasons)
+}
+
+func TestRiskAssessVelocityDenies(t *testing.T) {
+	now := time.Now()
+	profile := trustedProfile(now)
+	profile.RecentTransfers = 5
+
+	assessment := riskPolicy.Assess(riskTransaction(t, "10.00"), profile, now)
+
+	assert.Equal(t, entity.RiskDecisionDeny, assessment.Decision)
+	assert.Equal(t, []entity.RiskRule{entity.RiskRuleVelocity}, riskRules(assessment))
+}
+
+func TestRiskAssessNewPayeeAboveThreshold(t *testing.T) {
+	now := time.Now()
+	profile := trustedProfile(now)
+	profile.KnownPayee = false
+	profile.AverageAmount = decimal.NewFromInt(800)
+
+	assert.True(t, riskPolicy.Assess(riskTransaction(t, "1000.00"), profile, now).IsAllowed())
+
+	assessment := riskPolicy.Assess(riskTransaction(t, "1000.01"), profile, now)
+	assert.Equal(t, entity.RiskDecisionReview, assessment.Decision)
+	assert.Equal(t, []entity.RiskRule{entity.RiskRuleNewPayee}, riskRules(assessment))
+}
+
+func TestRiskAssessAmountAnomaly(t *testing.T) {
+	now := time.Now()
+	profile := trustedProfile(now)
+
+	assessment := riskPolicy.Assess(riskTransaction(t, "1000.01"), profile, now)
+	assert.Equal(t, []entity.RiskRule{entity.RiskRuleAmountAnomaly}, riskRules(assessment))
+
+	// Sem histórico suficiente a regra não se aplica
+	profile.CompletedCount = 2
+	assert.True(t, riskPolicy.Assess(riskTransaction(t, "1000.01"), profile, now).IsAllowed())
+}
+
+func TestRiskAssessNewAccount(t *testing.T) {
+	now := time.Now()
+	profile := trustedProfile(now)
+	profile.AccountCreatedAt = now.Add(-2 * 24 * time.Hour)
+
+	assert.True(t, riskPolicy.Assess(riskTransaction(t, "500.00"), profile, now).IsAllowed())
+
+	assessment := riskPolicy.Assess(riskTransaction(t, "600.00"), profile, now)
+	assert.Equal(t, []entity.RiskRule{entity.RiskRuleNewAccount}, riskRules(assessment))
+}
+
+func TestRiskAssessMostSevereDecisionWins(t *testing.T) {
+	now := time.Now()
+	profile := trustedProfile(now)
+	profile.KnownPayee = false
+	profile.RecentTransfers = 10
+
+	assessment := riskPolicy.Assess(riskTransaction(t, "5000.00"), profile, now)
+
+	assert.Equal(t, entity.RiskDecisionDeny, assessment.Decision)
+	assert.Len(t, assessment.Reasons, 3)
+	assert.Contains(t, assessment.Summary(), "último minuto")
+}
+
+func TestRiskPolicyZeroThresholdDisablesRule(t *testing.T) {
+	now := time.Now()
+	policy := riskPolicy
+	policy.VelocityMaxPerMinute = 0
+	profile := trustedProfile(now)
+	profile.RecentTransfers = 100
+
+	assert.True(t, policy.Assess(riskTransaction(t, "10.00"), profile, now).IsAllowed())
+}
+
+func TestRiskPolicyValidate(t *testing.T) {
+	assert.NoError(t, riskPolicy.Validate())
+
+	policy := riskPolicy
+	policy.NewPayeeAction = entity.RiskDecisionAllow
+	assert.Error(t, policy.Validate())
+}
+
+func TestEscalateToDeny(t *testing.T) {
+	now := time.Now()
+	profile := trustedProfile(now)
+	profile.KnownPayee = false
+
+	assessment := riskPolicy.Assess(riskTransaction(t, "2000.00"), profile, now)
+	assessment.EscalateToDeny()
+
+	assert.Equal(t, entity.RiskDecisionDeny, assessment.Decision)
+}
+
+func TestNewRiskReview(t *testing.T) {
+	now := time.Now()
+	transaction := riskTransaction(t, "2000.00")
+	profile := trustedProfile(now)
+	profile.KnownPayee = false
+
+	review := entity.NewRiskReview(transaction, riskPolicy.Assess(transaction, profile, now), riskPolicy.ReviewTTL, now)
+	assert.Equal(t, entity.RiskReviewStatusPending, review.Status)
+	require.NotNil(t, review.DueAt)
+	assert.Equal(t, now.Add(24*time.Hour), *review.DueAt)
+
+	profile.RecentTransfers = 10
+	denied := entity.NewRiskReview(transaction, riskPolicy.Assess(transaction, profile, now), riskPolicy.ReviewTTL, now)
+	assert.Equal(t, entity.RiskReviewStatusDenied, denied.Status)
+	assert.Nil(t, denied.DueAt)
+}
+
+func TestTransactionHoldForReview(t *testing.T) {
+	transaction := riskTransaction(t, "2000.00")
+
+	require.NoError(t, transaction.HoldForReview("retida"))
+	assert.True(t, transaction.IsUnderReview())
+	assert.Equal(t, "retida", *transaction.FailureReason)
+
+	assert.ErrorIs(t, transaction.HoldForReview("retida"), entity.ErrTransactionNotPending)
+}
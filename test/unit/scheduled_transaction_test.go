@@ -0,0 +1,54 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateScheduledTransaction(t *testing.T) {
+	scheduledFor := time.Now().Add(48 * time.Hour)
+	req := &entity.CreateTransactionRequest{
+		PayeeID:      "payee",
+		Amount:       decimal.NewFromInt(50),
+		ScheduledFor: &scheduledFor,
+	}
+
+	transaction, err := entity.FromCreateTransactionRequest(req, "payer")
+
+	assert.NoError(t, err)
+	assert.True(t, transaction.IsScheduled())
+	assert.Equal(t, "Agendada", transaction.GetStatusDescription())
+	assert.Equal(t, scheduledFor, transaction.EffectiveDate())
+	assert.False(t, transaction.CanBeAuthorized())
+}
+
+func TestScheduleRejectsInvalidDates(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer", "payee", decimal.NewFromInt(50))
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, transaction.Schedule(time.Now().Add(-time.Minute)), entity.ErrScheduleInPast)
+	assert.ErrorIs(t, transaction.Schedule(time.Now().Add(entity.MaxScheduleAhead+time.Hour)), entity.ErrScheduleTooFar)
+	assert.True(t, transaction.IsPending())
+}
+
+func TestCancelAndActivateScheduledTransaction(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer", "payee", decimal.NewFromInt(50))
+	assert.NoError(t, err)
+	assert.ErrorIs(t, transaction.Cancel("motivo"), entity.ErrTransactionNotScheduled)
+
+	assert.NoError(t, transaction.Schedule(time.Now().Add(time.Hour)))
+	assert.NoError(t, transaction.Cancel("desisti"))
+	assert.True(t, transaction.IsCancelled())
+	assert.Equal(t, "desisti", *transaction.FailureReason)
+	assert.ErrorIs(t, transaction.Activate(), entity.ErrTransactionNotScheduled)
+
+	other, err := entity.NewTransaction("payer", "payee", decimal.NewFromInt(50))
+	assert.NoError(t, err)
+	assert.NoError(t, other.Schedule(time.Now().Add(time.Hour)))
+	assert.NoError(t, other.Activate())
+	assert.True(t, other.CanBeAuthorized())
+}
@@ -0,0 +1,169 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func payeeLeg(id string, splitType entity.SplitType, value string) entity.SplitLegSpec {
+	return entity.SplitLegSpec{PayeeID: &id, SplitType: splitType, Value: decimal.RequireFromString(value)}
+}
+
+func platformLeg(splitType entity.SplitType, value string) entity.SplitLegSpec {
+	return entity.SplitLegSpec{SplitType: splitType, Value: decimal.RequireFromString(value)}
+}
+
+func legAmounts(split *entity.SplitPayment) []string {
+	var amounts []string
+	for _, leg := range split.Legs {
+		amounts = append(amounts, leg.Amount.StringFixed(2))
+	}
+	return amounts
+}
+
+func TestAllocateCentsKeepsTotalExact(t *testing.T) {
+	weights := []decimal.Decimal{decimal.NewFromInt(1), decimal.NewFromInt(1), decimal.NewFromInt(1)}
+
+	shares := entity.AllocateCents(decimal.NewFromInt(100), weights)
+
+	assert.Equal(t, "33.34", shares[0].StringFixed(2))
+	assert.Equal(t, "33.33", shares[1].StringFixed(2))
+	assert.Equal(t, "33.33", shares[2].StringFixed(2))
+}
+
+func TestAllocateCentsLargestRemainder(t *testing.T) {
+	weights := []decimal.Decimal{
+		decimal.RequireFromString("33.3"),
+		decimal.RequireFromString("33.3"),
+		decimal.RequireFromString("33.4"),
+	}
+
+	shares := entity.AllocateCents(decimal.RequireFromString("0.10"), weights)
+
+	total := decimal.Zero
+	for _, share := range shares {
+		total = total.Add(share)
+	}
+	assert.Equal(t, "0.10", total.StringFixed(2))
+	assert.Equal(t, "0.04", shares[2].StringFixed(2))
+}
+
+func TestNewSplitPaymentPercentages(t *testing.T) {
+	split, err := entity.NewSplitPayment("payer", decimal.NewFromInt(100), "Pedido", []entity.SplitLegSpec{
+		payeeLeg("loja-a", entity.SplitTypePercentage, "33.3333"),
+		payeeLeg("loja-b", entity.SplitTypePercentage, "33.3333"),
+		platformLeg(entity.SplitTypePercentage, "33.3334"),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"33.33", "33.33", "33.34"}, legAmounts(split))
+	assert.Equal(t, "33.34", split.PlatformAmount().StringFixed(2))
+	assert.Len(t, split.UserLegs(), 2)
+}
+
+func TestNewSplitPaymentFixedAndPercentage(t *testing.T) {
+	split, err := entity.NewSplitPayment("payer", decimal.RequireFromString("99.99"), "", []entity.SplitLegSpec{
+		platformLeg(entity.SplitTypeFixed, "4.99"),
+		payeeLeg("loja-a", entity.SplitTypePercentage, "50"),
+		payeeLeg("loja-b", entity.SplitTypePercentage, "50"),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"4.99", "47.50", "47.50"}, legAmounts(split))
+}
+
+func TestNewSplitPaymentValidation(t *testing.T) {
+	amount := decimal.NewFromInt(100)
+
+	_, err := entity.NewSplitPayment("payer", amount, "", []entity.SplitLegSpec{
+		payeeLeg("loja-a", entity.SplitTypePercentage, "60"),
+		payeeLeg("loja-b", entity.SplitTypePercentage, "30"),
+	})
+	assert.Error(t, err, "percentuais precisam somar 100")
+
+	_, err = entity.NewSplitPayment("payer", amount, "", []entity.SplitLegSpec{
+		payeeLeg("loja-a", entity.SplitTypeFixed, "60"),
+		payeeLeg("loja-b", entity.SplitTypeFixed, "30"),
+	})
+	assert.Error(t, err, "partes fixas precisam somar o total")
+
+	_, err = entity.NewSplitPayment("payer", amount, "", []entity.SplitLegSpec{
+		payeeLeg("loja-a", entity.SplitTypeFixed, "50"),
+		payeeLeg("loja-a", entity.SplitTypeFixed, "50"),
+	})
+	assert.Error(t, err, "recebedor repetido")
+
+	_, err = entity.NewSplitPayment("payer", amount, "", []entity.SplitLegSpec{
+		platformLeg(entity.SplitTypeFixed, "50"),
+		platformLeg(entity.SplitTypeFixed, "50"),
+	})
+	assert.Error(t, err, "sem recebedores")
+
+	_, err = entity.NewSplitPayment("payer", amount, "", []entity.SplitLegSpec{
+		payeeLeg("payer", entity.SplitTypeFixed, "50"),
+		payeeLeg("loja-b", entity.SplitTypeFixed, "50"),
+	})
+	assert.ErrorIs(t, err, entity.ErrSelfTransfer)
+
+	_, err = entity.NewSplitPayment("payer", amount, "", []entity.SplitLegSpec{
+		payeeLeg("loja-a", "quota", "50"),
+		payeeLeg("loja-b", entity.SplitTypeFixed, "50"),
+	})
+	assert.ErrorIs(t, err, entity.ErrInvalidSplitType)
+}
+
+func TestSplitPaymentProportionalRefund(t *testing.T) {
+	split, err := entity.NewSplitPayment("payer", decimal.NewFromInt(100), "", []entity.SplitLegSpec{
+		payeeLeg("loja-a", entity.SplitTypeFixed, "70"),
+		payeeLeg("loja-b", entity.SplitTypeFixed, "20"),
+		platformLeg(entity.SplitTypeFixed, "10"),
+	})
+	assert.NoError(t, err)
+
+	_, err = split.Refund(decimal.NewFromInt(10), "")
+	assert.ErrorIs(t, err, entity.ErrSplitPaymentNotRefundable)
+
+	split.Complete()
+
+	refund, err := split.Refund(decimal.RequireFromString("33.33"), "devolução parcial")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SplitPaymentStatusPartiallyRefunded, split.Status)
+	assert.Equal(t, "23.33", refund.Legs[0].Amount.StringFixed(2))
+	assert.Equal(t, "6.67", refund.Legs[1].Amount.StringFixed(2))
+	assert.Equal(t, "3.33", refund.Legs[2].Amount.StringFixed(2))
+
+	_, err = split.Refund(decimal.NewFromInt(70), "")
+	assert.ErrorIs(t, err, entity.ErrRefundExceedsAmount)
+
+	_, err = split.Refund(split.RefundableAmount(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.SplitPaymentStatusRefunded, split.Status)
+	for _, leg := range split.Legs {
+		assert.True(t, leg.IsFullyRefunded())
+	}
+}
+
+func TestSplitLegRefundFeeAddsUpToTransactionFee(t *testing.T) {
+	split, err := entity.NewSplitPayment("payer", decimal.NewFromInt(100), "", []entity.SplitLegSpec{
+		payeeLeg("loja-a", entity.SplitTypeFixed, "70"),
+		payeeLeg("loja-b", entity.SplitTypeFixed, "30"),
+	})
+	require.NoError(t, err)
+	split.Complete()
+	fee := decimal.RequireFromString("2.33")
+
+	// Estornos parciais devolvem a tarifa proporcional; ao fim a tarifa inteira volta, sem sobra de arredondamento
+	returned := decimal.Zero
+	for _, amount := range []string{"33.33", "33.33", "33.34"} {
+		refund, err := split.Refund(decimal.RequireFromString(amount), "")
+		require.NoError(t, err)
+		leg := split.Leg(refund.Legs[0].LegID)
+		returned = returned.Add(leg.RefundFee(fee, refund.Legs[0].Amount))
+	}
+	assert.True(t, split.Legs[0].IsFullyRefunded())
+	assert.Equal(t, "2.33", returned.StringFixed(2))
+}
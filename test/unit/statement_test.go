@@ -0,0 +1,182 @@
+package entity_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"payflow-api/internal/entity"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statementFixture() *entity.Statement {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	payee := "user-2"
+
+	movements := []*entity.StatementMovement{
+		{ID: "tx-1:sent", Kind: entity.StatementTransferSent, ReferenceID: "tx-1", CounterpartyID: &payee,
+			OccurredAt: from.Add(time.Hour), Amount: decimal.RequireFromString("-30.00")},
+		{ID: "tx-2:received", Kind: entity.StatementTransferReceived, ReferenceID: "tx-2",
+			OccurredAt: from.Add(48 * time.Hour), Amount: decimal.RequireFromString("12.50")},
+		{ID: "tx-1:reversed", Kind: entity.StatementTransferReversed, ReferenceID: "tx-1", CounterpartyID: &payee,
+			OccurredAt: from.Add(72 * time.Hour), Amount: decimal.RequireFromString("30.00")},
+	}
+
+	user := &entity.User{ID: "user-1", FullName: "Maria Conceição"}
+	return entity.NewStatement(user, from, to, decimal.RequireFromString("112.50"), movements, to)
+}
+
+func TestNewStatement_RunningBalance(t *testing.T) {
+	statement := statementFixture()
+
+	assert.Equal(t, "100.00", statement.OpeningBalance.StringFixed(2))
+	assert.Equal(t, "112.50", statement.ClosingBalance.StringFixed(2))
+	require.Len(t, statement.Entries, 3)
+	assert.Equal(t, "70.00", statement.Entries[0].Balance.StringFixed(2))
+	assert.Equal(t, "82.50", statement.Entries[1].Balance.StringFixed(2))
+	assert.Equal(t, "112.50", statement.Entries[2].Balance.StringFixed(2))
+	assert.Equal(t, "42.50", statement.TotalCredits().StringFixed(2))
+	assert.Equal(t, "30.00", statement.TotalDebits().StringFixed(2))
+}
+
+func TestNewStatement_WithoutMovements(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	statement := entity.NewStatement(&entity.User{ID: "user-1"}, from, from.AddDate(0, 1, 0), decimal.NewFromInt(50), nil, from)
+
+	assert.True(t, statement.OpeningBalance.Equal(statement.ClosingBalance))
+	assert.Empty(t, statement.ToStatementResponse().Entries)
+}
+
+func TestStatementResponse_FormatsAmounts(t *testing.T) {
+	response := statementFixture().ToStatementResponse()
+
+	assert.Equal(t, "R$ 100.00", response.OpeningBalance)
+	assert.Equal(t, "R$ 112.50", response.ClosingBalance)
+	assert.Equal(t, "R$ -30.00", response.Entries[0].Amount)
+	assert.Equal(t, "debit", response.Entries[0].Type)
+	assert.Equal(t, "credit", response.Entries[1].Type)
+	assert.Equal(t, "Transferência enviada", response.Entries[0].Description)
+
+	tx := &entity.Transaction{Amount: decimal.RequireFromString("30")}
+	assert.Equal(t, tx.GetAmountFormatted(), response.Entries[2].Amount)
+}
+
+func TestParseStatementPeriod(t *testing.T) {
+	now := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	from, to, err := entity.ParseStatementPeriod("", "", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, now, to)
+
+	// Data em "to" inclui o dia inteiro
+	from, to, err = entity.ParseStatementPeriod("2024-02-01", "2024-02-29", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), to)
+
+	// O fim nunca passa de agora
+	_, to, err = entity.ParseStatementPeriod("2024-03-01", "2024-12-31", now)
+	require.NoError(t, err)
+	assert.Equal(t, now, to)
+
+	_, _, err = entity.ParseStatementPeriod("2024-03-10", "2024-03-01", now)
+	assert.ErrorIs(t, err, entity.ErrInvalidStatementPeriod)
+
+	_, _, err = entity.ParseStatementPeriod("01/03/2024", "", now)
+	assert.ErrorIs(t, err, entity.ErrInvalidStatementPeriod)
+
+	_, _, err = entity.ParseStatementPeriod("2022-01-01", "2024-03-01", now)
+	assert.ErrorIs(t, err, entity.ErrStatementPeriodTooLong)
+}
+
+func TestParseStatementFormat(t *testing.T) {
+	format, err := entity.ParseStatementFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatementFormatJSON, format)
+
+	format, err = entity.ParseStatementFormat("OFX")
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatementFormatOFX, format)
+
+	_, err = entity.ParseStatementFormat("xlsx")
+	assert.ErrorIs(t, err, entity.ErrInvalidStatementFormat)
+}
+
+func TestStatementCSV(t *testing.T) {
+	content, err := statementFixture().Render(entity.StatementFormatCSV)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 6)
+	assert.Equal(t, "Saldo inicial", rows[1][3])
+	assert.Equal(t, "R$ 100.00", rows[1][7])
+	assert.Equal(t, "tx-1:sent", rows[2][1])
+	assert.Equal(t, "R$ -30.00", rows[2][6])
+	assert.Equal(t, "R$ 70.00", rows[2][7])
+	assert.Equal(t, "Saldo final", rows[5][3])
+	assert.Equal(t, "R$ 112.50", rows[5][7])
+}
+
+func TestStatementOFX(t *testing.T) {
+	content, err := statementFixture().Render(entity.StatementFormatOFX)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(string(content), "<?xml"))
+	assert.Contains(t, string(content), `<?OFX OFXHEADER="200" VERSION="220"`)
+
+	var doc struct {
+		Transactions []struct {
+			TrnType string `xml:"TRNTYPE"`
+			TrnAmt  string `xml:"TRNAMT"`
+			FITID   string `xml:"FITID"`
+		} `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKTRANLIST>STMTTRN"`
+		AcctID  string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKACCTFROM>ACCTID"`
+		Balance string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>LEDGERBAL>BALAMT"`
+		DTStart string `xml:"BANKMSGSRSV1>STMTTRNRS>STMTRS>BANKTRANLIST>DTSTART"`
+	}
+	require.NoError(t, xml.Unmarshal(content, &doc))
+
+	require.Len(t, doc.Transactions, 3)
+	assert.Equal(t, "DEBIT", doc.Transactions[0].TrnType)
+	assert.Equal(t, "-30.00", doc.Transactions[0].TrnAmt)
+	assert.Equal(t, "CREDIT", doc.Transactions[1].TrnType)
+	assert.Equal(t, "tx-2:received", doc.Transactions[1].FITID)
+	assert.Equal(t, "112.50", doc.Balance)
+	assert.Equal(t, "20240301000000.000[0:GMT]", doc.DTStart)
+	assert.LessOrEqual(t, len(doc.AcctID), 22)
+}
+
+func TestStatementPDF(t *testing.T) {
+	content, err := statementFixture().Render(entity.StatementFormatPDF)
+	require.NoError(t, err)
+
+	assert.True(t, bytes.HasPrefix(content, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(content, []byte("%%EOF\n")))
+	// Acentos em WinAnsi, não em UTF-8
+	assert.Contains(t, string(content), "Concei\xe7\xe3o")
+	assert.Contains(t, string(content), "(R$ -30.00)")
+	assert.Contains(t, string(content), "/Count 1")
+}
+
+func TestStatementPDF_Paginates(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	var movements []*entity.StatementMovement
+	for i := 0; i < 120; i++ {
+		movements = append(movements, &entity.StatementMovement{
+			ID: "m", Kind: entity.StatementTransferReceived, OccurredAt: from.Add(time.Duration(i) * time.Minute), Amount: decimal.NewFromInt(1),
+		})
+	}
+
+	statement := entity.NewStatement(&entity.User{ID: "user-1"}, from, from.AddDate(0, 1, 0), decimal.NewFromInt(120), movements, from)
+	content := statement.PDF()
+
+	assert.Contains(t, string(content), "/Count 3")
+}
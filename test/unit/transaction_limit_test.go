@@ -0,0 +1,94 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func defaultLimits() entity.TransactionLimits {
+	return entity.TransactionLimits{
+		PerTransaction: decimal.NewFromInt(5000),
+		Daily:          decimal.NewFromInt(8000),
+		Monthly:        decimal.NewFromInt(20000),
+		Nightly:        decimal.NewFromInt(1000),
+	}
+}
+
+func zeroUsage() entity.LimitUsage {
+	return entity.LimitUsage{Daily: decimal.Zero, Monthly: decimal.Zero, Nightly: decimal.Zero}
+}
+
+func TestLimitsCheck(t *testing.T) {
+	limits := defaultLimits()
+
+	tests := []struct {
+		name   string
+		amount int64
+		usage  entity.LimitUsage
+		night  bool
+		ok     bool
+	}{
+		{"Dentro dos limites", 1000, zeroUsage(), false, true},
+		{"Excede por transação", 6000, zeroUsage(), false, false},
+		{"Excede diário", 3000, entity.LimitUsage{Daily: decimal.NewFromInt(6000), Monthly: decimal.NewFromInt(6000), Nightly: decimal.Zero}, false, false},
+		{"Excede mensal", 3000, entity.LimitUsage{Daily: decimal.Zero, Monthly: decimal.NewFromInt(18000), Nightly: decimal.Zero}, false, false},
+		{"Excede noturno à noite", 1500, zeroUsage(), true, false},
+		{"Noturno ignorado de dia", 1500, zeroUsage(), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limits.Check(decimal.NewFromInt(tt.amount), tt.usage, tt.night)
+			if tt.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, entity.ErrAmountExceedsLimit)
+			}
+		})
+	}
+}
+
+func TestLimitsApplyOverride(t *testing.T) {
+	daily := decimal.NewFromInt(500)
+	override := &entity.UserLimitOverride{UserID: "user", Daily: &daily}
+
+	effective := defaultLimits().Apply(override)
+
+	assert.True(t, effective.Daily.Equal(daily))
+	assert.True(t, effective.PerTransaction.Equal(decimal.NewFromInt(5000)))
+	assert.True(t, defaultLimits().Apply(nil).Daily.Equal(decimal.NewFromInt(8000)))
+}
+
+func TestLimitsValidate(t *testing.T) {
+	limits := defaultLimits()
+	limits.Nightly = decimal.NewFromInt(9000)
+
+	assert.Error(t, limits.Validate())
+	assert.NoError(t, defaultLimits().Validate())
+}
+
+func TestLimitPolicyNightWindow(t *testing.T) {
+	location := time.FixedZone("BRT", -3*60*60)
+	policy := entity.LimitPolicy{Location: location, NightStartHour: 20, NightEndHour: 6}
+
+	afternoon := time.Date(2024, 3, 10, 15, 0, 0, 0, location)
+	evening := time.Date(2024, 3, 10, 22, 0, 0, 0, location)
+	earlyMorning := time.Date(2024, 3, 11, 2, 0, 0, 0, location)
+
+	assert.False(t, policy.IsNight(afternoon))
+	assert.True(t, policy.IsNight(evening))
+	assert.True(t, policy.IsNight(earlyMorning))
+
+	_, hasNight := policy.PeriodKeys(afternoon)[entity.LimitPeriodNightly]
+	assert.False(t, hasNight)
+
+	// A madrugada pertence à mesma noite que começou no dia anterior
+	assert.Equal(t, "2024-03-10", policy.PeriodKeys(evening)[entity.LimitPeriodNightly])
+	assert.Equal(t, "2024-03-10", policy.PeriodKeys(earlyMorning)[entity.LimitPeriodNightly])
+	assert.Equal(t, "2024-03-11", policy.PeriodKeys(earlyMorning)[entity.LimitPeriodDaily])
+	assert.Equal(t, "2024-03", policy.PeriodKeys(earlyMorning)[entity.LimitPeriodMonthly])
+}
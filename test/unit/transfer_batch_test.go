@@ -0,0 +1,107 @@
+package entity_test
+
+import (
+	"errors"
+	"payflow-api/internal/entity"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransferBatchDefaultsToBestEffort(t *testing.T) {
+	batch, err := entity.NewTransferBatch("payer", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.BatchModeBestEffort, batch.Mode)
+	assert.Equal(t, entity.BatchStatusPending, batch.Status)
+
+	_, err = entity.NewTransferBatch("payer", "parcial")
+	assert.ErrorIs(t, err, entity.ErrInvalidBatchMode)
+}
+
+func TestTransferBatchAddItem(t *testing.T) {
+	batch, err := entity.NewTransferBatch("payer", entity.BatchModeAllOrNothing)
+	assert.NoError(t, err)
+
+	assert.NoError(t, batch.AddItem(1, "payee-1", nil, decimal.NewFromFloat(10.5)))
+	assert.NoError(t, batch.AddItem(2, "payee-2", nil, decimal.NewFromInt(20)))
+	assert.Error(t, batch.AddItem(3, "payee-3", nil, decimal.Zero))
+	assert.Error(t, batch.AddItem(4, "payee-4", nil, decimal.RequireFromString("1.001")))
+	assert.ErrorIs(t, batch.AddItem(5, "payer", nil, decimal.NewFromInt(1)), entity.ErrSelfTransfer)
+
+	assert.Equal(t, 2, batch.TotalItems)
+	assert.Equal(t, "30.50", batch.TotalAmount.StringFixed(2))
+}
+
+func TestTransferBatchFinish(t *testing.T) {
+	batch, err := entity.NewTransferBatch("payer", entity.BatchModeBestEffort)
+	assert.NoError(t, err)
+	assert.NoError(t, batch.AddItem(1, "payee-1", nil, decimal.NewFromInt(10)))
+	assert.NoError(t, batch.AddItem(2, "payee-2", nil, decimal.NewFromInt(10)))
+
+	assert.NoError(t, batch.Start())
+	assert.ErrorIs(t, batch.Start(), entity.ErrBatchNotPending)
+
+	batch.Items[0].Complete("tx-1")
+	batch.Items[1].Fail(nil, "saldo insuficiente")
+	batch.Finish()
+
+	assert.Equal(t, entity.BatchStatusPartiallyCompleted, batch.Status)
+	assert.Equal(t, 1, batch.SucceededCount)
+	assert.Equal(t, 1, batch.FailedCount)
+	assert.NotNil(t, batch.CompletedAt)
+
+	batch.Items[1].Complete("tx-2")
+	batch.Finish()
+	assert.Equal(t, entity.BatchStatusCompleted, batch.Status)
+}
+
+func TestTransferBatchAbortKeepsCompletedItems(t *testing.T) {
+	batch, err := entity.NewTransferBatch("payer", entity.BatchModeBestEffort)
+	assert.NoError(t, err)
+	assert.NoError(t, batch.AddItem(1, "payee-1", nil, decimal.NewFromInt(10)))
+	assert.NoError(t, batch.AddItem(2, "payee-2", nil, decimal.NewFromInt(10)))
+	assert.NoError(t, batch.AddItem(3, "payee-3", nil, decimal.NewFromInt(10)))
+
+	// Num lote retomado só os itens sem resultado voltam a ser executados
+	batch.Items[0].Complete("tx-1")
+	pending := batch.PendingItems()
+	assert.Len(t, pending, 2)
+	assert.Equal(t, 2, pending[0].Position)
+
+	batch.Abort("processamento do lote interrompido")
+	assert.Empty(t, batch.PendingItems())
+	assert.Equal(t, entity.BatchItemStatusCompleted, batch.Items[0].Status)
+	assert.Equal(t, "processamento do lote interrompido", *batch.Items[2].FailureReason)
+	assert.Equal(t, entity.BatchStatusPartiallyCompleted, batch.Status)
+	assert.Equal(t, 2, batch.FailedCount)
+}
+
+func TestParseBatchCSV(t *testing.T) {
+	csv := "amount,payee_key,payee_id\n10.00,joao@example.com,\n5.5,,payee-2\n"
+
+	items, err := entity.ParseBatchCSV(strings.NewReader(csv))
+
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, "joao@example.com", items[0].PayeeKey)
+	assert.Equal(t, "payee-2", items[1].PayeeID)
+	assert.Equal(t, "5.50", items[1].Amount.StringFixed(2))
+}
+
+func TestParseBatchCSVReportsInvalidRows(t *testing.T) {
+	csv := "payee_id,amount\npayee-1,10\npayee-2,dez\npayee-3,abc\n"
+
+	_, err := entity.ParseBatchCSV(strings.NewReader(csv))
+
+	var validation *entity.BatchValidationError
+	assert.True(t, errors.As(err, &validation))
+	assert.ErrorIs(t, err, entity.ErrInvalidBatch)
+	assert.Len(t, validation.Items, 2)
+	assert.Equal(t, 2, validation.Items[0].Position)
+
+	_, err = entity.ParseBatchCSV(strings.NewReader("payee_id\npayee-1\n"))
+	assert.ErrorIs(t, err, entity.ErrInvalidBatch)
+}
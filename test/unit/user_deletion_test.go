@@ -0,0 +1,97 @@
+package entity_test
+
+import (
+	"payflow-api/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fiveYears = entity.RetentionPolicy{Period: 5 * 365 * 24 * time.Hour}
+
+func deletedUser(t *testing.T, deletedAt time.Time) *entity.User {
+	user := NewUser(t)
+	_, err := user.ChangeStatus(entity.AccountStatusClosed, "exclusão solicitada pelo titular", user.ID, deletedAt)
+	require.NoError(t, err)
+	require.NoError(t, user.SoftDelete(deletedAt))
+	return user
+}
+
+func TestSoftDeleteRequiresClosedAccount(t *testing.T) {
+	user := NewUser(t)
+
+	err := user.SoftDelete(time.Now())
+
+	assert.ErrorIs(t, err, entity.ErrAccountNotClosed)
+	assert.False(t, user.IsDeleted())
+}
+
+func TestSoftDeleteKeepsUserData(t *testing.T) {
+	now := time.Now()
+	user := deletedUser(t, now)
+
+	assert.True(t, user.IsDeleted())
+	assert.Equal(t, now, *user.DeletedAt)
+	assert.False(t, user.IsAnonymized())
+	assert.Equal(t, "João Silva", user.FullName)
+	assert.Equal(t, "11144477735", user.Document)
+
+	assert.ErrorIs(t, user.SoftDelete(now), entity.ErrUserNotFound)
+}
+
+func TestAnonymizeRespectsRetentionPeriod(t *testing.T) {
+	deletedAt := time.Now()
+	user := deletedUser(t, deletedAt)
+
+	assert.Equal(t, deletedAt.Add(fiveYears.Period), *user.AnonymizationDueAt(fiveYears))
+
+	err := user.Anonymize(fiveYears, deletedAt.Add(365*24*time.Hour))
+
+	assert.ErrorIs(t, err, entity.ErrRetentionPeriodActive)
+	assert.Equal(t, "João Silva", user.FullName)
+}
+
+func TestAnonymizeScrubsPersonalData(t *testing.T) {
+	deletedAt := time.Now().Add(-6 * 365 * 24 * time.Hour)
+	user := deletedUser(t, deletedAt)
+	id := user.ID
+	createdAt := user.CreatedAt
+	now := time.Now()
+
+	require.NoError(t, user.Anonymize(fiveYears, now))
+
+	assert.True(t, user.IsAnonymized())
+	assert.Equal(t, entity.AnonymizedUserName, user.FullName)
+	assert.Equal(t, "anonimizado-"+id+"@payflow.invalid", user.Email)
+	assert.Empty(t, user.Document)
+	assert.Empty(t, user.Password)
+	assert.NotContains(t, user.Email, "joao")
+
+	// Dados necessários ao histórico financeiro continuam
+	assert.Equal(t, id, user.ID)
+	assert.Equal(t, entity.UserTypeCommon, user.UserType)
+	assert.True(t, user.Balance.Equal(decimal.Zero))
+	assert.Equal(t, createdAt, user.CreatedAt)
+	assert.Equal(t, entity.AccountStatusClosed, user.Status)
+
+	assert.ErrorIs(t, user.Anonymize(fiveYears, now), entity.ErrUserAlreadyAnonymized)
+}
+
+func TestAnonymizeImmediatelyWithoutRetention(t *testing.T) {
+	now := time.Now()
+	user := deletedUser(t, now)
+	policy := entity.RetentionPolicy{}
+
+	assert.True(t, policy.AnonymizeImmediately())
+	assert.NoError(t, user.Anonymize(policy, now))
+}
+
+func TestAnonymizeRequiresDeletedUser(t *testing.T) {
+	user := NewUser(t)
+
+	assert.ErrorIs(t, user.Anonymize(entity.RetentionPolicy{}, time.Now()), entity.ErrUserNotDeleted)
+	assert.Nil(t, user.AnonymizationDueAt(fiveYears))
+}
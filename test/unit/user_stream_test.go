@@ -0,0 +1,104 @@
+package entity_test
+
+import (
+	"encoding/json"
+	"payflow-api/internal/entity"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_BalanceChangesCoalesce(t *testing.T) {
+	user, err := entity.NewUser("João Silva", "11144477735", "joao@example.com", "senha123", entity.UserTypeCommon)
+	require.NoError(t, err)
+	user.PullEvents()
+
+	user.CreditBalance(decimal.NewFromInt(100))
+	require.NoError(t, user.PlaceHold(decimal.NewFromInt(30)))
+	require.NoError(t, user.CaptureHold(decimal.NewFromInt(30)))
+
+	// Só o estado final interessa a quem acompanha o saldo
+	events := user.PullEvents()
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.EventBalanceChanged, events[0].Type)
+
+	var payload entity.BalanceChanged
+	require.NoError(t, events[0].Decode(&payload))
+	assert.Equal(t, user.ID, payload.UserID)
+	assert.True(t, user.Balance.Equal(payload.Balance))
+	assert.True(t, user.HeldBalance.Equal(payload.HeldBalance))
+}
+
+func TestUserStreamEventsFrom_BalanceChanged(t *testing.T) {
+	user, err := entity.NewUser("João Silva", "11144477735", "joao@example.com", "senha123", entity.UserTypeCommon)
+	require.NoError(t, err)
+	user.PullEvents()
+	user.CreditBalance(decimal.NewFromInt(80))
+
+	source := user.PullEvents()[0]
+	events, err := entity.UserStreamEventsFrom(source)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, user.ID, events[0].UserID)
+	assert.Equal(t, entity.UserStreamBalanceUpdated, events[0].Type)
+	assert.Equal(t, source.ID, events[0].SourceEventID)
+
+	var balance entity.BalanceResponse
+	require.NoError(t, json.Unmarshal(events[0].Data, &balance))
+	assert.Equal(t, user.ToBalanceResponse().Available, balance.Available)
+	assert.Equal(t, "80.00", balance.Balance)
+}
+
+func TestUserStreamEventsFrom_FailedGoesToPayerOnly(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer-1", "payee-1", decimal.NewFromInt(50))
+	require.NoError(t, err)
+	transaction.Fail("saldo insuficiente")
+
+	events, err := entity.UserStreamEventsFrom(transaction.PullEvents()[0])
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "payer-1", events[0].UserID)
+
+	var update entity.TransactionStreamUpdate
+	require.NoError(t, json.Unmarshal(events[0].Data, &update))
+	assert.Equal(t, entity.TransactionStatusFailed, update.Status)
+	assert.Equal(t, entity.TransferDirectionSent, update.Direction)
+	require.NotNil(t, update.Reason)
+	assert.Equal(t, "saldo insuficiente", *update.Reason)
+}
+
+func TestUserStreamEventsFrom_CompletedGoesToBothParties(t *testing.T) {
+	transaction, err := entity.NewTransaction("payer-1", "payee-1", decimal.NewFromInt(100))
+	require.NoError(t, err)
+	require.NoError(t, transaction.ApplyFee(decimal.NewFromInt(2), nil))
+	transaction.Complete()
+
+	events, err := entity.UserStreamEventsFrom(transaction.PullEvents()[0])
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	var sent, received entity.TransactionStreamUpdate
+	require.NoError(t, json.Unmarshal(events[0].Data, &sent))
+	require.NoError(t, json.Unmarshal(events[1].Data, &received))
+
+	assert.Equal(t, "payer-1", events[0].UserID)
+	assert.Equal(t, entity.TransferDirectionSent, sent.Direction)
+	assert.Equal(t, "payee-1", sent.CounterpartyID)
+
+	assert.Equal(t, "payee-1", events[1].UserID)
+	assert.Equal(t, entity.TransferDirectionReceived, received.Direction)
+	assert.Equal(t, "payer-1", received.CounterpartyID)
+	assert.Equal(t, "98.00", received.NetAmount)
+	assert.Equal(t, transaction.ID, received.TransactionID)
+}
+
+func TestUserStreamEventsFrom_IgnoresOtherEvents(t *testing.T) {
+	user, err := entity.NewUser("João Silva", "11144477735", "joao@example.com", "senha123", entity.UserTypeCommon)
+	require.NoError(t, err)
+
+	events, err := entity.UserStreamEventsFrom(user.PullEvents()[0])
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}